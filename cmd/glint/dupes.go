@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/output"
+	"github.com/aiseeq/glint/pkg/rules/duplication"
+)
+
+// dupesReport is the JSON document of `glint dupes`.
+type dupesReport struct {
+	MinTokens int                              `json:"min_tokens"`
+	Total     duplication.PackageDuplication   `json:"total"`
+	Packages  []duplication.PackageDuplication `json:"packages"`
+	Clones    []duplication.CloneClass         `json:"clones"`
+}
+
+func runDupes(_ *cobra.Command, args []string) error {
+	if flagDupesOutput != "text" && flagDupesOutput != "json" {
+		return fmt.Errorf("unknown dupes output %q (want text or json)", flagDupesOutput)
+	}
+	if flagDupesMinTokens <= 0 {
+		return fmt.Errorf("--min-tokens must be positive, got %d", flagDupesMinTokens)
+	}
+	projectRoot := "."
+	if len(args) > 0 {
+		projectRoot = args[0]
+	}
+
+	report, err := computeDupes(projectRoot, flagDupesMinTokens)
+	if err != nil {
+		return err
+	}
+	if flagDupesOutput == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return renderDupesText(os.Stdout, report)
+}
+
+// computeDupes finds the clone classes of the files the config does not
+// exclude, as the code-clone rule does, and totals them per package.
+func computeDupes(projectRoot string, minTokens int) (*dupesReport, error) {
+	cfg, _, err := loadConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	contexts, _, err := walkWithWalker(core.NewWalker(projectRoot, cfg).WithGoParsing(false))
+	if err != nil {
+		return nil, err
+	}
+
+	clones := duplication.FindClones(contexts, minTokens)
+	packages := duplication.DuplicationByPackage(contexts, clones)
+	total := duplication.PackageDuplication{Package: "total"}
+	for _, pkg := range packages {
+		total.Files += pkg.Files
+		total.Lines += pkg.Lines
+		total.DuplicatedLines += pkg.DuplicatedLines
+	}
+	if total.Lines > 0 {
+		total.Percent = 100 * float64(total.DuplicatedLines) / float64(total.Lines)
+	}
+	if clones == nil {
+		clones = []duplication.CloneClass{}
+	}
+	return &dupesReport{MinTokens: minTokens, Total: total, Packages: packages, Clones: clones}, nil
+}
+
+func renderDupesText(w io.Writer, report *dupesReport) error {
+	width := len("PACKAGE")
+	for _, pkg := range report.Packages {
+		width = max(width, len(pkg.Package))
+	}
+	row := func(rw *output.ReportWriter, pkg duplication.PackageDuplication) {
+		rw.Printf("%-*s %6d %8d %10d %7.1f%%\n", width, pkg.Package, pkg.Files, pkg.Lines, pkg.DuplicatedLines, pkg.Percent)
+	}
+
+	rw := output.NewReportWriter(w)
+	rw.Printf("%-*s %6s %8s %10s %8s\n", width, "PACKAGE", "FILES", "LINES", "DUPLICATED", "PERCENT")
+	for _, pkg := range report.Packages {
+		row(rw, pkg)
+	}
+	rw.Line(strings.Repeat("-", width+36))
+	row(rw, report.Total)
+	rw.Printf("\n%d clone classes of at least %d tokens; glint check --rule code-clone lists them\n",
+		len(report.Clones), report.MinTokens)
+	return rw.Err()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/rules/duplication"
+)
+
+func TestRenderDupesTextTotalsPackages(t *testing.T) {
+	report := &dupesReport{
+		MinTokens: 100,
+		Packages: []duplication.PackageDuplication{
+			{Package: "handlers", Files: 2, Lines: 200, DuplicatedLines: 50, Percent: 25},
+			{Package: "store", Files: 1, Lines: 100},
+		},
+		Total:  duplication.PackageDuplication{Package: "total", Files: 3, Lines: 300, DuplicatedLines: 50, Percent: 50.0 / 3},
+		Clones: make([]duplication.CloneClass, 1),
+	}
+	var out bytes.Buffer
+	if err := renderDupesText(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"PACKAGE   FILES    LINES DUPLICATED  PERCENT\n",
+		"handlers      2      200         50    25.0%\n",
+		"total         3      300         50    16.7%\n",
+		"1 clone classes of at least 100 tokens",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text report lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestComputeDupesFindsRenamedCopy(t *testing.T) {
+	root := t.TempDir()
+	handler := `package api
+
+func Create%[1]s(input map[string]string) (string, error) {
+	value, ok := input["%[2]s"]
+	if !ok || len(value) == 0 {
+		return "", errMissing
+	}
+	for key, other := range input {
+		if key != "%[2]s" && other == value {
+			return "", errDuplicate
+		}
+	}
+	normalized := normalize(value)
+	if err := store%[1]s(normalized); err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
+`
+	files := map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.24\n",
+		"api/user.go":   strings.NewReplacer("%[1]s", "User", "%[2]s", "name").Replace(handler),
+		"api/order.go":  strings.NewReplacer("%[1]s", "Order", "%[2]s", "item").Replace(handler),
+		"api/errors.go": "package api\n\nimport \"errors\"\n\nvar errMissing, errDuplicate = errors.New(\"missing\"), errors.New(\"duplicate\")\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := computeDupes(root, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Clones) != 1 || len(report.Clones[0].Fragments) != 2 {
+		t.Fatalf("want one class of two fragments, got %+v", report.Clones)
+	}
+	if len(report.Packages) != 1 || report.Packages[0].DuplicatedLines != 32 {
+		t.Fatalf("want the two 16-line copies counted in package api, got %+v", report.Packages)
+	}
+}
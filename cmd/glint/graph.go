@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/architecture"
+)
+
+func runGraph(_ *cobra.Command, args []string) error {
+	render, ok := graphRenderers[flagGraphFormat]
+	if !ok {
+		return fmt.Errorf("unknown graph format %q (want dot, mermaid or json)", flagGraphFormat)
+	}
+	projectRoot := "."
+	if len(args) > 0 {
+		projectRoot = args[0]
+	}
+
+	graph, err := buildProjectGraph(projectRoot)
+	if err != nil {
+		return err
+	}
+	if flagGraphLayer != "" {
+		if !graph.HasLayer(flagGraphLayer) {
+			return fmt.Errorf("unknown layer %q; the architecture declares: %s",
+				flagGraphLayer, strings.Join(graph.Layers, ", "))
+		}
+		graph.FilterLayer(flagGraphLayer)
+	}
+	if flagGraphViolations {
+		graph.FilterViolations()
+	}
+	return render(os.Stdout, graph)
+}
+
+// buildProjectGraph analyzes the project as `glint check` would and returns
+// its package graph with the findings counted per package.
+func buildProjectGraph(projectRoot string) (*architecture.Graph, error) {
+	cfg, enabledRules, err := loadConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	contexts, _, project, err := prepareAnalysis(projectRoot, cfg, enabledRules)
+	if err != nil {
+		return nil, err
+	}
+	if !hasGoFiles(contexts) {
+		return nil, fmt.Errorf("no Go packages below %s", projectRoot)
+	}
+	if project == nil {
+		// No enabled rule needed the typed project; the graph does.
+		project, err = core.LoadGoProject(projectRoot, contexts, core.GoProjectOptions{TolerateBrokenPackages: flagTolerant})
+		if err != nil {
+			return nil, fmt.Errorf("load Go project context: %w", err)
+		}
+	}
+
+	violations, err := analyzeProject(contexts, enabledRules, cfg, project)
+	if err != nil {
+		return nil, err
+	}
+	minSeverity, err := cfg.GetMinSeverity()
+	if err != nil {
+		return nil, err
+	}
+	graph, err := architecture.BuildGraph(project)
+	if err != nil {
+		return nil, err
+	}
+	graph.CountViolations(violations.BySeverity(minSeverity))
+	return graph, nil
+}
+
+var graphRenderers = map[string]func(io.Writer, *architecture.Graph) error{
+	"dot":     renderGraphDOT,
+	"mermaid": renderGraphMermaid,
+	"json":    renderGraphJSON,
+}
+
+// graphLabel names a package by its directory, with its finding count.
+func graphLabel(pkg architecture.GraphPackage) string {
+	name := pkg.Dir
+	if name == "." {
+		name = pkg.Path
+	}
+	if pkg.Violations > 0 {
+		name += " (" + strconv.Itoa(pkg.Violations) + ")"
+	}
+	return name
+}
+
+// graphByLayer groups package indexes by layer in the order of the
+// architecture, unlayered packages last under "".
+func graphByLayer(graph *architecture.Graph) ([]string, map[string][]int) {
+	groups := make(map[string][]int)
+	for i, pkg := range graph.Packages {
+		groups[pkg.Layer] = append(groups[pkg.Layer], i)
+	}
+	order := append(append([]string(nil), graph.Layers...), "")
+	return order, groups
+}
+
+func renderGraphDOT(w io.Writer, graph *architecture.Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph packages {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	ids := make(map[string]string, len(graph.Packages))
+	order, groups := graphByLayer(graph)
+	for n, layer := range order {
+		members := groups[layer]
+		if len(members) == 0 {
+			continue
+		}
+		indent := "\t"
+		if layer != "" {
+			fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", n, layer)
+			indent = "\t\t"
+		}
+		for _, i := range members {
+			pkg := graph.Packages[i]
+			ids[pkg.Path] = "p" + strconv.Itoa(i)
+			attrs := ""
+			if pkg.Violations > 0 {
+				attrs = ", color=red"
+			}
+			fmt.Fprintf(&b, "%s%s [label=%q%s];\n", indent, ids[pkg.Path], graphLabel(pkg), attrs)
+		}
+		if layer != "" {
+			b.WriteString("\t}\n")
+		}
+	}
+	for _, edge := range graph.Edges {
+		attrs := ""
+		if edge.Violation {
+			attrs = " [color=red, penwidth=2]"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", ids[edge.From], ids[edge.To], attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderGraphMermaid(w io.Writer, graph *architecture.Graph) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := make(map[string]string, len(graph.Packages))
+	order, groups := graphByLayer(graph)
+	for n, layer := range order {
+		members := groups[layer]
+		if len(members) == 0 {
+			continue
+		}
+		indent := "    "
+		if layer != "" {
+			fmt.Fprintf(&b, "    subgraph layer%d[\"%s\"]\n", n, mermaidText(layer))
+			indent = "        "
+		}
+		for _, i := range members {
+			pkg := graph.Packages[i]
+			ids[pkg.Path] = "p" + strconv.Itoa(i)
+			fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, ids[pkg.Path], mermaidText(graphLabel(pkg)))
+		}
+		if layer != "" {
+			b.WriteString("    end\n")
+		}
+	}
+	var violating []string
+	for n, edge := range graph.Edges {
+		fmt.Fprintf(&b, "    %s --> %s\n", ids[edge.From], ids[edge.To])
+		if edge.Violation {
+			violating = append(violating, strconv.Itoa(n))
+		}
+	}
+	if len(violating) > 0 {
+		fmt.Fprintf(&b, "    linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(violating, ","))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidText escapes the one character a quoted Mermaid label cannot hold.
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+func renderGraphJSON(w io.Writer, graph *architecture.Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(graph)
+}
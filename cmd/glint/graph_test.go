@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/rules/architecture"
+)
+
+func sampleGraph() *architecture.Graph {
+	return &architecture.Graph{
+		Layers: []string{"handler", "service"},
+		Packages: []architecture.GraphPackage{
+			{Path: "example.com/app/handlers", Dir: "handlers", Layer: "handler"},
+			{Path: "example.com/app/services", Dir: "services", Layer: "service", Violations: 2},
+			{Path: "example.com/app/models", Dir: "models"},
+		},
+		Edges: []architecture.GraphEdge{
+			{From: "example.com/app/handlers", To: "example.com/app/services", Imports: []string{"handlers/h.go:3"}},
+			{From: "example.com/app/services", To: "example.com/app/handlers", Imports: []string{"services/s.go:4"}, Violation: true},
+			{From: "example.com/app/services", To: "example.com/app/models", Imports: []string{"services/s.go:5"}},
+		},
+	}
+}
+
+func TestRenderGraphDOTClustersLayersAndMarksViolations(t *testing.T) {
+	var out bytes.Buffer
+	if err := renderGraphDOT(&out, sampleGraph()); err != nil {
+		t.Fatal(err)
+	}
+	dot := out.String()
+	for _, want := range []string{
+		"subgraph cluster_0 {\n\t\tlabel=\"handler\";",
+		"p1 [label=\"services (2)\", color=red];",
+		"\tp2 [label=\"models\"];", // unlayered, outside every cluster
+		"p1 -> p0 [color=red, penwidth=2];",
+		"p0 -> p1;",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output lacks %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestRenderGraphMermaidStylesViolatingLinks(t *testing.T) {
+	var out bytes.Buffer
+	if err := renderGraphMermaid(&out, sampleGraph()); err != nil {
+		t.Fatal(err)
+	}
+	mermaid := out.String()
+	for _, want := range []string{
+		"subgraph layer1[\"service\"]",
+		"p1[\"services (2)\"]",
+		"p1 --> p0",
+		"linkStyle 1 stroke:red",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid output lacks %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestGraphFilterViolationsKeepsOnlyViolatingEdges(t *testing.T) {
+	graph := sampleGraph()
+	graph.FilterViolations()
+
+	var out bytes.Buffer
+	if err := renderGraphJSON(&out, graph); err != nil {
+		t.Fatal(err)
+	}
+	var decoded architecture.Graph
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Edges) != 1 || !decoded.Edges[0].Violation {
+		t.Fatalf("only the violating edge must remain, got %+v", decoded.Edges)
+	}
+	if len(decoded.Packages) != 2 {
+		t.Fatalf("only the ends of the violating edge must remain, got %+v", decoded.Packages)
+	}
+}
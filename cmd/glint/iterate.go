@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/fix"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+// defaultMaxFixPasses bounds --iterate. Real chains of fixes revealing fixes
+// settle in two or three passes; a run still changing files after ten is
+// almost certainly going round in circles.
+const defaultMaxFixPasses = 10
+
+// fixPassRecord is what one applied pass changed: the content each file had
+// before the pass, and the rules whose fixes were applied.
+type fixPassRecord struct {
+	before map[string]string
+	rules  map[string]int
+}
+
+// fixHistory remembers the passes of one --iterate run to tell a chain of
+// fixes that settles from two fixers undoing each other.
+type fixHistory struct {
+	passes []fixPassRecord
+}
+
+// record stores a pass. before must hold the content of every file the pass
+// changed, read before it was applied. A file written with some of its fixes
+// left out counts with the fixes that were applied.
+func (h *fixHistory) record(before map[string]string, results []fix.Result) {
+	pass := fixPassRecord{before: before, rules: make(map[string]int)}
+	for _, result := range results {
+		if !result.Written {
+			continue
+		}
+		for _, applied := range result.Fixes {
+			if !slices.Contains(result.Unapplied, applied) {
+				pass.rules[applied.RuleName]++
+			}
+		}
+	}
+	h.passes = append(h.passes, pass)
+}
+
+// findOscillation returns the rules of the passes that brought the files back
+// to where an earlier pass started, or nil if none did. current reads a file
+// as it is now.
+func (h *fixHistory) findOscillation(current func(string) (string, error)) ([]string, error) {
+	last := len(h.passes) - 1
+	for start := last - 1; start >= 0; start-- {
+		// The state before pass start, for every file changed since: a file
+		// first changed in a later pass had that content until then.
+		state := make(map[string]string)
+		for pass := last; pass >= start; pass-- {
+			for file, content := range h.passes[pass].before {
+				state[file] = content
+			}
+		}
+		same, err := sameContent(state, current)
+		if err != nil {
+			return nil, err
+		}
+		if !same {
+			continue
+		}
+		involved := make(map[string]bool)
+		for _, pass := range h.passes[start:] {
+			for rule := range pass.rules {
+				involved[rule] = true
+			}
+		}
+		names := make([]string, 0, len(involved))
+		for rule := range involved {
+			names = append(names, rule)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	return nil, nil
+}
+
+func sameContent(state map[string]string, current func(string) (string, error)) (bool, error) {
+	for file, content := range state {
+		now, err := current(file)
+		if err != nil {
+			return false, err
+		}
+		if now != content {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// summary lists the rules of the pass by the number of fixes applied.
+func (p fixPassRecord) summary() string {
+	names := make([]string, 0, len(p.rules))
+	for rule := range p.rules {
+		names = append(names, rule)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, rule := range names {
+		parts = append(parts, fmt.Sprintf("%s %d", rule, p.rules[rule]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// iterateFixes fixes the root, then re-analyzes only the files each pass
+// changed and fixes them again, until a pass changes nothing, the fixes
+// oscillate, or --max-passes is reached.
+func iterateFixes(projectRoot string, cfg *core.Config, fixableRules []rules.Rule, engine *fix.Engine) error {
+	if flagMaxPasses < 1 {
+		return fmt.Errorf("--max-passes must be at least 1, got %d", flagMaxPasses)
+	}
+
+	var history fixHistory
+	var onlyFiles map[string]bool
+	for pass := 1; pass <= flagMaxPasses; pass++ {
+		fmt.Fprintf(os.Stdout, "\n=== Pass %d ===\n", pass)
+		before := newFileSnapshot()
+		results, err := fixPass(projectRoot, cfg, fixableRules, engine, false, onlyFiles, before.capture)
+		if errors.Is(err, errNothingToFix) {
+			fmt.Fprintf(os.Stdout, "\nStable after %d pass(es).\n", pass)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// A file written with some fixes left out changed all the same: the
+		// next pass has to look at it again, and the oscillation check has
+		// to see it.
+		changed := make(map[string]bool)
+		applied := 0
+		for _, result := range results {
+			if result.Written {
+				changed[result.File] = true
+				applied += result.FixesApplied
+			}
+		}
+		if applied == 0 {
+			fmt.Fprintf(os.Stdout, "\nNo fix could be applied in pass %d; stopping.\n", pass)
+			return nil
+		}
+
+		history.record(before.contents, results)
+		fmt.Fprintf(os.Stdout, "Pass %d: applied %d fixes in %d files (%s)\n",
+			pass, applied, len(changed), history.passes[len(history.passes)-1].summary())
+
+		cycle, err := history.findOscillation(readFile)
+		if err != nil {
+			return fmt.Errorf("compare pass %d with the earlier ones: %w", pass, err)
+		}
+		if cycle != nil {
+			return fmt.Errorf("fixes oscillate after pass %d: %s undo each other; fix these findings by hand",
+				pass, strings.Join(cycle, " and "))
+		}
+		onlyFiles = changed
+	}
+
+	fmt.Fprintf(os.Stderr, "Still changing files after %d passes; stopped. Raise --max-passes or run glint fix again.\n", flagMaxPasses)
+	return nil
+}
+
+// fileSnapshot keeps the content files had before a pass changed them.
+type fileSnapshot struct {
+	contents map[string]string
+}
+
+func newFileSnapshot() *fileSnapshot {
+	return &fileSnapshot{contents: make(map[string]string)}
+}
+
+// capture reads the files the fixes are about to change.
+func (s *fileSnapshot) capture(fixes []*fix.Fix) error {
+	for _, f := range fixes {
+		if _, ok := s.contents[f.File]; ok {
+			continue
+		}
+		content, err := readFile(f.File)
+		if err != nil {
+			return err
+		}
+		s.contents[f.File] = content
+	}
+	return nil
+}
+
+func readFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return string(content), nil
+}
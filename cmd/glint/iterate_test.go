@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/fix"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+// Two findings on one line produce the same first-match edit, so one pass
+// fixes only the first comparison; the second is found on the next pass.
+func TestIterateFixesRunsUntilStable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flags.go")
+	source := "package flags\n\nfunc both(a, b bool) bool {\n\treturn a == true && b == true\n}\n"
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	prevUnsafe, prevPasses := flagUnsafe, flagMaxPasses
+	flagUnsafe, flagMaxPasses = true, defaultMaxFixPasses
+	t.Cleanup(func() { flagUnsafe, flagMaxPasses = prevUnsafe, prevPasses })
+
+	rule, ok := rules.Get("bool-compare")
+	if !ok {
+		t.Fatal("bool-compare must be registered")
+	}
+	engine := fix.NewEngine(fix.DefaultRegistry, false)
+	if err := iterateFixes(dir, core.DefaultConfig(), []rules.Rule{rule}, engine); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "return a && b") {
+		t.Fatalf("both comparisons must be simplified, got:\n%s", content)
+	}
+}
+
+func TestFixHistoryDetectsTwoFixersUndoingEachOther(t *testing.T) {
+	files := map[string]string{"a.go": "var x interface{}", "b.go": "untouched"}
+	read := func(path string) (string, error) {
+		content, ok := files[path]
+		if !ok {
+			return "", os.ErrNotExist
+		}
+		return content, nil
+	}
+	results := func(rule string) []fix.Result {
+		return []fix.Result{{File: "a.go", FixesApplied: 1, Written: true, Fixes: []*fix.Fix{{File: "a.go", RuleName: rule}}}}
+	}
+
+	var history fixHistory
+	history.record(map[string]string{"a.go": files["a.go"]}, results("interface-any"))
+	files["a.go"] = "var x any"
+	if cycle, err := history.findOscillation(read); err != nil || cycle != nil {
+		t.Fatalf("a single change is not an oscillation, got %v (%v)", cycle, err)
+	}
+
+	history.record(map[string]string{"a.go": files["a.go"]}, results("prefer-interface"))
+	files["a.go"] = "var x interface{}"
+	cycle, err := history.findOscillation(read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(cycle, ",") != "interface-any,prefer-interface" {
+		t.Fatalf("both fixers of the cycle must be named, got %v", cycle)
+	}
+}
+
+// rewriteRule reports every line holding one of its words.
+type rewriteRule struct {
+	*rules.BaseRule
+	rewrites map[string]string
+}
+
+func (r *rewriteRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
+	var violations []*core.Violation
+	for i, line := range ctx.Lines {
+		for from := range r.rewrites {
+			if strings.Contains(line, from) {
+				violations = append(violations, r.CreateViolation(ctx.RelPath, i+1, "rewrite "+from))
+			}
+		}
+	}
+	return violations
+}
+
+// rewriteFixer rewrites the reported word and, with every fix, emits an edit
+// that no longer matches the file: each file it touches is written with an
+// error, the partial apply --iterate has to follow.
+type rewriteFixer struct {
+	rule *rewriteRule
+}
+
+func (f *rewriteFixer) RuleName() string              { return f.rule.Name() }
+func (f *rewriteFixer) CanFix(v *core.Violation) bool { return v.Rule == f.rule.Name() }
+func (f *rewriteFixer) Safety() fix.Safety            { return fix.SafetySafe }
+
+func (f *rewriteFixer) GenerateFix(ctx *core.FileContext, v *core.Violation) []*fix.Fix {
+	line := ctx.Lines[v.Line-1]
+	for from, to := range f.rule.rewrites {
+		if strings.Contains(line, from) {
+			return []*fix.Fix{
+				{File: ctx.Path, StartLine: v.Line, EndLine: v.Line, OldText: line,
+					NewText: strings.ReplaceAll(line, from, to), RuleName: f.RuleName(), Violation: v},
+				{File: ctx.Path, StartLine: 1, EndLine: 1, OldText: "package gone",
+					NewText: "package p", RuleName: f.RuleName(), Violation: v},
+			}
+		}
+	}
+	return nil
+}
+
+// iterateRewrites runs --iterate over one file with the given rewriting rules
+// and returns the error and the file as the run left it.
+func iterateRewrites(t *testing.T, source string, rewrites map[string]map[string]string) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "p.go")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prevPasses := flagMaxPasses
+	flagMaxPasses = defaultMaxFixPasses
+	t.Cleanup(func() { flagMaxPasses = prevPasses })
+
+	registry := fix.NewRegistry()
+	var fixable []rules.Rule
+	for name, words := range rewrites {
+		rule := &rewriteRule{BaseRule: rules.NewBaseRule(name, "patterns", "rewrites words", core.SeverityLow), rewrites: words}
+		registry.Register(&rewriteFixer{rule: rule})
+		fixable = append(fixable, rule)
+	}
+	runErr := iterateFixes(dir, core.DefaultConfig(), fixable, fix.NewEngine(registry, false))
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content), runErr
+}
+
+// A file written with one of its fixes left out has changed: the next pass
+// must come back to it and find what the applied fix revealed.
+func TestIterateFixesRevisitsPartiallyAppliedFile(t *testing.T) {
+	content, err := iterateRewrites(t, "package p\n\n// step1\n",
+		map[string]map[string]string{"steps": {"step1": "step2", "step2": "step3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "package p\n\n// step3\n" {
+		t.Fatalf("every step must be taken, got:\n%s", content)
+	}
+}
+
+// Two fixers undoing each other are caught even when every pass applies
+// only part of its fixes.
+func TestIterateFixesDetectsOscillationThroughPartialApplies(t *testing.T) {
+	_, err := iterateRewrites(t, "package p\n\n// x interface{}\n", map[string]map[string]string{
+		"to-any":       {"interface{}": "any"},
+		"to-interface": {"any": "interface{}"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "oscillate") ||
+		!strings.Contains(err.Error(), "to-any and to-interface") {
+		t.Fatalf("the oscillation must be reported naming both fixers, got %v", err)
+	}
+}
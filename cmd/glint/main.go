@@ -24,7 +24,7 @@ import (
 	_ "github.com/aiseeq/glint/pkg/rules/architecture"
 	_ "github.com/aiseeq/glint/pkg/rules/deadcode"
 	_ "github.com/aiseeq/glint/pkg/rules/doccheck"
-	_ "github.com/aiseeq/glint/pkg/rules/duplication"
+	"github.com/aiseeq/glint/pkg/rules/duplication"
 	_ "github.com/aiseeq/glint/pkg/rules/naming"
 	_ "github.com/aiseeq/glint/pkg/rules/patterns"
 	_ "github.com/aiseeq/glint/pkg/rules/security"
@@ -65,9 +65,26 @@ var (
 	flagTolerant    bool
 	flagTiming      bool
 	// Fix command flags
-	flagDryRun  bool
-	flagForce   bool
-	flagFixRule string
+	flagDryRun    bool
+	flagForce     bool
+	flagFixRule   string
+	flagSuppress  bool
+	flagReason    string
+	flagUnsafe    bool
+	flagIterate   bool
+	flagMaxPasses int
+	// Graph command flags
+	flagGraphFormat     string
+	flagGraphLayer      string
+	flagGraphViolations bool
+	// Metrics command flags
+	flagMetricsOutput string
+	flagMetricsLevel  string
+	// Dupes command flags
+	flagDupesOutput    string
+	flagDupesMinTokens int
+	// Secrets command flags
+	flagSecretsHistory bool
 )
 
 // timings collects per-phase and per-rule durations under --timing; nil (the
@@ -153,14 +170,111 @@ Use --dry-run=false to actually apply fixes.
 Findings silenced by configuration exceptions or by inline suppression
 comments are left alone, exactly as 'glint check' reports them.
 
+With --suppress, every finding of every enabled rule is silenced at its source
+instead, with a comment carrying --reason:
+
+  glint fix --suppress --reason="pre-existing, tracked in PLAT-123" --dry-run=false
+
+Rules that forbid suppression keep their findings.
+
+Only safe fixes, which keep the program's behavior, are applied by default.
+Fixes that may change behavior are listed and need --unsafe. Two fixes from
+different rules that edit the same range are reported as a conflict and left
+for the next run.
+
+A fix can reveal the next finding. --iterate re-analyzes the fixed files and
+fixes again until nothing changes, stopping after --max-passes or when two
+fixers keep undoing each other.
+
 Available fixers:
   - interface-any: Replace the empty interface type with any (Go 1.18+)
   - deprecated-ioutil: Replace io/ioutil with io/os
   - bool-compare: Simplify boolean comparisons (x == true -> x)
-  - md-line-break, md-list-after-label: Markdown formatting`,
+  - go-modern: slices.Sort, slices.Contains, built-in min/max, maps.Keys and
+    range-over-int, only up to the go directive of the module
+  - md-line-break, md-list-after-label: Markdown formatting
+  - frontend-env-fallback: throw on a missing required env instead of a fallback
+  - e2e-blind-wait: waitForTimeout -> a TODO-marked waitForResponse skeleton
+  - financial-fp-rounding: route cent rounding through the money_helper
+    configured for the rule`,
 	RunE: runFix,
 }
 
+var graphCmd = &cobra.Command{
+	Use:   "graph [path]",
+	Short: "Export the package dependency graph with its layers",
+	Long: `Export the dependency graph of the module's packages, classified into the
+layers of the architecture config, with the number of findings in each package.
+Edges with an import the layer rules do not allow are marked.
+
+  glint graph --format=dot | dot -Tsvg > graph.svg
+  glint graph --format=mermaid --violations
+  glint graph --format=json --layer=domain
+
+Formats: dot (Graphviz), mermaid, json. --layer keeps one layer's packages and
+the edges that touch them; --violations keeps the violating edges only.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGraph,
+}
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics [path]",
+	Short: "Export design metrics of the Go packages",
+	Long: `Export design metrics of the module's packages, computed from the typed
+packages as the architecture rules see them:
+
+  package   afferent/efferent coupling, instability, abstractness and the
+            distance from the main sequence
+  struct    LCOM4, the number of method groups that share no state
+  function  fan-in and fan-out over static calls
+
+  glint metrics -o json > metrics.json
+  glint metrics -o csv --level=struct
+
+JSON holds all three levels; CSV writes the table of one --level. The
+package-coupling, struct-cohesion and function-fan-out rules report the
+values over their configured thresholds.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMetrics,
+}
+
+var dupesCmd = &cobra.Command{
+	Use:   "dupes [path]",
+	Short: "Report the share of duplicated lines per package",
+	Long: `Find the token-based clone classes the code-clone rule reports — code
+repeated with renamed identifiers, changed literals or different wrapping —
+and report how many lines of each package lie in a clone.
+
+  glint dupes
+  glint dupes --min-tokens=150 -o json > dupes.json
+
+Text prints the per-package table; JSON adds every clone class with all its
+fragments. Test files are not counted.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDupes,
+}
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets [path]",
+	Short: "Scan for hardcoded secrets, also in the git history",
+	Long: `Run the hardcoded-secret rule alone over the working tree, with its
+configuration, exceptions and suppressions.
+
+  glint secrets
+  glint secrets --history -o json > secrets.json
+
+--history also scans every file version the git history of all branches and
+tags introduced: a key deleted in a later commit is still readable by anyone
+who clones the repository and has to be rotated. Each blob is scanned once,
+however many commits and paths carry it, and is reported as
+<commit>:<path> of the first commit that introduced it. Only the paths
+'glint check' would analyze are read; blobs still at HEAD are left to the
+working tree scan, and binary blobs and blobs over 1 MiB are skipped.
+Secrets are masked in the output as in 'glint check'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSecrets,
+}
+
 func init() {
 	// Check command flags
 	checkCmd.Flags().StringVarP(&flagCategory, "category", "c", "", "Run only specified category")
@@ -186,8 +300,32 @@ func init() {
 	fixCmd.Flags().BoolVar(&flagDryRun, "dry-run", true, "Show what would be fixed without applying (default: true)")
 	fixCmd.Flags().BoolVar(&flagForce, "force", false, "Apply fixes even with uncommitted changes")
 	fixCmd.Flags().StringVarP(&flagFixRule, "rule", "r", "", "Fix only specified rule")
+	fixCmd.Flags().BoolVar(&flagSuppress, "suppress", false, "Silence every current finding with a nolint comment at its source instead of fixing it")
+	fixCmd.Flags().StringVar(&flagReason, "reason", "", "Reason written into each suppression comment (required with --suppress)")
+	fixCmd.Flags().BoolVar(&flagUnsafe, "unsafe", false, "Also apply fixes that may change behavior (semantic-change and unsafe)")
+	fixCmd.Flags().BoolVar(&flagIterate, "iterate", false, "Re-analyze the fixed files and fix again until nothing changes")
+	fixCmd.Flags().IntVar(&flagMaxPasses, "max-passes", defaultMaxFixPasses, "Upper bound on the passes of --iterate")
 	fixCmd.Flags().BoolVarP(&flagVerbose, "verbose", "v", false, "Show detailed output")
 
+	// Graph command flags
+	graphCmd.Flags().StringVarP(&flagGraphFormat, "format", "f", "dot", "Output format: dot, mermaid, json")
+	graphCmd.Flags().StringVar(&flagGraphLayer, "layer", "", "Show only the packages of this layer and their edges")
+	graphCmd.Flags().BoolVar(&flagGraphViolations, "violations", false, "Show only edges that violate the layer rules")
+	graphCmd.Flags().BoolVar(&flagTolerant, "tolerate-broken-packages", false, "Graph the packages that type-check instead of failing")
+
+	// Metrics command flags
+	metricsCmd.Flags().StringVarP(&flagMetricsOutput, "output", "o", "json", "Output format: json, csv")
+	metricsCmd.Flags().StringVar(&flagMetricsLevel, "level", "package", "Table written as CSV: package, struct, function")
+	metricsCmd.Flags().BoolVar(&flagTolerant, "tolerate-broken-packages", false, "Measure the packages that type-check instead of failing")
+
+	// Dupes command flags
+	dupesCmd.Flags().StringVarP(&flagDupesOutput, "output", "o", "text", "Output format: text, json")
+	dupesCmd.Flags().IntVar(&flagDupesMinTokens, "min-tokens", duplication.DefaultCloneTokens, "Shortest clone counted, in normalized tokens")
+
+	// Secrets command flags
+	secretsCmd.Flags().BoolVar(&flagSecretsHistory, "history", false, "Also scan the file versions in the git history")
+	secretsCmd.Flags().StringVarP(&flagOutput, "output", "o", "", "Output format: console, json, summary (default from config, else console)")
+
 	// Root commands
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(rulesCmd)
@@ -195,6 +333,10 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(fixCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(dupesCmd)
+	rootCmd.AddCommand(secretsCmd)
 }
 
 func runCheck(_ *cobra.Command, args []string) error {
@@ -459,7 +601,9 @@ func prepareAnalysis(projectRoot string, cfg *core.Config, enabledRules []rules.
 		requireSSA = requireSSA || projectRule.RequiresSSA()
 	}
 
-	walker := core.NewWalker(projectRoot, cfg).WithGoParsing(projectRuleCount == 0)
+	walker := core.NewWalker(projectRoot, cfg).
+		WithGoParsing(projectRuleCount == 0).
+		WithExtraFiles(rules.ExtraFiles(enabledRules))
 	contexts, walker, err := walkWithWalker(walker)
 	if err != nil {
 		return nil, walker, nil, err
@@ -616,8 +760,9 @@ func runStatelessRules(contexts []*core.FileContext, enabledRules []rules.Rule,
 
 // runRule applies one rule to one file and filters the findings the same way
 // for every caller: rule exceptions, inline suppression, severity overrides.
+// Files admitted for another rule's ExtraFiles are not this rule's to read.
 func runRule(ctx *core.FileContext, rule rules.Rule, cfg *core.Config, overrides severityOverrides) core.ViolationList {
-	if cfg.IsFileExcepted(rule.Category(), rule.Name(), ctx.RelPath) {
+	if !rules.AnalyzesFile(rule, ctx.Path) || cfg.IsFileExcepted(rule.Category(), rule.Name(), ctx.RelPath) {
 		return nil
 	}
 
@@ -890,6 +1035,9 @@ func runFix(_ *cobra.Command, args []string) error {
 			return fmt.Errorf("unknown rule %q; run 'glint rules' to list them", flagFixRule)
 		}
 	}
+	if flagSuppress && strings.TrimSpace(flagReason) == "" {
+		return errors.New("--suppress needs --reason: a suppression without one is indistinguishable from a fix nobody reviewed")
+	}
 
 	for _, projectRoot := range projectRoots {
 		if err := fixProjectRoot(projectRoot); err != nil {
@@ -930,6 +1078,9 @@ func fixProjectRoot(projectRoot string) error {
 	if err != nil {
 		return err
 	}
+	if flagSuppress {
+		return suppressProjectRoot(projectRoot, cfg, enabledRules, engine, dryRun)
+	}
 
 	// Filter to only rules that have fixers
 	var fixableRules []rules.Rule
@@ -955,12 +1106,44 @@ func fixProjectRoot(projectRoot string) error {
 		fmt.Printf("Running %d fixable rules...\n", len(fixableRules))
 	}
 
+	if flagIterate && !dryRun {
+		return iterateFixes(projectRoot, cfg, fixableRules, engine)
+	}
+	if flagIterate {
+		fmt.Println("--iterate needs fixes applied to find the next ones; showing the first pass only.")
+	}
+	_, err = fixPass(projectRoot, cfg, fixableRules, engine, dryRun, nil, nil)
+	if errors.Is(err, errNothingToFix) {
+		return nil
+	}
+	return err
+}
+
+// errNothingToFix signals a pass that found no finding it could fix. It ends
+// a run, or an --iterate loop, without failing it.
+var errNothingToFix = errors.New("nothing to fix")
+
+// fixPass analyzes the root once and applies the fixes found, returning the
+// per-file results, or errNothingToFix. With onlyFiles set, findings outside
+// those files are ignored; beforeApply, when set, sees the fixes before they
+// are applied and can stop the pass.
+func fixPass(projectRoot string, cfg *core.Config, fixableRules []rules.Rule, engine *fix.Engine, dryRun bool,
+	onlyFiles map[string]bool, beforeApply func([]*fix.Fix) error) ([]fix.Result, error) {
 	// Collect findings through the same pipeline as `check`, so that project
 	// rules run, and configuration exceptions and inline suppression comments
 	// are honored.
 	contexts, _, project, err := prepareAnalysis(projectRoot, cfg, fixableRules)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if onlyFiles != nil {
+		kept := contexts[:0]
+		for _, ctx := range contexts {
+			if onlyFiles[ctx.Path] {
+				kept = append(kept, ctx)
+			}
+		}
+		contexts = kept
 	}
 
 	// Build context map for fixers (by both absolute and relative paths)
@@ -973,23 +1156,64 @@ func fixProjectRoot(projectRoot string) error {
 	rules.ResetState(fixableRules)
 	violations, err := analyzeProject(contexts, fixableRules, cfg, project)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if onlyFiles != nil {
+		// Project rules report on the whole tree, not only on the contexts
+		// they were given.
+		var inScope core.ViolationList
+		for _, violation := range violations {
+			if _, ok := contextMap[violation.File]; ok {
+				inScope = append(inScope, violation)
+			}
+		}
+		violations = inScope
 	}
 
 	if len(violations) == 0 {
 		fmt.Println("No issues found that can be fixed.")
-		return nil
+		return nil, errNothingToFix
 	}
 
 	// Generate fixes
-	fixes := engine.GenerateFixes(violations, contextMap)
+	fixes := selectFixes(engine.GenerateFixes(violations, contextMap))
 
 	if len(fixes) == 0 {
 		fmt.Println("No automatic fixes available for the found issues.")
-		return nil
+		return nil, errNothingToFix
 	}
 
-	// Show preview
+	if beforeApply != nil {
+		if err := beforeApply(fixes); err != nil {
+			return nil, err
+		}
+	}
+	return applyFixes(engine, fixes, dryRun), nil
+}
+
+// selectFixes keeps the fixes --unsafe allows and that collide with no other
+// rule's fix, and says what it left out: a fix that silently disappears reads
+// as a rule that has no fixer.
+func selectFixes(fixes []*fix.Fix) []*fix.Fix {
+	allowed := fix.SafetySafe
+	if flagUnsafe {
+		allowed = fix.SafetyUnsafe
+	}
+	selection := fix.Select(fixes, allowed)
+	if len(selection.Held) > 0 {
+		fmt.Printf("Fixes that may change behavior were held back (use --unsafe to apply them): %s\n\n",
+			fix.HeldSummary(selection.Held))
+	}
+	for _, conflict := range selection.Conflicts {
+		fmt.Fprintf(os.Stderr, "Conflict: %s; neither was applied, run glint fix again afterwards\n", conflict)
+	}
+	return selection.Apply
+}
+
+// applyFixes previews the fixes and, unless this is a dry run, applies them
+// and reports per-file results.
+func applyFixes(engine *fix.Engine, fixes []*fix.Fix, dryRun bool) []fix.Result {
 	fmt.Print(engine.Preview(fixes))
 
 	if dryRun {
@@ -1002,16 +1226,15 @@ func fixProjectRoot(projectRoot string) error {
 	// Report results
 	totalFixed := 0
 	for _, result := range results {
+		// A file written with some fixes left out keeps the ones applied.
+		totalFixed += result.FixesApplied
 		if result.Error != nil {
 			fmt.Fprintf(os.Stderr, "Error fixing %s: %v\n", result.File, result.Error)
-		} else {
-			totalFixed += result.FixesApplied
-			if flagVerbose {
-				fmt.Printf("Fixed %d issues in %s\n", result.FixesApplied, result.File)
-			}
+		} else if flagVerbose {
+			fmt.Printf("Fixed %d issues in %s\n", result.FixesApplied, result.File)
 		}
 	}
 
 	fmt.Printf("\nApplied %d fixes in %d files.\n", totalFixed, len(results))
-	return nil
+	return results
 }
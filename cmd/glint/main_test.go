@@ -319,6 +319,50 @@ func TestPrepareAnalysisWithoutProjectRuleUsesWalkerParser(t *testing.T) {
 	}
 }
 
+// Файлы, которые walker принял ради ExtraFiles одного правила (values.yaml
+// для hardcoded-secret), не попадают в правила, которые их не запрашивали.
+func TestAnalyzeFilesRunsExtraFilesOnlyThroughClaimingRule(t *testing.T) {
+	root := writeAnalysisModule(t, "package check\n")
+	if err := os.WriteFile(filepath.Join(root, "values.yaml"), []byte("database:\n  password: hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	secretRule, ok := rules.Get("hardcoded-secret")
+	if !ok {
+		t.Fatal("hardcoded-secret is not registered")
+	}
+	fileRule := &pathStubRule{BaseRule: rules.NewBaseRule("path-stub", "patterns", "test file rule", core.SeverityLow)}
+	enabled := []rules.Rule{secretRule, fileRule}
+
+	contexts, _, _, err := prepareAnalysis(root, core.DefaultConfig(), enabled)
+	if err != nil {
+		t.Fatalf("prepare analysis: %v", err)
+	}
+	if len(contexts) != 2 {
+		t.Fatalf("walker must admit values.yaml for hardcoded-secret, got %d files", len(contexts))
+	}
+	violations := analyzeFiles(contexts, enabled, core.DefaultConfig(), nil)
+	if len(violations) != 1 || violations[0].File != "values.yaml" {
+		t.Fatalf("want the password in values.yaml, got %+v", violations)
+	}
+	if len(fileRule.paths) != 1 || fileRule.paths[0] != "check.go" {
+		t.Fatalf("the stub rule must see check.go only, saw %v", fileRule.paths)
+	}
+}
+
+// pathStubRule records the files it is run on; stateful, so that it is run
+// on one file at a time.
+type pathStubRule struct {
+	*rules.BaseRule
+	paths []string
+}
+
+func (r *pathStubRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
+	r.paths = append(r.paths, ctx.RelPath)
+	return nil
+}
+
+func (r *pathStubRule) ResetState() { r.paths = nil }
+
 func TestPrepareAnalysisSkipsGoProjectForTreeWithoutGoFiles(t *testing.T) {
 	root := t.TempDir()
 	if err := os.WriteFile(filepath.Join(root, "app.ts"), []byte("export const x = 1\n"), 0644); err != nil {
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/metrics"
+)
+
+func runMetrics(_ *cobra.Command, args []string) error {
+	if flagMetricsOutput != "json" && flagMetricsOutput != "csv" {
+		return fmt.Errorf("unknown metrics output %q (want json or csv)", flagMetricsOutput)
+	}
+	projectRoot := "."
+	if len(args) > 0 {
+		projectRoot = args[0]
+	}
+
+	report, err := computeProjectMetrics(projectRoot)
+	if err != nil {
+		return err
+	}
+	if flagMetricsOutput == "csv" {
+		return metrics.WriteCSV(os.Stdout, report, flagMetricsLevel)
+	}
+	return metrics.WriteJSON(os.Stdout, report)
+}
+
+// computeProjectMetrics loads the typed packages of the project, skipping the
+// files the config excludes, and measures them. No rule runs.
+func computeProjectMetrics(projectRoot string) (*metrics.Report, error) {
+	cfg, _, err := loadConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	contexts, _, err := walkWithWalker(core.NewWalker(projectRoot, cfg).WithGoParsing(false))
+	if err != nil {
+		return nil, err
+	}
+	if !hasGoFiles(contexts) {
+		return nil, fmt.Errorf("no Go packages below %s", projectRoot)
+	}
+	project, err := core.LoadGoProject(projectRoot, contexts, core.GoProjectOptions{TolerateBrokenPackages: flagTolerant})
+	if err != nil {
+		return nil, fmt.Errorf("load Go project context: %w", err)
+	}
+	return metrics.Compute(project), nil
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/output"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+// secretsRuleName is the rule `glint secrets` runs.
+const secretsRuleName = "hardcoded-secret"
+
+// maxHistoryBlobSize bounds the blobs read from history: larger ones are data
+// or build output, not configuration someone typed a key into.
+const maxHistoryBlobSize = 1 << 20
+
+func runSecrets(_ *cobra.Command, args []string) error {
+	startTime := time.Now()
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	projectRoot, err := resolveProjectRoot(path)
+	if err != nil {
+		return err
+	}
+	cfg, _, err := loadConfig(projectRoot)
+	if err != nil {
+		return err
+	}
+	rule, ok := rules.Get(secretsRuleName)
+	if !ok || !cfg.IsRuleEnabled(rule.Category(), rule.Name()) {
+		return fmt.Errorf("rule %q is disabled in the configuration of %s", secretsRuleName, projectRoot)
+	}
+	overrides, err := buildSeverityOverrides(cfg, []rules.Rule{rule})
+	if err != nil {
+		return err
+	}
+
+	contexts, walker, err := walkWithWalker(core.NewWalker(projectRoot, cfg).
+		WithGoParsing(false).
+		WithExtraFiles(rules.ExtraFiles([]rules.Rule{rule})))
+	if err != nil {
+		return err
+	}
+	var violations core.ViolationList
+	for _, ctx := range contexts {
+		violations = append(violations, runRule(ctx, rule, cfg, overrides)...)
+	}
+	stats := output.Stats{FilesAnalyzed: len(contexts), FilesSkipped: walker.Stats().SkippedFiles, RulesRun: 1}
+
+	if flagSecretsHistory {
+		found, scanned, err := scanHistory(projectRoot, cfg, rule, overrides)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, found...)
+		stats.FilesAnalyzed += scanned
+	}
+
+	stats.Duration = time.Since(startTime).Seconds()
+	if err := outputResults(cfg.Settings.Output, violations, stats); err != nil {
+		return fmt.Errorf("output error: %w", err)
+	}
+	if shouldFailAnalysis(violations) {
+		return errFindingsReported
+	}
+	return nil
+}
+
+// historyBlob is a file version from the git history: the blob, the first
+// commit that introduced it and its path there, relative to the project root.
+type historyBlob struct {
+	hash   string
+	commit string
+	path   string
+}
+
+// scanHistory runs the rule over the blobs of the history and returns its
+// findings, filed under <commit>:<path>, with the number of blobs scanned.
+func scanHistory(projectRoot string, cfg *core.Config, rule rules.Rule, overrides severityOverrides) (core.ViolationList, int, error) {
+	blobs, err := historyBlobs(projectRoot, cfg, rule)
+	if err != nil {
+		return nil, 0, err
+	}
+	var violations core.ViolationList
+	scanned := 0
+	err = readBlobs(projectRoot, blobs, func(blob historyBlob, content []byte) error {
+		if bytes.IndexByte(content, 0) >= 0 {
+			return nil
+		}
+		ctx, err := core.NewFileContextChecked(filepath.Join(projectRoot, blob.path), projectRoot, content, cfg)
+		if err != nil {
+			return err
+		}
+		scanned++
+		for _, violation := range runRule(ctx, rule, cfg, overrides) {
+			violation.File = shortCommit(blob.commit) + ":" + blob.path
+			violation.WithContext("commit", blob.commit)
+			violation.WithContext("blob", blob.hash)
+			violations = append(violations, violation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return violations, scanned, nil
+}
+
+// historyBlobs lists the blobs the commits of all refs introduced below the
+// project root, oldest first and once per blob hash: a secret kept through
+// ten commits or copied into ten files is one finding. Blobs still at HEAD
+// are left out, and so are the paths the rule does not read.
+func historyBlobs(projectRoot string, cfg *core.Config, rule rules.Rule) ([]historyBlob, error) {
+	// -z keeps paths with spaces or non-ASCII bytes unquoted.
+	log, err := gitOutput(projectRoot, "log", "--all", "--reverse", "--raw", "--no-abbrev", "--no-renames",
+		"--relative", "-z", "--format=commit %H")
+	if err != nil {
+		return nil, err
+	}
+	seen, err := headBlobs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	var blobs []historyBlob
+	commit := ""
+	// Records are "commit <hash>", then per file ":<old mode> <new mode>
+	// <old blob> <new blob> <status>" and the path, each NUL-terminated.
+	records := strings.Split(string(log), "\x00")
+	for i := 0; i < len(records); i++ {
+		record := strings.TrimLeft(records[i], "\n")
+		if hash, ok := strings.CutPrefix(record, "commit "); ok {
+			commit = hash
+			continue
+		}
+		fields := strings.Fields(record)
+		if !strings.HasPrefix(record, ":") || len(fields) < 5 || i+1 >= len(records) {
+			continue
+		}
+		i++
+		path := records[i]
+		mode, hash := fields[1], fields[3]
+		// Deletions carry no new blob; gitlinks point at submodule commits.
+		if strings.Trim(hash, "0") == "" || mode == "160000" || seen[hash] {
+			continue
+		}
+		if !rules.AnalyzesFile(rule, path) || cfg.ShouldExclude(path) || inSkippedDir(path, cfg) {
+			continue // a later copy at a path the rule reads is still scanned
+		}
+		seen[hash] = true
+		blobs = append(blobs, historyBlob{hash: hash, commit: commit, path: path})
+	}
+	return blobs, nil
+}
+
+// headBlobs returns the hashes of the blobs in the HEAD tree, which the working
+// tree scan covers. A repository without commits has none.
+func headBlobs(projectRoot string) (map[string]bool, error) {
+	blobs := make(map[string]bool)
+	if _, err := gitOutput(projectRoot, "rev-parse", "--verify", "--quiet", "HEAD^{commit}"); err != nil {
+		// Expected for an unborn HEAD: git log has already proved the repository.
+		return blobs, nil
+	}
+	head, err := gitOutput(projectRoot, "ls-tree", "-r", "-z", "--full-tree", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range strings.Split(string(head), "\x00") {
+		// <mode> <type> <hash>\t<path>
+		if fields := strings.Fields(entry); len(fields) >= 3 {
+			blobs[fields[2]] = true
+		}
+	}
+	return blobs, nil
+}
+
+// inSkippedDir reports whether a slash-separated path lies below a directory
+// the walker does not descend into.
+func inSkippedDir(path string, cfg *core.Config) bool {
+	dirs := strings.Split(path, "/")
+	for _, dir := range dirs[:len(dirs)-1] {
+		if slices.Contains(cfg.SkipDirs(), dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBlobs streams the contents of the blobs through one git cat-file
+// process, skipping those over maxHistoryBlobSize.
+func readBlobs(projectRoot string, blobs []historyBlob, visit func(historyBlob, []byte) error) error {
+	if len(blobs) == 0 {
+		return nil
+	}
+	var request strings.Builder
+	for _, blob := range blobs {
+		request.WriteString(blob.hash + "\n")
+	}
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Dir = projectRoot
+	cmd.Stdin = strings.NewReader(request.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("git cat-file in %q: %w", projectRoot, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("git cat-file in %q: %w", projectRoot, err)
+	}
+	readErr := readBatch(bufio.NewReader(stdout), blobs, visit)
+	// After a failure git may still be writing: drain it so that it exits.
+	_, drainErr := io.Copy(io.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git cat-file in %q: %w: %s", projectRoot, err, strings.TrimSpace(stderr.String()))
+	}
+	return errors.Join(readErr, drainErr)
+}
+
+// readBatch parses the `git cat-file --batch` answers, one per blob:
+// "<hash> <type> <size>\n<content>\n".
+func readBatch(r *bufio.Reader, blobs []historyBlob, visit func(historyBlob, []byte) error) error {
+	for _, blob := range blobs {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("read blob %s: %w", blob.hash, err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 || fields[0] != blob.hash {
+			return fmt.Errorf("read blob %s: unexpected header %q", blob.hash, strings.TrimSpace(header))
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("read blob %s: size %q: %w", blob.hash, fields[2], err)
+		}
+		if size > maxHistoryBlobSize {
+			if _, err := r.Discard(int(size) + 1); err != nil {
+				return fmt.Errorf("skip blob %s: %w", blob.hash, err)
+			}
+			continue
+		}
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(r, content); err != nil {
+			return fmt.Errorf("read blob %s: %w", blob.hash, err)
+		}
+		if err := visit(blob, content[:size]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitOutput runs git in dir and returns its standard output; the error
+// carries what git printed to standard error.
+func gitOutput(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git %s in %q: %w: %s", args[0], dir, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git %s in %q: %w", args[0], dir, err)
+	}
+	return out, nil
+}
+
+func shortCommit(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+// gitRepo creates a repository in a temporary directory; commit writes the
+// files (an empty content deletes one) and commits them.
+func gitRepo(t *testing.T) (string, func(message string, files map[string]string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	git("config", "user.email", "dev@example.com")
+	git("config", "user.name", "dev")
+	return root, func(message string, files map[string]string) string {
+		t.Helper()
+		for name, content := range files {
+			path := filepath.Join(root, name)
+			if content == "" {
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", message)
+		return git("rev-parse", "HEAD")
+	}
+}
+
+func TestScanHistoryFindsDeletedSecret(t *testing.T) {
+	// Assembled at runtime so the fixture does not trip glint's own scan.
+	key := strings.Join([]string{"AKIA", "IOSFODNN7", "HISTORY"}, "")
+	leaked := "package config\n\nconst awsKey = \"" + key + "\"\n"
+	root, commit := gitRepo(t)
+	first := commit("add config", map[string]string{
+		"config/aws.go":  leaked,
+		"config/copy.go": leaked,
+		"notes.txt":      leaked,
+	})
+	commit("move key to the environment", map[string]string{
+		"config/aws.go":  "package config\n",
+		"config/copy.go": "",
+		"notes.txt":      "",
+	})
+	commit("unrelated", map[string]string{"main.go": "package main\n"})
+
+	cfg := core.DefaultConfig()
+	rule, ok := rules.Get(secretsRuleName)
+	if !ok {
+		t.Fatalf("rule %q is not registered", secretsRuleName)
+	}
+	violations, scanned, err := scanHistory(root, cfg, rule, severityOverrides{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One blob for both copies; the .txt file is not analyzed and the blobs
+	// still at HEAD are the working tree scan's.
+	if scanned != 1 || len(violations) != 1 {
+		t.Fatalf("scanned %d blobs with %d findings, want 1 and 1: %v", scanned, len(violations), violations)
+	}
+	v := violations[0]
+	if v.File != first[:12]+":config/aws.go" || v.Line != 3 {
+		t.Errorf("finding at %s:%d, want %s:config/aws.go:3", v.File, v.Line, first[:12])
+	}
+	if v.Context["commit"] != first {
+		t.Errorf("commit context %v, want %s", v.Context["commit"], first)
+	}
+	if strings.Contains(v.Code, key) || !strings.Contains(v.Code, "[REDACTED]") {
+		t.Errorf("code must be masked, got %q", v.Code)
+	}
+}
+
+func TestHistoryBlobsWithinSubdirectory(t *testing.T) {
+	root, commit := gitRepo(t)
+	commit("add services", map[string]string{
+		"backend/app.go":  "package app\n\nvar a = 1\n",
+		"frontend/app.ts": "export const a = 1;\n",
+	})
+	commit("change both", map[string]string{
+		"backend/app.go":  "package app\n\nvar a = 2\n",
+		"frontend/app.ts": "export const a = 2;\n",
+	})
+
+	rule, ok := rules.Get(secretsRuleName)
+	if !ok {
+		t.Fatalf("rule %q is not registered", secretsRuleName)
+	}
+	blobs, err := historyBlobs(filepath.Join(root, "backend"), core.DefaultConfig(), rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 1 || blobs[0].path != "app.go" {
+		t.Fatalf("want the first backend/app.go only, relative to the project root, got %+v", blobs)
+	}
+}
+
+func TestHistoryBlobsQuotedPathsAndSkippedCopies(t *testing.T) {
+	root, commit := gitRepo(t)
+	commit("add notes", map[string]string{"notes.txt": "package config\n\nvar a = 1\n"})
+	commit("copy into code", map[string]string{
+		"config/my keys.go": "package config\n\nvar a = 1\n",
+		"config/größe.go":   "package config\n\nvar b = 2\n",
+	})
+	commit("clean up", map[string]string{
+		"notes.txt":         "",
+		"config/my keys.go": "",
+		"config/größe.go":   "",
+	})
+
+	rule, ok := rules.Get(secretsRuleName)
+	if !ok {
+		t.Fatalf("rule %q is not registered", secretsRuleName)
+	}
+	blobs, err := historyBlobs(root, core.DefaultConfig(), rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, blob := range blobs {
+		paths = append(paths, blob.path)
+	}
+	// The blob first seen as notes.txt is still scanned under its .go copy.
+	if strings.Join(paths, ",") != "config/größe.go,config/my keys.go" {
+		t.Fatalf("want both .go copies under their real names, got %q", paths)
+	}
+}
+
+func TestHistoryBlobsWithoutCommits(t *testing.T) {
+	root, _ := gitRepo(t)
+
+	rule, ok := rules.Get(secretsRuleName)
+	if !ok {
+		t.Fatalf("rule %q is not registered", secretsRuleName)
+	}
+	blobs, err := historyBlobs(root, core.DefaultConfig(), rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 0 {
+		t.Fatalf("want no blobs in a repository without commits, got %+v", blobs)
+	}
+}
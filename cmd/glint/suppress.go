@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/fix"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+// suppressProjectRoot silences the current findings of one root at their
+// source. The findings are exactly those 'glint check' reports — same rules,
+// exceptions and minimum severity — so that a check right after reports none
+// but the findings of rules that forbid suppression.
+func suppressProjectRoot(projectRoot string, cfg *core.Config, enabledRules []rules.Rule, engine *fix.Engine, dryRun bool) error {
+	selected := make([]rules.Rule, 0, len(enabledRules))
+	honors := make(map[string]bool, len(enabledRules))
+	for _, rule := range enabledRules {
+		if flagFixRule != "" && rule.Name() != flagFixRule {
+			continue
+		}
+		selected = append(selected, rule)
+		honors[rule.Name()] = rules.HonorsSuppression(rule)
+	}
+
+	contexts, _, project, err := prepareAnalysis(projectRoot, cfg, selected)
+	if err != nil {
+		return err
+	}
+	contextMap := make(map[string]*core.FileContext)
+	for _, ctx := range contexts {
+		contextMap[ctx.Path] = ctx
+		contextMap[ctx.RelPath] = ctx
+	}
+
+	rules.ResetState(selected)
+	violations, err := analyzeProject(contexts, selected, cfg, project)
+	if err != nil {
+		return err
+	}
+	minSeverity, err := cfg.GetMinSeverity()
+	if err != nil {
+		return err
+	}
+
+	var suppressible []*core.Violation
+	exempt := 0
+	for _, violation := range violations.BySeverity(minSeverity) {
+		if !honors[violation.Rule] {
+			exempt++
+			continue
+		}
+		suppressible = append(suppressible, violation)
+	}
+	if exempt > 0 {
+		fmt.Fprintf(os.Stdout, "%d finding(s) of rules that forbid suppression were left in place.\n", exempt)
+	}
+
+	fixes, skipped := fix.SuppressionFixes(suppressible, contextMap, flagReason)
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "Cannot suppress %s [%s]: %s\n", s.Violation.Location(), s.Violation.Rule, s.Reason)
+	}
+	if len(fixes) == 0 {
+		fmt.Fprintln(os.Stdout, "No findings to suppress.")
+		return nil
+	}
+	applyFixes(engine, fixes, dryRun)
+	return nil
+}
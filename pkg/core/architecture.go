@@ -0,0 +1,194 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ArchitecturePresetLayered is the Handler→Service→Repository scheme the
+// architecture rules apply when a config declares no layers of its own.
+const ArchitecturePresetLayered = "layered"
+
+// Layer roles select the content checks of layer-violation: SQL has no
+// business in a handler or a service, HTTP none in a repository.
+const (
+	LayerRoleHandler    = "handler"
+	LayerRoleService    = "service"
+	LayerRoleRepository = "repository"
+)
+
+// ArchitectureConfig declares the layers of a project and the dependencies
+// allowed between them, the modules that must not depend on each other in a
+// cycle, and the imports some packages must not make at all.
+type ArchitectureConfig struct {
+	// Preset names a built-in layer scheme. It is exclusive with Layers.
+	Preset           string                  `yaml:"preset,omitempty"`
+	Layers           []LayerConfig           `yaml:"layers,omitempty"`
+	Modules          []ModuleConfig          `yaml:"modules,omitempty"`
+	ForbiddenImports []ForbiddenImportConfig `yaml:"forbidden_imports,omitempty"`
+}
+
+// LayerConfig is one named layer of the architecture.
+type LayerConfig struct {
+	Name string `yaml:"name"`
+	// Packages are globs over package directories relative to the project
+	// root (internal/adapters/**) or over full import paths. A file belongs
+	// to the first layer with a matching glob.
+	Packages []string `yaml:"packages"`
+	// MayImport lists the layers this one may depend on. A layer may always
+	// import its own packages.
+	MayImport []string `yaml:"may_import,omitempty"`
+	// Role is one of the LayerRole* constants, or empty for a layer whose
+	// code layer-violation does not inspect.
+	Role string `yaml:"role,omitempty"`
+}
+
+// ModuleConfig is a group of packages that module-cycle treats as one node:
+// the packages of two modules may import each other in one direction only.
+type ModuleConfig struct {
+	Name string `yaml:"name"`
+	// Packages are globs as in LayerConfig.Packages.
+	Packages []string `yaml:"packages"`
+}
+
+// ForbiddenImportConfig forbids the packages matching Packages to depend on
+// the packages matching Imports, directly or through other project packages.
+type ForbiddenImportConfig struct {
+	// Packages are globs as in LayerConfig.Packages.
+	Packages []string `yaml:"packages"`
+	// Imports are globs over import paths: net/http, github.com/aws/**.
+	Imports []string `yaml:"imports"`
+	Reason  string   `yaml:"reason,omitempty"`
+}
+
+// architecturePresets maps preset names to their layers. The globs match
+// package directories as well as file paths, so that a user_repo.go outside
+// a repository package is still classified by its name.
+var architecturePresets = map[string][]LayerConfig{
+	ArchitecturePresetLayered: {
+		{
+			Name:      "handler",
+			Packages:  []string{"**/*handler*{,/**}", "**/routing{,/**}"},
+			MayImport: []string{"service", "repository"},
+			Role:      LayerRoleHandler,
+		},
+		{
+			Name:      "service",
+			Packages:  []string{"**/*service*{,/**}"},
+			MayImport: []string{"repository"},
+			Role:      LayerRoleService,
+		},
+		{
+			Name:     "repository",
+			Packages: []string{"**/{repo,repository,repositories,*_repo,*_repository}{,.go,/**}"},
+			Role:     LayerRoleRepository,
+		},
+	},
+}
+
+// ResolvedLayers returns the declared layers, or those of the preset — the
+// layered one when neither is set.
+func (a ArchitectureConfig) ResolvedLayers() []LayerConfig {
+	if len(a.Layers) > 0 {
+		return a.Layers
+	}
+	if a.Preset != "" {
+		return architecturePresets[a.Preset]
+	}
+	return architecturePresets[ArchitecturePresetLayered]
+}
+
+// mergeArchitecture overlays each part an override declares. The layers and
+// their preset are one description: halves of two make no graph.
+func mergeArchitecture(base, override ArchitectureConfig) ArchitectureConfig {
+	merged := base
+	if override.Preset != "" || len(override.Layers) > 0 {
+		merged.Preset = override.Preset
+		merged.Layers = override.Layers
+	}
+	if len(override.Modules) > 0 {
+		merged.Modules = override.Modules
+	}
+	if len(override.ForbiddenImports) > 0 {
+		merged.ForbiddenImports = override.ForbiddenImports
+	}
+	return merged
+}
+
+// validate reports unknown presets and roles, duplicate or unknown layer and
+// module names, and malformed globs.
+func (a ArchitectureConfig) validate() error {
+	if a.Preset != "" {
+		if len(a.Layers) > 0 {
+			return fmt.Errorf("architecture: preset %q and layers are mutually exclusive", a.Preset)
+		}
+		if _, ok := architecturePresets[a.Preset]; !ok {
+			return fmt.Errorf("architecture.preset: unknown preset %q", a.Preset)
+		}
+	}
+
+	names := make(map[string]bool, len(a.Layers))
+	for i, layer := range a.Layers {
+		if layer.Name == "" {
+			return fmt.Errorf("architecture.layers[%d]: name is required", i)
+		}
+		if names[layer.Name] {
+			return fmt.Errorf("architecture.layers[%d]: duplicate layer %q", i, layer.Name)
+		}
+		names[layer.Name] = true
+		if err := validateGlobs(fmt.Sprintf("architecture.layers[%d].packages", i), layer.Packages); err != nil {
+			return err
+		}
+		switch layer.Role {
+		case "", LayerRoleHandler, LayerRoleService, LayerRoleRepository:
+		default:
+			return fmt.Errorf("architecture.layers[%d].role: unknown role %q (want %s, %s or %s)",
+				i, layer.Role, LayerRoleHandler, LayerRoleService, LayerRoleRepository)
+		}
+	}
+	for i, layer := range a.Layers {
+		for _, target := range layer.MayImport {
+			if !names[target] {
+				return fmt.Errorf("architecture.layers[%d].may_import: unknown layer %q", i, target)
+			}
+		}
+	}
+
+	modules := make(map[string]bool, len(a.Modules))
+	for i, module := range a.Modules {
+		if module.Name == "" {
+			return fmt.Errorf("architecture.modules[%d]: name is required", i)
+		}
+		if modules[module.Name] {
+			return fmt.Errorf("architecture.modules[%d]: duplicate module %q", i, module.Name)
+		}
+		modules[module.Name] = true
+		if err := validateGlobs(fmt.Sprintf("architecture.modules[%d].packages", i), module.Packages); err != nil {
+			return err
+		}
+	}
+
+	for i, forbidden := range a.ForbiddenImports {
+		if err := validateGlobs(fmt.Sprintf("architecture.forbidden_imports[%d].packages", i), forbidden.Packages); err != nil {
+			return err
+		}
+		if err := validateGlobs(fmt.Sprintf("architecture.forbidden_imports[%d].imports", i), forbidden.Imports); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateGlobs requires at least one pattern and every pattern well-formed.
+func validateGlobs(key string, patterns []string) error {
+	if len(patterns) == 0 {
+		return fmt.Errorf("%s: at least one pattern is required", key)
+	}
+	for i, pattern := range patterns {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("%s[%d]: malformed glob pattern %q", key, i, pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigArchitecture(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".glint.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`version: 1
+architecture:
+  layers:
+    - name: domain
+      packages: ["internal/domain/**"]
+    - name: http
+      packages: ["internal/adapters/http/**"]
+      may_import: [domain]
+      role: handler
+`), 0o644))
+
+	cfg, err := LoadConfig(configPath)
+	require.NoError(t, err)
+
+	layers := cfg.Architecture.ResolvedLayers()
+	require.Len(t, layers, 2)
+	assert.Equal(t, []string{"domain"}, layers[1].MayImport)
+	assert.Equal(t, LayerRoleHandler, layers[1].Role)
+}
+
+func TestArchitectureDefaultsToLayeredPreset(t *testing.T) {
+	layers := DefaultConfig().Architecture.ResolvedLayers()
+
+	var names []string
+	for _, layer := range layers {
+		names = append(names, layer.Name)
+	}
+	assert.Equal(t, []string{"handler", "service", "repository"}, names)
+}
+
+func TestValidateRejectsInconsistentArchitecture(t *testing.T) {
+	tests := map[string]ArchitectureConfig{
+		"unknown preset":    {Preset: "onion"},
+		"preset and layers": {Preset: ArchitecturePresetLayered, Layers: []LayerConfig{{Name: "a", Packages: []string{"a"}}}},
+		"duplicate layer":   {Layers: []LayerConfig{{Name: "a", Packages: []string{"a"}}, {Name: "a", Packages: []string{"b"}}}},
+		"unknown target":    {Layers: []LayerConfig{{Name: "a", Packages: []string{"a"}, MayImport: []string{"b"}}}},
+		"no packages":       {Layers: []LayerConfig{{Name: "a"}}},
+		"malformed glob":    {Layers: []LayerConfig{{Name: "a", Packages: []string{"a/[x"}}}},
+		"unknown role":      {Layers: []LayerConfig{{Name: "a", Packages: []string{"a"}, Role: "controller"}}},
+	}
+	for name, arch := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Architecture = arch
+			assert.Error(t, cfg.Validate())
+		})
+	}
+}
+
+func TestMergeConfigsOverlaysArchitectureParts(t *testing.T) {
+	base := DefaultConfig()
+	base.Architecture = ArchitectureConfig{
+		Layers:  []LayerConfig{{Name: "a", Packages: []string{"a"}}},
+		Modules: []ModuleConfig{{Name: "m", Packages: []string{"m/**"}}},
+	}
+
+	kept := MergeConfigs(base, &Config{})
+	assert.Equal(t, base.Architecture, kept.Architecture)
+
+	forbidden := []ForbiddenImportConfig{{Packages: []string{"a"}, Imports: []string{"net/http"}}}
+	override := &Config{Architecture: ArchitectureConfig{Preset: ArchitecturePresetLayered, ForbiddenImports: forbidden}}
+	merged := MergeConfigs(base, override)
+	assert.Equal(t, ArchitecturePresetLayered, merged.Architecture.Preset)
+	assert.Empty(t, merged.Architecture.Layers, "the preset replaces the base layers as a whole")
+	assert.Equal(t, base.Architecture.Modules, merged.Architecture.Modules)
+	assert.Equal(t, forbidden, merged.Architecture.ForbiddenImports)
+}
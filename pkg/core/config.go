@@ -16,6 +16,8 @@ type Config struct {
 	Extends    string                    `yaml:"extends,omitempty"`
 	Settings   SettingsConfig            `yaml:"settings"`
 	Categories map[string]CategoryConfig `yaml:"categories"`
+	// Architecture declares the layers the architecture rules enforce.
+	Architecture ArchitectureConfig `yaml:"architecture,omitempty"`
 }
 
 // SettingsConfig contains global settings
@@ -194,7 +196,8 @@ func loadConfigChain(path string, visiting map[string]bool) (*Config, error) {
 }
 
 // Validate reports configuration values that glint would otherwise have to
-// guess about: unparseable severities anywhere in the file.
+// guess about: unparseable severities anywhere in the file, and an
+// architecture that does not describe a graph.
 func (c *Config) Validate() error {
 	// Version 0 means the key is absent, which older configs rely on.
 	if c.Version != 0 && c.Version != SupportedConfigVersion {
@@ -211,6 +214,9 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("settings.exclude[%d]: malformed glob pattern %q", i, pattern)
 		}
 	}
+	if err := c.Architecture.validate(); err != nil {
+		return err
+	}
 	for name, cat := range c.Categories {
 		if cat.SeverityOverride != "" {
 			if _, err := ParseSeverity(cat.SeverityOverride); err != nil {
@@ -307,10 +313,11 @@ func LoadConfigWithDefaults(projectRoot string) (*Config, error) {
 // MergeConfigs merges two configs, with override taking precedence
 func MergeConfigs(base, override *Config) *Config {
 	result := &Config{
-		Version:    override.Version,
-		Extends:    override.Extends,
-		Settings:   base.Settings,
-		Categories: make(map[string]CategoryConfig),
+		Version:      override.Version,
+		Extends:      override.Extends,
+		Settings:     base.Settings,
+		Categories:   make(map[string]CategoryConfig),
+		Architecture: mergeArchitecture(base.Architecture, override.Architecture),
 	}
 
 	// Merge settings
@@ -25,6 +25,9 @@ type FileContext struct {
 	GoPackage string
 	GoImports []string
 
+	// TypeScript/JavaScript-specific (nil until parsed)
+	TSAST *TSFile
+
 	// Configuration
 	Config *Config
 }
@@ -109,21 +112,66 @@ func (ctx *FileContext) IsTestFile() bool {
 //	//nolint:<rule-name>
 //	// <rule-name>: safe — <reason>
 //
-// The marker must appear inside a comment ("//" or "/*"); string literals
-// containing the same text do not suppress. Rule names match exactly:
-// "nolint:my-rule" does not suppress rule "my-rule-extended" and vice versa.
+// The marker must appear inside a comment ("//" or "/*", "--" in SQL files);
+// string literals containing the same text do not suppress. Rule names match
+// exactly: "nolint:my-rule" does not suppress rule "my-rule-extended" and vice
+// versa.
 func (ctx *FileContext) IsSuppressed(line int, ruleName string) bool {
 	for checkLine := line - 1; checkLine <= line; checkLine++ {
 		if checkLine < 1 || checkLine > len(ctx.Lines) {
 			continue
 		}
-		if commentHasSuppressionMarker(ctx.Lines[checkLine-1], ruleName) {
+		if commentHasMarker(ctx.CommentPart(ctx.Lines[checkLine-1]), ruleName) {
 			return true
 		}
 	}
 	return false
 }
 
+// IsSQLFile returns true if this is an SQL file
+func (ctx *FileContext) IsSQLFile() bool {
+	return strings.HasSuffix(ctx.Path, ".sql")
+}
+
+// CommentPart returns the comment part of one of the file's lines in the
+// file's own comment syntax: SQL comments start with "--", everything else
+// analyzed uses "//" and "/*".
+func (ctx *FileContext) CommentPart(line string) string {
+	if ctx.IsSQLFile() {
+		return sqlCommentPart(line)
+	}
+	return commentPart(line)
+}
+
+// sqlCommentPart returns the substring of an SQL line starting at its comment
+// marker ("--" or "/*"), skipping markers inside quoted literals and
+// identifiers.
+func sqlCommentPart(line string) string {
+	inQuote := byte(0)
+	for i := 0; i < len(line)-1; i++ {
+		c := line[i]
+		if inQuote != 0 {
+			if c == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			inQuote = c
+		case '-':
+			if line[i+1] == '-' {
+				return line[i:]
+			}
+		case '/':
+			if line[i+1] == '*' {
+				return line[i:]
+			}
+		}
+	}
+	return ""
+}
+
 // LineSuppresses reports whether the line's comment part carries a
 // suppression marker for the given rule (nolint:<rule> / <rule>: safe).
 // Single canonical implementation — rules must delegate here instead of
@@ -135,7 +183,12 @@ func LineSuppresses(line, ruleName string) bool {
 // commentHasSuppressionMarker checks the comment part of a line for
 // suppression markers of the given rule.
 func commentHasSuppressionMarker(line, ruleName string) bool {
-	comment := commentPart(line)
+	return commentHasMarker(commentPart(line), ruleName)
+}
+
+// commentHasMarker checks an already extracted comment for suppression
+// markers of the given rule.
+func commentHasMarker(comment, ruleName string) bool {
 	if comment == "" {
 		return false
 	}
@@ -285,6 +338,30 @@ func (ctx *FileContext) SetGoAST(fset *token.FileSet, file *ast.File) {
 	}
 }
 
+// HasTSAST returns true if the TypeScript syntax tree is available
+func (ctx *FileContext) HasTSAST() bool {
+	return ctx.TSAST != nil
+}
+
+// SetTSAST sets the TypeScript syntax tree for this file
+func (ctx *FileContext) SetTSAST(file *TSFile) {
+	ctx.TSAST = file
+}
+
+// TypeScriptAST returns the syntax tree of a TypeScript or JavaScript file,
+// or nil for any other file. The walker parses these files up front; a
+// context built without it is parsed through the shared parser's cache. The
+// result is not stored on the context, which rules read concurrently.
+func (ctx *FileContext) TypeScriptAST() *TSFile {
+	if ctx.TSAST != nil {
+		return ctx.TSAST
+	}
+	if !ctx.IsTypeScriptFile() && !ctx.IsJavaScriptFile() {
+		return nil
+	}
+	return SharedParser().ParseTypeScriptFile(ctx.Path, ctx.Content)
+}
+
 // PositionFor returns the position for a given ast.Node
 func (ctx *FileContext) PositionFor(node ast.Node) token.Position {
 	if node == nil || ctx.GoFileSet == nil {
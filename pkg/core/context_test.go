@@ -159,6 +159,22 @@ func TestFileContextHasGoAST(t *testing.T) {
 	assert.False(t, ctx.HasGoAST())
 }
 
+func TestFileContextTypeScriptAST(t *testing.T) {
+	ctx := NewFileContext("/project/src/app.ts", "/project", []byte("export const x = 1\n"), nil)
+	assert.False(t, ctx.HasTSAST())
+
+	// Without the walker's tree the file is parsed on demand, not stored.
+	file := ctx.TypeScriptAST()
+	require.NotNil(t, file)
+	assert.Equal(t, TSExport, file.Program.Children[0].Kind)
+	assert.False(t, ctx.HasTSAST())
+
+	ctx.SetTSAST(file)
+	assert.Same(t, file, ctx.TypeScriptAST())
+
+	assert.Nil(t, NewFileContext("/project/main.go", "/project", []byte("package main\n"), nil).TypeScriptAST())
+}
+
 func TestFileContextExtension(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -303,3 +319,16 @@ func TestFileContextIsSuppressed(t *testing.T) {
 		})
 	}
 }
+
+func TestFileContextIsSuppressedSQL(t *testing.T) {
+	ctx := &FileContext{Path: "schema.sql", Lines: []string{
+		`SELECT '-- nolint:sql-select-star' FROM t;`,
+		`SELECT * FROM users; -- nolint:sql-select-star -- report query`,
+		`/* sql-select-star: safe */`,
+		`SELECT * FROM t;`,
+	}}
+
+	assert.False(t, ctx.IsSuppressed(1, "sql-select-star"), "marker inside a string literal")
+	assert.True(t, ctx.IsSuppressed(2, "sql-select-star"))
+	assert.True(t, ctx.IsSuppressed(4, "sql-select-star"), "block comment on the line above")
+}
@@ -43,11 +43,20 @@ type GoProjectContext struct {
 	Program     *ssa.Program
 	Packages    []*GoPackageContext
 	Files       []*FileContext
+	// AllFiles are all analyzed files, Go or not, in path order, for project
+	// rules that compare Go with another language.
+	AllFiles []*FileContext
 	// SkippedPackages lists packages excluded from typed analysis; always empty
 	// unless GoProjectOptions.TolerateBrokenPackages is set.
 	SkippedPackages []SkippedPackage
+	// Config is the configuration the files were read with; project rules
+	// that need more than their own settings (the architecture) read it here.
+	Config *Config
 
 	filesByPath map[string]*FileContext
+
+	sharedMu sync.Mutex
+	shared   map[any]any
 }
 
 // GoPackageContext connects a loaded typed package and its optional SSA package
@@ -58,6 +67,24 @@ type GoPackageContext struct {
 	Files   []*FileContext
 }
 
+// Shared returns the value of type T stored in the project under key,
+// building it on first use: work several project rules derive from the same
+// program, like the call graph the taint rules walk, is done once per project.
+// Keys are values of an unexported type of the package that builds them.
+func Shared[T any](ctx *GoProjectContext, key any, build func() T) T {
+	ctx.sharedMu.Lock()
+	defer ctx.sharedMu.Unlock()
+	if value, ok := ctx.shared[key].(T); ok {
+		return value
+	}
+	if ctx.shared == nil {
+		ctx.shared = make(map[any]any)
+	}
+	value := build()
+	ctx.shared[key] = value
+	return value
+}
+
 // File resolves an absolute or project-relative path to its existing file context.
 func (ctx *GoProjectContext) File(path string) (*FileContext, error) {
 	if ctx == nil {
@@ -164,7 +191,9 @@ func loadGoProjectWithOptions(root string, contexts []*FileContext, opts GoProje
 		ProjectRoot:     absRoot,
 		FileSet:         fset,
 		Files:           append([]*FileContext(nil), goFiles...),
+		AllFiles:        sortedFileContexts(contexts),
 		SkippedPackages: skipped,
+		Config:          projectConfig(contexts),
 		filesByPath:     filesByPath,
 	}
 	compiled, err := attachLoadedGoPackages(project, loaded, loader.parsed)
@@ -186,6 +215,17 @@ func loadGoProjectWithOptions(root string, contexts []*FileContext, opts GoProje
 	return project, nil
 }
 
+// projectConfig returns the config of the analyzed files, which the walker
+// gives every file alike, or the defaults.
+func projectConfig(contexts []*FileContext) *Config {
+	for _, fileCtx := range contexts {
+		if fileCtx != nil && fileCtx.Config != nil {
+			return fileCtx.Config
+		}
+	}
+	return DefaultConfig()
+}
+
 // goModuleDirs resolves the modules that own the analyzed files. With tolerate
 // set, a file outside any module is reported instead of aborting the load: it
 // still gets a syntax tree, only type information is unavailable for it.
@@ -285,6 +325,12 @@ func prepareGoProjectFiles(root string, contexts []*FileContext) (map[string][]b
 	return overlay, filesByPath, goFiles, nil
 }
 
+func sortedFileContexts(contexts []*FileContext) []*FileContext {
+	sorted := append([]*FileContext(nil), contexts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	return sorted
+}
+
 func (loader *goProjectLoader) parseFile(callbackFset *token.FileSet, filename string, src []byte) (*ast.File, error) {
 	path, err := absolutePath(loader.root, filename)
 	if err != nil {
@@ -5,6 +5,8 @@ import (
 	"go/parser"
 	"go/token"
 	"hash/fnv"
+	"path/filepath"
+	"strings"
 	"sync"
 )
 
@@ -13,6 +15,10 @@ type Parser struct {
 	// Cache for parsed Go files
 	cache   map[goFileCacheKey]*parsedGoFile
 	cacheMu sync.RWMutex
+
+	// Cache for parsed TypeScript, TSX and JavaScript files
+	tsCache   map[goFileCacheKey]*TSFile
+	tsCacheMu sync.RWMutex
 }
 
 // goFileCacheKey identifies a parsed file by path and content, so that parsing
@@ -54,7 +60,8 @@ func SharedParser() *Parser {
 // NewParser creates a new parser
 func NewParser() *Parser {
 	return &Parser{
-		cache: make(map[goFileCacheKey]*parsedGoFile),
+		cache:   make(map[goFileCacheKey]*parsedGoFile),
+		tsCache: make(map[goFileCacheKey]*TSFile),
 	}
 }
 
@@ -86,6 +93,34 @@ func (p *Parser) ParseGoFile(path string, content []byte) (*token.FileSet, *ast.
 	return fset, file, err
 }
 
+// ParseTypeScriptFile parses a TypeScript, TSX or JavaScript file and returns
+// its syntax tree. JSX is recognized everywhere but in .ts, .mts and .cts
+// files, where <T>x is a type assertion. The tree is never nil: syntax errors
+// are recorded in TSFile.Errors.
+func (p *Parser) ParseTypeScriptFile(path string, content []byte) *TSFile {
+	key := newGoFileCacheKey(path, content)
+
+	p.tsCacheMu.RLock()
+	if cached, ok := p.tsCache[key]; ok {
+		p.tsCacheMu.RUnlock()
+		return cached
+	}
+	p.tsCacheMu.RUnlock()
+
+	jsx := true
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ts", ".mts", ".cts":
+		jsx = false
+	}
+	file := ParseTypeScript(content, jsx)
+
+	p.tsCacheMu.Lock()
+	p.tsCache[key] = file
+	p.tsCacheMu.Unlock()
+
+	return file
+}
+
 // ExtractFullFunctionName extracts the full function name (package.function)
 func ExtractFullFunctionName(call *ast.CallExpr) string {
 	switch fn := call.Fun.(type) {
@@ -120,3 +120,19 @@ func main() {
 	assert.Contains(t, calls, "localFunc")
 	assert.Contains(t, calls, "fmt.Println")
 }
+
+func TestParserParseTypeScriptFile(t *testing.T) {
+	p := NewParser()
+	content := []byte("const n = <number>value;\n")
+
+	// A .ts file reads <number> as a type assertion, a .tsx file as an element.
+	file := p.ParseTypeScriptFile("a.ts", content)
+	assert.Empty(t, file.Errors)
+	assert.Same(t, file, p.ParseTypeScriptFile("a.ts", content))
+	assert.NotEmpty(t, p.ParseTypeScriptFile("a.tsx", content).Errors)
+
+	// Same path, different content must not return the stale tree
+	changed := p.ParseTypeScriptFile("a.ts", []byte("let m = 1\n"))
+	assert.NotSame(t, file, changed)
+	assert.Equal(t, "let", changed.Program.Children[0].Text)
+}
@@ -0,0 +1,280 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TSNodeKind classifies a TSNode. The comment on each kind gives the layout
+// of its Children; a nil child is an optional part the source leaves out.
+type TSNodeKind int
+
+const (
+	// TSProgram holds the statements of a file.
+	TSProgram TSNodeKind = iota
+	// TSError is a token the parser could not place; Text is the token.
+	TSError
+
+	// TSBlock holds statements.
+	TSBlock
+	// TSEmpty is a lone semicolon.
+	TSEmpty
+	// TSVarDecl holds TSDeclarator children; Text is const, let or var.
+	TSVarDecl
+	// TSDeclarator is [binding, init?].
+	TSDeclarator
+	// TSFunction is a function declaration or expression, an arrow function
+	// or a method: TSParam children, then the body — a TSBlock, an expression
+	// for an arrow with an expression body, or nil for a signature without a
+	// body. Text is the name, when there is one.
+	TSFunction
+	// TSParam is [binding, default?]; a rest parameter's binding is a
+	// TSSpread. Text is the name of an identifier binding.
+	TSParam
+	// TSClass holds the members: TSFunction methods, TSProperty fields and
+	// TSBlock static blocks. Text is the name, when there is one.
+	TSClass
+	// TSIf is [test, consequent, alternate?].
+	TSIf
+	// TSFor is [init?, test?, update?, body].
+	TSFor
+	// TSForIn is [left, right, body]; Text is "in" or "of".
+	TSForIn
+	// TSWhile is [test, body].
+	TSWhile
+	// TSDoWhile is [body, test].
+	TSDoWhile
+	// TSReturn is [argument?].
+	TSReturn
+	// TSThrow is [argument].
+	TSThrow
+	// TSTry is [block, handler?, finalizer?]; the handler is a TSCatch.
+	TSTry
+	// TSCatch is [param?, body].
+	TSCatch
+	// TSSwitch is [discriminant, TSCase...].
+	TSSwitch
+	// TSCase is [test?, statements...]; the test is nil for default.
+	TSCase
+	// TSBreak and TSContinue have no children; Text is the label, if any.
+	TSBreak
+	TSContinue
+	// TSLabeled is [body]; Text is the label.
+	TSLabeled
+	// TSExprStmt is [expression].
+	TSExprStmt
+	// TSImport has no children; Text is the quoted module specifier.
+	TSImport
+	// TSExport is [declaration or expression?]; Text is "default" for a
+	// default export.
+	TSExport
+	// TSTypeDecl is an interface, type alias, enum or ambient declaration,
+	// none of which runs; a namespace keeps its body as [TSBlock]. Text is the
+	// declared name.
+	TSTypeDecl
+
+	// TSIdentifier is an identifier, this or super; Text is the name.
+	TSIdentifier
+	// TSLiteral is a number, string, regular expression, true, false or
+	// null; Text is the literal as written.
+	TSLiteral
+	// TSTemplateLiteral holds the substitution expressions; Text is the
+	// literal as written.
+	TSTemplateLiteral
+	// TSTaggedTemplate is [tag, TSTemplateLiteral].
+	TSTaggedTemplate
+	// TSArray holds the elements; a hole is nil.
+	TSArray
+	// TSObject holds TSProperty, TSFunction (methods) and TSSpread children.
+	TSObject
+	// TSProperty is [key, value?] in an object or a class. The key is a
+	// TSIdentifier, a TSLiteral or a computed expression; a shorthand
+	// property has no value. Text is the key's name unless it is computed.
+	TSProperty
+	// TSSpread is [argument].
+	TSSpread
+	// TSMember is [object, TSIdentifier property]; Text is "." or "?.".
+	TSMember
+	// TSIndex is [object, index]; Text is "[" or "?.[".
+	TSIndex
+	// TSCall is [callee, arguments...]; Text is "(" or "?.(".
+	TSCall
+	// TSNew is [callee, arguments...].
+	TSNew
+	// TSUnary is [operand]; Text is the prefix operator: !, -, typeof,
+	// await, ++...
+	TSUnary
+	// TSUpdate is [operand] with a postfix ++ or -- in Text.
+	TSUpdate
+	// TSBinary is [left, right]; Text is the operator, logical ones included.
+	TSBinary
+	// TSAssign is [target, value]; Text is the operator: =, +=, ??=...
+	TSAssign
+	// TSConditional is [test, consequent, alternate].
+	TSConditional
+	// TSSequence holds comma-separated expressions.
+	TSSequence
+	// TSParen is [expression].
+	TSParen
+	// TSTypeAssertion is [expression] under a type-only operator; Text is
+	// "as", "satisfies", "!" or "<>" for an angle-bracket assertion.
+	TSTypeAssertion
+
+	// TSJSXElement holds TSJSXAttribute and TSSpread attributes, then the
+	// children: TSJSXText, TSJSXExpression and TSJSXElement. Text is the tag
+	// name, "" for a fragment.
+	TSJSXElement
+	// TSJSXAttribute is [value?]: a TSLiteral string, a TSJSXExpression or a
+	// TSJSXElement. Text is the attribute name.
+	TSJSXAttribute
+	// TSJSXExpression is [expression?]; a container holding only a comment
+	// has none.
+	TSJSXExpression
+	// TSJSXText is text between tags; Text is the raw text.
+	TSJSXText
+)
+
+var tsNodeKindNames = [...]string{
+	TSProgram: "Program", TSError: "Error", TSBlock: "Block", TSEmpty: "Empty",
+	TSVarDecl: "VarDecl", TSDeclarator: "Declarator", TSFunction: "Function", TSParam: "Param",
+	TSClass: "Class", TSIf: "If", TSFor: "For", TSForIn: "ForIn", TSWhile: "While",
+	TSDoWhile: "DoWhile", TSReturn: "Return", TSThrow: "Throw", TSTry: "Try", TSCatch: "Catch",
+	TSSwitch: "Switch", TSCase: "Case", TSBreak: "Break", TSContinue: "Continue",
+	TSLabeled: "Labeled", TSExprStmt: "ExprStmt", TSImport: "Import", TSExport: "Export",
+	TSTypeDecl: "TypeDecl", TSIdentifier: "Identifier", TSLiteral: "Literal",
+	TSTemplateLiteral: "TemplateLiteral", TSTaggedTemplate: "TaggedTemplate", TSArray: "Array",
+	TSObject: "Object", TSProperty: "Property", TSSpread: "Spread", TSMember: "Member",
+	TSIndex: "Index", TSCall: "Call", TSNew: "New", TSUnary: "Unary", TSUpdate: "Update",
+	TSBinary: "Binary", TSAssign: "Assign", TSConditional: "Conditional", TSSequence: "Sequence",
+	TSParen: "Paren", TSTypeAssertion: "TypeAssertion", TSJSXElement: "JSXElement",
+	TSJSXAttribute: "JSXAttribute", TSJSXExpression: "JSXExpression", TSJSXText: "JSXText",
+}
+
+func (k TSNodeKind) String() string {
+	if k >= 0 && int(k) < len(tsNodeKindNames) {
+		return tsNodeKindNames[k]
+	}
+	return "TSNodeKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// TSPosition is a place in TypeScript source. Line and Column are 1-based;
+// Column counts bytes.
+type TSPosition struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// TSNode is a node of a TypeScript, TSX or JavaScript syntax tree. Types are
+// parsed past, not kept: the tree describes what runs.
+type TSNode struct {
+	Kind TSNodeKind
+	Text string
+	// Start is the node's first byte; End is just past its last byte.
+	Start    TSPosition
+	End      TSPosition
+	Children []*TSNode
+}
+
+// Child returns the i-th child, or nil when the node has fewer or the child
+// is an absent optional part.
+func (n *TSNode) Child(i int) *TSNode {
+	if n == nil || i < 0 || i >= len(n.Children) {
+		return nil
+	}
+	return n.Children[i]
+}
+
+// Walk calls fn for the node and its descendants in source order, with the
+// ancestors of each, outermost first. Returning false skips a node's
+// children.
+func (n *TSNode) Walk(fn func(node *TSNode, ancestors []*TSNode) bool) {
+	var ancestors []*TSNode
+	var walk func(*TSNode)
+	walk = func(node *TSNode) {
+		if !fn(node, ancestors) {
+			return
+		}
+		ancestors = append(ancestors, node)
+		for _, child := range node.Children {
+			if child != nil {
+				walk(child)
+			}
+		}
+		ancestors = ancestors[:len(ancestors)-1]
+	}
+	if n != nil {
+		walk(n)
+	}
+}
+
+// Path returns the dotted name an identifier or a chain of property accesses
+// spells — "process.env.API_URL", with "?." read as "." — and "" for any
+// other expression. Parentheses and type assertions are looked through.
+func (n *TSNode) Path() string {
+	n = n.Unwrap()
+	if n == nil {
+		return ""
+	}
+	switch n.Kind {
+	case TSIdentifier:
+		return n.Text
+	case TSMember:
+		object := n.Children[0].Path()
+		if object == "" {
+			return ""
+		}
+		return object + "." + n.Children[1].Text
+	}
+	return ""
+}
+
+// Name returns the last segment of Path: "error" for logger.error.
+func (n *TSNode) Name() string {
+	path := n.Path()
+	return path[strings.LastIndexByte(path, '.')+1:]
+}
+
+// Unwrap returns the expression inside parentheses and type assertions.
+func (n *TSNode) Unwrap() *TSNode {
+	for n != nil && (n.Kind == TSParen || n.Kind == TSTypeAssertion) {
+		n = n.Child(0)
+	}
+	return n
+}
+
+// Attribute returns the attribute of a JSX element with the given name.
+func (n *TSNode) Attribute(name string) *TSNode {
+	if n == nil || n.Kind != TSJSXElement {
+		return nil
+	}
+	for _, child := range n.Children {
+		if child.Kind == TSJSXAttribute && child.Text == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// TSSyntaxError is a place where the source did not parse; the parser
+// skipped ahead and went on.
+type TSSyntaxError struct {
+	Pos     TSPosition
+	Message string
+}
+
+// TSFile is a parsed TypeScript, TSX or JavaScript file.
+type TSFile struct {
+	Program  *TSNode
+	Comments []TSToken
+	Errors   []TSSyntaxError
+	src      []byte
+}
+
+// Text returns the source of a node.
+func (f *TSFile) Text(n *TSNode) string {
+	if f == nil || n == nil {
+		return ""
+	}
+	return string(f.src[n.Start.Offset:n.End.Offset])
+}
@@ -0,0 +1,369 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TSTokenKind classifies a TypeScript/JavaScript token.
+type TSTokenKind int
+
+const (
+	// TSIdent is an identifier or a keyword; TSKeywords tells them apart.
+	TSIdent TSTokenKind = iota
+	TSNumber
+	TSString
+	// TSTemplate is a whole template literal, substitutions included.
+	TSTemplate
+	TSRegExp
+	TSPunct
+	TSComment
+)
+
+// TSToken is one token of TypeScript/JavaScript source. Line and Column are
+// 1-based; Column counts bytes. Offset and End delimit the token's bytes.
+type TSToken struct {
+	Kind   TSTokenKind
+	Text   string
+	Line   int
+	Column int
+	Offset int
+	End    int
+	// NewlineBefore reports a line break between the previous token and this
+	// one — what automatic semicolon insertion looks at.
+	NewlineBefore bool
+}
+
+// TSKeywords are the reserved words and the contextual keywords the
+// rules look at.
+var TSKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"let": true, "static": true, "yield": true, "await": true, "async": true, "of": true,
+	"as": true, "interface": true, "type": true, "undefined": true,
+}
+
+// tsPunctuators lists the multi-character punctuators longest first. ">>"
+// and ">>>" are missing on purpose: they close nested type arguments far more
+// often than they shift, and a parser joins two ">" where it needs a shift.
+var tsPunctuators = []string{
+	"...", "===", "!==", "**=", "<<=", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<",
+}
+
+// tsRegExpAfterKeyword are the keywords after which "/" starts a regular
+// expression rather than dividing.
+var tsRegExpAfterKeyword = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true,
+	"of": true, "new": true, "delete": true, "void": true, "throw": true, "instanceof": true,
+	"yield": true, "await": true,
+}
+
+// LexTypeScript splits TypeScript, TSX or JavaScript source into tokens,
+// comments included. It never fails: an unterminated literal ends at the end
+// of its line (strings) or of the source (templates, comments).
+func LexTypeScript(src []byte) []TSToken {
+	lx := &tsLexer{src: src, line: 1, lineStart: 0}
+	var tokens []TSToken
+	for {
+		tok, ok := lx.next(tokens)
+		if !ok {
+			return tokens
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+type tsLexer struct {
+	src       []byte
+	pos       int
+	line      int
+	lineStart int
+}
+
+// next scans the token after the whitespace at the current position. The
+// tokens so far decide whether a slash starts a regular expression.
+func (lx *tsLexer) next(previous []TSToken) (TSToken, bool) {
+	newline := lx.skipSpace()
+	if lx.pos >= len(lx.src) {
+		return TSToken{}, false
+	}
+	start, line, column := lx.pos, lx.line, lx.pos-lx.lineStart+1
+	kind := lx.scan(previous)
+	return TSToken{
+		Kind:          kind,
+		Text:          string(lx.src[start:lx.pos]),
+		Line:          line,
+		Column:        column,
+		Offset:        start,
+		End:           lx.pos,
+		NewlineBefore: newline,
+	}, true
+}
+
+func (lx *tsLexer) skipSpace() bool {
+	newline := false
+	for lx.pos < len(lx.src) {
+		r, size := utf8.DecodeRune(lx.src[lx.pos:])
+		if r == '\n' {
+			newline = true
+			lx.advanceLine(lx.pos + 1)
+			continue
+		}
+		if !unicode.IsSpace(r) && r != '\uFEFF' {
+			return newline
+		}
+		lx.pos += size
+	}
+	return newline
+}
+
+func (lx *tsLexer) advanceLine(next int) {
+	lx.pos = next
+	lx.line++
+	lx.lineStart = next
+}
+
+// advanceTo moves to end, counting the line breaks on the way.
+func (lx *tsLexer) advanceTo(end int) {
+	for lx.pos < end {
+		if lx.src[lx.pos] == '\n' {
+			lx.advanceLine(lx.pos + 1)
+			continue
+		}
+		lx.pos++
+	}
+}
+
+func (lx *tsLexer) peek(offset int) byte {
+	if lx.pos+offset < len(lx.src) {
+		return lx.src[lx.pos+offset]
+	}
+	return 0
+}
+
+func (lx *tsLexer) scan(previous []TSToken) TSTokenKind {
+	c := lx.src[lx.pos]
+	switch {
+	case c == '/' && lx.peek(1) == '/':
+		end := lx.pos
+		for end < len(lx.src) && lx.src[end] != '\n' {
+			end++
+		}
+		lx.pos = end
+		return TSComment
+	case c == '/' && lx.peek(1) == '*':
+		end := strings.Index(string(lx.src[lx.pos+2:]), "*/")
+		if end < 0 {
+			lx.advanceTo(len(lx.src))
+		} else {
+			lx.advanceTo(lx.pos + 2 + end + 2)
+		}
+		return TSComment
+	case c == '"' || c == '\'':
+		lx.scanString(c)
+		return TSString
+	case c == '`':
+		lx.scanTemplate()
+		return TSTemplate
+	case isDigit(c) || (c == '.' && isDigit(lx.peek(1))):
+		lx.scanNumber()
+		return TSNumber
+	case c == '/' && regExpAllowed(previous):
+		lx.scanRegExp()
+		return TSRegExp
+	}
+	if r, size := utf8.DecodeRune(lx.src[lx.pos:]); isIdentStart(r) {
+		lx.pos += size
+		for lx.pos < len(lx.src) {
+			r, size = utf8.DecodeRune(lx.src[lx.pos:])
+			if !isIdentStart(r) && !unicode.IsDigit(r) {
+				break
+			}
+			lx.pos += size
+		}
+		return TSIdent
+	}
+	lx.scanPunct()
+	return TSPunct
+}
+
+// scanString scans a quoted string; a raw line break ends it unterminated.
+func (lx *tsLexer) scanString(quote byte) {
+	lx.pos++
+	for lx.pos < len(lx.src) {
+		switch c := lx.src[lx.pos]; c {
+		case '\\':
+			if lx.peek(1) == '\n' {
+				lx.advanceLine(lx.pos + 2)
+				continue
+			}
+			lx.pos += 2
+		case quote:
+			lx.pos++
+			return
+		case '\n':
+			return
+		default:
+			lx.pos++
+		}
+	}
+	lx.pos = min(lx.pos, len(lx.src))
+}
+
+// scanTemplate scans a template literal, lexing each substitution as code
+// until its closing brace, so strings and templates nested there are skipped
+// whole.
+func (lx *tsLexer) scanTemplate() {
+	lx.pos++
+	for lx.pos < len(lx.src) {
+		switch c := lx.src[lx.pos]; {
+		case c == '\\':
+			lx.advanceTo(min(lx.pos+2, len(lx.src)))
+		case c == '`':
+			lx.pos++
+			return
+		case c == '$' && lx.peek(1) == '{':
+			lx.pos += 2
+			lx.skipSubstitution()
+		default:
+			lx.advanceTo(lx.pos + 1)
+		}
+	}
+}
+
+func (lx *tsLexer) skipSubstitution() {
+	depth := 1
+	var inner []TSToken
+	for {
+		tok, ok := lx.next(inner)
+		if !ok {
+			return
+		}
+		if tok.Kind == TSPunct {
+			switch tok.Text {
+			case "{":
+				depth++
+			case "}":
+				depth--
+				if depth == 0 {
+					return
+				}
+			}
+		}
+		inner = append(inner, tok)
+	}
+}
+
+// scanNumber scans a decimal, hex, octal or binary number with separators,
+// a fraction, an exponent and a BigInt suffix where they apply.
+func (lx *tsLexer) scanNumber() {
+	if lx.src[lx.pos] == '0' && isLetter(lx.peek(1)) && strings.ContainsRune("xXoObB", rune(lx.peek(1))) {
+		lx.pos += 2
+		for lx.pos < len(lx.src) && (isDigit(lx.src[lx.pos]) || isLetter(lx.src[lx.pos]) || lx.src[lx.pos] == '_') {
+			lx.pos++
+		}
+		return
+	}
+	for lx.pos < len(lx.src) && (isDigit(lx.src[lx.pos]) || lx.src[lx.pos] == '_' || (lx.src[lx.pos] == '.' && isDigit(lx.peek(1)))) {
+		lx.pos++
+	}
+	if c := lx.peek(0); c == 'e' || c == 'E' {
+		lx.pos++
+		if sign := lx.peek(0); sign == '+' || sign == '-' {
+			lx.pos++
+		}
+		for lx.pos < len(lx.src) && (isDigit(lx.src[lx.pos]) || lx.src[lx.pos] == '_') {
+			lx.pos++
+		}
+	}
+	if lx.peek(0) == 'n' {
+		lx.pos++
+	}
+}
+
+func (lx *tsLexer) scanRegExp() {
+	lx.pos++
+	inClass := false
+	for lx.pos < len(lx.src) {
+		switch c := lx.src[lx.pos]; c {
+		case '\\':
+			lx.pos += 2
+			continue
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '\n':
+			return
+		case '/':
+			if !inClass {
+				lx.pos++
+				for lx.pos < len(lx.src) && isLetter(lx.src[lx.pos]) {
+					lx.pos++ // flags
+				}
+				return
+			}
+		}
+		lx.pos++
+	}
+	lx.pos = min(lx.pos, len(lx.src))
+}
+
+func (lx *tsLexer) scanPunct() {
+	rest := lx.src[lx.pos:]
+	for _, punct := range tsPunctuators {
+		if strings.HasPrefix(string(rest[:min(len(rest), len(punct))]), punct) {
+			// "?." followed by a digit is a conditional and a number: a?.5:1
+			if punct == "?." && len(rest) > 2 && isDigit(rest[2]) {
+				continue
+			}
+			lx.pos += len(punct)
+			return
+		}
+	}
+	_, size := utf8.DecodeRune(rest)
+	lx.pos += size
+}
+
+// regExpAllowed reports whether a slash after the given tokens starts a
+// regular expression: where an operand is expected, not after one.
+func regExpAllowed(previous []TSToken) bool {
+	for i := len(previous) - 1; i >= 0; i-- {
+		tok := previous[i]
+		switch tok.Kind {
+		case TSComment:
+			continue
+		case TSNumber, TSString, TSTemplate, TSRegExp:
+			return false
+		case TSIdent:
+			return tsRegExpAfterKeyword[tok.Text]
+		default:
+			// After "<" the slash closes a JSX element: </div>.
+			switch tok.Text {
+			case ")", "]", "}", "++", "--", "<":
+				return false
+			}
+			return true
+		}
+	}
+	return true
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
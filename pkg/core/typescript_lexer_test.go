@@ -0,0 +1,84 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lexTexts(src string) []string {
+	var texts []string
+	for _, tok := range LexTypeScript([]byte(src)) {
+		texts = append(texts, tok.Text)
+	}
+	return texts
+}
+
+func TestLexTypeScriptTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "declaration with strict equality",
+			src:  "const ok = a !== b && c?.d === 'x';",
+			want: []string{"const", "ok", "=", "a", "!==", "b", "&&", "c", "?.", "d", "===", "'x'", ";"},
+		},
+		{
+			name: "braces inside strings and comments are text",
+			src:  "f(\"{\", '}') // }\n/* { */ g()",
+			want: []string{"f", "(", "\"{\"", ",", "'}'", ")", "// }", "/* { */", "g", "(", ")"},
+		},
+		{
+			name: "template with nested template and braces",
+			src:  "x = `a ${fn({ k: `b ${c}` })} }` + 1",
+			want: []string{"x", "=", "`a ${fn({ k: `b ${c}` })} }`", "+", "1"},
+		},
+		{
+			name: "regular expression where an operand is expected, division after one",
+			src:  "r = /[/]+\\/x/gi; q = a / b / c",
+			want: []string{"r", "=", "/[/]+\\/x/gi", ";", "q", "=", "a", "/", "b", "/", "c"},
+		},
+		{
+			name: "numbers",
+			src:  "1_000_000 0xFF 1.5e-3 10n .5 items[0].name",
+			want: []string{"1_000_000", "0xFF", "1.5e-3", "10n", ".5", "items", "[", "0", "]", ".", "name"},
+		},
+		{
+			name: "nested type arguments close one by one",
+			src:  "let m: Map<string, Array<number>>= x",
+			want: []string{"let", "m", ":", "Map", "<", "string", ",", "Array", "<", "number", ">", ">=", "x"},
+		},
+		{
+			name: "JSX closing tag is no regular expression",
+			src:  "<div>hi</div>",
+			want: []string{"<", "div", ">", "hi", "<", "/", "div", ">"},
+		},
+		{
+			name: "unterminated string ends at the line",
+			src:  "a = 'open\nb = 1",
+			want: []string{"a", "=", "'open", "b", "=", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, lexTexts(tt.src))
+		})
+	}
+}
+
+func TestLexTypeScriptPositions(t *testing.T) {
+	tokens := LexTypeScript([]byte("const a = `x\ny`\n  return a"))
+	require.Len(t, tokens, 6)
+
+	assert.Equal(t, TSTemplate, tokens[3].Kind)
+	assert.Equal(t, 1, tokens[3].Line)
+	assert.Equal(t, TSIdent, tokens[4].Kind)
+	assert.Equal(t, 3, tokens[4].Line, "the line break inside the template is counted")
+	assert.Equal(t, 3, tokens[4].Column)
+	assert.True(t, tokens[4].NewlineBefore)
+	assert.False(t, tokens[5].NewlineBefore)
+	assert.Equal(t, "return", string([]byte("const a = `x\ny`\n  return a")[tokens[4].Offset:tokens[4].End]))
+}
@@ -0,0 +1,1839 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// tsEOF is the kind of the token past the end of the source.
+const tsEOF TSTokenKind = -1
+
+// tsLookaheadLimit bounds how far the parser looks ahead to tell an arrow
+// function's parameters from a parenthesized expression.
+const tsLookaheadLimit = 4096
+
+var tsAssignOperators = map[string]bool{
+	"=": true, "+=": true, "-=": true, "*=": true, "/=": true, "%=": true, "**=": true,
+	"<<=": true, "&=": true, "|=": true, "^=": true, "&&=": true, "||=": true, "??=": true,
+}
+
+var tsBinaryPrecedence = map[string]int{
+	"??": 1, "||": 2, "&&": 3, "|": 4, "^": 5, "&": 6,
+	"==": 7, "!=": 7, "===": 7, "!==": 7,
+	"<": 8, ">": 8, "<=": 8, ">=": 8, "instanceof": 8, "in": 8, "as": 8, "satisfies": 8,
+	"<<": 9, ">>": 9, ">>>": 9,
+	"+": 10, "-": 10, "*": 11, "/": 11, "%": 11, "**": 12,
+}
+
+var tsPrefixOperators = map[string]bool{
+	"!": true, "~": true, "+": true, "-": true, "++": true, "--": true,
+	"typeof": true, "void": true, "delete": true, "await": true,
+}
+
+// tsMemberModifiers precede a class member's name.
+var tsMemberModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "static": true, "readonly": true,
+	"abstract": true, "override": true, "declare": true, "async": true, "accessor": true,
+	"get": true, "set": true,
+}
+
+// tsTypePrefixes are the type operators written before their operand.
+var tsTypePrefixes = map[string]bool{
+	"keyof": true, "typeof": true, "readonly": true, "unique": true, "infer": true,
+	"asserts": true, "new": true, "abstract": true,
+}
+
+// ParseTypeScript parses TypeScript or JavaScript source. jsx enables JSX,
+// as in .tsx, .jsx and .js files; without it "<T>x" is a type assertion. It
+// never fails: what does not parse is recorded in Errors and skipped.
+func ParseTypeScript(src []byte, jsx bool) *TSFile {
+	state := &tsParseState{src: src, lineStarts: []int{0}, committed: make(map[int]bool)}
+	for i, c := range src {
+		if c == '\n' {
+			state.lineStarts = append(state.lineStarts, i+1)
+		}
+	}
+	p := newTSParser(state, jsx, 0)
+	program := &TSNode{Kind: TSProgram, Start: state.position(0)}
+	program.Children = p.statements(true)
+	program.End = state.position(len(src))
+
+	// Template substitutions are parsed apart, so their comments come late.
+	sort.Slice(state.comments, func(i, j int) bool { return state.comments[i].Offset < state.comments[j].Offset })
+	return &TSFile{Program: program, Comments: state.comments, Errors: state.errors, src: src}
+}
+
+// tsParseState is what the parsers of a file and of its template
+// substitutions share.
+type tsParseState struct {
+	src        []byte
+	lineStarts []int
+	comments   []TSToken
+	committed  map[int]bool // offsets of the comments kept so far
+	errors     []TSSyntaxError
+}
+
+func (s *tsParseState) position(offset int) TSPosition {
+	line := sort.Search(len(s.lineStarts), func(i int) bool { return s.lineStarts[i] > offset })
+	return TSPosition{Offset: offset, Line: line, Column: offset - s.lineStarts[line-1] + 1}
+}
+
+// tsLexed is a token with the comments before it. The comments are kept
+// once the token is parsed: a token lexed ahead may be dropped when JSX text
+// turns out to follow, and so must its comments.
+type tsLexed struct {
+	tok      TSToken
+	comments []TSToken
+}
+
+type tsParser struct {
+	s   *tsParseState
+	lx  *tsLexer
+	jsx bool
+	// tok is the current token; ahead holds the tokens lexed past it.
+	tok   TSToken
+	ahead []tsLexed
+	// lexed is the last token lexed, which decides whether a slash starts a
+	// regular expression.
+	lexed []TSToken
+	// prevEnd is the offset just past the last consumed token.
+	prevEnd  int
+	consumed int
+	// noIn is set in the head of a for loop, where "in" is no operator.
+	noIn bool
+}
+
+func newTSParser(s *tsParseState, jsx bool, offset int) *tsParser {
+	p := &tsParser{s: s, lx: &tsLexer{src: s.src}, jsx: jsx, prevEnd: offset}
+	p.seek(offset)
+	p.setCurrent(p.lex())
+	return p
+}
+
+// seek moves the lexer to offset and drops what was lexed ahead.
+func (p *tsParser) seek(offset int) {
+	pos := p.s.position(offset)
+	p.lx.pos, p.lx.line, p.lx.lineStart = offset, pos.Line, offset-pos.Column+1
+	p.ahead = nil
+	p.lexed = nil
+}
+
+func (p *tsParser) lex() tsLexed {
+	var comments []TSToken
+	for {
+		tok, ok := p.lx.next(p.lexed)
+		if !ok {
+			end := len(p.s.src)
+			pos := p.s.position(end)
+			return tsLexed{tok: TSToken{Kind: tsEOF, Line: pos.Line, Column: pos.Column, Offset: end, End: end}, comments: comments}
+		}
+		if tok.Kind == TSComment {
+			comments = append(comments, tok)
+			continue
+		}
+		p.lexed = append(p.lexed[:0], tok)
+		return tsLexed{tok: tok, comments: comments}
+	}
+}
+
+func (p *tsParser) setCurrent(l tsLexed) {
+	for _, comment := range l.comments {
+		if !p.s.committed[comment.Offset] {
+			p.s.committed[comment.Offset] = true
+			p.s.comments = append(p.s.comments, comment)
+		}
+	}
+	p.tok = l.tok
+}
+
+// next consumes the current token.
+func (p *tsParser) next() {
+	if p.tok.Kind == tsEOF {
+		return
+	}
+	p.prevEnd = p.tok.End
+	p.consumed++
+	if len(p.ahead) > 0 {
+		l := p.ahead[0]
+		p.ahead = p.ahead[1:]
+		p.setCurrent(l)
+		return
+	}
+	p.setCurrent(p.lex())
+}
+
+// peekAt returns the token i places ahead; 0 is the current one.
+func (p *tsParser) peekAt(i int) TSToken {
+	if i == 0 {
+		return p.tok
+	}
+	for len(p.ahead) < i {
+		if n := len(p.ahead); (n > 0 && p.ahead[n-1].tok.Kind == tsEOF) || (n == 0 && p.tok.Kind == tsEOF) {
+			return TSToken{Kind: tsEOF, Offset: len(p.s.src), End: len(p.s.src)}
+		}
+		p.ahead = append(p.ahead, p.lex())
+	}
+	return p.ahead[i-1].tok
+}
+
+func (p *tsParser) peek() TSToken {
+	return p.peekAt(1)
+}
+
+func tokIs(tok TSToken, text string) bool {
+	return (tok.Kind == TSPunct || tok.Kind == TSIdent) && tok.Text == text
+}
+
+// adjacent reports whether b follows a with nothing between them, as the
+// pieces of ">>=" do.
+func adjacent(a, b TSToken) bool {
+	return b.Kind == TSPunct && a.End == b.Offset
+}
+
+func (p *tsParser) at(text string) bool {
+	return tokIs(p.tok, text)
+}
+
+func (p *tsParser) atEOF() bool {
+	return p.tok.Kind == tsEOF
+}
+
+// atCloser reports whether the current token ends an enclosing construct,
+// which an out-of-place expression leaves to it.
+func (p *tsParser) atCloser() bool {
+	if p.atEOF() {
+		return true
+	}
+	if p.tok.Kind != TSPunct {
+		return false
+	}
+	switch p.tok.Text {
+	case ")", "]", "}", ";", ",", ":":
+		return true
+	}
+	return false
+}
+
+func (p *tsParser) eat(text string) bool {
+	if p.at(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *tsParser) expect(text string) {
+	if !p.eat(text) {
+		p.errorf("expected %q, found %q", text, p.tok.Text)
+	}
+}
+
+func (p *tsParser) errorf(format string, args ...any) {
+	p.s.errors = append(p.s.errors, TSSyntaxError{Pos: p.start(), Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *tsParser) start() TSPosition {
+	return p.s.position(p.tok.Offset)
+}
+
+// node builds a node that ends with the last consumed token.
+func (p *tsParser) node(kind TSNodeKind, start TSPosition, text string, children ...*TSNode) *TSNode {
+	end := start
+	if p.prevEnd > start.Offset {
+		end = p.s.position(p.prevEnd)
+	}
+	return &TSNode{Kind: kind, Text: text, Start: start, End: end, Children: children}
+}
+
+// unexpected records the current token as out of place and returns it as an
+// error node. A closer stays for the construct it closes.
+func (p *tsParser) unexpected() *TSNode {
+	start, text := p.start(), p.tok.Text
+	p.errorf("unexpected %q", text)
+	if !p.atCloser() {
+		p.next()
+	}
+	return p.node(TSError, start, text)
+}
+
+// skip drops the current token, whatever it is, to make progress.
+func (p *tsParser) skip() *TSNode {
+	start, text := p.start(), p.tok.Text
+	p.errorf("unexpected %q", text)
+	p.next()
+	return p.node(TSError, start, text)
+}
+
+// allowIn lifts the for-head ban on "in" until the returned func restores it.
+func (p *tsParser) allowIn() func() {
+	saved := p.noIn
+	p.noIn = false
+	return func() { p.noIn = saved }
+}
+
+// list parses comma-separated items after an opening bracket through the
+// closing one. A closer of another kind ends a list left open.
+func (p *tsParser) list(closing string, item func() *TSNode) []*TSNode {
+	var items []*TSNode
+	for !p.at(closing) && !p.atEOF() {
+		if p.tok.Kind == TSPunct && strings.Contains(");]}", p.tok.Text) {
+			break
+		}
+		before := p.consumed
+		items = append(items, item())
+		if p.eat(",") || p.at(closing) {
+			continue
+		}
+		if p.consumed == before && !p.atCloser() {
+			items = append(items, p.skip())
+		} else if p.consumed == before && p.at(":") {
+			p.skip()
+		}
+	}
+	p.expect(closing)
+	return items
+}
+
+// statements parses statements up to a closing brace, which the caller
+// consumes, or the end of the source. At the top level a stray closing
+// brace is skipped.
+func (p *tsParser) statements(top bool) []*TSNode {
+	var list []*TSNode
+	for !p.atEOF() {
+		if p.at("}") {
+			if !top {
+				return list
+			}
+			list = append(list, p.skip())
+			continue
+		}
+		before := p.consumed
+		stmt := p.statement()
+		if p.consumed == before {
+			stmt = p.skip()
+		}
+		list = append(list, stmt)
+	}
+	return list
+}
+
+// endStatement consumes the semicolon that ends a statement, if written.
+func (p *tsParser) endStatement(n *TSNode) *TSNode {
+	if p.eat(";") {
+		n.End = p.s.position(p.prevEnd)
+	}
+	return n
+}
+
+func (p *tsParser) statement() *TSNode {
+	start := p.start()
+	tok := p.tok
+	if tok.Kind == TSPunct {
+		switch tok.Text {
+		case "{":
+			return p.block()
+		case ";":
+			p.next()
+			return p.node(TSEmpty, start, "")
+		case "@":
+			p.decorators()
+			return p.statement()
+		}
+	}
+	if tok.Kind != TSIdent {
+		return p.expressionStatement()
+	}
+	if n := p.declaration(start); n != nil {
+		return n
+	}
+	if n := p.controlFlow(start); n != nil {
+		return n
+	}
+	if n := p.moduleStatement(); n != nil {
+		return n
+	}
+	if tokIs(p.peek(), ":") && !TSKeywords[tok.Text] {
+		p.next()
+		p.next()
+		return p.node(TSLabeled, start, tok.Text, p.statement())
+	}
+	return p.expressionStatement()
+}
+
+// declaration parses a variable, function, class or type declaration from its
+// keyword, or returns nil without consuming anything if the current token
+// does not start one.
+func (p *tsParser) declaration(start TSPosition) *TSNode {
+	next := p.peek()
+	switch p.tok.Text {
+	case "var":
+		return p.endStatement(p.varDecl())
+	case "const":
+		if tokIs(next, "enum") {
+			return p.typeDecl()
+		}
+		return p.endStatement(p.varDecl())
+	case "let":
+		if startsBinding(next) {
+			return p.endStatement(p.varDecl())
+		}
+	case "function":
+		return p.endStatement(p.function(start))
+	case "async":
+		if tokIs(next, "function") && !next.NewlineBefore {
+			p.next()
+			return p.endStatement(p.function(start))
+		}
+	case "class":
+		return p.class(start)
+	case "abstract":
+		if tokIs(next, "class") {
+			p.next()
+			return p.class(start)
+		}
+	case "interface", "enum":
+		if next.Kind == TSIdent {
+			return p.typeDecl()
+		}
+	case "type", "declare", "namespace", "module":
+		if (next.Kind == TSIdent || next.Kind == TSString) && !next.NewlineBefore {
+			return p.typeDecl()
+		}
+	}
+	return nil
+}
+
+// controlFlow parses a statement that starts with a control flow keyword, or
+// returns nil without consuming anything.
+func (p *tsParser) controlFlow(start TSPosition) *TSNode {
+	keyword := p.tok.Text
+	switch keyword {
+	case "if":
+		return p.ifStatement()
+	case "for":
+		return p.forStatement()
+	case "while":
+		p.next()
+		test := p.parenthesized()
+		return p.node(TSWhile, start, "", test, p.statement())
+	case "do":
+		p.next()
+		body := p.statement()
+		p.expect("while")
+		test := p.parenthesized()
+		return p.endStatement(p.node(TSDoWhile, start, "", body, test))
+	case "return":
+		p.next()
+		var arg *TSNode
+		if !p.tok.NewlineBefore && !p.at(";") && !p.at("}") && !p.atEOF() {
+			arg = p.expression()
+		}
+		return p.endStatement(p.node(TSReturn, start, "", arg))
+	case "throw":
+		p.next()
+		return p.endStatement(p.node(TSThrow, start, "", p.expression()))
+	case "try":
+		return p.tryStatement()
+	case "switch":
+		return p.switchStatement()
+	case "break", "continue":
+		p.next()
+		label := ""
+		if p.tok.Kind == TSIdent && !p.tok.NewlineBefore && !TSKeywords[p.tok.Text] {
+			label = p.tok.Text
+			p.next()
+		}
+		kind := TSBreak
+		if keyword == "continue" {
+			kind = TSContinue
+		}
+		return p.endStatement(p.node(kind, start, label))
+	}
+	return nil
+}
+
+// moduleStatement parses an import or export declaration, or returns nil
+// without consuming anything: import( and import. start expressions.
+func (p *tsParser) moduleStatement() *TSNode {
+	switch p.tok.Text {
+	case "import":
+		if next := p.peek(); !tokIs(next, "(") && !tokIs(next, ".") {
+			return p.importDecl()
+		}
+	case "export":
+		return p.exportDecl()
+	}
+	return nil
+}
+
+func (p *tsParser) expressionStatement() *TSNode {
+	start := p.start()
+	expr := p.expression()
+	return p.endStatement(p.node(TSExprStmt, start, "", expr))
+}
+
+func (p *tsParser) block() *TSNode {
+	start := p.start()
+	defer p.allowIn()()
+	p.expect("{")
+	body := p.statements(false)
+	p.expect("}")
+	return p.node(TSBlock, start, "", body...)
+}
+
+func (p *tsParser) parenthesized() *TSNode {
+	defer p.allowIn()()
+	p.expect("(")
+	expr := p.expression()
+	p.expect(")")
+	return expr
+}
+
+func startsBinding(tok TSToken) bool {
+	switch tok.Kind {
+	case TSIdent:
+		return tok.Text != "in" && tok.Text != "of" && tok.Text != "instanceof"
+	case TSPunct:
+		return tok.Text == "[" || tok.Text == "{"
+	}
+	return false
+}
+
+func (p *tsParser) varDecl() *TSNode {
+	start, kind := p.start(), p.tok.Text
+	p.next()
+	var decls []*TSNode
+	for {
+		declStart := p.start()
+		binding := p.bindingTarget()
+		p.eat("!")
+		if p.eat(":") {
+			p.skipType()
+		}
+		var init *TSNode
+		if p.eat("=") {
+			init = p.assignment()
+		}
+		decls = append(decls, p.node(TSDeclarator, declStart, "", binding, init))
+		if !p.eat(",") {
+			return p.node(TSVarDecl, start, kind, decls...)
+		}
+	}
+}
+
+func (p *tsParser) bindingTarget() *TSNode {
+	switch {
+	case p.at("["):
+		return p.arrayLiteral()
+	case p.at("{"):
+		return p.objectLiteral()
+	case p.tok.Kind == TSIdent:
+		start, name := p.start(), p.tok.Text
+		p.next()
+		return p.node(TSIdentifier, start, name)
+	}
+	return p.unexpected()
+}
+
+func (p *tsParser) ifStatement() *TSNode {
+	start := p.start()
+	p.next()
+	test := p.parenthesized()
+	consequent := p.statement()
+	var alternate *TSNode
+	if p.eat("else") {
+		alternate = p.statement()
+	}
+	return p.node(TSIf, start, "", test, consequent, alternate)
+}
+
+func (p *tsParser) forStatement() *TSNode {
+	start := p.start()
+	p.next()
+	p.eat("await")
+	p.expect("(")
+	var init *TSNode
+	if !p.at(";") {
+		p.noIn = true
+		if p.at("var") || p.at("const") || (p.at("let") && startsBinding(p.peek())) {
+			init = p.varDecl()
+		} else {
+			init = p.expression()
+		}
+		p.noIn = false
+		if p.at("of") || p.at("in") {
+			kind := p.tok.Text
+			p.next()
+			right := p.expression()
+			p.expect(")")
+			return p.node(TSForIn, start, kind, init, right, p.statement())
+		}
+	}
+	p.expect(";")
+	var test, update *TSNode
+	if !p.at(";") {
+		test = p.expression()
+	}
+	p.expect(";")
+	if !p.at(")") {
+		update = p.expression()
+	}
+	p.expect(")")
+	return p.node(TSFor, start, "", init, test, update, p.statement())
+}
+
+func (p *tsParser) tryStatement() *TSNode {
+	start := p.start()
+	p.next()
+	block := p.block()
+	var handler, finalizer *TSNode
+	if p.at("catch") {
+		catchStart := p.start()
+		p.next()
+		var param *TSNode
+		if p.eat("(") {
+			param = p.bindingTarget()
+			if p.eat(":") {
+				p.skipType()
+			}
+			p.expect(")")
+		}
+		handler = p.node(TSCatch, catchStart, "", param, p.block())
+	}
+	if p.eat("finally") {
+		finalizer = p.block()
+	}
+	return p.node(TSTry, start, "", block, handler, finalizer)
+}
+
+func (p *tsParser) switchStatement() *TSNode {
+	start := p.start()
+	p.next()
+	discriminant := p.parenthesized()
+	p.expect("{")
+	cases := []*TSNode{discriminant}
+	for !p.at("}") && !p.atEOF() {
+		caseStart := p.start()
+		var test *TSNode
+		switch {
+		case p.eat("case"):
+			test = p.expression()
+		case p.eat("default"):
+		default:
+			cases = append(cases, p.skip())
+			continue
+		}
+		p.expect(":")
+		body := []*TSNode{test}
+		for !p.at("case") && !p.at("default") && !p.at("}") && !p.atEOF() {
+			before := p.consumed
+			stmt := p.statement()
+			if p.consumed == before {
+				stmt = p.skip()
+			}
+			body = append(body, stmt)
+		}
+		cases = append(cases, p.node(TSCase, caseStart, "", body...))
+	}
+	p.expect("}")
+	return p.node(TSSwitch, start, "", cases...)
+}
+
+// importDecl parses an import declaration; what it imports does not run.
+func (p *tsParser) importDecl() *TSNode {
+	start := p.start()
+	p.next()
+	for !p.atEOF() && !p.at(";") {
+		switch {
+		case p.tok.Kind == TSString:
+			spec := p.tok.Text
+			p.next()
+			if (p.at("assert") || p.at("with")) && !p.tok.NewlineBefore && tokIs(p.peek(), "{") {
+				p.next()
+				p.skipBalanced()
+			}
+			return p.endStatement(p.node(TSImport, start, spec))
+		case p.at("{"):
+			p.skipBalanced()
+		case p.at("="):
+			// import fs = require('fs')
+			p.next()
+			p.assignment()
+			return p.endStatement(p.node(TSImport, start, ""))
+		default:
+			p.next()
+		}
+	}
+	return p.endStatement(p.node(TSImport, start, ""))
+}
+
+func (p *tsParser) exportDecl() *TSNode {
+	start := p.start()
+	p.next()
+	switch {
+	case p.eat("default"):
+		next := p.peek()
+		if p.at("function") || p.at("class") || p.at("interface") || p.at("@") ||
+			(p.at("abstract") && tokIs(next, "class")) ||
+			(p.at("async") && tokIs(next, "function") && !next.NewlineBefore) {
+			return p.node(TSExport, start, "default", p.statement())
+		}
+		expr := p.assignment()
+		return p.endStatement(p.node(TSExport, start, "default", expr))
+	case p.at("{"), p.at("*"), p.at("type") && tokIs(p.peek(), "{"):
+		p.eat("type")
+		if p.at("{") {
+			p.skipBalanced()
+		} else {
+			p.next()
+			if p.eat("as") {
+				p.next()
+			}
+		}
+		if p.eat("from") && p.tok.Kind == TSString {
+			p.next()
+		}
+		return p.endStatement(p.node(TSExport, start, ""))
+	case p.at("="):
+		p.next()
+		return p.endStatement(p.node(TSExport, start, "", p.expression()))
+	case p.at("as"):
+		// export as namespace Lib
+		p.next()
+		p.next()
+		p.next()
+		return p.endStatement(p.node(TSExport, start, ""))
+	}
+	return p.node(TSExport, start, "", p.statement())
+}
+
+// typeDecl parses a declaration that only describes types, from its
+// keyword.
+func (p *tsParser) typeDecl() *TSNode {
+	start, keyword := p.start(), p.tok.Text
+	p.next()
+	if keyword == "const" {
+		keyword = "enum"
+		p.next()
+	}
+	if keyword == "declare" {
+		if p.eat("global") {
+			return p.node(TSTypeDecl, start, "global", p.block())
+		}
+		inner := p.statement()
+		return p.node(TSTypeDecl, start, inner.Text)
+	}
+	name := p.tok.Text
+	p.next()
+	switch keyword {
+	case "interface":
+		p.skipTypeParams()
+		if p.eat("extends") {
+			for !p.at("{") && !p.atEOF() && !p.at(";") {
+				if p.at("<") {
+					p.skipAngles()
+					continue
+				}
+				p.next()
+			}
+		}
+		if p.at("{") {
+			p.skipBalanced()
+		}
+	case "type":
+		p.skipTypeParams()
+		p.expect("=")
+		p.skipType()
+		return p.endStatement(p.node(TSTypeDecl, start, name))
+	case "enum":
+		if p.at("{") {
+			p.skipBalanced()
+		}
+	case "namespace", "module":
+		var dotted strings.Builder
+		dotted.WriteString(name)
+		for p.at(".") && p.peek().Kind == TSIdent {
+			p.next()
+			dotted.WriteByte('.')
+			dotted.WriteString(p.tok.Text)
+			p.next()
+		}
+		name = dotted.String()
+		if p.at("{") {
+			return p.node(TSTypeDecl, start, name, p.block())
+		}
+		return p.endStatement(p.node(TSTypeDecl, start, name))
+	}
+	return p.node(TSTypeDecl, start, name)
+}
+
+// function parses a function declaration or expression from its keyword.
+func (p *tsParser) function(start TSPosition) *TSNode {
+	p.expect("function")
+	p.eat("*")
+	name := ""
+	if p.tok.Kind == TSIdent {
+		name = p.tok.Text
+		p.next()
+	}
+	return p.functionRest(start, name)
+}
+
+// functionRest parses what follows a function's name: type parameters,
+// parameters, return type and body.
+func (p *tsParser) functionRest(start TSPosition, name string) *TSNode {
+	p.skipTypeParams()
+	children := p.params()
+	if p.eat(":") {
+		p.skipType()
+	}
+	var body *TSNode
+	if p.at("{") {
+		body = p.block()
+	}
+	return p.node(TSFunction, start, name, append(children, body)...)
+}
+
+func (p *tsParser) params() []*TSNode {
+	if !p.at("(") {
+		p.errorf("expected parameters, found %q", p.tok.Text)
+		return nil
+	}
+	p.next()
+	return p.list(")", p.param)
+}
+
+var tsParamModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "readonly": true, "override": true,
+}
+
+func (p *tsParser) param() *TSNode {
+	start := p.start()
+	p.decorators()
+	for p.tok.Kind == TSIdent && tsParamModifiers[p.tok.Text] && startsBinding(p.peek()) {
+		p.next()
+	}
+	var binding *TSNode
+	if p.at("...") {
+		spreadStart := p.start()
+		p.next()
+		binding = p.node(TSSpread, spreadStart, "", p.bindingTarget())
+	} else {
+		binding = p.bindingTarget()
+	}
+	p.eat("?")
+	if p.eat(":") {
+		p.skipType()
+	}
+	var def *TSNode
+	if p.eat("=") {
+		def = p.assignment()
+	}
+	name := ""
+	if binding.Kind == TSIdentifier {
+		name = binding.Text
+	}
+	return p.node(TSParam, start, name, binding, def)
+}
+
+// decorators parses past decorators: @Component({...}).
+func (p *tsParser) decorators() {
+	for p.at("@") {
+		p.next()
+		start := p.start()
+		p.callTail(start, p.primary(), true)
+	}
+}
+
+// class parses a class declaration or expression from its keyword. The
+// heritage clauses are parsed past.
+func (p *tsParser) class(start TSPosition) *TSNode {
+	p.expect("class")
+	name := ""
+	if p.tok.Kind == TSIdent && !p.at("extends") && !p.at("implements") {
+		name = p.tok.Text
+		p.next()
+	}
+	p.skipTypeParams()
+	if p.eat("extends") {
+		heritageStart := p.start()
+		p.callTail(heritageStart, p.primary(), true)
+		p.skipTypeParams()
+	}
+	if p.eat("implements") {
+		for !p.at("{") && !p.atEOF() && !p.at(";") {
+			p.next()
+		}
+	}
+	if !p.eat("{") {
+		p.errorf("expected class body, found %q", p.tok.Text)
+		return p.node(TSClass, start, name)
+	}
+	var members []*TSNode
+	for !p.at("}") && !p.atEOF() {
+		before := p.consumed
+		member := p.classMember()
+		if p.consumed == before {
+			member = p.skip()
+		}
+		if member != nil {
+			members = append(members, member)
+		}
+	}
+	p.expect("}")
+	return p.node(TSClass, start, name, members...)
+}
+
+func (p *tsParser) classMember() *TSNode {
+	start := p.start()
+	if p.eat(";") {
+		return nil
+	}
+	p.decorators()
+	for p.tok.Kind == TSIdent && tsMemberModifiers[p.tok.Text] && startsPropertyName(p.peek()) {
+		p.next()
+	}
+	if p.at("static") && tokIs(p.peek(), "{") {
+		p.next()
+		return p.block()
+	}
+	p.eat("*")
+	if p.at("[") && p.peek().Kind == TSIdent && tokIs(p.peekAt(2), ":") {
+		// An index signature: [key: string]: T.
+		p.skipBalanced()
+		if p.eat(":") {
+			p.skipType()
+		}
+		p.eat(";")
+		return nil
+	}
+	key, name := p.propertyName()
+	p.eat("?")
+	p.eat("!")
+	if p.at("(") || p.at("<") {
+		return p.endStatement(p.functionRest(start, name))
+	}
+	if p.eat(":") {
+		p.skipType()
+	}
+	var value *TSNode
+	if p.eat("=") {
+		value = p.assignment()
+	}
+	return p.endStatement(p.node(TSProperty, start, name, key, value))
+}
+
+func startsPropertyName(tok TSToken) bool {
+	switch tok.Kind {
+	case TSIdent, TSString, TSNumber:
+		return true
+	case TSPunct:
+		return tok.Text == "[" || tok.Text == "#" || tok.Text == "*"
+	}
+	return false
+}
+
+// propertyName parses the key of an object or class member and returns it
+// with its name, "" when it is computed.
+func (p *tsParser) propertyName() (*TSNode, string) {
+	start := p.start()
+	switch {
+	case p.at("["):
+		defer p.allowIn()()
+		p.next()
+		key := p.assignment()
+		p.expect("]")
+		return key, ""
+	case p.at("#") && p.peek().Kind == TSIdent:
+		p.next()
+		name := "#" + p.tok.Text
+		p.next()
+		return p.node(TSIdentifier, start, name), name
+	case p.tok.Kind == TSIdent:
+		name := p.tok.Text
+		p.next()
+		return p.node(TSIdentifier, start, name), name
+	case p.tok.Kind == TSString, p.tok.Kind == TSNumber:
+		text := p.tok.Text
+		p.next()
+		return p.node(TSLiteral, start, text), strings.Trim(text, `"'`)
+	}
+	return p.unexpected(), ""
+}
+
+func (p *tsParser) expression() *TSNode {
+	start := p.start()
+	first := p.assignment()
+	if !p.at(",") {
+		return first
+	}
+	items := []*TSNode{first}
+	for p.eat(",") {
+		items = append(items, p.assignment())
+	}
+	return p.node(TSSequence, start, "", items...)
+}
+
+func (p *tsParser) assignment() *TSNode {
+	start := p.start()
+	left := p.conditional()
+	op, width := p.assignOperator()
+	if width == 0 {
+		return left
+	}
+	for range width {
+		p.next()
+	}
+	return p.node(TSAssign, start, op, left, p.assignment())
+}
+
+// assignOperator returns the assignment operator at the current token and
+// how many tokens it spans: the lexer leaves ">>=" and ">>>=" in pieces.
+func (p *tsParser) assignOperator() (string, int) {
+	if p.tok.Kind != TSPunct {
+		return "", 0
+	}
+	if tsAssignOperators[p.tok.Text] {
+		return p.tok.Text, 1
+	}
+	if p.tok.Text == ">" {
+		a := p.peek()
+		if adjacent(p.tok, a) && a.Text == ">=" {
+			return ">>=", 2
+		}
+		if adjacent(p.tok, a) && a.Text == ">" {
+			if b := p.peekAt(2); adjacent(a, b) && b.Text == ">=" {
+				return ">>>=", 3
+			}
+		}
+	}
+	return "", 0
+}
+
+func (p *tsParser) conditional() *TSNode {
+	start := p.start()
+	condition := p.binary(0)
+	if !p.at("?") {
+		return condition
+	}
+	p.next()
+	restore := p.allowIn()
+	consequent := p.assignment()
+	restore()
+	p.expect(":")
+	return p.node(TSConditional, start, "", condition, consequent, p.assignment())
+}
+
+func (p *tsParser) binary(minPrecedence int) *TSNode {
+	start := p.start()
+	left := p.unary()
+	for {
+		op, width := p.binaryOperator()
+		precedence := tsBinaryPrecedence[op]
+		if width == 0 || precedence <= minPrecedence {
+			return left
+		}
+		for range width {
+			p.next()
+		}
+		if op == "as" || op == "satisfies" {
+			p.skipType()
+			left = p.node(TSTypeAssertion, start, op, left)
+			continue
+		}
+		if op == "**" {
+			precedence-- // right-associative
+		}
+		left = p.node(TSBinary, start, op, left, p.binary(precedence))
+	}
+}
+
+// binaryOperator returns the binary operator at the current token and how
+// many tokens it spans: the lexer leaves ">>" and ">>>" in pieces.
+func (p *tsParser) binaryOperator() (string, int) {
+	tok := p.tok
+	switch tok.Kind {
+	case TSIdent:
+		switch tok.Text {
+		case "instanceof":
+			return tok.Text, 1
+		case "in":
+			if !p.noIn {
+				return tok.Text, 1
+			}
+		case "as", "satisfies":
+			if !tok.NewlineBefore {
+				return tok.Text, 1
+			}
+		}
+	case TSPunct:
+		if tok.Text == ">" {
+			a := p.peek()
+			if !adjacent(tok, a) {
+				return ">", 1
+			}
+			switch a.Text {
+			case ">=":
+				return "", 0
+			case ">":
+				b := p.peekAt(2)
+				switch {
+				case adjacent(a, b) && b.Text == ">=":
+					return "", 0
+				case adjacent(a, b) && b.Text == ">":
+					return ">>>", 3
+				}
+				return ">>", 2
+			}
+			return ">", 1
+		}
+		if _, ok := tsBinaryPrecedence[tok.Text]; ok {
+			return tok.Text, 1
+		}
+	}
+	return "", 0
+}
+
+func (p *tsParser) unary() *TSNode {
+	start := p.start()
+	tok := p.tok
+	if (tok.Kind == TSPunct || tok.Kind == TSIdent) && tsPrefixOperators[tok.Text] {
+		if tok.Kind == TSPunct || startsExpression(p.peek()) {
+			p.next()
+			return p.node(TSUnary, start, tok.Text, p.unary())
+		}
+	}
+	if tokIs(tok, "yield") {
+		p.next()
+		var arg *TSNode
+		if !p.tok.NewlineBefore && (p.at("*") || startsExpression(p.tok)) {
+			p.eat("*")
+			arg = p.assignment()
+		}
+		return p.node(TSUnary, start, "yield", arg)
+	}
+	if p.at("<") && !p.jsx {
+		// <T>value, or a generic arrow function: <T>(x: T) => x
+		p.skipAngles()
+		if p.at("(") && p.arrowAhead(0) {
+			return p.arrow(start)
+		}
+		return p.node(TSTypeAssertion, start, "<>", p.unary())
+	}
+	expr := p.callTail(start, p.primary(), true)
+	if (p.at("++") || p.at("--")) && !p.tok.NewlineBefore {
+		op := p.tok.Text
+		p.next()
+		return p.node(TSUpdate, start, op, expr)
+	}
+	return expr
+}
+
+// startsExpression reports whether an operand can begin with tok, which
+// tells "await x" from a variable named await.
+func startsExpression(tok TSToken) bool {
+	switch tok.Kind {
+	case TSIdent:
+		return tok.Text != "in" && tok.Text != "of" && tok.Text != "instanceof" && tok.Text != "as"
+	case TSNumber, TSString, TSTemplate, TSRegExp:
+		return true
+	case TSPunct:
+		switch tok.Text {
+		case "(", "[", "{", "!", "~", "+", "-", "++", "--", "<", "#", "@":
+			return true
+		}
+	}
+	return false
+}
+
+// callTail parses the member accesses, calls, indexes, tagged templates
+// and non-null assertions after an expression. A new expression's callee
+// takes no calls: its arguments are the new's own.
+func (p *tsParser) callTail(start TSPosition, expr *TSNode, calls bool) *TSNode {
+	for {
+		switch {
+		case p.at("."):
+			p.next()
+			expr = p.node(TSMember, start, ".", expr, p.propertyIdent())
+		case p.at("?."):
+			p.next()
+			switch {
+			case p.at("("):
+				args := p.arguments()
+				expr = p.node(TSCall, start, "?.(", append([]*TSNode{expr}, args...)...)
+			case p.at("["):
+				index := p.index()
+				expr = p.node(TSIndex, start, "?.[", expr, index)
+			default:
+				expr = p.node(TSMember, start, "?.", expr, p.propertyIdent())
+			}
+		case p.at("["):
+			index := p.index()
+			expr = p.node(TSIndex, start, "[", expr, index)
+		case p.at("(") && calls:
+			args := p.arguments()
+			expr = p.node(TSCall, start, "(", append([]*TSNode{expr}, args...)...)
+		case p.tok.Kind == TSTemplate:
+			template := p.template()
+			expr = p.node(TSTaggedTemplate, start, "", expr, template)
+		case p.at("!") && !p.tok.NewlineBefore:
+			p.next()
+			expr = p.node(TSTypeAssertion, start, "!", expr)
+		case p.at("<"):
+			width := p.typeArgumentsAhead()
+			if width == 0 {
+				return expr
+			}
+			for range width {
+				p.next()
+			}
+		default:
+			return expr
+		}
+	}
+}
+
+func (p *tsParser) index() *TSNode {
+	defer p.allowIn()()
+	p.next()
+	index := p.expression()
+	p.expect("]")
+	return index
+}
+
+// propertyIdent parses the name after a dot; keywords are names there.
+func (p *tsParser) propertyIdent() *TSNode {
+	start := p.start()
+	switch {
+	case p.tok.Kind == TSIdent:
+		name := p.tok.Text
+		p.next()
+		return p.node(TSIdentifier, start, name)
+	case p.at("#") && p.peek().Kind == TSIdent:
+		p.next()
+		name := "#" + p.tok.Text
+		p.next()
+		return p.node(TSIdentifier, start, name)
+	}
+	return p.unexpected()
+}
+
+func (p *tsParser) arguments() []*TSNode {
+	defer p.allowIn()()
+	p.next()
+	return p.list(")", p.element)
+}
+
+// element parses an argument or an array element, spread or not.
+func (p *tsParser) element() *TSNode {
+	if p.at("...") {
+		start := p.start()
+		p.next()
+		return p.node(TSSpread, start, "", p.assignment())
+	}
+	return p.assignment()
+}
+
+// typeArgumentsAhead returns how many tokens the type arguments at the
+// current "<" span when a call or a template follows them — f<T>(x) — and 0
+// when the "<" compares.
+func (p *tsParser) typeArgumentsAhead() int {
+	depth := 0
+	for i := 0; i < 256; i++ {
+		tok := p.peekAt(i)
+		switch tok.Kind {
+		case tsEOF:
+			return 0
+		case TSPunct:
+			switch tok.Text {
+			case "<":
+				depth++
+			case ">":
+				depth--
+				if depth == 0 {
+					if next := p.peekAt(i + 1); tokIs(next, "(") || next.Kind == TSTemplate {
+						return i + 1
+					}
+					return 0
+				}
+			case ",", ".", "[", "]", "{", "}", "(", ")", "|", "&", "=>", ":", "?", "-":
+			default:
+				return 0
+			}
+		}
+	}
+	return 0
+}
+
+func (p *tsParser) primary() *TSNode {
+	start := p.start()
+	tok := p.tok
+	switch tok.Kind {
+	case TSNumber, TSString, TSRegExp:
+		p.next()
+		return p.node(TSLiteral, start, tok.Text)
+	case TSTemplate:
+		return p.template()
+	case TSIdent:
+		return p.identifierExpression(start)
+	case TSPunct:
+		switch tok.Text {
+		case "(":
+			if p.arrowAhead(0) {
+				return p.arrow(start)
+			}
+			return p.node(TSParen, start, "", p.parenthesized())
+		case "[":
+			return p.arrayLiteral()
+		case "{":
+			return p.objectLiteral()
+		case "<":
+			if p.jsx && !p.genericArrowAhead() {
+				element := p.jsxElement()
+				p.next()
+				return element
+			}
+			p.skipAngles()
+			if p.at("(") && p.arrowAhead(0) {
+				return p.arrow(start)
+			}
+		case "#":
+			if p.peek().Kind == TSIdent {
+				return p.propertyIdent()
+			}
+		case "@":
+			p.decorators()
+			return p.primary()
+		}
+	}
+	return p.unexpected()
+}
+
+func (p *tsParser) identifierExpression(start TSPosition) *TSNode {
+	tok, next := p.tok, p.peek()
+	switch tok.Text {
+	case "function":
+		return p.function(start)
+	case "class":
+		return p.class(start)
+	case "new":
+		return p.newExpression(start)
+	case "true", "false", "null":
+		p.next()
+		return p.node(TSLiteral, start, tok.Text)
+	case "async":
+		if !next.NewlineBefore {
+			switch {
+			case tokIs(next, "function"):
+				p.next()
+				return p.function(start)
+			case next.Kind == TSIdent && tokIs(p.peekAt(2), "=>"):
+				p.next()
+				return p.arrow(start)
+			case tokIs(next, "(") && p.arrowAhead(1):
+				p.next()
+				return p.arrow(start)
+			}
+		}
+	}
+	if tokIs(next, "=>") && !next.NewlineBefore {
+		return p.arrow(start)
+	}
+	p.next()
+	return p.node(TSIdentifier, start, tok.Text)
+}
+
+func (p *tsParser) newExpression(start TSPosition) *TSNode {
+	p.next()
+	if p.at(".") {
+		// new.target
+		p.next()
+		target := p.node(TSIdentifier, start, "new")
+		return p.node(TSMember, start, ".", target, p.propertyIdent())
+	}
+	calleeStart := p.start()
+	var callee *TSNode
+	if p.at("new") {
+		callee = p.newExpression(calleeStart)
+	} else {
+		callee = p.primary()
+	}
+	children := []*TSNode{p.callTail(calleeStart, callee, false)}
+	if p.at("(") {
+		children = append(children, p.arguments()...)
+	}
+	return p.node(TSNew, start, "", children...)
+}
+
+// arrowAhead reports whether the parenthesis at lookahead i opens the
+// parameters of an arrow function.
+func (p *tsParser) arrowAhead(i int) bool {
+	depth := 0
+	for j := i; j < i+tsLookaheadLimit; j++ {
+		tok := p.peekAt(j)
+		if tok.Kind == tsEOF {
+			return false
+		}
+		if tok.Kind != TSPunct {
+			continue
+		}
+		switch tok.Text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return p.arrowAfterParams(j + 1)
+			}
+		}
+	}
+	return false
+}
+
+// arrowAfterParams reports whether the tokens from lookahead i go on as an
+// arrow function after its parameters: "=>", or a return type and "=>".
+func (p *tsParser) arrowAfterParams(i int) bool {
+	tok := p.peekAt(i)
+	if tokIs(tok, "=>") {
+		return !tok.NewlineBefore
+	}
+	if !tokIs(tok, ":") {
+		return false
+	}
+	depth := 0
+	for j := i + 1; j < i+tsLookaheadLimit; j++ {
+		tok = p.peekAt(j)
+		if tok.Kind == tsEOF {
+			return false
+		}
+		if depth == 0 && tok.NewlineBefore && !tokIs(tok, "|") && !tokIs(tok, "&") {
+			return false
+		}
+		if tok.Kind != TSPunct {
+			continue
+		}
+		switch tok.Text {
+		case "(", "[", "{", "<":
+			depth++
+		case ")", "]", "}", ">":
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case "=>":
+			if depth == 0 {
+				return true
+			}
+		case ";", ",", "=":
+			if depth == 0 {
+				return false
+			}
+		case "&&", "||", "??", "+", "*", "/", "%", "==", "===", "!=", "!==", "++", "--":
+			return false
+		}
+	}
+	return false
+}
+
+// genericArrowAhead tells the type parameters of a generic arrow function
+// in a JSX file — <T,>(x: T) => x — from an element.
+func (p *tsParser) genericArrowAhead() bool {
+	if p.peek().Kind != TSIdent {
+		return false
+	}
+	next := p.peekAt(2)
+	return tokIs(next, ",") || tokIs(next, "extends")
+}
+
+// arrow parses an arrow function from its parameters, async already
+// consumed.
+func (p *tsParser) arrow(start TSPosition) *TSNode {
+	var children []*TSNode
+	if p.tok.Kind == TSIdent {
+		paramStart, name := p.start(), p.tok.Text
+		p.next()
+		children = []*TSNode{p.node(TSParam, paramStart, name, p.node(TSIdentifier, paramStart, name), nil)}
+	} else {
+		children = p.params()
+		if p.eat(":") {
+			p.skipType()
+		}
+	}
+	p.expect("=>")
+	restore := p.allowIn()
+	defer restore()
+	var body *TSNode
+	if p.at("{") {
+		body = p.block()
+	} else {
+		body = p.assignment()
+	}
+	return p.node(TSFunction, start, "", append(children, body)...)
+}
+
+func (p *tsParser) arrayLiteral() *TSNode {
+	start := p.start()
+	defer p.allowIn()()
+	p.next()
+	var elements []*TSNode
+	for !p.at("]") && !p.atEOF() {
+		if p.eat(",") {
+			elements = append(elements, nil) // a hole
+			continue
+		}
+		if p.tok.Kind == TSPunct && strings.Contains(");}", p.tok.Text) {
+			break
+		}
+		before := p.consumed
+		elements = append(elements, p.element())
+		if p.eat(",") || p.at("]") {
+			continue
+		}
+		if p.consumed == before && !p.atCloser() {
+			elements = append(elements, p.skip())
+		} else if p.consumed == before && p.at(":") {
+			p.skip()
+		}
+	}
+	p.expect("]")
+	return p.node(TSArray, start, "", elements...)
+}
+
+func (p *tsParser) objectLiteral() *TSNode {
+	start := p.start()
+	defer p.allowIn()()
+	p.next()
+	members := p.list("}", p.objectMember)
+	return p.node(TSObject, start, "", members...)
+}
+
+func (p *tsParser) objectMember() *TSNode {
+	start := p.start()
+	if p.at("...") {
+		p.next()
+		return p.node(TSSpread, start, "", p.assignment())
+	}
+	for (p.at("get") || p.at("set") || p.at("async")) && startsPropertyName(p.peek()) {
+		p.next()
+	}
+	p.eat("*")
+	key, name := p.propertyName()
+	switch {
+	case p.at("(") || p.at("<"):
+		return p.functionRest(start, name)
+	case p.eat(":"):
+		return p.node(TSProperty, start, name, key, p.assignment())
+	case p.at("="):
+		// A default in a destructuring pattern: { limit = 10 }.
+		p.next()
+		target := &TSNode{Kind: key.Kind, Text: key.Text, Start: key.Start, End: key.End}
+		value := p.node(TSAssign, key.Start, "=", target, p.assignment())
+		return p.node(TSProperty, start, name, key, value)
+	}
+	return p.node(TSProperty, start, name, key, nil)
+}
+
+// template parses a template literal, each substitution with a parser of its
+// own started inside the token.
+func (p *tsParser) template() *TSNode {
+	start, tok := p.start(), p.tok
+	var substitutions []*TSNode
+	text := tok.Text
+scan:
+	for i := 1; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case text[i] == '$' && i+1 < len(text) && text[i+1] == '{':
+			sub := newTSParser(p.s, p.jsx, tok.Offset+i+2)
+			substitutions = append(substitutions, sub.expression())
+			if !sub.at("}") || sub.tok.End > tok.End {
+				break scan
+			}
+			i = sub.tok.End - tok.Offset - 1
+		}
+	}
+	p.next()
+	return p.node(TSTemplateLiteral, start, tok.Text, substitutions...)
+}
+
+// jsxElement parses an element from its "<". It leaves the ">" that ends the
+// element current: code follows it in an expression, text among a parent's
+// children.
+func (p *tsParser) jsxElement() *TSNode {
+	start := p.start()
+	p.next()
+	name := ""
+	if !p.at(">") {
+		name = p.jsxName()
+	}
+	if p.at("<") {
+		p.skipAngles() // <Select<Option> ...>
+	}
+	var children []*TSNode
+	for !p.at(">") && !p.at("/") && !p.atEOF() {
+		before := p.consumed
+		attribute := p.jsxAttribute()
+		if p.consumed == before {
+			attribute = p.skip()
+		}
+		children = append(children, attribute)
+	}
+	if p.eat("/") {
+		if !p.at(">") {
+			p.errorf("expected \">\", found %q", p.tok.Text)
+		}
+	} else if p.at(">") {
+		children = append(children, p.jsxChildren()...)
+	}
+	element := p.node(TSJSXElement, start, name, children...)
+	element.End = p.s.position(p.tok.End)
+	return element
+}
+
+// jsxName parses a tag or attribute name, which may be dotted, dashed or
+// namespaced: Form.Item, aria-label, xlink:href.
+func (p *tsParser) jsxName() string {
+	name := p.tok.Text
+	p.next()
+	for (p.at("-") || p.at(".") || p.at(":")) && p.tok.Offset == p.prevEnd {
+		separator := p.tok.Text
+		p.next()
+		if p.tok.Kind != TSIdent || p.tok.Offset != p.prevEnd {
+			return name + separator
+		}
+		name += separator + p.tok.Text
+		p.next()
+	}
+	return name
+}
+
+func (p *tsParser) jsxAttribute() *TSNode {
+	start := p.start()
+	if p.at("{") {
+		// {...props}
+		p.next()
+		p.eat("...")
+		arg := p.assignment()
+		p.expect("}")
+		return p.node(TSSpread, start, "", arg)
+	}
+	if p.tok.Kind != TSIdent {
+		return p.unexpected()
+	}
+	name := p.jsxName()
+	var value *TSNode
+	if p.eat("=") {
+		switch {
+		case p.tok.Kind == TSString:
+			value = p.primary()
+		case p.at("{"):
+			value = p.jsxExpression()
+			p.next()
+		case p.at("<"):
+			value = p.jsxElement()
+			p.next()
+		}
+	}
+	return p.node(TSJSXAttribute, start, name, value)
+}
+
+// jsxExpression parses an expression container from its "{" and, like
+// jsxElement, leaves its "}" current.
+func (p *tsParser) jsxExpression() *TSNode {
+	start := p.start()
+	restore := p.allowIn()
+	defer restore()
+	p.next()
+	var expr *TSNode
+	if !p.at("}") {
+		expr = p.element()
+	}
+	if !p.at("}") {
+		p.errorf("expected \"}\", found %q", p.tok.Text)
+	}
+	container := p.node(TSJSXExpression, start, "", expr)
+	container.End = p.s.position(p.tok.End)
+	return container
+}
+
+// jsxChildren parses an element's children from the ">" of its opening tag
+// through its closing tag, whose ">" it leaves current. Text between them
+// is not code, so the lexer starts over after each child.
+func (p *tsParser) jsxChildren() []*TSNode {
+	var children []*TSNode
+	for {
+		p.seek(p.tok.End)
+		if text := p.jsxText(); text != nil {
+			children = append(children, text)
+		}
+		p.setCurrent(p.lex())
+		switch {
+		case p.atEOF():
+			p.errorf("unterminated JSX element")
+			return children
+		case p.at("{"):
+			children = append(children, p.jsxExpression())
+		case p.at("<") && tokIs(p.peek(), "/"):
+			p.next()
+			p.next()
+			for !p.at(">") && !p.atEOF() {
+				p.next()
+			}
+			return children
+		default:
+			children = append(children, p.jsxElement())
+		}
+	}
+}
+
+// jsxText scans raw text up to the next tag or expression container.
+func (p *tsParser) jsxText() *TSNode {
+	src := p.s.src
+	start, end := p.lx.pos, p.lx.pos
+	for end < len(src) && src[end] != '<' && src[end] != '{' {
+		end++
+	}
+	p.lx.advanceTo(end)
+	text := string(src[start:end])
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	return &TSNode{Kind: TSJSXText, Text: text, Start: p.s.position(start), End: p.s.position(end)}
+}
+
+// skipBalanced parses past a bracketed group from its opening bracket.
+func (p *tsParser) skipBalanced() {
+	depth := 0
+	for !p.atEOF() {
+		if p.tok.Kind == TSPunct {
+			switch p.tok.Text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			}
+		}
+		p.next()
+		if depth <= 0 {
+			return
+		}
+	}
+}
+
+func (p *tsParser) skipTypeParams() {
+	if p.at("<") {
+		p.skipAngles()
+	}
+}
+
+// skipAngles parses past type parameters or arguments from their "<".
+func (p *tsParser) skipAngles() {
+	depth := 0
+	for !p.atEOF() {
+		switch {
+		case p.at("(") || p.at("[") || p.at("{"):
+			p.skipBalanced()
+			continue
+		case p.at("<"):
+			depth++
+		case p.at(">"):
+			depth--
+		case p.at(";") || p.at(")") || p.at("]") || p.at("}"):
+			return
+		}
+		p.next()
+		if depth <= 0 {
+			return
+		}
+	}
+}
+
+// skipType parses past a type.
+func (p *tsParser) skipType() {
+	p.skipTypeOperands()
+	if p.at("extends") && !p.tok.NewlineBefore {
+		// A conditional type: T extends U ? X : Y.
+		p.next()
+		p.skipTypeOperands()
+		if p.eat("?") {
+			p.skipType()
+			p.expect(":")
+			p.skipType()
+		}
+	}
+}
+
+// skipTypeOperands parses past a union or intersection.
+func (p *tsParser) skipTypeOperands() {
+	if p.at("|") || p.at("&") {
+		p.next()
+	}
+	for {
+		p.skipTypeOperand()
+		if !p.at("|") && !p.at("&") {
+			return
+		}
+		p.next()
+	}
+}
+
+func (p *tsParser) skipTypeOperand() {
+	for p.tok.Kind == TSIdent && tsTypePrefixes[p.tok.Text] && startsType(p.peek()) {
+		p.next()
+	}
+	switch {
+	case p.at("("):
+		p.skipBalanced()
+		if p.eat("=>") {
+			p.skipType()
+		}
+	case p.at("<"):
+		// A generic function type: <T>(x: T) => T.
+		p.skipAngles()
+		if p.at("(") {
+			p.skipBalanced()
+		}
+		if p.eat("=>") {
+			p.skipType()
+		}
+	case p.at("[") || p.at("{"):
+		p.skipBalanced()
+	case p.at("-"):
+		p.next()
+		p.next()
+	case slices.Contains([]TSTokenKind{TSString, TSNumber, TSTemplate}, p.tok.Kind):
+		p.next()
+	case p.tok.Kind == TSIdent:
+		p.next()
+		for p.at(".") && p.peek().Kind == TSIdent {
+			p.next()
+			p.next()
+		}
+		if p.at("<") && !p.tok.NewlineBefore {
+			p.skipAngles()
+		}
+		if p.at("is") && !p.tok.NewlineBefore {
+			// A type predicate: value is string.
+			p.next()
+			p.skipType()
+		}
+	default:
+		return
+	}
+	for p.at("[") && !p.tok.NewlineBefore {
+		p.skipBalanced()
+	}
+}
+
+func startsType(tok TSToken) bool {
+	switch tok.Kind {
+	case TSIdent, TSString, TSNumber, TSTemplate:
+		return true
+	case TSPunct:
+		return strings.Contains("([{<-", tok.Text)
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dumpTS renders a tree as Kind:text(children...), with _ for an absent
+// child.
+func dumpTS(n *TSNode) string {
+	if n == nil {
+		return "_"
+	}
+	var b strings.Builder
+	b.WriteString(n.Kind.String())
+	if n.Text != "" && n.Kind != TSTemplateLiteral {
+		b.WriteString(":" + n.Text)
+	}
+	if len(n.Children) > 0 {
+		parts := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			parts[i] = dumpTS(child)
+		}
+		b.WriteString("(" + strings.Join(parts, " ") + ")")
+	}
+	return b.String()
+}
+
+func parseTS(t *testing.T, src string, jsx bool) *TSFile {
+	t.Helper()
+	file := ParseTypeScript([]byte(src), jsx)
+	require.Empty(t, file.Errors, "syntax errors in:\n%s", src)
+	return file
+}
+
+func TestParseTypeScriptStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "declaration with a type annotation",
+			src:  "const total: number = a + b * 2;",
+			want: "VarDecl:const(Declarator(Identifier:total Binary:+(Identifier:a Binary:*(Identifier:b Literal:2))))",
+		},
+		{
+			name: "try with catch and finally",
+			src:  "try { save() } catch (err: unknown) { log(err) } finally { done() }",
+			want: "Try(Block(ExprStmt(Call:((Identifier:save))) Catch(Identifier:err Block(ExprStmt(Call:((Identifier:log Identifier:err)))) Block(ExprStmt(Call:((Identifier:done))))",
+		},
+		{
+			name: "optional chaining and nullish coalescing",
+			src:  "x = a?.b?.[k]?.(1) ?? {}",
+			want: "ExprStmt(Assign:=(Identifier:x Binary:??(Call:?.((Index:?.[(Member:?.(Identifier:a Identifier:b) Identifier:k) Literal:1) Object)))",
+		},
+		{
+			name: "arrow functions",
+			src:  "const f = async (x: number, { y = 1 }: Opts): Promise<void> => x; g(v => v.id)",
+			want: "VarDecl:const(Declarator(Identifier:f Function(Param:x(Identifier:x _) Param(Object(Property:y(Identifier:y Assign:=(Identifier:y Literal:1))) _) Identifier:x))) " +
+				"ExprStmt(Call:((Identifier:g Function(Param:v(Identifier:v _) Member:.(Identifier:v Identifier:id))))",
+		},
+		{
+			name: "parenthesized expression is no arrow",
+			src:  "y = (a + b) * c; z = cond ? (p) : q;",
+			want: "ExprStmt(Assign:=(Identifier:y Binary:*(Paren(Binary:+(Identifier:a Identifier:b)) Identifier:c))) " +
+				"ExprStmt(Assign:=(Identifier:z Conditional(Identifier:cond Paren(Identifier:p) Identifier:q)))",
+		},
+		{
+			name: "types are parsed past",
+			src: "interface A { b: string }\ntype U = 'a' | 'b'\nenum E { X }\n" +
+				"let v = w as unknown as U; const n = m!.k; f<string>(x); if (a < b) {}",
+			want: "TypeDecl:A TypeDecl:U TypeDecl:E " +
+				"VarDecl:let(Declarator(Identifier:v TypeAssertion:as(TypeAssertion:as(Identifier:w)))) " +
+				"VarDecl:const(Declarator(Identifier:n Member:.(TypeAssertion:!(Identifier:m) Identifier:k))) " +
+				"ExprStmt(Call:((Identifier:f Identifier:x)) " +
+				"If(Binary:<(Identifier:a Identifier:b) Block _)",
+		},
+		{
+			name: "loops and shifts",
+			src:  "for (const [k, v] of Object.entries(o)) x >>= 1; for (let i = 0; i < n; i++) y = a >> b >>> c",
+			want: "ForIn:of(VarDecl:const(Declarator(Array(Identifier:k Identifier:v) _)) Call:((Member:.(Identifier:Object Identifier:entries) Identifier:o) ExprStmt(Assign:>>=(Identifier:x Literal:1))) " +
+				"For(VarDecl:let(Declarator(Identifier:i Literal:0)) Binary:<(Identifier:i Identifier:n) Update:++(Identifier:i) ExprStmt(Assign:=(Identifier:y Binary:>>>(Binary:>>(Identifier:a Identifier:b) Identifier:c))))",
+		},
+		{
+			name: "class with fields and methods",
+			src:  "export class Store extends Base<T> implements I {\n  private items: Item[] = []\n  @observable count = 0\n  get size(): number { return this.items.length }\n}",
+			want: "Export(Class:Store(Property:items(Identifier:items Array) Property:count(Identifier:count Literal:0) Function:size(Block(Return(Member:.(Member:.(Identifier:this Identifier:items) Identifier:length))))))",
+		},
+		{
+			name: "template substitutions are expressions",
+			src:  "s = `${a.b}-${fn({ k: `${c}` })}`",
+			want: "ExprStmt(Assign:=(Identifier:s TemplateLiteral(Member:.(Identifier:a Identifier:b) Call:((Identifier:fn Object(Property:k(Identifier:k TemplateLiteral(Identifier:c)))))))",
+		},
+		{
+			name: "imports and exports",
+			src:  "import React, { useState } from 'react'\nimport type { A } from \"./a\";\nexport { x } from './x'\nexport default function App() {}",
+			want: "Import:'react' Import:\"./a\" Export Export:default(Function:App(Block))",
+		},
+		{
+			name: "return ends at a line break",
+			src:  "function f() { return\n  value }",
+			want: "Function:f(Block(Return(_) ExprStmt(Identifier:value)))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := parseTS(t, tt.src, false)
+			var got []string
+			for _, stmt := range file.Program.Children {
+				got = append(got, dumpTS(stmt))
+			}
+			assert.Equal(t, tt.want, strings.Join(got, " "))
+		})
+	}
+}
+
+func TestParseTypeScriptJSX(t *testing.T) {
+	src := `export const Row = ({ wallet }: Props) => (
+  <div key={wallet.id} className="row" {...rest}>
+    Don't {/* a comment */} lose {wallet.name}!
+    <input value={wallet.address} onChange={e => set(e.target.value)} disabled />
+    <>{items.map(i => <Item key={i} />)}</>
+  </div>
+)
+`
+	file := parseTS(t, src, true)
+	var element *TSNode
+	file.Program.Walk(func(n *TSNode, _ []*TSNode) bool {
+		if element == nil && n.Kind == TSJSXElement {
+			element = n
+		}
+		return true
+	})
+	require.NotNil(t, element)
+
+	assert.Equal(t, "div", element.Text)
+	assert.Equal(t, TSPosition{Offset: 46, Line: 2, Column: 3}, element.Start)
+	assert.Equal(t, 6, element.End.Line)
+	assert.Equal(t, "wallet.id", element.Attribute("key").Child(0).Child(0).Path())
+	assert.Equal(t, `"row"`, element.Attribute("className").Child(0).Text)
+
+	var kinds []string
+	for _, child := range element.Children {
+		kinds = append(kinds, child.Kind.String())
+	}
+	assert.Equal(t, []string{"JSXAttribute", "JSXAttribute", "Spread", "JSXText", "JSXExpression", "JSXText", "JSXExpression", "JSXText", "JSXElement", "JSXElement"}, kinds)
+	assert.Nil(t, element.Children[4].Child(0), "a comment-only container has no expression")
+
+	input := element.Children[8]
+	assert.Equal(t, "input", input.Text)
+	assert.Equal(t, "wallet.address", input.Attribute("value").Child(0).Child(0).Path())
+	assert.NotNil(t, input.Attribute("onChange"))
+	assert.NotNil(t, input.Attribute("disabled"))
+	assert.Equal(t, `<input value={wallet.address} onChange={e => set(e.target.value)} disabled />`, file.Text(input))
+
+	require.Len(t, file.Comments, 1)
+	assert.Equal(t, "/* a comment */", file.Comments[0].Text)
+}
+
+func TestParseTypeScriptGenericsOutsideJSX(t *testing.T) {
+	file := parseTS(t, "const f = <T,>(x: T) => x; const n = <number>value;", false)
+	assert.Equal(t, "VarDecl:const(Declarator(Identifier:f Function(Param:x(Identifier:x _) Identifier:x)))", dumpTS(file.Program.Children[0]))
+	assert.Equal(t, "VarDecl:const(Declarator(Identifier:n TypeAssertion:<>(Identifier:value)))", dumpTS(file.Program.Children[1]))
+
+	file = parseTS(t, "const f = <T,>(x: T) => x", true)
+	assert.Equal(t, "VarDecl:const(Declarator(Identifier:f Function(Param:x(Identifier:x _) Identifier:x)))", dumpTS(file.Program.Children[0]))
+}
+
+func TestParseTypeScriptRecoversFromErrors(t *testing.T) {
+	file := ParseTypeScript([]byte("const a = ;\nfoo(1, ]\n}\nconst b = 2\n"), false)
+	assert.NotEmpty(t, file.Errors)
+	last := file.Program.Children[len(file.Program.Children)-1]
+	assert.Equal(t, "VarDecl:const(Declarator(Identifier:b Literal:2))", dumpTS(last))
+	assert.Equal(t, 4, last.Start.Line)
+
+	// Unterminated constructs end with the source.
+	for _, src := range []string{"f(", "<div>", "class {", "`${", "x = {a: [", "<a b={"} {
+		assert.NotPanics(t, func() { ParseTypeScript([]byte(src), true) }, src)
+	}
+}
+
+func TestTSNodeHelpers(t *testing.T) {
+	file := parseTS(t, "(process.env as any)?.API_URL!.trim()", false)
+	call := file.Program.Children[0].Child(0)
+	require.Equal(t, TSCall, call.Kind)
+	member := call.Child(0)
+	assert.Equal(t, "process.env.API_URL.trim", member.Path())
+	assert.Equal(t, "trim", member.Name())
+	assert.Equal(t, "", call.Path())
+	assert.Nil(t, call.Child(5))
+
+	var paths []string
+	file.Program.Walk(func(n *TSNode, ancestors []*TSNode) bool {
+		if n.Kind == TSIdentifier {
+			paths = append(paths, n.Text)
+			assert.Equal(t, TSProgram, ancestors[0].Kind)
+		}
+		return n.Kind != TSParen
+	})
+	assert.Equal(t, []string{"API_URL", "trim"}, paths, "returning false skips the children")
+}
@@ -16,6 +16,9 @@ type Walker struct {
 	config      *Config
 	parser      *Parser
 	parseGo     bool
+	// Base-name globs of the files admitted beyond the analyzable
+	// extensions, for the rules that read them.
+	extraFiles []string
 
 	// Worker pool size. The channels belong to one walk: walk creates them,
 	// so the same walker can be reused.
@@ -56,6 +59,13 @@ func (w *Walker) WithGoParsing(enabled bool) *Walker {
 	return w
 }
 
+// WithExtraFiles also admits the files whose base name matches one of the
+// glob patterns.
+func (w *Walker) WithExtraFiles(patterns []string) *Walker {
+	w.extraFiles = patterns
+	return w
+}
+
 // WithWorkers sets the number of worker goroutines
 func (w *Walker) WithWorkers(n int) *Walker {
 	if n > 0 {
@@ -224,6 +234,11 @@ func (w *Walker) processFile(path string) (*FileContext, error) {
 		}
 	}
 
+	// Parse TypeScript and JavaScript files; syntax errors are kept on the tree
+	if ctx.IsTypeScriptFile() || ctx.IsJavaScriptFile() {
+		ctx.SetTSAST(w.parser.ParseTypeScriptFile(path, content))
+	}
+
 	return ctx, nil
 }
 
@@ -236,6 +251,12 @@ func (w *Walker) shouldSkipDir(name string) bool {
 
 // isAnalyzableFile returns true if file should be analyzed
 func (w *Walker) isAnalyzableFile(path string) bool {
+	return IsAnalyzablePath(path) || MatchesFileName(path, w.extraFiles)
+}
+
+// IsAnalyzablePath reports whether a file with this name is analyzed at all,
+// by its extension.
+func IsAnalyzablePath(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 
 	analyzableExtensions := []string{
@@ -247,14 +268,18 @@ func (w *Walker) isAnalyzableFile(path string) bool {
 		".conf", // server configuration rules (rules must guard by extension)
 	}
 
-	for _, e := range analyzableExtensions {
-		if ext == e {
-			// Debug: uncomment to see which files are considered
-			// fmt.Printf("DEBUG isAnalyzable: %s -> true (ext=%s)\n", path, ext)
+	return slices.Contains(analyzableExtensions, ext)
+}
+
+// MatchesFileName reports whether the base name of path matches one of the
+// glob patterns, such as "*.yaml", ".env.*" or "Dockerfile".
+func MatchesFileName(path string, patterns []string) bool {
+	name := filepath.Base(path)
+	for _, pattern := range patterns {
+		if ok, err := filepath.Match(pattern, name); err == nil && ok {
 			return true
 		}
 	}
-
 	return false
 }
 
@@ -95,6 +95,25 @@ func TestWalkerAnalyzesNginxConfig(t *testing.T) {
 	assert.Equal(t, "nginx.conf", contexts[0].RelPath)
 }
 
+func TestWalkerAdmitsExtraFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"main.go", "config.yaml", ".env.production", "Dockerfile", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte("package main\n"), 0644))
+	}
+
+	contexts, errs := NewWalker(tmpDir, DefaultConfig()).WithExtraFiles([]string{"*.yaml", ".env.*", "Dockerfile"}).WalkSync()
+	require.Empty(t, errs)
+	var names []string
+	for _, ctx := range contexts {
+		names = append(names, ctx.RelPath)
+	}
+	assert.ElementsMatch(t, []string{"main.go", "config.yaml", ".env.production", "Dockerfile"}, names)
+
+	contexts, errs = NewWalker(tmpDir, DefaultConfig()).WalkSync()
+	require.Empty(t, errs)
+	assert.Len(t, contexts, 1, "only the analyzable extensions without extra files")
+}
+
 func TestWalkerWalkSync(t *testing.T) {
 	// Create temp directory structure
 	tmpDir := t.TempDir()
@@ -301,6 +320,27 @@ func main() {
 	assert.Contains(t, ctx.GoImports, "fmt")
 }
 
+func TestWalkerParsesTypeScriptFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.tsx"), []byte("export const A = () => <div />\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# A\n"), 0644))
+
+	contexts, errs := NewWalker(tmpDir, DefaultConfig()).WalkSync()
+
+	require.Empty(t, errs)
+	require.Len(t, contexts, 2)
+	for _, ctx := range contexts {
+		if ctx.IsTypeScriptFile() {
+			require.True(t, ctx.HasTSAST())
+			assert.Empty(t, ctx.TSAST.Errors)
+			assert.Same(t, ctx.TSAST, ctx.TypeScriptAST())
+		} else {
+			assert.False(t, ctx.HasTSAST())
+			assert.Nil(t, ctx.TypeScriptAST())
+		}
+	}
+}
+
 func TestWalkerWithGoParsingDisabledLeavesGoASTForProjectLoader(t *testing.T) {
 	tmpDir := t.TempDir()
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n"), 0644))
@@ -111,6 +111,12 @@ func (f *BoolCompareFixer) tryFixPattern(file string, line int, content string,
 	}
 }
 
+// Safety reports a semantic change: the pattern matches text, so a comparison
+// inside a string literal on the same line is rewritten too.
+func (f *BoolCompareFixer) Safety() Safety {
+	return SafetySemanticChange
+}
+
 func init() {
 	DefaultRegistry.Register(NewBoolCompareFixer())
 }
@@ -67,6 +67,13 @@ func (f *DeprecatedIoutilFixer) GenerateFix(ctx *core.FileContext, v *core.Viola
 	return nil
 }
 
+// Safety reports a semantic change: most replacements are aliases, but
+// os.ReadDir returns []fs.DirEntry where ioutil.ReadDir returned []os.FileInfo,
+// and the code using the result has to follow.
+func (f *DeprecatedIoutilFixer) Safety() Safety {
+	return SafetySemanticChange
+}
+
 func init() {
 	DefaultRegistry.Register(NewDeprecatedIoutilFixer())
 }
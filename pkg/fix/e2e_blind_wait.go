@@ -0,0 +1,89 @@
+package fix
+
+import (
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// waitForResponseSkeleton replaces a fixed pause. The URL to wait for is the
+// one thing the fixer cannot know; the placeholder keeps the test failing until
+// someone writes it in.
+const waitForResponseSkeleton = "waitForResponse((response) => response.url().includes('TODO') && response.ok())"
+
+// E2EBlindWaitFixer replaces page.waitForTimeout(N) with a waitForResponse
+// skeleton marked TODO. It does not guess what the test waits for; it turns a
+// pause that hides the question into a placeholder that asks it.
+type E2EBlindWaitFixer struct{}
+
+// NewE2EBlindWaitFixer creates the fixer
+func NewE2EBlindWaitFixer() *E2EBlindWaitFixer {
+	return &E2EBlindWaitFixer{}
+}
+
+// RuleName returns the rule this fixer is for
+func (f *E2EBlindWaitFixer) RuleName() string {
+	return "e2e-blind-wait"
+}
+
+// CanFix reports whether the finding is a fixed pause; networkidle waits and
+// unawaited URL assertions need a human to name what to wait for.
+func (f *E2EBlindWaitFixer) CanFix(v *core.Violation) bool {
+	return v != nil && v.Rule == "e2e-blind-wait" && v.Context["wait"] == "timeout"
+}
+
+// GenerateFix rewrites every `.waitForTimeout(...)` call on the line and puts a
+// TODO comment above it. The line is replaced whole, because the comment and
+// the rewrite have to land together.
+func (f *E2EBlindWaitFixer) GenerateFix(ctx *core.FileContext, v *core.Violation) []*Fix {
+	if ctx == nil || !f.CanFix(v) || v.Line < 1 || v.Line > len(ctx.Lines) {
+		return nil
+	}
+	line := ctx.Lines[v.Line-1]
+	tokens := jsCodeTokens(string(ctx.Content))
+
+	rewritten := line
+	rewrites := 0
+	// Right to left, so the columns of the earlier calls stay valid.
+	for i := len(tokens) - 1; i > 0; i-- {
+		tok := tokens[i]
+		if tok.line != v.Line || !tok.isIdent("waitForTimeout") || !tokens[i-1].isPunct(".") {
+			continue
+		}
+		if i+1 >= len(tokens) || !tokens[i+1].isPunct("(") {
+			continue
+		}
+		end := closing(tokens, i+1)
+		if end < 0 || tokens[end].line != v.Line {
+			continue
+		}
+		rewritten = rewritten[:tok.col-1] + waitForResponseSkeleton + rewritten[tokens[end].endCol-1:]
+		rewrites++
+	}
+	if rewrites == 0 {
+		return nil
+	}
+
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	todo := indent + "// TODO(glint): wait for the response this step depends on, not for a fixed time"
+	return []*Fix{{
+		File:      ctx.Path,
+		StartLine: v.Line,
+		EndLine:   v.Line,
+		OldText:   line,
+		NewText:   todo + "\n" + rewritten,
+		Message:   "Replace waitForTimeout with a waitForResponse skeleton",
+		RuleName:  "e2e-blind-wait",
+		Violation: v,
+	}}
+}
+
+// Safety reports unsafe: the skeleton waits for a URL containing 'TODO' and
+// times out until someone fills it in.
+func (f *E2EBlindWaitFixer) Safety() Safety {
+	return SafetyUnsafe
+}
+
+func init() {
+	DefaultRegistry.Register(NewE2EBlindWaitFixer())
+}
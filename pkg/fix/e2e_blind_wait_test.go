@@ -0,0 +1,28 @@
+package fix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+func TestE2EBlindWaitFixerWritesTODOSkeleton(t *testing.T) {
+	source := "test('saves', async ({ page }) => {\n" +
+		"    await page.waitForTimeout(1000); // `waitForTimeout(1000)` was flaky\n" +
+		"});\n"
+	v := &core.Violation{Rule: "e2e-blind-wait", Line: 2, Context: map[string]any{"wait": "timeout"}}
+
+	got := applyToSource(t, NewE2EBlindWaitFixer(), "save.spec.ts", source, v)
+
+	assert.Equal(t, "test('saves', async ({ page }) => {\n"+
+		"    // TODO(glint): wait for the response this step depends on, not for a fixed time\n"+
+		"    await page.waitForResponse((response) => response.url().includes('TODO') && response.ok()); // `waitForTimeout(1000)` was flaky\n"+
+		"});\n", got)
+}
+
+func TestE2EBlindWaitFixerLeavesNetworkIdle(t *testing.T) {
+	v := &core.Violation{Rule: "e2e-blind-wait", Line: 1}
+	assert.False(t, NewE2EBlindWaitFixer().CanFix(v), "only the fixed pause has a mechanical replacement")
+}
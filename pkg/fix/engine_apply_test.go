@@ -67,6 +67,29 @@ func TestApplyFixesReportsFixThatDoesNotMatch(t *testing.T) {
 	assert.Equal(t, 0, results[0].FixesApplied)
 	require.Error(t, results[0].Error, "an unapplied fix must surface as an error")
 	assert.Contains(t, results[0].Error.Error(), "test-rule")
+	assert.False(t, results[0].Written, "nothing applied, nothing to write")
+}
+
+// A file where only some fixes match is written with those, and says which
+// were left out: --iterate must treat it as changed.
+func TestApplyFixesWritesPartialApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.go")
+	require.NoError(t, os.WriteFile(path, []byte("package x\nvar a = 1\n"), 0o644))
+
+	stale := &Fix{File: path, StartLine: 1, EndLine: 1, OldText: "package y", NewText: "package z", RuleName: "test-rule"}
+	results := NewEngine(NewRegistry(), false).ApplyFixes([]*Fix{
+		{File: path, StartLine: 2, EndLine: 2, OldText: "var a = 1", NewText: "var a = 2", RuleName: "test-rule"},
+		stale,
+	})
+
+	require.Len(t, results, 1)
+	require.Error(t, results[0].Error)
+	assert.Equal(t, 1, results[0].FixesApplied)
+	assert.True(t, results[0].Written)
+	assert.Equal(t, []*Fix{stale}, results[0].Unapplied)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "package x\nvar a = 2\n", string(content))
 }
 
 // Matching only the first line of a multi-line OldText allowed replacing a
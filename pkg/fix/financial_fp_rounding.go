@@ -0,0 +1,214 @@
+package fix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// FinancialFPRoundingFixer routes `Math.floor(money * 100) / 100` through the
+// money helper configured for the rule, so that cent rounding is done in one
+// place that knows about IEEE-754. Without a configured helper there is nothing
+// correct to rewrite to, and no fix is offered.
+type FinancialFPRoundingFixer struct{}
+
+// NewFinancialFPRoundingFixer creates the fixer
+func NewFinancialFPRoundingFixer() *FinancialFPRoundingFixer {
+	return &FinancialFPRoundingFixer{}
+}
+
+// RuleName returns the rule this fixer is for
+func (f *FinancialFPRoundingFixer) RuleName() string {
+	return "financial-fp-rounding"
+}
+
+// CanFix reports whether the rule recorded a money helper for the finding.
+func (f *FinancialFPRoundingFixer) CanFix(v *core.Violation) bool {
+	if v == nil || v.Rule != "financial-fp-rounding" {
+		return false
+	}
+	helper, ok := v.Context["money_helper"].(string)
+	return ok && helper != ""
+}
+
+// GenerateFix rewrites each Math.floor|ceil|trunc(x * m) / 100 on the line:
+// x * 100 becomes helper(x, 'floor'), and x * pct becomes
+// helper(x * pct / 100, 'floor'), the value the original rounds to cents.
+func (f *FinancialFPRoundingFixer) GenerateFix(ctx *core.FileContext, v *core.Violation) []*Fix {
+	if ctx == nil || !f.CanFix(v) {
+		return nil
+	}
+	helper, ok := v.Context["money_helper"].(string)
+	if !ok {
+		return nil
+	}
+	tokens := jsCodeTokens(string(ctx.Content))
+
+	var fixes []*Fix
+	for i := range tokens {
+		if tokens[i].line != v.Line {
+			continue
+		}
+		edit := f.roundingEdit(ctx, tokens, i, helper)
+		if edit == nil {
+			continue
+		}
+		edit.Message = "Round money through " + helper
+		edit.RuleName = "financial-fp-rounding"
+		edit.Violation = v
+		fixes = append(fixes, edit)
+	}
+	if len(fixes) == 0 {
+		return nil
+	}
+
+	module, _ := v.Context["money_helper_import"].(string)
+	if module == "" {
+		return fixes // the helper is a global, or imported by hand
+	}
+	importFix, ok := ensureJSImport(ctx, tokens, helper, module)
+	if !ok {
+		return nil // a call to a helper the file cannot see does not run
+	}
+	if importFix != nil {
+		importFix.RuleName = "financial-fp-rounding"
+		importFix.Violation = v
+		fixes = append(fixes, importFix)
+	}
+	return fixes
+}
+
+// roundingEdit matches Math . mode ( x * m ) / 100 at i.
+func (f *FinancialFPRoundingFixer) roundingEdit(ctx *core.FileContext, tokens []jsToken, i int, helper string) *Fix {
+	if i+3 >= len(tokens) || !tokens[i].isIdent("Math") || !tokens[i+1].isPunct(".") || !tokens[i+3].isPunct("(") {
+		return nil
+	}
+	mode := tokens[i+2].text
+	if mode != "floor" && mode != "ceil" && mode != "trunc" {
+		return nil
+	}
+	closeParen := closing(tokens, i+3)
+	if closeParen < 0 || closeParen+2 >= len(tokens) ||
+		!tokens[closeParen+1].isPunct("/") || tokens[closeParen+2].text != "100" {
+		return nil
+	}
+	// Only a division that ends the operand: Math.floor(x * 100) / 100 * y
+	// would read differently as helper(x) * y.
+	if after := closeParen + 3; after < len(tokens) && tokens[after].line == tokens[closeParen].line &&
+		(tokens[after].isPunct("*") || tokens[after].isPunct("**") || tokens[after].isPunct("/") || tokens[after].isPunct("%")) {
+		return nil
+	}
+
+	star := -1
+	for j := i + 4; j < closeParen; j++ {
+		if tokens[j].kind != jsPunct {
+			continue
+		}
+		switch tokens[j].text {
+		case "(", "[", "{":
+			j = closing(tokens, j)
+			if j < 0 {
+				return nil
+			}
+		case "*":
+			star = j
+		case "+", "-", "?", ":", "||", "&&", "??", ",", "==", "===", "!=", "!==", "<", ">", "<=", ">=", "&", "|", "^", "<<", ">>", ">>>":
+			return nil // the product is not the whole argument
+		}
+	}
+	if star < 0 || star == i+4 || star+1 >= closeParen {
+		return nil
+	}
+
+	value, ok := sourceBetween(ctx.Lines, tokens, i+4, star-1)
+	if !ok {
+		return nil
+	}
+	multiplier, ok := sourceBetween(ctx.Lines, tokens, star+1, closeParen-1)
+	if !ok {
+		return nil
+	}
+	if multiplier != "100" {
+		value = fmt.Sprintf("%s * %s / 100", value, multiplier)
+	}
+	return tokenEdit(ctx.Path, ctx.Lines, tokens, i, closeParen+2, fmt.Sprintf("%s(%s, '%s')", helper, value, mode))
+}
+
+// Safety reports a semantic change: the helper rounds differently from the
+// float arithmetic it replaces, by the cent the rule is about.
+func (f *FinancialFPRoundingFixer) Safety() Safety {
+	return SafetySemanticChange
+}
+
+// ensureJSImport returns the edit that imports name from module in a
+// TypeScript or JavaScript file. (nil, true) means the name is imported
+// already; ok=false means the file is CommonJS or its imports cannot be found,
+// and the caller must drop its rewrite.
+func ensureJSImport(ctx *core.FileContext, tokens []jsToken, name, module string) (*Fix, bool) {
+	lastImportLine := 0
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].isIdent("import") || (i > 0 && tokens[i-1].line == tokens[i].line) {
+			continue
+		}
+		// An import declaration ends with its module string: after `from`,
+		// or right after `import` for a side-effect import.
+		end := i + 1
+		for end < len(tokens) && tokens[end].kind != jsString {
+			if tokens[end].isIdent(name) {
+				return nil, true
+			}
+			if tokens[end].isPunct(";") || tokens[end].isPunct("(") {
+				break // import() or a statement this loop does not understand
+			}
+			end++
+		}
+		if end < len(tokens) && tokens[end].kind == jsString {
+			if end+1 < len(tokens) && tokens[end+1].isPunct(";") {
+				end++
+			}
+			lastImportLine = tokens[end].endLine
+			i = end
+		}
+	}
+
+	if lastImportLine == 0 {
+		if ctx.IsJavaScriptFile() && !strings.HasSuffix(ctx.Path, ".mjs") && !strings.HasSuffix(ctx.Path, ".jsx") {
+			return nil, false // a .js file without imports may well be CommonJS
+		}
+		lastImportLine = directivePrologueEnd(tokens)
+	}
+	if lastImportLine >= len(ctx.Lines) {
+		return nil, false
+	}
+
+	// Inserted before the line after the imports, the shape Select knows
+	// composes with other insertions.
+	next := ctx.Lines[lastImportLine]
+	return &Fix{
+		File:      ctx.Path,
+		StartLine: lastImportLine + 1,
+		EndLine:   lastImportLine + 1,
+		OldText:   next,
+		NewText:   fmt.Sprintf("import { %s } from '%s';\n%s", name, module, next),
+		Message:   "Import " + name + " from " + module,
+	}, true
+}
+
+// directivePrologueEnd returns the last line of the leading 'use client' or
+// 'use strict' directives, which must stay first in the file; 0 without any.
+func directivePrologueEnd(tokens []jsToken) int {
+	end := 0
+	for i := 0; i < len(tokens) && tokens[i].kind == jsString; i++ {
+		end = tokens[i].endLine
+		if i+1 < len(tokens) && tokens[i+1].isPunct(";") {
+			i++
+			end = tokens[i].endLine
+		}
+	}
+	return end
+}
+
+func init() {
+	DefaultRegistry.Register(NewFinancialFPRoundingFixer())
+}
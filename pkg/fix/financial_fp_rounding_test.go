@@ -0,0 +1,68 @@
+package fix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+func roundingViolation(line int, module string) *core.Violation {
+	return &core.Violation{Rule: "financial-fp-rounding", Line: line, Context: map[string]any{
+		"money_helper": "roundMoney", "money_helper_import": module,
+	}}
+}
+
+func TestFinancialFPRoundingFixerRoutesThroughHelper(t *testing.T) {
+	source := "'use client';\n" +
+		"import { api } from './api';\n" +
+		"\n" +
+		"const max = Math.floor(balance * 100) / 100 + Math.ceil(amount * pct) / 100; // Math.floor(x * 100) / 100\n"
+
+	got := applyToSource(t, NewFinancialFPRoundingFixer(), "withdraw.ts", source, roundingViolation(4, "@/lib/money"))
+
+	assert.Equal(t, "'use client';\n"+
+		"import { api } from './api';\n"+
+		"import { roundMoney } from '@/lib/money';\n"+
+		"\n"+
+		"const max = roundMoney(balance, 'floor') + roundMoney(amount * pct / 100, 'ceil'); // Math.floor(x * 100) / 100\n", got)
+}
+
+func TestFinancialFPRoundingFixerLeavesSumsAndMissingHelper(t *testing.T) {
+	source := "const v = Math.floor(fee + amount * 100) / 100;\n"
+	ctx, err := core.NewFileContextChecked("/project/v.ts", "/project", []byte(source), core.DefaultConfig())
+	require.NoError(t, err)
+
+	fixer := NewFinancialFPRoundingFixer()
+	assert.Empty(t, fixer.GenerateFix(ctx, roundingViolation(1, "")), "the product is not the whole argument")
+	assert.False(t, fixer.CanFix(&core.Violation{Rule: "financial-fp-rounding", Line: 1}),
+		"without a configured helper there is nothing to route through")
+}
+
+// Two identical roundings on one line are two edits at different columns;
+// deduplication must keep both.
+func TestFinancialFPRoundingFixerRewritesIdenticalRoundingsOnOneLine(t *testing.T) {
+	source := "const spread = Math.floor(balance * 100) / 100 - Math.floor(balance * 100) / 100;\n"
+	dir := t.TempDir()
+	ctx, err := core.NewFileContextChecked(filepath.Join(dir, "spread.ts"), dir, []byte(source), core.DefaultConfig())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(ctx.Path, []byte(source), 0o644))
+
+	v := roundingViolation(1, "")
+	v.File = ctx.Path
+	engine := NewEngine(DefaultRegistry, false)
+	fixes := engine.GenerateFixes([]*core.Violation{v}, map[string]*core.FileContext{ctx.Path: ctx})
+	require.Len(t, fixes, 2)
+
+	results := engine.ApplyFixes(fixes)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+	assert.Equal(t, 2, results[0].FixesApplied)
+	fixed, err := os.ReadFile(ctx.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "const spread = roundMoney(balance, 'floor') - roundMoney(balance, 'floor');\n", string(fixed))
+}
@@ -24,6 +24,10 @@ type Fixer interface {
 	// A fix often needs more than one edit: rewriting a call also means adding
 	// the import it now needs.
 	GenerateFix(ctx *core.FileContext, v *core.Violation) []*Fix
+
+	// Safety returns how much the fixes of this fixer may change behavior.
+	// Only safe fixes are applied unless the user asks for more.
+	Safety() Safety
 }
 
 // Fix represents a single code fix
@@ -37,17 +41,22 @@ type Fix struct {
 	NewText   string // Replacement text
 	Message   string // Description of the fix
 	RuleName  string // Rule that triggered this fix
+	Safety    Safety // At least the level of the fixer that generated it
 	Violation *core.Violation
 }
 
 // fixedFilePermissions is the mode used when a fixed file has to be created.
 const fixedFilePermissions = 0o644
 
-// Result represents the outcome of applying fixes to one file.
+// Result represents the outcome of applying fixes to one file. A file can be
+// written with an error: the fixes that matched are applied, those that no
+// longer did are in Unapplied.
 type Result struct {
 	File         string
 	FixesApplied int
 	Fixes        []*Fix
+	Unapplied    []*Fix
+	Written      bool
 	Error        error
 }
 
@@ -156,6 +165,9 @@ func (e *Engine) GenerateFixes(violations []*core.Violation, contexts map[string
 
 		for _, fix := range fixer.GenerateFix(ctx, v) {
 			if fix != nil {
+				// A fixer may rate a single fix riskier than itself, never
+				// safer.
+				fix.Safety = max(fix.Safety, fixer.Safety())
 				fixes = append(fixes, fix)
 			}
 		}
@@ -166,17 +178,19 @@ func (e *Engine) GenerateFixes(violations []*core.Violation, contexts map[string
 
 // dedupeFixes drops textually identical edits. Several violations in one file
 // legitimately generate the same import edit; applying it twice used to leave
-// the file uncompilable.
+// the file uncompilable. The columns are part of the edit: two math.Max(a, b)
+// calls on one line are two rewrites, not one.
 func dedupeFixes(fixes []*Fix) []*Fix {
 	type editKey struct {
 		file               string
 		startLine, endLine int
+		startCol, endCol   int
 		oldText, newText   string
 	}
 	seen := make(map[editKey]bool, len(fixes))
 	deduped := fixes[:0]
 	for _, fix := range fixes {
-		key := editKey{fix.File, fix.StartLine, fix.EndLine, fix.OldText, fix.NewText}
+		key := editKey{fix.File, fix.StartLine, fix.EndLine, fix.StartCol, fix.EndCol, fix.OldText, fix.NewText}
 		if seen[key] {
 			continue
 		}
@@ -230,30 +244,45 @@ func (e *Engine) applyToFile(file string, fixes []*Fix) Result {
 	// Apply from the bottom up, so that earlier fixes keep their line numbers.
 	sortedFixes := make([]*Fix, len(fixes))
 	copy(sortedFixes, fixes)
+	// Column edits on one line go right to left for the same reason.
 	sort.SliceStable(sortedFixes, func(i, j int) bool {
-		return sortedFixes[i].StartLine > sortedFixes[j].StartLine
+		if sortedFixes[i].StartLine != sortedFixes[j].StartLine {
+			return sortedFixes[i].StartLine > sortedFixes[j].StartLine
+		}
+		return sortedFixes[i].StartCol > sortedFixes[j].StartCol
 	})
 
 	// Apply each fix. A fix that does not match the file anymore is collected
 	// and reported: silently dropping it made "Applied N fixes" unverifiable.
-	var unapplied []*Fix
 	for _, fix := range sortedFixes {
 		if e.applyFix(fix, &lines) {
 			result.FixesApplied++
 		} else {
-			unapplied = append(unapplied, fix)
+			result.Unapplied = append(result.Unapplied, fix)
 		}
 	}
-	if len(unapplied) > 0 {
-		names := make([]string, 0, len(unapplied))
-		for _, fix := range unapplied {
+	if len(result.Unapplied) > 0 {
+		names := make([]string, 0, len(result.Unapplied))
+		for _, fix := range result.Unapplied {
 			names = append(names, fmt.Sprintf("%s (line %d)", fix.RuleName, fix.StartLine))
 		}
 		result.Error = fmt.Errorf("%d fix(es) no longer match the file and were not applied: %s",
-			len(unapplied), strings.Join(names, ", "))
+			len(result.Unapplied), strings.Join(names, ", "))
 	}
 
-	if e.dryRun {
+	if strings.HasSuffix(file, ".go") && result.FixesApplied > 0 {
+		tidied, err := tidyImports(string(content), lines)
+		if err != nil {
+			// The edits left the file unparsable: writing it would turn a
+			// lint finding into a build failure.
+			result.FixesApplied = 0
+			result.Error = fmt.Errorf("tidy imports: %w", err)
+			return result
+		}
+		lines = tidied
+	}
+
+	if e.dryRun || result.FixesApplied == 0 {
 		return result
 	}
 
@@ -263,6 +292,7 @@ func (e *Engine) applyToFile(file string, fixes []*Fix) Result {
 		result.Error = fmt.Errorf("write file: %w", err)
 		return result
 	}
+	result.Written = true
 
 	return result
 }
@@ -0,0 +1,129 @@
+package fix
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// FrontendEnvFallbackFixer removes the fallback after a required public
+// environment variable and throws when the variable is missing, so that a
+// build without it fails instead of talking to http://localhost.
+type FrontendEnvFallbackFixer struct{}
+
+// NewFrontendEnvFallbackFixer creates the fixer
+func NewFrontendEnvFallbackFixer() *FrontendEnvFallbackFixer {
+	return &FrontendEnvFallbackFixer{}
+}
+
+// RuleName returns the rule this fixer is for
+func (f *FrontendEnvFallbackFixer) RuleName() string {
+	return "frontend-env-fallback"
+}
+
+// CanFix reports whether the finding is a fallback on a named variable; the
+// placeholder and bracket-access findings have no mechanical fix.
+func (f *FrontendEnvFallbackFixer) CanFix(v *core.Violation) bool {
+	if v == nil || v.Rule != "frontend-env-fallback" {
+		return false
+	}
+	name, ok := v.Context["env_var"].(string)
+	return ok && name != ""
+}
+
+// GenerateFix replaces `|| 'fallback'` after process.env.NAME with a throw.
+// Only a fallback that is a single literal or name is removed: anything longer
+// may be an expression the line goes on to use.
+func (f *FrontendEnvFallbackFixer) GenerateFix(ctx *core.FileContext, v *core.Violation) []*Fix {
+	if ctx == nil || !f.CanFix(v) {
+		return nil
+	}
+	name, ok := v.Context["env_var"].(string)
+	if !ok {
+		return nil
+	}
+	tokens := jsCodeTokens(string(ctx.Content))
+
+	for i := range tokens {
+		if tokens[i].line != v.Line {
+			continue
+		}
+		access, ok := envAccessEnd(tokens, i, name)
+		if !ok || access+2 >= len(tokens) {
+			continue
+		}
+		op := tokens[access+1]
+		if !op.isPunct("||") && !op.isPunct("??") {
+			continue
+		}
+		end, err := simpleOperandEnd(tokens, access+2)
+		if err != nil {
+			// Expected for a longer fallback: it is left for a person to rewrite.
+			continue
+		}
+		throw := fmt.Sprintf("|| (() => { throw new Error('%s is not set') })()", name)
+		edit := tokenEdit(ctx.Path, ctx.Lines, tokens, access+1, end, throw)
+		if edit == nil {
+			return nil
+		}
+		edit.Message = "Throw when " + name + " is missing instead of falling back"
+		edit.RuleName = "frontend-env-fallback"
+		edit.Violation = v
+		return []*Fix{edit}
+	}
+	return nil
+}
+
+// envAccessEnd matches process.env.NAME or process.env['NAME'] starting at i
+// and returns the index of its last token.
+func envAccessEnd(tokens []jsToken, i int, name string) (int, bool) {
+	if i+4 >= len(tokens) || !tokens[i].isIdent("process") || !tokens[i+1].isPunct(".") || !tokens[i+2].isIdent("env") {
+		return 0, false
+	}
+	switch {
+	case tokens[i+3].isPunct(".") && tokens[i+4].isIdent(name):
+		return i + 4, true
+	case tokens[i+3].isPunct("[") && i+5 < len(tokens) && tokens[i+4].kind == jsString &&
+		len(tokens[i+4].text) >= 2 && tokens[i+4].text[1:len(tokens[i+4].text)-1] == name && tokens[i+5].isPunct("]"):
+		return i + 5, true
+	}
+	return 0, false
+}
+
+// errComplexOperand reports a fallback the fixer leaves alone because it is
+// more than one literal or name.
+var errComplexOperand = errors.New("operand is not a single literal or name")
+
+// simpleOperandEnd returns the last token of an operand that is one literal
+// or a dotted name, followed by the end of the expression.
+func simpleOperandEnd(tokens []jsToken, start int) (int, error) {
+	end := start
+	switch tokens[start].kind {
+	case jsString, jsTemplate, jsNumber:
+	case jsIdent:
+		for end+2 < len(tokens) && tokens[end+1].isPunct(".") && tokens[end+2].kind == jsIdent {
+			end += 2
+		}
+	default:
+		return 0, fmt.Errorf("%w: starts with %q", errComplexOperand, tokens[start].text)
+	}
+	if end+1 < len(tokens) && tokens[end+1].line == tokens[end].endLine {
+		switch tokens[end+1].text {
+		case ")", ",", ";", "]", "}":
+		default:
+			return 0, fmt.Errorf("%w: continues with %q", errComplexOperand, tokens[end+1].text)
+		}
+	}
+	return end, nil
+}
+
+// Safety reports a semantic change: code that ran with the fallback now throws
+// where the variable is missing — which is the point, but a change.
+func (f *FrontendEnvFallbackFixer) Safety() Safety {
+	return SafetySemanticChange
+}
+
+func init() {
+	DefaultRegistry.Register(NewFrontendEnvFallbackFixer())
+}
@@ -0,0 +1,68 @@
+package fix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// applyToSource writes source to a file named name, applies the fixes the
+// fixer generates for v and returns the file afterwards.
+func applyToSource(t *testing.T, fixer Fixer, name, source string, v *core.Violation) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	ctx, err := core.NewFileContextChecked(path, dir, []byte(source), core.DefaultConfig())
+	require.NoError(t, err)
+
+	fixes := fixer.GenerateFix(ctx, v)
+	require.NotEmpty(t, fixes, "the fixer must offer a fix")
+	results := NewEngine(NewRegistry(), false).ApplyFixes(fixes)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func envFallbackViolation(line int, name string) *core.Violation {
+	return &core.Violation{Rule: "frontend-env-fallback", Line: line, Context: map[string]any{"env_var": name}}
+}
+
+func TestFrontendEnvFallbackFixerThrowsInsteadOfFallingBack(t *testing.T) {
+	source := "// falls back to || 'http://localhost' when unset\n" +
+		"const url = process.env.NEXT_PUBLIC_SUPABASE_URL || 'http://localhost'; // `|| x`\n"
+
+	got := applyToSource(t, NewFrontendEnvFallbackFixer(), "client.ts", source,
+		envFallbackViolation(2, "NEXT_PUBLIC_SUPABASE_URL"))
+
+	assert.Equal(t, "// falls back to || 'http://localhost' when unset\n"+
+		"const url = process.env.NEXT_PUBLIC_SUPABASE_URL || (() => { throw new Error('NEXT_PUBLIC_SUPABASE_URL is not set') })(); // `|| x`\n",
+		got, "only the code is edited; the comments keep their text")
+}
+
+func TestFrontendEnvFallbackFixerHandlesBracketAccess(t *testing.T) {
+	source := "createClient(process.env['NEXT_PUBLIC_SUPABASE_ANON_KEY'] ?? `anon`, opts)\n"
+
+	got := applyToSource(t, NewFrontendEnvFallbackFixer(), "client.ts", source,
+		envFallbackViolation(1, "NEXT_PUBLIC_SUPABASE_ANON_KEY"))
+
+	assert.Equal(t, "createClient(process.env['NEXT_PUBLIC_SUPABASE_ANON_KEY'] || (() => { throw new Error('NEXT_PUBLIC_SUPABASE_ANON_KEY is not set') })(), opts)\n", got)
+}
+
+// A fallback that goes on past one operand is an expression the fixer cannot
+// cut without changing what the rest of the line computes.
+func TestFrontendEnvFallbackFixerLeavesCompoundFallbacks(t *testing.T) {
+	source := "const url = process.env.NEXT_PUBLIC_SUPABASE_URL || 'http://' + host;\n"
+	ctx, err := core.NewFileContextChecked("/project/client.ts", "/project", []byte(source), core.DefaultConfig())
+	require.NoError(t, err)
+
+	assert.Empty(t, NewFrontendEnvFallbackFixer().GenerateFix(ctx, envFallbackViolation(1, "NEXT_PUBLIC_SUPABASE_URL")))
+}
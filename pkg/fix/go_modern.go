@@ -0,0 +1,172 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"go/version"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// GoModernFixer applies the rewrite a go-modern finding carries. The rule
+// proves each rewrite equivalent with type information and records the exact
+// replacement, its range and the Go version it needs; the fixer only checks
+// that the module may use that version and that the source still reads the
+// same.
+type GoModernFixer struct{}
+
+// NewGoModernFixer creates the fixer
+func NewGoModernFixer() *GoModernFixer {
+	return &GoModernFixer{}
+}
+
+// RuleName returns the rule this fixer is for
+func (f *GoModernFixer) RuleName() string {
+	return "go-modern"
+}
+
+// CanFix reports whether the finding carries a replacement. The deprecated
+// reflect headers have none: unsafe.Slice is a redesign, not a rewrite.
+func (f *GoModernFixer) CanFix(v *core.Violation) bool {
+	if v == nil || v.Rule != "go-modern" {
+		return false
+	}
+	replacement, ok := v.Context["replacement"].(string)
+	return ok && replacement != ""
+}
+
+// GenerateFix replaces the reported range with the replacement and adds the
+// imports it needs. Nothing is emitted when the module's go directive is older
+// than the feature or cannot be read.
+func (f *GoModernFixer) GenerateFix(ctx *core.FileContext, v *core.Violation) []*Fix {
+	if ctx == nil || !f.CanFix(v) || v.Line < 1 || v.Column < 1 {
+		return nil
+	}
+	minGo, _ := v.Context["min_go"].(string)
+	if !moduleAllows(ctx.Path, minGo) {
+		return nil
+	}
+	endLine, ok := contextInt(v, "end_line")
+	if !ok || endLine < v.Line || endLine > len(ctx.Lines) {
+		return nil
+	}
+	endCol, ok := contextInt(v, "end_column")
+	if !ok || endCol < 1 {
+		return nil
+	}
+	replacement, ok := v.Context["replacement"].(string)
+	if !ok {
+		return nil
+	}
+
+	edit := rangeEdit(ctx, v.Line, v.Column, endLine, endCol, replacement)
+	if edit == nil {
+		return nil
+	}
+	edit.Message = "Replace with " + strings.SplitN(replacement, "\n", 2)[0]
+	edit.RuleName = "go-modern"
+	edit.Violation = v
+	fixes := []*Fix{edit}
+
+	imports, _ := v.Context["imports"].([]string)
+	importFix, ok := ensureImports(ctx, imports...)
+	if !ok {
+		return nil // rewriting the code without its imports breaks the build
+	}
+	if importFix != nil {
+		importFix.RuleName = "go-modern"
+		importFix.Violation = v
+		fixes = append(fixes, importFix)
+	}
+	return fixes
+}
+
+// rangeEdit builds the edit replacing the text between two 1-based byte
+// positions. A range within one line is a column edit; a range over several
+// lines replaces those lines whole, keeping what precedes the start and
+// follows the end.
+func rangeEdit(ctx *core.FileContext, startLine, startCol, endLine, endCol int, replacement string) *Fix {
+	first := ctx.Lines[startLine-1]
+	last := ctx.Lines[endLine-1]
+	if startCol-1 > len(first) || endCol-1 > len(last) {
+		return nil
+	}
+	if startLine == endLine {
+		if endCol < startCol {
+			return nil
+		}
+		return &Fix{
+			File:      ctx.Path,
+			StartLine: startLine,
+			EndLine:   startLine,
+			StartCol:  startCol,
+			EndCol:    endCol,
+			OldText:   first[startCol-1 : endCol-1],
+			NewText:   replacement,
+		}
+	}
+	return &Fix{
+		File:      ctx.Path,
+		StartLine: startLine,
+		EndLine:   endLine,
+		OldText:   strings.Join(ctx.Lines[startLine-1:endLine], "\n"),
+		NewText:   first[:startCol-1] + replacement + last[endCol-1:],
+	}
+}
+
+func contextInt(v *core.Violation, key string) (int, bool) {
+	switch value := v.Context[key].(type) {
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	}
+	return 0, false
+}
+
+// moduleAllows reports whether the go directive of the module owning path is
+// at least minGo. A module without a readable directive allows nothing: the
+// fixer must never emit a feature newer than the module's language version.
+func moduleAllows(path, minGo string) bool {
+	if minGo == "" {
+		return false
+	}
+	goVersion, ok := moduleGoVersion(path)
+	return ok && version.Compare("go"+goVersion, "go"+minGo) >= 0
+}
+
+// moduleGoVersion reads the go directive of the nearest go.mod above path.
+func moduleGoVersion(path string) (string, bool) {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			return goDirective(content)
+		}
+		if filepath.Dir(dir) == dir {
+			return "", false
+		}
+	}
+}
+
+func goDirective(goMod []byte) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(goMod))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "go" {
+			return fields[1], version.IsValid("go" + fields[1])
+		}
+	}
+	return "", false
+}
+
+// Safety reports safe: the rule proves every rewrite it records equivalent.
+func (f *GoModernFixer) Safety() Safety {
+	return SafetySafe
+}
+
+func init() {
+	DefaultRegistry.Register(NewGoModernFixer())
+}
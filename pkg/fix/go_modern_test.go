@@ -0,0 +1,187 @@
+package fix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// goModernModule writes a module with the given go directive and one source
+// file, and returns the file's context.
+func goModernModule(t *testing.T, goVersion, source string) *core.FileContext {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n\ngo "+goVersion+"\n"), 0o644))
+	path := filepath.Join(root, "m.go")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	ctx, err := core.NewFileContextChecked(path, root, []byte(source), core.DefaultConfig())
+	require.NoError(t, err)
+	return ctx
+}
+
+func goModernViolation(line, col, endLine, endCol int, minGo, replacement string, imports ...string) *core.Violation {
+	v := &core.Violation{Rule: "go-modern", File: "m.go", Line: line, Column: col}
+	v.WithContext("replacement", replacement)
+	v.WithContext("min_go", minGo)
+	v.WithContext("end_line", endLine)
+	v.WithContext("end_column", endCol)
+	if len(imports) > 0 {
+		v.WithContext("imports", imports)
+	}
+	return v
+}
+
+const sortSliceSource = `package m
+
+import (
+	"sort"
+)
+
+func Sorted(ids []int) []int {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+`
+
+// The multi-line call is replaced whole, slices is imported and sort, which
+// lost its only use, is dropped so the file still compiles.
+func TestGoModernFixerRewritesSortSlice(t *testing.T) {
+	ctx := goModernModule(t, "1.21", sortSliceSource)
+	v := goModernViolation(8, 2, 10, 4, "1.21", "slices.Sort(ids)", "slices")
+
+	fixes := NewGoModernFixer().GenerateFix(ctx, v)
+	require.Len(t, fixes, 2)
+	assert.Equal(t, "\tslices.Sort(ids)", fixes[0].NewText)
+
+	results := NewEngine(NewRegistry(), false).ApplyFixes(fixes)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+
+	fixed, err := os.ReadFile(ctx.Path)
+	require.NoError(t, err)
+	assert.Equal(t, `package m
+
+import (
+	"slices"
+)
+
+func Sorted(ids []int) []int {
+	slices.Sort(ids)
+	return ids
+}
+`, string(fixed))
+}
+
+func TestGoModernFixerRespectsGoDirective(t *testing.T) {
+	ctx := goModernModule(t, "1.20", sortSliceSource)
+	v := goModernViolation(8, 2, 10, 4, "1.21", "slices.Sort(ids)", "slices")
+
+	assert.Empty(t, NewGoModernFixer().GenerateFix(ctx, v), "slices needs Go 1.21")
+}
+
+func TestGoModernFixerSingleLineColumnEdit(t *testing.T) {
+	ctx := goModernModule(t, "1.22", `package m
+
+func Count(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += i
+	}
+	return total
+}
+`)
+	v := goModernViolation(5, 2, 5, 26, "1.22", "for i := range n {")
+
+	fixes := NewGoModernFixer().GenerateFix(ctx, v)
+	require.Len(t, fixes, 1, "range over int needs no import")
+	assert.Equal(t, "for i := 0; i < n; i++ {", fixes[0].OldText)
+	assert.Equal(t, 2, fixes[0].StartCol)
+	assert.Equal(t, 26, fixes[0].EndCol)
+}
+
+// Without a replacement (deprecated reflect headers) there is nothing to apply.
+func TestGoModernFixerMetadata(t *testing.T) {
+	fixer := NewGoModernFixer()
+	assert.Equal(t, "go-modern", fixer.RuleName())
+	assert.True(t, fixer.CanFix(goModernViolation(1, 1, 1, 2, "1.21", "max(a, b)")))
+	assert.False(t, fixer.CanFix(&core.Violation{Rule: "go-modern", Context: map[string]any{"pattern": "deprecated-reflect"}}))
+	assert.False(t, fixer.CanFix(nil))
+}
+
+func TestTidyImportsKeepsImportsStillInUse(t *testing.T) {
+	original := "package m\n\nimport (\n\t\"fmt\"\n\t\"sort\"\n)\n\nfunc f(x []int) { sort.Ints(x); sort.Ints(x); fmt.Println(x) }\n"
+	fixed := "package m\n\nimport (\n\t\"fmt\"\n\t\"sort\"\n)\n\nfunc f(x []int) { slices.Sort(x); sort.Ints(x); fmt.Println(x) }\n"
+
+	lines, err := tidyImports(original, strings.Split(fixed, "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Split(fixed, "\n"), lines)
+}
+
+// Two findings in one file each add slices on their own, before different
+// lines; the file must still end up with one sorted import group.
+func TestTidyImportsMergesIndependentImportEdits(t *testing.T) {
+	original := "package m\n\nimport (\n\t\"math\"\n\t\"sort\"\n)\n\nvar _ = math.Pi\nvar _ = sort.Ints\n"
+	fixed := "package m\n\nimport (\n\t\"maps\"\n\t\"slices\"\n\t\"math\"\n\t\"slices\"\n\t\"sort\"\n)\n\nvar _ = math.Pi\nvar _ = slices.Sort[[]int]\nvar _ = maps.Keys[map[int]int]\n"
+
+	lines, err := tidyImports(original, strings.Split(fixed, "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "package m\n\nimport (\n\t\"maps\"\n\t\"math\"\n\t\"slices\"\n)\n\nvar _ = math.Pi\nvar _ = slices.Sort[[]int]\nvar _ = maps.Keys[map[int]int]\n",
+		strings.Join(lines, "\n"))
+}
+
+// An edit that leaves the file unparsable is reported and nothing is written.
+func TestTidyImportsReportsUnparsableResult(t *testing.T) {
+	original := "package m\n\nfunc f() {}\n"
+
+	_, err := tidyImports(original, strings.Split("package m\n\nfunc f() {\n", "\n"))
+	require.Error(t, err)
+	_, err = tidyImports("package m\n\nfunc f( {}\n", strings.Split(original, "\n"))
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "m.go")
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o644))
+	results := NewEngine(NewRegistry(), false).ApplyFixes([]*Fix{{
+		File: path, StartLine: 3, EndLine: 3, StartCol: 10, EndCol: 12, OldText: "{}", NewText: "{", RuleName: "test",
+	}})
+	require.Len(t, results, 1)
+	require.Error(t, results[0].Error)
+	assert.Zero(t, results[0].FixesApplied)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(content))
+}
+
+// Two identical calls on one line are two findings with two column edits; the
+// engine must apply both, or the next run reports the line again.
+func TestGoModernFixerRewritesIdenticalCallsOnOneLine(t *testing.T) {
+	ctx := goModernModule(t, "1.21", `package m
+
+import "math"
+
+func Span(a, b float64) float64 {
+	return math.Max(a, b) - math.Max(a, b)
+}
+`)
+	first := goModernViolation(6, 9, 6, 23, "1.21", "max(a, b)")
+	second := goModernViolation(6, 26, 6, 40, "1.21", "max(a, b)")
+
+	engine := NewEngine(DefaultRegistry, false)
+	fixes := engine.GenerateFixes([]*core.Violation{first, second}, map[string]*core.FileContext{"m.go": ctx})
+	require.Len(t, fixes, 2)
+
+	results := engine.ApplyFixes(fixes)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+	assert.Equal(t, 2, results[0].FixesApplied)
+	fixed, err := os.ReadFile(ctx.Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(fixed), "\treturn max(a, b) - max(a, b)\n")
+}
@@ -2,6 +2,11 @@ package fix
 
 import (
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/aiseeq/glint/pkg/core"
@@ -83,3 +88,152 @@ func importInsertionLine(ctx *core.FileContext, first string) (int, bool) {
 	}
 	return 0, false
 }
+
+// tidyImports repairs what independent import edits do to one file. Each fix
+// adds the imports it needs on its own, so two fixes can add the same package
+// twice, add it where the group is no longer sorted, remove the last use of
+// another import (sort.Slice becoming slices.Sort), or add one whose rewrite
+// was left out as a conflict — and a duplicate or unused import does not
+// compile. Imports the original file already left unused are the compiler's
+// to report. A file that does not parse, before or after the fixes, is an
+// error: the fixed lines must not be written blind.
+func tidyImports(original string, lines []string) ([]string, error) {
+	before, err := parser.ParseFile(token.NewFileSet(), "", original, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse original file: %w", err)
+	}
+	// Applied edits may hold several lines in one element; the positions the
+	// parser reports count real lines.
+	fixed := strings.Join(lines, "\n")
+	fset := token.NewFileSet()
+	after, err := parser.ParseFile(fset, "", fixed, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse fixed file: %w", err)
+	}
+	lines = strings.Split(fixed, "\n")
+
+	usedBefore := usedPackageNames(before)
+	usedAfter := usedPackageNames(after)
+	importedBefore := make(map[string]bool, len(before.Imports))
+	for _, spec := range before.Imports {
+		importedBefore[importSpecText(spec)] = true
+	}
+	seen := make(map[string]bool)
+	drop := make(map[int]bool)
+	for _, spec := range after.Imports {
+		// Only a line holding nothing but this import is dropped; anything
+		// else on it (a comment, a second spec) is left for a human.
+		text := importSpecText(spec)
+		line := fset.Position(spec.Pos()).Line
+		if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[line-1]), "import ")) != text {
+			continue
+		}
+		name, err := importName(spec)
+		if err != nil {
+			return nil, err
+		}
+		if seen[text] || (name != "" && !usedAfter[name] && (usedBefore[name] || !importedBefore[text])) {
+			drop[line] = true
+		}
+		seen[text] = true
+	}
+
+	kept := make([]string, 0, len(lines)-len(drop))
+	for i, line := range lines {
+		if !drop[i+1] {
+			kept = append(kept, line)
+		}
+	}
+	return sortImportRuns(kept), nil
+}
+
+// importSpecText returns an import spec as gofmt writes it on its own line.
+func importSpecText(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name + " " + spec.Path.Value
+	}
+	return spec.Path.Value
+}
+
+// sortImportRuns sorts each run of plain import lines inside an import group
+// by path, the order gofmt keeps. A run ends at a blank line, a comment or
+// anything that is not a single spec, exactly where gofmt stops sorting too.
+func sortImportRuns(lines []string) []string {
+	inGroup := false
+	runStart := -1
+	flush := func(end int) {
+		if runStart >= 0 && end-runStart > 1 {
+			run := lines[runStart:end]
+			sort.SliceStable(run, func(i, j int) bool { return importLinePath(run[i]) < importLinePath(run[j]) })
+		}
+		runStart = -1
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "import (":
+			inGroup = true
+		case !inGroup:
+			continue
+		case trimmed == ")":
+			flush(i)
+			inGroup = false
+		case importLinePath(line) != "":
+			if runStart < 0 {
+				runStart = i
+			}
+		default:
+			flush(i)
+		}
+	}
+	return lines
+}
+
+// importLinePath returns the quoted path of a line holding one import spec and
+// nothing else, "" for any other line.
+func importLinePath(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || len(fields) > 2 {
+		return ""
+	}
+	path := fields[len(fields)-1]
+	if len(path) < 2 || path[0] != '"' || path[len(path)-1] != '"' {
+		return ""
+	}
+	return path
+}
+
+// importName returns the name an import is referred to by: its explicit name,
+// or the last element of its path. Blank, dot and cgo imports have no name to
+// look for.
+func importName(spec *ast.ImportSpec) (string, error) {
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return "", fmt.Errorf("import path %s: %w", spec.Path.Value, err)
+	}
+	if path == "C" {
+		return "", nil
+	}
+	if spec.Name != nil {
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return "", nil
+		}
+		return spec.Name.Name, nil
+	}
+	return path[strings.LastIndex(path, "/")+1:], nil
+}
+
+// usedPackageNames collects the identifiers used as the left side of a
+// selector — every pkg in pkg.Name.
+func usedPackageNames(file *ast.File) map[string]bool {
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return used
+}
@@ -62,6 +62,12 @@ func (f *InterfaceAnyFixer) GenerateFix(ctx *core.FileContext, v *core.Violation
 	}}
 }
 
+// Safety reports safe: any is an alias of interface{}, so nothing changes but
+// the spelling.
+func (f *InterfaceAnyFixer) Safety() Safety {
+	return SafetySafe
+}
+
 func init() {
 	// Register with default registry when package is imported
 	DefaultRegistry.Register(NewInterfaceAnyFixer())
@@ -0,0 +1,347 @@
+package fix
+
+import (
+	"strings"
+)
+
+// jsTokenKind classifies the tokens the TypeScript/JavaScript fixers match on.
+type jsTokenKind int
+
+const (
+	jsIdent jsTokenKind = iota
+	jsNumber
+	jsString   // '...' or "..."
+	jsTemplate // `...`, substitutions included
+	jsRegex
+	jsComment
+	jsPunct
+)
+
+// jsToken is one token with its 1-based position: Col is the byte column of
+// its first byte, EndCol the column just past its last byte on EndLine.
+type jsToken struct {
+	kind    jsTokenKind
+	text    string
+	line    int
+	col     int
+	endLine int
+	endCol  int
+}
+
+// jsPunctuators are the multi-byte operators the fixers need to see whole,
+// longest first.
+var jsPunctuators = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=",
+	"*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+// tokenizeJS splits a TypeScript or JavaScript source into tokens. It is no
+// parser: it only knows where strings, template literals, comments and regular
+// expressions begin and end, which is what an edit needs to stay out of them.
+// Unterminated literals run to the end of the source.
+func tokenizeJS(src string) []jsToken {
+	t := jsTokenizer{src: src, line: 1, col: 1}
+	var tokens []jsToken
+	for t.pos < len(src) {
+		c := src[t.pos]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			t.advance(1)
+			continue
+		}
+		startLine, startCol, start := t.line, t.col, t.pos
+		kind := t.scan(c, lastCode(tokens))
+		tokens = append(tokens, jsToken{
+			kind: kind, text: src[start:t.pos],
+			line: startLine, col: startCol, endLine: t.line, endCol: t.col,
+		})
+	}
+	return tokens
+}
+
+type jsTokenizer struct {
+	src       string
+	pos       int
+	line, col int
+}
+
+func (t *jsTokenizer) advance(n int) {
+	for i := 0; i < n && t.pos < len(t.src); i++ {
+		if t.src[t.pos] == '\n' {
+			t.line++
+			t.col = 1
+		} else {
+			t.col++
+		}
+		t.pos++
+	}
+}
+
+// scan consumes one token starting with c and returns its kind. prev is the
+// last non-comment token, which tells a regular expression from a division.
+func (t *jsTokenizer) scan(c byte, prev *jsToken) jsTokenKind {
+	rest := t.src[t.pos:]
+	switch {
+	case strings.HasPrefix(rest, "//"):
+		t.advanceTo(strings.IndexByte(rest, '\n'))
+		return jsComment
+	case strings.HasPrefix(rest, "/*"):
+		end := strings.Index(rest[2:], "*/")
+		if end >= 0 {
+			end += 4
+		}
+		t.advanceTo(end)
+		return jsComment
+	case c == '\'' || c == '"':
+		t.scanQuoted(c)
+		return jsString
+	case c == '`':
+		t.scanTemplate()
+		return jsTemplate
+	case c == '/' && regexAllowedAfter(prev):
+		t.scanRegex()
+		return jsRegex
+	case isJSIdentStart(c):
+		n := 1
+		for n < len(rest) && isJSIdentPart(rest[n]) {
+			n++
+		}
+		t.advance(n)
+		return jsIdent
+	case c >= '0' && c <= '9', c == '.' && len(rest) > 1 && rest[1] >= '0' && rest[1] <= '9':
+		n := 1
+		for n < len(rest) && (isJSIdentPart(rest[n]) || rest[n] == '.' ||
+			((rest[n] == '+' || rest[n] == '-') && (rest[n-1] == 'e' || rest[n-1] == 'E'))) {
+			n++
+		}
+		t.advance(n)
+		return jsNumber
+	}
+	for _, op := range jsPunctuators {
+		if strings.HasPrefix(rest, op) {
+			t.advance(len(op))
+			return jsPunct
+		}
+	}
+	t.advance(1)
+	return jsPunct
+}
+
+// advanceTo moves n bytes ahead, or to the end of the source for n < 0.
+func (t *jsTokenizer) advanceTo(n int) {
+	if n < 0 {
+		n = len(t.src) - t.pos
+	}
+	t.advance(n)
+}
+
+func (t *jsTokenizer) scanQuoted(quote byte) {
+	t.advance(1)
+	for t.pos < len(t.src) {
+		switch t.src[t.pos] {
+		case '\\':
+			t.advance(2)
+		case quote:
+			t.advance(1)
+			return
+		case '\n':
+			return // an unterminated string ends with its line
+		default:
+			t.advance(1)
+		}
+	}
+}
+
+// scanTemplate consumes a template literal with its substitutions, which may
+// hold strings, comments and templates of their own.
+func (t *jsTokenizer) scanTemplate() {
+	t.advance(1)
+	for t.pos < len(t.src) {
+		switch {
+		case t.src[t.pos] == '\\':
+			t.advance(2)
+		case t.src[t.pos] == '`':
+			t.advance(1)
+			return
+		case strings.HasPrefix(t.src[t.pos:], "${"):
+			t.advance(2)
+			t.scanSubstitution()
+		default:
+			t.advance(1)
+		}
+	}
+}
+
+func (t *jsTokenizer) scanSubstitution() {
+	depth := 1
+	var prev *jsToken
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			t.advance(1)
+			continue
+		}
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				t.advance(1)
+				return
+			}
+		}
+		start := t.pos
+		kind := t.scan(c, prev)
+		if kind != jsComment {
+			prev = &jsToken{kind: kind, text: t.src[start:t.pos]}
+		}
+	}
+}
+
+func (t *jsTokenizer) scanRegex() {
+	t.advance(1)
+	inClass := false
+	for t.pos < len(t.src) {
+		switch c := t.src[t.pos]; {
+		case c == '\\':
+			t.advance(2)
+			continue
+		case c == '\n':
+			return
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			t.advance(1)
+			for t.pos < len(t.src) && isJSIdentPart(t.src[t.pos]) {
+				t.advance(1) // flags
+			}
+			return
+		}
+		t.advance(1)
+	}
+}
+
+// regexAllowedAfter reports whether a slash after prev starts a regular
+// expression rather than a division: only an operand can be divided.
+func regexAllowedAfter(prev *jsToken) bool {
+	if prev == nil {
+		return true
+	}
+	switch prev.kind {
+	case jsNumber, jsString, jsTemplate, jsRegex:
+		return false
+	case jsIdent:
+		switch prev.text {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return true
+		}
+		return false
+	case jsComment:
+		return true
+	case jsPunct:
+		return prev.text != ")" && prev.text != "]" && prev.text != "}"
+	}
+	return true
+}
+
+func lastCode(tokens []jsToken) *jsToken {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i].kind != jsComment {
+			return &tokens[i]
+		}
+	}
+	return nil
+}
+
+func isJSIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isJSIdentPart(c byte) bool {
+	return isJSIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// jsCodeTokens returns the tokens that are not comments, in order.
+func jsCodeTokens(src string) []jsToken {
+	all := tokenizeJS(src)
+	code := all[:0]
+	for _, tok := range all {
+		if tok.kind != jsComment {
+			code = append(code, tok)
+		}
+	}
+	return code
+}
+
+// isJSPunct reports whether the token is the given punctuator.
+func (tok jsToken) isPunct(text string) bool {
+	return tok.kind == jsPunct && tok.text == text
+}
+
+// isIdent reports whether the token is the given identifier.
+func (tok jsToken) isIdent(text string) bool {
+	return tok.kind == jsIdent && tok.text == text
+}
+
+// closing returns the index of the bracket closing the one at open, or -1.
+func closing(tokens []jsToken, open int) int {
+	pairs := map[string]string{"(": ")", "[": "]", "{": "}"}
+	want, ok := pairs[tokens[open].text]
+	if !ok || tokens[open].kind != jsPunct {
+		return -1
+	}
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].kind != jsPunct {
+			continue
+		}
+		switch tokens[i].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				if tokens[i].text != want {
+					return -1
+				}
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// sourceBetween returns the source text from the start of tokens[from] to the
+// end of tokens[to], both on one line of lines.
+func sourceBetween(lines []string, tokens []jsToken, from, to int) (string, bool) {
+	first, last := tokens[from], tokens[to]
+	if first.line != last.endLine || first.line < 1 || first.line > len(lines) {
+		return "", false
+	}
+	line := lines[first.line-1]
+	if last.endCol-1 > len(line) {
+		return "", false
+	}
+	return line[first.col-1 : last.endCol-1], true
+}
+
+// tokenEdit builds the column edit replacing tokens[from..to], which must lie
+// on one line.
+func tokenEdit(path string, lines []string, tokens []jsToken, from, to int, replacement string) *Fix {
+	old, ok := sourceBetween(lines, tokens, from, to)
+	if !ok {
+		return nil
+	}
+	return &Fix{
+		File:      path,
+		StartLine: tokens[from].line,
+		EndLine:   tokens[from].line,
+		StartCol:  tokens[from].col,
+		EndCol:    tokens[to].endCol,
+		OldText:   old,
+		NewText:   replacement,
+	}
+}
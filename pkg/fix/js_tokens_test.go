@@ -0,0 +1,40 @@
+package fix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeJSKeepsLiteralsAndCommentsWhole(t *testing.T) {
+	src := "const url = `${base}/api?x=${a || 'b'}` // || 'http://localhost'\n" +
+		"/* Math.floor(amount * 100) / 100 */ const r = /a\\/b[/]/g.test(s) / 2;\n"
+
+	tokens := tokenizeJS(src)
+	var kinds []jsTokenKind
+	var texts []string
+	for _, tok := range tokens {
+		kinds = append(kinds, tok.kind)
+		texts = append(texts, tok.text)
+	}
+
+	assert.Contains(t, texts, "`${base}/api?x=${a || 'b'}`", "a template with substitutions is one token")
+	assert.Contains(t, texts, "// || 'http://localhost'")
+	assert.Contains(t, texts, "/* Math.floor(amount * 100) / 100 */")
+	assert.Contains(t, texts, "/a\\/b[/]/g", "a slash after = starts a regular expression")
+	assert.NotContains(t, texts, "||", "the only || is inside a template and a comment")
+
+	last := tokens[len(tokens)-3]
+	assert.Equal(t, jsPunct, last.kind, "a slash after ) divides")
+	assert.Equal(t, "/", last.text)
+	assert.Equal(t, 2, last.line)
+}
+
+func TestTokenizeJSPositions(t *testing.T) {
+	tokens := tokenizeJS("a\n  `x\ny` + 1")
+	require.Len(t, tokens, 4)
+	assert.Equal(t, jsToken{kind: jsTemplate, text: "`x\ny`", line: 2, col: 3, endLine: 3, endCol: 3}, tokens[1])
+	assert.Equal(t, 3, tokens[2].line)
+	assert.Equal(t, 4, tokens[2].col)
+}
@@ -75,6 +75,12 @@ func (f *MapIterationOrderFixer) GenerateFix(ctx *core.FileContext, v *core.Viol
 	return fixes
 }
 
+// Safety reports a semantic change: fixing the order is the point — the loop
+// body now sees the keys sorted, which is a change in behavior however welcome.
+func (f *MapIterationOrderFixer) Safety() Safety {
+	return SafetySemanticChange
+}
+
 func init() {
 	DefaultRegistry.Register(NewMapIterationOrderFixer())
 }
@@ -105,6 +105,11 @@ func (f *MdLineBreakFixer) GenerateFix(ctx *core.FileContext, v *core.Violation)
 	}}
 }
 
+// Safety reports safe: only the Markdown source changes, never code.
+func (f *MdLineBreakFixer) Safety() Safety {
+	return SafetySafe
+}
+
 func init() {
 	DefaultRegistry.Register(NewMdLineBreakFixer())
 }
@@ -66,6 +66,11 @@ func (f *MdListAfterLabelFixer) GenerateFix(ctx *core.FileContext, v *core.Viola
 	}}
 }
 
+// Safety reports safe: only the Markdown source changes, never code.
+func (f *MdListAfterLabelFixer) Safety() Safety {
+	return SafetySafe
+}
+
 func init() {
 	DefaultRegistry.Register(NewMdListAfterLabelFixer())
 }
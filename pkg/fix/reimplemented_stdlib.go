@@ -136,6 +136,13 @@ func targetType(signature string) string {
 	return strings.TrimSpace(typeName)
 }
 
+// Safety reports a semantic change: the loop is matched by its shape, and only
+// the start of its condition is checked — `if n == name && ok` would lose the
+// second operand.
+func (f *ReimplementedStdlibFixer) Safety() Safety {
+	return SafetySemanticChange
+}
+
 func init() {
 	DefaultRegistry.Register(NewReimplementedStdlibFixer())
 }
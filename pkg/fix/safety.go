@@ -0,0 +1,168 @@
+package fix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Safety says how much a fix may change what the code does. Levels are
+// ordered: a caller allowing one level allows every level below it.
+type Safety int
+
+const (
+	// SafetySafe fixes keep the program's behavior: the rewrite is proven
+	// equivalent, or only formatting changes.
+	SafetySafe Safety = iota
+	// SafetySemanticChange fixes change behavior on purpose or may change it
+	// in cases the fixer cannot see, such as a text match inside a literal.
+	SafetySemanticChange
+	// SafetyUnsafe fixes may break the build or the program and need review.
+	SafetyUnsafe
+)
+
+// String returns the level as written in the CLI output.
+func (s Safety) String() string {
+	switch s {
+	case SafetySafe:
+		return "safe"
+	case SafetySemanticChange:
+		return "semantic-change"
+	case SafetyUnsafe:
+		return "unsafe"
+	}
+	return fmt.Sprintf("Safety(%d)", int(s))
+}
+
+// Conflict is a pair of edits from different rules that overlap the same
+// range. Applying one would make the other no longer match, so neither is
+// applied; a second run sees the code after the rest has been fixed.
+type Conflict struct {
+	Fix   *Fix
+	Other *Fix
+}
+
+// String names both rules and where they collide.
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s:%d: %s and %s edit the same range",
+		c.Fix.File, c.Fix.StartLine, c.Fix.RuleName, c.Other.RuleName)
+}
+
+// Selection splits generated fixes into those to apply and those held back.
+type Selection struct {
+	Apply []*Fix
+	// Held are the fixes above the allowed safety level.
+	Held []*Fix
+	// Conflicts are the overlapping edits; every edit of both findings is
+	// left out of Apply.
+	Conflicts []Conflict
+}
+
+// Select keeps the fixes at or below allowed and drops the ones that collide
+// with a fix from another rule. Held fixes take no part in conflicts: a fix
+// that is not applied cannot get in the way of one that is.
+func Select(fixes []*Fix, allowed Safety) Selection {
+	var selection Selection
+	var candidates []*Fix
+	for _, fix := range fixes {
+		if fix.Safety > allowed {
+			selection.Held = append(selection.Held, fix)
+			continue
+		}
+		candidates = append(candidates, fix)
+	}
+
+	dropped := make(map[*Fix]bool)
+	for i, fix := range candidates {
+		for _, other := range candidates[i+1:] {
+			if fix.RuleName == other.RuleName || !overlaps(fix, other) {
+				continue
+			}
+			selection.Conflicts = append(selection.Conflicts, Conflict{Fix: fix, Other: other})
+			dropped[fix] = true
+			dropped[other] = true
+		}
+	}
+
+	// A finding is fixed by all of its rewrites or by none. Its import edits
+	// stay: dedupeFixes may have merged another finding's identical edit into
+	// this one, and tidyImports removes an added import nothing uses.
+	droppedFindings := make(map[*core.Violation]bool)
+	for fix := range dropped {
+		if fix.Violation != nil {
+			droppedFindings[fix.Violation] = true
+		}
+	}
+	for _, fix := range candidates {
+		if dropped[fix] || (fix.Violation != nil && droppedFindings[fix.Violation] && !isInsertion(fix)) {
+			continue
+		}
+		selection.Apply = append(selection.Apply, fix)
+	}
+	return selection
+}
+
+// overlaps reports whether two edits touch the same text. Insertions before a
+// line, the shape of every import edit, never collide with each other: two
+// fixes adding imports to the same group compose, and tidyImports removes the
+// duplicates.
+func overlaps(a, b *Fix) bool {
+	if a.File != b.File {
+		return false
+	}
+	if isInsertion(a) && isInsertion(b) {
+		return false
+	}
+	if a.StartLine > lastLine(b) || b.StartLine > lastLine(a) {
+		return false
+	}
+	// Two column edits on one line collide only if their columns do.
+	if a.StartLine == b.StartLine && lastLine(a) == a.StartLine && lastLine(b) == b.StartLine &&
+		a.StartCol > 0 && a.EndCol > 0 && b.StartCol > 0 && b.EndCol > 0 {
+		return a.StartCol < b.EndCol && b.StartCol < a.EndCol
+	}
+	return true
+}
+
+func lastLine(fix *Fix) int {
+	if fix.EndLine > fix.StartLine {
+		return fix.EndLine
+	}
+	return fix.StartLine
+}
+
+// isInsertion reports whether the edit only adds lines before an unchanged
+// one.
+func isInsertion(fix *Fix) bool {
+	return lastLine(fix) == fix.StartLine && fix.StartCol == 0 &&
+		fix.OldText != "" && strings.HasSuffix(fix.NewText, "\n"+fix.OldText)
+}
+
+// HeldSummary lists the rules whose fixes were held back with the number of
+// findings each, in a stable order: "bool-compare (2), map-iteration-order (1)".
+// The import edit that comes with a rewrite is not a finding of its own.
+func HeldSummary(held []*Fix) string {
+	counts := make(map[string]int)
+	counted := make(map[*core.Violation]bool)
+	for _, fix := range held {
+		if fix.Violation != nil {
+			if counted[fix.Violation] {
+				continue
+			}
+			counted[fix.Violation] = true
+		}
+		counts[fix.RuleName]++
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s (%d)", name, counts[name]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,134 @@
+package fix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+func TestGenerateFixesTakesTheFixerSafety(t *testing.T) {
+	ctx := fixerContext(t, `package rules
+
+import (
+	"fmt"
+)
+
+func a(m map[string]int) {
+	for k, v := range m {
+		fmt.Println(k, v)
+	}
+}
+`)
+
+	fixes := NewEngine(DefaultRegistry, true).GenerateFixes(
+		[]*core.Violation{mapOrderViolation(8)},
+		map[string]*core.FileContext{"rule.go": ctx},
+	)
+
+	require.NotEmpty(t, fixes)
+	for _, fix := range fixes {
+		assert.Equal(t, SafetySemanticChange, fix.Safety, "every edit of the fix carries the fixer's level")
+	}
+}
+
+func TestSelectHoldsBackFixesAboveTheAllowedLevel(t *testing.T) {
+	safe := &Fix{File: "a.go", StartLine: 3, OldText: "interface{}", NewText: "any", RuleName: "interface-any"}
+	risky := &Fix{File: "a.go", StartLine: 9, OldText: "x == true", NewText: "x", RuleName: "bool-compare", Safety: SafetySemanticChange}
+
+	selection := Select([]*Fix{safe, risky}, SafetySafe)
+	assert.Equal(t, []*Fix{safe}, selection.Apply)
+	assert.Equal(t, []*Fix{risky}, selection.Held)
+	assert.Equal(t, "bool-compare (1)", HeldSummary(selection.Held))
+
+	selection = Select([]*Fix{safe, risky}, SafetyUnsafe)
+	assert.Equal(t, []*Fix{safe, risky}, selection.Apply)
+	assert.Empty(t, selection.Held)
+}
+
+// Two rules rewriting one range used to apply whichever came first and fail
+// the other with "no longer match the file", naming only the loser.
+func TestSelectReportsOverlapOfDifferentRulesAsConflict(t *testing.T) {
+	modern := &core.Violation{Rule: "go-modern"}
+	stdlib := &core.Violation{Rule: "reimplemented-stdlib"}
+	rewrite := &Fix{File: "a.go", StartLine: 5, EndLine: 12, OldText: "func ...", NewText: "func ...", RuleName: "reimplemented-stdlib", Violation: stdlib}
+	imports := &Fix{File: "a.go", StartLine: 3, EndLine: 3, OldText: "\t\"strings\"", NewText: "\t\"slices\"\n\t\"strings\"", RuleName: "reimplemented-stdlib", Violation: stdlib}
+	inner := &Fix{File: "a.go", StartLine: 7, EndLine: 7, StartCol: 2, EndCol: 20, OldText: "for i := 0; i < n;", NewText: "for i := range n", RuleName: "go-modern", Violation: modern}
+	unrelated := &Fix{File: "a.go", StartLine: 20, EndLine: 20, OldText: "interface{}", NewText: "any", RuleName: "interface-any"}
+
+	selection := Select([]*Fix{rewrite, imports, inner, unrelated}, SafetyUnsafe)
+
+	require.Len(t, selection.Conflicts, 1)
+	assert.Contains(t, selection.Conflicts[0].String(), "reimplemented-stdlib and go-modern")
+	assert.Equal(t, []*Fix{imports, unrelated}, selection.Apply,
+		"both rewrites are left out; the import edit stays for tidyImports to drop if unused")
+}
+
+func TestSelectDoesNotReportEditsThatDoNotCollide(t *testing.T) {
+	left := &Fix{File: "a.go", StartLine: 4, StartCol: 1, EndCol: 10, OldText: "ioutil.Re", NewText: "os.Re", RuleName: "deprecated-ioutil"}
+	right := &Fix{File: "a.go", StartLine: 4, StartCol: 20, EndCol: 31, OldText: "interface{}", NewText: "any", RuleName: "interface-any"}
+	sameRule := &Fix{File: "a.go", StartLine: 4, OldText: "interface{}", NewText: "any", RuleName: "interface-any"}
+	mapsImport := &Fix{File: "a.go", StartLine: 2, OldText: "\t\"strings\"", NewText: "\t\"maps\"\n\t\"strings\"", RuleName: "map-iteration-order"}
+	slicesImport := &Fix{File: "a.go", StartLine: 2, OldText: "\t\"strings\"", NewText: "\t\"slices\"\n\t\"strings\"", RuleName: "go-modern"}
+	otherFile := &Fix{File: "b.go", StartLine: 4, OldText: "interface{}", NewText: "any", RuleName: "bool-compare"}
+
+	selection := Select([]*Fix{left, right, mapsImport, slicesImport, otherFile}, SafetySafe)
+	assert.Empty(t, selection.Conflicts, "disjoint columns, import insertions and other files compose")
+	assert.Len(t, selection.Apply, 5)
+
+	selection = Select([]*Fix{right, sameRule}, SafetySafe)
+	assert.Empty(t, selection.Conflicts, "a rule's own overlapping edits are not a conflict between rules")
+}
+
+// An edit that fills an empty line ends in a newline like an insertion but
+// replaces the line, so two of them collide.
+func TestSelectReportsTwoRulesFillingOneEmptyLine(t *testing.T) {
+	first := &Fix{File: "a.go", StartLine: 6, OldText: "", NewText: "\tdefer f.Close()\n", RuleName: "resource-leak"}
+	second := &Fix{File: "a.go", StartLine: 6, OldText: "", NewText: "\t_ = f.Sync()\n", RuleName: "unchecked-error"}
+
+	selection := Select([]*Fix{first, second}, SafetyUnsafe)
+	require.Len(t, selection.Conflicts, 1)
+	assert.Empty(t, selection.Apply)
+}
+
+// Column edits on one line are applied right to left, so the left one keeps its
+// columns.
+func TestApplyFixesAppliesColumnEditsOnOneLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.go")
+	require.NoError(t, os.WriteFile(path, []byte("package x\nvar a, b interface{} = x == true, 1\n"), 0o644))
+
+	results := NewEngine(NewRegistry(), false).ApplyFixes([]*Fix{
+		{File: path, StartLine: 2, EndLine: 2, StartCol: 10, EndCol: 21, OldText: "interface{}", NewText: "any", RuleName: "interface-any"},
+		{File: path, StartLine: 2, EndLine: 2, StartCol: 24, EndCol: 33, OldText: "x == true", NewText: "x", RuleName: "bool-compare"},
+	})
+
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "package x\nvar a, b any = x, 1\n", string(content))
+}
+
+// An import edit kept after its rewrite lost a conflict must not leave an
+// unused import behind.
+func TestApplyFixesDropsAddedImportNothingUses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.go")
+	source := "package x\n\nimport (\n\t\"strings\"\n)\n\nvar _ = strings.ToUpper\n"
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+
+	results := NewEngine(NewRegistry(), false).ApplyFixes([]*Fix{{
+		File: path, StartLine: 4, EndLine: 4,
+		OldText: "\t\"strings\"", NewText: "\t\"slices\"\n\t\"strings\"",
+		RuleName: "go-modern",
+	}})
+
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, source, string(content))
+}
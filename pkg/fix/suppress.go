@@ -0,0 +1,329 @@
+package fix
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// SuppressionRule is the rule name suppression edits are reported under: they
+// belong to no single rule, one comment can silence several.
+const SuppressionRule = "nolint"
+
+// SkippedSuppression is a finding no suppression comment could be placed for.
+type SkippedSuppression struct {
+	Violation *core.Violation
+	Reason    string
+}
+
+// commentPlacement says where the suppression comment of one line goes.
+type commentPlacement int
+
+const (
+	placeTrailing commentPlacement = iota // after the code on the violation line
+	placeAbove                            // on its own line directly above
+)
+
+// SuppressionFixes returns the edits that silence every given finding at its
+// source with a nolint comment carrying the reason:
+//
+//	db := NewRepo(nil) //nolint:nil-di // pre-existing, tracked in PLAT-123
+//
+// All rules reported on one line share one comment. A line that already has a
+// nolint list gets the rule names merged into it instead of a second comment.
+// The comment goes on the line above when a trailing one would change the
+// code or its formatting: the line ends inside a multi-line string, already
+// has another comment, or sits next to trailing comments gofmt would realign.
+// Go, TypeScript/JavaScript and SQL are supported, each in its own comment
+// syntax; findings in other files, or where no comment can go, are returned
+// as skipped. The caller filters out rules that forbid suppression.
+func SuppressionFixes(violations []*core.Violation, contexts map[string]*core.FileContext, reason string) ([]*Fix, []SkippedSuppression) {
+	reason = strings.Join(strings.Fields(reason), " ")
+
+	type lineKey struct {
+		path string
+		line int
+	}
+	byLine := make(map[lineKey][]*core.Violation)
+	lineContexts := make(map[lineKey]*core.FileContext)
+	var skipped []SkippedSuppression
+	for _, v := range violations {
+		ctx, ok := contexts[v.File]
+		switch {
+		case !ok:
+			skipped = append(skipped, SkippedSuppression{v, "file was not analyzed"})
+			continue
+		case v.Line < 1 || v.Line > len(ctx.Lines):
+			skipped = append(skipped, SkippedSuppression{v, "finding has no source line"})
+			continue
+		case commentSyntaxFor(ctx) == nil:
+			skipped = append(skipped, SkippedSuppression{v, "no comment syntax for " + ctx.Extension() + " files"})
+			continue
+		}
+		key := lineKey{ctx.Path, v.Line}
+		byLine[key] = append(byLine[key], v)
+		lineContexts[key] = ctx
+	}
+
+	keys := make([]lineKey, 0, len(byLine))
+	for key := range byLine {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return keys[i].line < keys[j].line
+	})
+
+	var fixes []*Fix
+	for _, key := range keys {
+		lineViolations := byLine[key]
+		fix, skip := suppressLine(lineContexts[key], key.line, ruleNames(lineViolations), reason)
+		if fix == nil {
+			for _, v := range lineViolations {
+				skipped = append(skipped, SkippedSuppression{v, skip})
+			}
+			continue
+		}
+		fix.Violation = lineViolations[0]
+		fixes = append(fixes, fix)
+	}
+	return fixes, skipped
+}
+
+// ruleNames returns the distinct rules of the findings in report order.
+func ruleNames(violations []*core.Violation) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, v := range violations {
+		if !seen[v.Rule] {
+			seen[v.Rule] = true
+			names = append(names, v.Rule)
+		}
+	}
+	return names
+}
+
+// commentSyntax renders a suppression comment in one language.
+type commentSyntax struct {
+	// line starts a comment running to the end of the line.
+	line string
+	// jsx wraps a comment placed among JSX children.
+	jsx bool
+}
+
+func commentSyntaxFor(ctx *core.FileContext) *commentSyntax {
+	switch {
+	case ctx.IsGoFile():
+		return &commentSyntax{line: "//"}
+	case ctx.IsTypeScriptFile(), ctx.IsJavaScriptFile():
+		return &commentSyntax{line: "//", jsx: strings.HasSuffix(ctx.Path, "x")}
+	case ctx.IsSQLFile():
+		return &commentSyntax{line: "--"}
+	}
+	return nil
+}
+
+// render writes "//nolint:a,b // reason" in the file's syntax.
+func (c *commentSyntax) render(rules []string, reason string) string {
+	text := c.line + "nolint:" + strings.Join(rules, ",")
+	if reason != "" {
+		text += " " + c.line + " " + reason
+	}
+	return text
+}
+
+// renderJSX writes the comment as a JSX child expression, the only comment
+// JSX text accepts.
+func (c *commentSyntax) renderJSX(rules []string, reason string) string {
+	text := "nolint:" + strings.Join(rules, ",")
+	if reason != "" {
+		text += " -- " + strings.ReplaceAll(reason, "*/", "* /")
+	}
+	return "{/* " + text + " */}"
+}
+
+// suppressLine builds the edit silencing the given rules on one line, or says
+// why no comment can go there.
+func suppressLine(ctx *core.FileContext, line int, rules []string, reason string) (fix *Fix, skip string) {
+	syntax := commentSyntaxFor(ctx)
+	text := ctx.Lines[line-1]
+
+	// An existing list on the line, or on a comment line directly above it,
+	// takes the new names.
+	for _, target := range []int{line, line - 1} {
+		if target < 1 || (target != line && !isCommentLine(ctx, target)) {
+			continue
+		}
+		if merged, ok := mergeNolint(ctx, ctx.Lines[target-1], rules); ok {
+			return lineEdit(ctx, target, merged, rules), ""
+		}
+	}
+
+	if syntax.jsx && isJSXMarkup(text) {
+		if !acceptsJSXChild(text) {
+			return nil, fmt.Sprintf("JSX line %d takes no comment child; suppress it by hand", line)
+		}
+		return lineEdit(ctx, line, strings.TrimRight(text, " \t")+" "+syntax.renderJSX(rules, reason), rules), ""
+	}
+
+	placement, skip := placementFor(ctx, line)
+	if skip != "" {
+		return nil, skip
+	}
+	comment := syntax.render(rules, reason)
+	if placement == placeAbove {
+		return lineEdit(ctx, line, leadingIndent(text)+comment+"\n"+text, rules), ""
+	}
+	return lineEdit(ctx, line, strings.TrimRight(text, " \t")+" "+comment, rules), ""
+}
+
+func lineEdit(ctx *core.FileContext, line int, newText string, rules []string) *Fix {
+	return &Fix{
+		File:      ctx.Path,
+		StartLine: line,
+		EndLine:   line,
+		OldText:   ctx.Lines[line-1],
+		NewText:   newText,
+		Message:   "Suppress " + strings.Join(rules, ", "),
+		RuleName:  SuppressionRule,
+	}
+}
+
+// mergeNolint adds the rules to a nolint list in the line's comment, keeping
+// everything after the list (usually the reason) as it is.
+func mergeNolint(ctx *core.FileContext, text string, rules []string) (string, bool) {
+	comment := ctx.CommentPart(text)
+	idx := strings.Index(comment, "nolint:")
+	if idx < 0 {
+		return "", false
+	}
+	listStart := len(text) - len(comment) + idx + len("nolint:")
+	listEnd := listStart
+	for listEnd < len(text) && (isRuleNameByte(text[listEnd]) || text[listEnd] == ',') {
+		listEnd++
+	}
+	for listEnd > listStart && text[listEnd-1] == ',' {
+		listEnd-- // "nolint:a, b" — the spaced rest of the list stays as written
+	}
+	existing := strings.Split(text[listStart:listEnd], ",")
+	list := append([]string(nil), existing...)
+	for _, rule := range rules {
+		if !slices.Contains(existing, rule) {
+			list = append(list, rule)
+		}
+	}
+	return text[:listStart] + strings.Join(list, ",") + text[listEnd:], true
+}
+
+func isRuleNameByte(c byte) bool {
+	return c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isCommentLine reports whether the line holds a comment and nothing else.
+func isCommentLine(ctx *core.FileContext, line int) bool {
+	text := strings.TrimSpace(ctx.Lines[line-1])
+	return text != "" && ctx.CommentPart(text) == text
+}
+
+// placementFor decides where the comment of a line goes, or says why it can
+// go nowhere.
+func placementFor(ctx *core.FileContext, line int) (placement commentPlacement, skip string) {
+	text := ctx.Lines[line-1]
+	if strings.TrimSpace(text) == "" || isCommentLine(ctx, line) {
+		return placeAbove, ""
+	}
+	if ctx.IsGoFile() {
+		if inside, opens := goStringAt(ctx, line); inside {
+			return 0, fmt.Sprintf("line %d is inside a multi-line string literal", line)
+		} else if opens {
+			return placeAbove, ""
+		}
+	}
+	if ctx.IsTypeScriptFile() || ctx.IsJavaScriptFile() {
+		if inside, opens := jsStringAt(ctx, line); inside {
+			return 0, fmt.Sprintf("line %d is inside a multi-line template literal", line)
+		} else if opens {
+			return placeAbove, ""
+		}
+	}
+	if ctx.CommentPart(text) != "" {
+		return placeAbove, ""
+	}
+	// gofmt aligns the trailing comments of adjacent lines: a new one next to
+	// them would reformat the neighbourhood.
+	for _, neighbour := range []int{line - 1, line + 1} {
+		if neighbour < 1 || neighbour > len(ctx.Lines) || isCommentLine(ctx, neighbour) {
+			continue
+		}
+		if ctx.CommentPart(ctx.Lines[neighbour-1]) != "" {
+			return placeAbove, ""
+		}
+	}
+	return placeTrailing, ""
+}
+
+// goStringAt reports whether the line starts inside a multi-line string
+// literal (no comment can go on it or above it) or ends inside one that it
+// opens (the comment has to go above).
+func goStringAt(ctx *core.FileContext, line int) (inside, opens bool) {
+	if ctx.GoAST == nil || ctx.GoFileSet == nil {
+		return false, false
+	}
+	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		start := ctx.GoFileSet.Position(lit.Pos()).Line
+		end := ctx.GoFileSet.Position(lit.End()).Line
+		switch {
+		case start < line && line <= end:
+			inside = true
+		case start == line && end > line:
+			opens = true
+		}
+		return true
+	})
+	return inside, opens
+}
+
+// jsStringAt is goStringAt for TypeScript and JavaScript: template literals
+// and continued strings are the literals that span lines.
+func jsStringAt(ctx *core.FileContext, line int) (inside, opens bool) {
+	for _, tok := range jsCodeTokens(string(ctx.Content)) {
+		if tok.kind != jsString && tok.kind != jsTemplate {
+			continue
+		}
+		switch {
+		case tok.line < line && line <= tok.endLine:
+			inside = true
+		case tok.line == line && tok.endLine > line:
+			opens = true
+		}
+	}
+	return inside, opens
+}
+
+// isJSXMarkup reports whether a line is JSX markup rather than script code.
+func isJSXMarkup(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), "<")
+}
+
+// acceptsJSXChild reports whether a comment child can follow the line: after
+// an opening tag it becomes the element's first child. After a self-closing or
+// closing tag it could land next to a root element, which is a syntax error.
+func acceptsJSXChild(text string) bool {
+	trimmed := strings.TrimSpace(text)
+	return strings.HasSuffix(trimmed, ">") && !strings.HasSuffix(trimmed, "/>") && !strings.HasPrefix(trimmed, "</")
+}
+
+func leadingIndent(text string) string {
+	return text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+}
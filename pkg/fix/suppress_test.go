@@ -0,0 +1,166 @@
+package fix
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+func suppressContext(t *testing.T, name, source string) *core.FileContext {
+	t.Helper()
+	root := t.TempDir()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	ctx, err := core.NewFileContextChecked(path, root, []byte(source), core.DefaultConfig())
+	require.NoError(t, err)
+	if ctx.IsGoFile() {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, source, parser.ParseComments)
+		require.NoError(t, err)
+		ctx.SetGoAST(fset, file)
+	}
+	return ctx
+}
+
+func suppress(t *testing.T, ctx *core.FileContext, reason string, violations ...*core.Violation) ([]*Fix, []SkippedSuppression) {
+	t.Helper()
+	for _, v := range violations {
+		v.File = ctx.RelPath
+	}
+	return SuppressionFixes(violations, map[string]*core.FileContext{ctx.RelPath: ctx}, reason)
+}
+
+func TestSuppressionTrailingGoComment(t *testing.T) {
+	ctx := suppressContext(t, "m.go", "package m\n\nfunc f() {\n\tpanic(\"x\")\n}\n")
+
+	fixes, skipped := suppress(t, ctx, "  pre-existing,\n tracked in PLAT-123 ",
+		&core.Violation{Rule: "no-panic", Line: 4},
+		&core.Violation{Rule: "magic-string", Line: 4},
+		&core.Violation{Rule: "no-panic", Line: 4})
+
+	assert.Empty(t, skipped)
+	require.Len(t, fixes, 1, "all rules of one line share one comment")
+	assert.Equal(t, "\tpanic(\"x\") //nolint:no-panic,magic-string // pre-existing, tracked in PLAT-123", fixes[0].NewText)
+	assert.Equal(t, SuppressionRule, fixes[0].RuleName)
+}
+
+// The applied comment has to be one IsSuppressed honours, or the next check
+// reports the same finding again.
+func TestSuppressionIsHonoured(t *testing.T) {
+	ctx := suppressContext(t, "m.go", "package m\n\nvar x = 42\n")
+	fixes, _ := suppress(t, ctx, "legacy", &core.Violation{Rule: "magic-number", Line: 3})
+	require.Len(t, fixes, 1)
+
+	results := NewEngine(NewRegistry(), false).ApplyFixes(fixes)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+
+	content, err := os.ReadFile(ctx.Path)
+	require.NoError(t, err)
+	fixed, err := core.NewFileContextChecked(ctx.Path, filepath.Dir(ctx.Path), content, core.DefaultConfig())
+	require.NoError(t, err)
+	assert.True(t, fixed.IsSuppressed(3, "magic-number"))
+}
+
+func TestSuppressionGoesAboveNextToTrailingComments(t *testing.T) {
+	ctx := suppressContext(t, "m.go", "package m\n\nconst (\n\ta = 1 // one\n\tb = 2\n)\n")
+
+	fixes, _ := suppress(t, ctx, "legacy", &core.Violation{Rule: "magic-number", Line: 5})
+
+	require.Len(t, fixes, 1)
+	assert.Equal(t, "\t//nolint:magic-number // legacy\n\tb = 2", fixes[0].NewText)
+}
+
+func TestSuppressionAroundRawStrings(t *testing.T) {
+	ctx := suppressContext(t, "m.go", "package m\n\nvar q = `SELECT *\nFROM t`\n")
+
+	fixes, skipped := suppress(t, ctx, "legacy",
+		&core.Violation{Rule: "sql-select-star", Line: 3},
+		&core.Violation{Rule: "sql-select-star", Line: 4})
+
+	require.Len(t, fixes, 1)
+	assert.Equal(t, "//nolint:sql-select-star // legacy\nvar q = `SELECT *", fixes[0].NewText)
+	require.Len(t, skipped, 1, "a comment inside the literal would change the string")
+	assert.Contains(t, skipped[0].Reason, "multi-line string")
+}
+
+func TestSuppressionMergesExistingList(t *testing.T) {
+	ctx := suppressContext(t, "m.go", "package m\n\nvar x = 42 //nolint:magic-number // legacy\n\n//nolint:a, b // old\nvar y = 7\n")
+
+	fixes, _ := suppress(t, ctx, "new reason",
+		&core.Violation{Rule: "no-global", Line: 3},
+		&core.Violation{Rule: "magic-number", Line: 3},
+		&core.Violation{Rule: "c", Line: 6})
+
+	require.Len(t, fixes, 2)
+	assert.Equal(t, "var x = 42 //nolint:magic-number,no-global // legacy", fixes[0].NewText)
+	assert.Equal(t, 5, fixes[1].StartLine)
+	assert.Equal(t, "//nolint:a,c, b // old", fixes[1].NewText)
+}
+
+func TestSuppressionSQL(t *testing.T) {
+	ctx := suppressContext(t, "schema.sql", "SELECT * FROM users;\n")
+
+	fixes, _ := suppress(t, ctx, "report query", &core.Violation{Rule: "sql-select-star", Line: 1})
+
+	require.Len(t, fixes, 1)
+	assert.Equal(t, "SELECT * FROM users; --nolint:sql-select-star -- report query", fixes[0].NewText)
+}
+
+func TestSuppressionTypeScriptAndJSX(t *testing.T) {
+	ts := suppressContext(t, "api.ts", "const url = `${base}\n/path`;\nconst x = 1;\n")
+	fixes, _ := suppress(t, ts, "legacy",
+		&core.Violation{Rule: "hardcoded-url", Line: 1},
+		&core.Violation{Rule: "magic-number", Line: 3})
+	require.Len(t, fixes, 2)
+	assert.Equal(t, "//nolint:hardcoded-url // legacy\nconst url = `${base}", fixes[0].NewText)
+	assert.Equal(t, "const x = 1; //nolint:magic-number // legacy", fixes[1].NewText)
+
+	tsx := suppressContext(t, "App.tsx", "export const App = () => (\n  <div className=\"a\">\n    <img src=\"x\" />\n  </div>\n);\n")
+	fixes, skipped := suppress(t, tsx, "design */ system",
+		&core.Violation{Rule: "inline-style", Line: 2},
+		&core.Violation{Rule: "img-alt", Line: 3})
+	require.Len(t, fixes, 1)
+	assert.Equal(t, "  <div className=\"a\"> {/* nolint:inline-style -- design * / system */}", fixes[0].NewText)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "img-alt", skipped[0].Violation.Rule)
+}
+
+func TestSuppressionAroundTemplateLiterals(t *testing.T) {
+	ctx := suppressContext(t, "repo.ts", "const q = `\n  SELECT * FROM t\n  WHERE id = 1`;\nconst s = 'a`b';\n")
+
+	fixes, skipped := suppress(t, ctx, "legacy",
+		&core.Violation{Rule: "sql-select-star", Line: 1},
+		&core.Violation{Rule: "sql-select-star", Line: 2},
+		&core.Violation{Rule: "magic-number", Line: 3},
+		&core.Violation{Rule: "hardcoded-string", Line: 4})
+
+	require.Len(t, fixes, 2)
+	assert.Equal(t, "//nolint:sql-select-star // legacy\nconst q = `", fixes[0].NewText)
+	assert.Equal(t, "const s = 'a`b'; //nolint:hardcoded-string // legacy", fixes[1].NewText)
+	require.Len(t, skipped, 2, "a comment inside the literal would change the string")
+	for _, skip := range skipped {
+		assert.Contains(t, skip.Reason, "template literal")
+	}
+}
+
+func TestSuppressionSkipsUnsupportedFiles(t *testing.T) {
+	ctx := suppressContext(t, "notes.txt", "TODO\n")
+
+	fixes, skipped := suppress(t, ctx, "legacy",
+		&core.Violation{Rule: "todo", Line: 1},
+		&core.Violation{Rule: "todo", Line: 9})
+	assert.Empty(t, fixes)
+	require.Len(t, skipped, 2)
+
+	_, skipped = SuppressionFixes([]*core.Violation{{Rule: "todo", File: "missing.go", Line: 1}}, nil, "legacy")
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "file was not analyzed", skipped[0].Reason)
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// functionMetrics counts the distinct static callees of every function and,
+// in the other direction, its distinct callers in the project.
+func (m *measurer) functionMetrics() []FunctionMetrics {
+	type declared struct {
+		obj     *types.Func
+		metrics FunctionMetrics
+	}
+	var functions []*declared
+	byObj := make(map[*types.Func]*declared)
+	callers := make(map[*types.Func]map[*types.Func]bool)
+
+	for _, pkgCtx := range m.packages {
+		info := pkgCtx.Package.TypesInfo
+		m.funcDecls(pkgCtx, func(fn *ast.FuncDecl, obj *types.Func) {
+			file, line := m.position(fn.Name.Pos())
+			d := &declared{obj: obj, metrics: FunctionMetrics{
+				Package: pkgCtx.Package.PkgPath,
+				Name:    functionName(obj),
+				File:    file,
+				Line:    line,
+			}}
+			functions = append(functions, d)
+			byObj[obj] = d
+
+			callees := make(map[*types.Func]bool)
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					if callee := staticCallee(info, call); callee != nil && callee != obj {
+						callees[callee] = true
+					}
+				}
+				return true
+			})
+			d.metrics.FanOut = len(callees)
+			for callee := range callees {
+				if callers[callee] == nil {
+					callers[callee] = make(map[*types.Func]bool)
+				}
+				callers[callee][obj] = true
+			}
+		})
+	}
+
+	result := make([]FunctionMetrics, 0, len(functions))
+	for _, d := range functions {
+		d.metrics.FanIn = len(callers[d.obj])
+		result = append(result, d.metrics)
+	}
+	return result
+}
+
+// staticCallee resolves the function or method a call names, generic
+// instantiations to their origin. Calls of function values and conversions
+// have none.
+func staticCallee(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return nil
+	}
+	callee, ok := info.Uses[ident].(*types.Func)
+	if !ok {
+		return nil
+	}
+	return callee.Origin()
+}
+
+// functionName renders Func or (*T).Method / T.Method, without the package.
+func functionName(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return fn.Name()
+	}
+	recv := sig.Recv().Type()
+	pointer := false
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv, pointer = ptr.Elem(), true
+	}
+	name := recv.String()
+	if named, ok := recv.(*types.Named); ok {
+		name = named.Obj().Name()
+	}
+	if pointer {
+		return "(*" + name + ")." + fn.Name()
+	}
+	return name + "." + fn.Name()
+}
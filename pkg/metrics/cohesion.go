@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// structMetrics computes LCOM4 for every struct type with methods: methods are
+// connected when they use a common field of the receiver or one calls the
+// other on it, and LCOM4 is the number of connected groups.
+func (m *measurer) structMetrics() []StructMetrics {
+	var result []StructMetrics
+	for _, pkgCtx := range m.packages {
+		info := pkgCtx.Package.TypesInfo
+		methodsOf := make(map[*types.TypeName][]*ast.FuncDecl)
+		var order []*types.TypeName
+		m.funcDecls(pkgCtx, func(fn *ast.FuncDecl, obj *types.Func) {
+			recv := receiverStruct(obj)
+			if recv == nil {
+				return
+			}
+			if _, seen := methodsOf[recv]; !seen {
+				order = append(order, recv)
+			}
+			methodsOf[recv] = append(methodsOf[recv], fn)
+		})
+
+		for _, typeName := range order {
+			methods := methodsOf[typeName]
+			st, ok := typeName.Type().Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			file, line := m.position(typeName.Pos())
+			result = append(result, StructMetrics{
+				Package: pkgCtx.Package.PkgPath,
+				Name:    typeName.Name(),
+				File:    file,
+				Line:    line,
+				Methods: len(methods),
+				Fields:  st.NumFields(),
+				LCOM:    lcom4(info, methods),
+			})
+		}
+	}
+	if result == nil {
+		return []StructMetrics{}
+	}
+	return result
+}
+
+// receiverStruct returns the named struct type a method is declared on.
+func receiverStruct(fn *types.Func) *types.TypeName {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return nil
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named.Origin().Obj()
+}
+
+// lcom4 counts the connected groups of methods. A field or method reached
+// through an embedded struct counts as the embedded field itself. Methods that
+// use no state of the receiver (constant getters, no-op interface methods) and
+// accessors, which use a single field and nothing else, belong to no group:
+// each would otherwise count as a responsibility of its own.
+func lcom4(info *types.Info, methods []*ast.FuncDecl) int {
+	index := make(map[string]int, len(methods))
+	for i, fn := range methods {
+		index[fn.Name.Name] = i
+	}
+	fields := make([]map[*types.Var]bool, len(methods))
+	calls := make([][]int, len(methods))
+	for i, fn := range methods {
+		fields[i] = receiverUses(info, fn, func(name string) {
+			if other, ok := index[name]; ok && other != i {
+				calls[i] = append(calls[i], other)
+			}
+		})
+	}
+
+	parent := make([]int, len(methods))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) { parent[find(a)] = find(b) }
+
+	counted := make([]bool, len(methods))
+	fieldUser := make(map[*types.Var]int)
+	for i := range methods {
+		if len(calls[i]) == 0 && len(fields[i]) <= 1 {
+			continue
+		}
+		counted[i] = true
+		for field := range fields[i] {
+			if other, seen := fieldUser[field]; seen {
+				union(i, other)
+			} else {
+				fieldUser[field] = i
+			}
+		}
+		for _, other := range calls[i] {
+			union(i, other)
+		}
+	}
+
+	groups := make(map[int]bool)
+	for i := range methods {
+		if counted[i] {
+			groups[find(i)] = true
+		}
+	}
+	if len(groups) == 0 {
+		return 1
+	}
+	return len(groups)
+}
+
+// receiverUses returns the fields of the receiver a method uses and reports
+// the names of the receiver's own methods it calls.
+func receiverUses(info *types.Info, fn *ast.FuncDecl, call func(name string)) map[*types.Var]bool {
+	used := make(map[*types.Var]bool)
+	recv := receiverVar(info, fn)
+	if recv == nil {
+		return used
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := ast.Unparen(sel.X).(*ast.Ident)
+		if !ok || info.Uses[ident] != recv {
+			return true
+		}
+		selection, ok := info.Selections[sel]
+		if !ok {
+			return true
+		}
+		if selection.Kind() == types.FieldVal || len(selection.Index()) > 1 {
+			if field := fieldAt(recv.Type(), selection.Index()[0]); field != nil {
+				used[field] = true
+			}
+		} else {
+			call(sel.Sel.Name)
+		}
+		return true
+	})
+	return used
+}
+
+// receiverVar returns the named receiver of a method, nil for `func (T) M()`.
+func receiverVar(info *types.Info, fn *ast.FuncDecl) *types.Var {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 || len(fn.Recv.List[0].Names) == 0 {
+		return nil
+	}
+	recv, _ := info.Defs[fn.Recv.List[0].Names[0]].(*types.Var)
+	return recv
+}
+
+// fieldAt returns the i-th field of the struct behind t.
+func fieldAt(t types.Type, i int) *types.Var {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok || i >= st.NumFields() {
+		return nil
+	}
+	return st.Field(i)
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Levels a CSV export can be written at, one table each.
+const (
+	LevelPackage  = "package"
+	LevelStruct   = "struct"
+	LevelFunction = "function"
+)
+
+// WriteJSON writes the whole report.
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteCSV writes the table of one level, with a header row.
+func WriteCSV(w io.Writer, report *Report, level string) error {
+	var rows [][]string
+	switch level {
+	case LevelPackage:
+		rows = append(rows, []string{"package", "dir", "afferent", "efferent", "instability", "interfaces", "types", "abstractness", "distance"})
+		for _, p := range report.Packages {
+			rows = append(rows, []string{p.Path, p.Dir, itoa(p.Afferent), itoa(p.Efferent), ftoa(p.Instability),
+				itoa(p.Interfaces), itoa(p.Types), ftoa(p.Abstractness), ftoa(p.Distance)})
+		}
+	case LevelStruct:
+		rows = append(rows, []string{"package", "struct", "file", "line", "methods", "fields", "lcom"})
+		for _, s := range report.Structs {
+			rows = append(rows, []string{s.Package, s.Name, s.File, itoa(s.Line), itoa(s.Methods), itoa(s.Fields), itoa(s.LCOM)})
+		}
+	case LevelFunction:
+		rows = append(rows, []string{"package", "function", "file", "line", "fan_in", "fan_out"})
+		for _, f := range report.Functions {
+			rows = append(rows, []string{f.Package, f.Name, f.File, itoa(f.Line), itoa(f.FanIn), itoa(f.FanOut)})
+		}
+	default:
+		return fmt.Errorf("unknown metrics level %q (want %s, %s or %s)", level, LevelPackage, LevelStruct, LevelFunction)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("write metrics CSV: %w", err)
+	}
+	return nil
+}
+
+func itoa(n int) string { return strconv.Itoa(n) }
+
+func ftoa(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
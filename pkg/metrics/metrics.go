@@ -0,0 +1,141 @@
+// Package metrics computes design metrics of a loaded Go project: coupling
+// and instability of packages, cohesion of structs, fan-in and fan-out of
+// functions. The architecture rules turn them into findings; `glint metrics`
+// exports them.
+package metrics
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Report holds the metrics of one project.
+type Report struct {
+	Packages  []PackageMetrics  `json:"packages"`
+	Structs   []StructMetrics   `json:"structs"`
+	Functions []FunctionMetrics `json:"functions"`
+}
+
+// PackageMetrics are Robert C. Martin's package metrics. Coupling counts the
+// packages of the project only: the standard library and third-party modules
+// are dependencies every package has and no design decision of this one.
+type PackageMetrics struct {
+	Path string `json:"path"`
+	Dir  string `json:"dir"`
+	// File and Line locate the package clause of its first file.
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Afferent coupling (Ca): project packages that import this one.
+	Afferent int `json:"afferent"`
+	// Efferent coupling (Ce): project packages this one imports.
+	Efferent int `json:"efferent"`
+	// Instability is Ce / (Ca + Ce): 0 for a package everything depends on,
+	// 1 for one that depends on everything and nothing on it.
+	Instability float64 `json:"instability"`
+	// Interfaces and Types count the package-level named types.
+	Interfaces int `json:"interfaces"`
+	Types      int `json:"types"`
+	// Abstractness is Interfaces / Types.
+	Abstractness float64 `json:"abstractness"`
+	// Distance from the main sequence, |A + I - 1|: near 0 a package is as
+	// abstract as it is stable; near 1 it is stable and concrete (hard to
+	// change, everything breaks) or unstable and abstract (unused).
+	Distance float64 `json:"distance"`
+}
+
+// StructMetrics measure the cohesion of a struct type with methods.
+type StructMetrics struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Methods int    `json:"methods"`
+	Fields  int    `json:"fields"`
+	// LCOM is LCOM4: the number of groups of methods that share no field and
+	// call no method of one another. 1 is one responsibility; 2 and more are
+	// types that could be split along the groups.
+	LCOM int `json:"lcom"`
+}
+
+// FunctionMetrics count the static calls of a function or method.
+type FunctionMetrics struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	// FanIn counts the distinct project functions that call this one.
+	FanIn int `json:"fanIn"`
+	// FanOut counts the distinct functions this one calls, the standard
+	// library's included; calls through interfaces and function values count
+	// the method or not at all, as the types tell.
+	FanOut int `json:"fanOut"`
+}
+
+// Compute measures every package of the project. Test files are not part of
+// the loaded packages and not measured.
+func Compute(ctx *core.GoProjectContext) *Report {
+	report := &Report{Packages: []PackageMetrics{}, Structs: []StructMetrics{}, Functions: []FunctionMetrics{}}
+	if ctx == nil {
+		return report
+	}
+
+	m := &measurer{ctx: ctx, internal: make(map[string]bool)}
+	for _, pkgCtx := range ctx.Packages {
+		if pkgCtx != nil && pkgCtx.Package != nil && pkgCtx.Package.Types != nil && pkgCtx.Package.TypesInfo != nil {
+			m.internal[pkgCtx.Package.PkgPath] = true
+			m.packages = append(m.packages, pkgCtx)
+		}
+	}
+	sort.Slice(m.packages, func(i, j int) bool { return m.packages[i].Package.PkgPath < m.packages[j].Package.PkgPath })
+
+	report.Packages = m.packageMetrics()
+	report.Structs = m.structMetrics()
+	report.Functions = m.functionMetrics()
+	return report
+}
+
+type measurer struct {
+	ctx      *core.GoProjectContext
+	packages []*core.GoPackageContext
+	internal map[string]bool
+}
+
+// position returns the project-relative file and line of pos.
+func (m *measurer) position(pos token.Pos) (string, int) {
+	position := m.ctx.FileSet.Position(pos)
+	file := position.Filename
+	if rel, err := filepath.Rel(m.ctx.ProjectRoot, file); err == nil {
+		file = filepath.ToSlash(rel)
+	}
+	return file, position.Line
+}
+
+// funcDecls calls visit for every function declaration with a body, in file
+// and source order.
+func (m *measurer) funcDecls(pkgCtx *core.GoPackageContext, visit func(*ast.FuncDecl, *types.Func)) {
+	info := pkgCtx.Package.TypesInfo
+	for _, file := range pkgCtx.Package.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			if obj, ok := info.Defs[fn.Name].(*types.Func); ok {
+				visit(fn, obj)
+			}
+		}
+	}
+}
+
+// ratio divides without producing NaN for an empty denominator.
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
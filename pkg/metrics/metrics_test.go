@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func find[T any](t *testing.T, items []T, match func(T) bool) T {
+	t.Helper()
+	for _, item := range items {
+		if match(item) {
+			return item
+		}
+	}
+	var zero T
+	t.Fatalf("no matching metrics in %+v", items)
+	return zero
+}
+
+func computeFixture(t *testing.T) *Report {
+	t.Helper()
+	project := rulestest.Project(t, map[string]string{
+		"domain/domain.go": `package domain
+
+type Store interface{ Save(string) error }
+
+type Clock interface{ Now() int64 }
+
+type Order struct{ ID string }
+`,
+		"app/app.go": `package app
+
+import (
+	"strings"
+
+	"example.com/rulestest/domain"
+)
+
+type Service struct {
+	store  domain.Store
+	log    []string
+	counts map[string]int
+}
+
+func (s *Service) Save(id string) error { return s.store.Save(strings.TrimSpace(id)) }
+
+func (s *Service) Retry(id string) error { return s.Save(id) }
+
+func (s *Service) Log(msg string) { s.log = append(s.log, msg) }
+
+func (s *Service) Flush() []string { out := s.log; s.log = nil; s.Count("flush"); return out }
+
+func (s *Service) Count(key string) { s.counts[key]++ }
+
+func (s *Service) Store() domain.Store { return s.store }
+
+func (s *Service) Name() string { return "service" }
+`,
+		"http/http.go": `package http
+
+import (
+	"example.com/rulestest/app"
+	"example.com/rulestest/domain"
+)
+
+func Handle(s *app.Service, o domain.Order) error {
+	s.Log(o.ID)
+	return s.Save(o.ID)
+}
+`,
+	})
+	return Compute(project)
+}
+
+func TestCompute_PackageMetrics(t *testing.T) {
+	report := computeFixture(t)
+
+	domain := find(t, report.Packages, func(p PackageMetrics) bool { return p.Dir == "domain" })
+	assert.Equal(t, 2, domain.Afferent)
+	assert.Equal(t, 0, domain.Efferent, "the standard library does not count")
+	assert.Equal(t, 0.0, domain.Instability)
+	assert.Equal(t, 2, domain.Interfaces)
+	assert.Equal(t, 3, domain.Types)
+	assert.Equal(t, 0.67, domain.Abstractness)
+	assert.Equal(t, 0.33, domain.Distance)
+	assert.Equal(t, "domain/domain.go", domain.File)
+	assert.Equal(t, 1, domain.Line)
+
+	app := find(t, report.Packages, func(p PackageMetrics) bool { return p.Dir == "app" })
+	assert.Equal(t, 1, app.Afferent)
+	assert.Equal(t, 1, app.Efferent)
+	assert.Equal(t, 0.5, app.Instability)
+	assert.Equal(t, 0.5, app.Distance)
+
+	http := find(t, report.Packages, func(p PackageMetrics) bool { return p.Dir == "http" })
+	assert.Equal(t, 2, http.Efferent)
+	assert.Equal(t, 1.0, http.Instability)
+	assert.Equal(t, 0.0, http.Distance)
+}
+
+func TestCompute_StructCohesion(t *testing.T) {
+	report := computeFixture(t)
+
+	require.Len(t, report.Structs, 1)
+	service := report.Structs[0]
+	assert.Equal(t, "Service", service.Name)
+	assert.Equal(t, "app/app.go", service.File)
+	assert.Equal(t, 9, service.Line)
+	assert.Equal(t, 7, service.Methods)
+	assert.Equal(t, 3, service.Fields)
+	// Save+Retry share the store, Flush+Count the log and counts; Log, Store
+	// are accessors and Name uses no state.
+	assert.Equal(t, 2, service.LCOM)
+}
+
+func TestCompute_FanInFanOut(t *testing.T) {
+	report := computeFixture(t)
+
+	save := find(t, report.Functions, func(f FunctionMetrics) bool { return f.Name == "(*Service).Save" })
+	assert.Equal(t, 2, save.FanIn, "Retry and Handle")
+	assert.Equal(t, 2, save.FanOut, "Store.Save and strings.TrimSpace")
+
+	handle := find(t, report.Functions, func(f FunctionMetrics) bool { return f.Name == "Handle" })
+	assert.Equal(t, 0, handle.FanIn)
+	assert.Equal(t, 2, handle.FanOut)
+	assert.Equal(t, "http/http.go", handle.File)
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := computeFixture(t)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCSV(&out, report, LevelStruct))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "package,struct,file,line,methods,fields,lcom", lines[0])
+	assert.Equal(t, "example.com/rulestest/app,Service,app/app.go,9,7,3,2", lines[1])
+
+	assert.Error(t, WriteCSV(&out, report, "module"))
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"go/types"
+	"math"
+	"path/filepath"
+)
+
+// packageMetrics computes coupling from the import graph of the project
+// packages and abstractness from their package-level types.
+func (m *measurer) packageMetrics() []PackageMetrics {
+	afferent := make(map[string]int)
+	efferent := make(map[string]int)
+	for _, pkgCtx := range m.packages {
+		pkg := pkgCtx.Package
+		for path := range pkg.Imports {
+			if m.internal[path] && path != pkg.PkgPath {
+				efferent[pkg.PkgPath]++
+				afferent[path]++
+			}
+		}
+	}
+
+	result := make([]PackageMetrics, 0, len(m.packages))
+	for _, pkgCtx := range m.packages {
+		pkg := pkgCtx.Package
+		metrics := PackageMetrics{
+			Path:     pkg.PkgPath,
+			Afferent: afferent[pkg.PkgPath],
+			Efferent: efferent[pkg.PkgPath],
+		}
+		if len(pkg.Syntax) > 0 {
+			metrics.File, metrics.Line = m.position(pkg.Syntax[0].Package)
+			metrics.Dir = filepath.ToSlash(filepath.Dir(metrics.File))
+		}
+		metrics.Instability = ratio(metrics.Efferent, metrics.Afferent+metrics.Efferent)
+
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			metrics.Types++
+			if types.IsInterface(typeName.Type()) {
+				metrics.Interfaces++
+			}
+		}
+		metrics.Abstractness = ratio(metrics.Interfaces, metrics.Types)
+		metrics.Distance = round(math.Abs(metrics.Abstractness + metrics.Instability - 1))
+		metrics.Instability = round(metrics.Instability)
+		metrics.Abstractness = round(metrics.Abstractness)
+		result = append(result, metrics)
+	}
+	return result
+}
+
+// round keeps two decimals, which is all the metrics mean and keeps the
+// exported numbers readable.
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,122 @@
+package architecture
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewForbiddenImportRule())
+}
+
+// ForbiddenImportRule enforces the `architecture.forbidden_imports` lists of
+// the config: packages matching `packages` must not depend on an import
+// matching `imports` — directly, or through other project packages, since a
+// domain package that reaches net/http through a helper still drags HTTP into
+// the domain.
+type ForbiddenImportRule struct {
+	*rules.BaseRule
+}
+
+// NewForbiddenImportRule creates the rule
+func NewForbiddenImportRule() *ForbiddenImportRule {
+	return &ForbiddenImportRule{
+		BaseRule: rules.NewBaseRule(
+			"forbidden-import",
+			"architecture",
+			"Detects packages that depend on imports the architecture config forbids them, directly or through other packages",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// RequiresSSA reports that the import graph of the typed packages is enough.
+func (r *ForbiddenImportRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile does nothing: indirect dependencies need the import graph.
+func (r *ForbiddenImportRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// AnalyzeGoProject reports, for every restricted package, the shortest path
+// to each forbidden import it depends on.
+func (r *ForbiddenImportRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil || ctx.Config == nil {
+		return nil, errors.New("forbidden import: Go project context without config")
+	}
+
+	graph, err := newImportGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, forbidden := range ctx.Config.Architecture.ForbiddenImports {
+		for _, node := range graph.ordered {
+			if !matchesAny(forbidden.Packages, node.relDir, node.path) {
+				continue
+			}
+			for _, chain := range r.forbiddenChains(graph, node, forbidden) {
+				violations = append(violations, r.createViolation(node, forbidden, chain))
+			}
+		}
+	}
+	return violations, nil
+}
+
+// forbiddenChains searches breadth-first from a restricted package and returns
+// the shortest import chain to each forbidden import. Other restricted
+// packages are not entered: they are reported on their own.
+func (r *ForbiddenImportRule) forbiddenChains(graph *importGraph, start *packageNode, forbidden core.ForbiddenImportConfig) [][]importSpec {
+	reachedBy := map[string][]importSpec{start.path: nil}
+	queue := []*packageNode{start}
+	reported := make(map[string]bool)
+	var chains [][]importSpec
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, spec := range node.specs {
+			chain := append(append([]importSpec(nil), reachedBy[node.path]...), spec)
+			if matchesAny(forbidden.Imports, spec.target) {
+				if !reported[spec.target] {
+					reported[spec.target] = true
+					chains = append(chains, chain)
+				}
+				continue
+			}
+			next, internal := graph.packages[spec.target]
+			if !internal || matchesAny(forbidden.Packages, next.relDir, next.path) {
+				continue
+			}
+			if _, seen := reachedBy[next.path]; !seen {
+				reachedBy[next.path] = chain
+				queue = append(queue, next)
+			}
+		}
+	}
+	return chains
+}
+
+func (r *ForbiddenImportRule) createViolation(node *packageNode, forbidden core.ForbiddenImportConfig, chain []importSpec) *core.Violation {
+	first, target := chain[0], chain[len(chain)-1].target
+	message := fmt.Sprintf("Package %s must not depend on %s: %s", node.path, target, describeChain(chain))
+	if forbidden.Reason != "" {
+		message += " (" + forbidden.Reason + ")"
+	}
+
+	v := r.CreateViolation(first.file.RelPath, first.line, message)
+	v.WithCode(first.file.GetLine(first.line))
+	if len(chain) == 1 {
+		v.WithSuggestion("Remove the import, or move the code that needs " + target + " out of " + node.relDir)
+	} else {
+		v.WithSuggestion("Cut the chain at one of its imports, so that " + node.relDir + " no longer reaches " + target)
+	}
+	v.WithContext("package", node.path)
+	v.WithContext("forbidden_import", target)
+	v.WithContext("depth", len(chain))
+
+	return v
+}
@@ -0,0 +1,82 @@
+package architecture
+
+import (
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForbiddenImportRule_DirectAndIndirect(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"internal/domain/order.go": `package domain
+
+import (
+	"database/sql"
+
+	"example.com/rulestest/internal/format"
+)
+
+var _ *sql.DB
+
+var Label = format.Label
+`,
+		"internal/domain/money/money.go": "package money\n\nimport \"net/http\"\n\nvar _ = http.StatusOK\n",
+		"internal/format/format.go": `package format
+
+import "example.com/rulestest/internal/transport"
+
+var Label = transport.Name
+`,
+		"internal/transport/transport.go": `package transport
+
+import "net/http"
+
+var Name = http.MethodGet
+`,
+	})
+	project.Config = core.DefaultConfig()
+	project.Config.Architecture.ForbiddenImports = []core.ForbiddenImportConfig{{
+		Packages: []string{"internal/domain/**"},
+		Imports:  []string{"net/http", "database/sql", "github.com/aws/**"},
+		Reason:   "the domain knows no infrastructure",
+	}}
+
+	violations, err := NewForbiddenImportRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 3)
+	direct, indirect, nested := violations[0], violations[1], violations[2]
+
+	assert.Equal(t, "internal/domain/order.go", direct.File)
+	assert.Equal(t, 4, direct.Line)
+	assert.Equal(t, "database/sql", direct.Context["forbidden_import"])
+	assert.Contains(t, direct.Message, "(the domain knows no infrastructure)")
+
+	assert.Equal(t, "internal/domain/order.go", indirect.File)
+	assert.Equal(t, 6, indirect.Line)
+	assert.Equal(t, 3, indirect.Context["depth"])
+	assert.Contains(t, indirect.Message, "internal/domain/order.go:6 imports example.com/rulestest/internal/format → "+
+		"internal/format/format.go:3 imports example.com/rulestest/internal/transport → "+
+		"internal/transport/transport.go:3 imports net/http")
+
+	assert.Equal(t, "internal/domain/money/money.go", nested.File)
+	assert.Equal(t, "net/http", nested.Context["forbidden_import"])
+}
+
+func TestForbiddenImportRule_UnrestrictedPackagesIgnored(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"internal/transport/transport.go": "package transport\n\nimport \"net/http\"\n\nvar Name = http.MethodGet\n",
+	})
+	project.Config = core.DefaultConfig()
+	project.Config.Architecture.ForbiddenImports = []core.ForbiddenImportConfig{{
+		Packages: []string{"internal/domain/**"},
+		Imports:  []string{"net/http"},
+	}}
+
+	violations, err := NewForbiddenImportRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
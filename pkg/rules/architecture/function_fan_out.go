@@ -0,0 +1,75 @@
+package architecture
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/metrics"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+const defaultMaxFanOut = 25
+
+func init() {
+	rules.Register(NewFunctionFanOutRule())
+}
+
+// FunctionFanOutRule reports functions that call too many distinct functions:
+// each callee is a reason for the function to change.
+type FunctionFanOutRule struct {
+	*rules.BaseRule
+	maxFanOut int
+}
+
+// NewFunctionFanOutRule creates the rule
+func NewFunctionFanOutRule() *FunctionFanOutRule {
+	return &FunctionFanOutRule{
+		BaseRule: rules.NewBaseRule(
+			"function-fan-out",
+			"architecture",
+			"Detects functions that call too many distinct functions",
+			core.SeverityLow,
+		),
+		maxFanOut: defaultMaxFanOut,
+	}
+}
+
+// Configure sets rule settings
+func (r *FunctionFanOutRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	r.maxFanOut = r.GetIntSetting("max_fan_out", defaultMaxFanOut)
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *FunctionFanOutRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile does nothing: callees are resolved through the type information.
+func (r *FunctionFanOutRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// AnalyzeGoProject reports each function over the threshold at its name.
+func (r *FunctionFanOutRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("function fan-out: nil Go project context")
+	}
+
+	var violations []*core.Violation
+	for _, fn := range metrics.Compute(ctx).Functions {
+		if fn.FanOut <= r.maxFanOut {
+			continue
+		}
+		v := r.CreateViolation(fn.File, fn.Line,
+			fmt.Sprintf("Function '%s' calls %d distinct functions (max: %d)", fn.Name, fn.FanOut, r.maxFanOut))
+		v.WithSuggestion("Group the calls that serve one step into a helper, so that the function reads as the sequence of its steps")
+		v.WithContext("fan_out", fn.FanOut)
+		v.WithContext("fan_in", fn.FanIn)
+		v.WithContext("function", fn.Name)
+		violations = append(violations, v)
+	}
+	return violations, nil
+}
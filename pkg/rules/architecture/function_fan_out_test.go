@@ -0,0 +1,45 @@
+package architecture
+
+import (
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctionFanOutRule(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"run/run.go": `package run
+
+import (
+	"fmt"
+	"strings"
+)
+
+func step() {}
+
+func Run(args []string) string {
+	step()
+	step()
+	fmt.Println(len(args))
+	return strings.Join(args, ",")
+}
+
+func Small() { step() }
+`,
+	})
+	rule := NewFunctionFanOutRule()
+	require.NoError(t, rule.Configure(map[string]any{"max_fan_out": 2}))
+
+	violations, err := rule.AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "run/run.go", v.File)
+	assert.Equal(t, 10, v.Line)
+	assert.Contains(t, v.Message, "Function 'Run' calls 3 distinct functions (max: 2)")
+	assert.Equal(t, 3, v.Context["fan_out"], "step is called twice but counted once")
+	assert.Equal(t, 0, v.Context["fan_in"])
+}
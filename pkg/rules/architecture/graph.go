@@ -0,0 +1,145 @@
+package architecture
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Graph is the package dependency graph of a project, classified into the
+// layers of its configuration, for `glint graph` to render.
+type Graph struct {
+	// Layers are the layer names of the architecture, in declaration order.
+	Layers   []string       `json:"layers"`
+	Packages []GraphPackage `json:"packages"`
+	Edges    []GraphEdge    `json:"edges"`
+}
+
+// GraphPackage is one package of the project.
+type GraphPackage struct {
+	Path string `json:"path"`
+	// Dir is the package directory relative to the project root.
+	Dir string `json:"dir"`
+	// Layer is empty for a package outside every layer.
+	Layer string `json:"layer,omitempty"`
+	// Violations counts the findings reported in the package's files; the
+	// caller fills it in from its own analysis.
+	Violations int `json:"violations"`
+}
+
+// GraphEdge is the dependency of one project package on another.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Imports are the file:line positions of the imports behind the edge.
+	Imports []string `json:"imports"`
+	// Violation marks an edge with an import the layer rules do not allow,
+	// as import-direction reports it.
+	Violation bool `json:"violation"`
+}
+
+// BuildGraph collects the graph of the project's packages. Imports of the
+// standard library and third-party modules are left out.
+func BuildGraph(ctx *core.GoProjectContext) (*Graph, error) {
+	graph, err := newImportGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &Graph{Layers: []string{}, Packages: []GraphPackage{}, Edges: []GraphEdge{}}
+	for _, l := range graph.model.layers {
+		result.Layers = append(result.Layers, l.name)
+	}
+
+	for _, node := range graph.ordered {
+		pkg := GraphPackage{Path: node.path, Dir: node.relDir}
+		if node.layer != nil {
+			pkg.Layer = node.layer.name
+		}
+		result.Packages = append(result.Packages, pkg)
+
+		edges := make(map[string]*GraphEdge)
+		for _, spec := range node.specs {
+			target, internal := graph.packages[spec.target]
+			if !internal {
+				continue
+			}
+			edge, ok := edges[spec.target]
+			if !ok {
+				edge = &GraphEdge{From: node.path, To: spec.target}
+				edges[spec.target] = edge
+			}
+			edge.Imports = append(edge.Imports, spec.position())
+			from := graph.model.layerOfFile(spec.file.RelPath)
+			if from != nil && target.layer != nil && !from.allows(target.layer) {
+				edge.Violation = true
+			}
+		}
+		for _, target := range node.imports {
+			result.Edges = append(result.Edges, *edges[target])
+		}
+	}
+	return result, nil
+}
+
+// CountViolations adds every finding to the package whose directory holds
+// its file. Findings outside Go packages are not counted.
+func (g *Graph) CountViolations(violations []*core.Violation) {
+	byDir := make(map[string]int, len(g.Packages))
+	for i, pkg := range g.Packages {
+		byDir[pkg.Dir] = i
+	}
+	for _, v := range violations {
+		if i, ok := byDir[path.Dir(filepath.ToSlash(v.File))]; ok {
+			g.Packages[i].Violations++
+		}
+	}
+}
+
+// FilterLayer keeps the packages of one layer and the edges that touch them,
+// with the packages at their other end.
+func (g *Graph) FilterLayer(name string) {
+	inLayer := make(map[string]bool)
+	keep := make(map[string]bool)
+	for _, pkg := range g.Packages {
+		if pkg.Layer == name {
+			inLayer[pkg.Path] = true
+			keep[pkg.Path] = true
+		}
+	}
+	g.filterEdges(func(edge GraphEdge) bool { return inLayer[edge.From] || inLayer[edge.To] }, keep)
+}
+
+// FilterViolations keeps the edges that violate the layer rules and the
+// packages at their ends.
+func (g *Graph) FilterViolations() {
+	g.filterEdges(func(edge GraphEdge) bool { return edge.Violation }, map[string]bool{})
+}
+
+// filterEdges keeps the edges matching keepEdge and the packages in keep or
+// at an end of a kept edge.
+func (g *Graph) filterEdges(keepEdge func(GraphEdge) bool, keep map[string]bool) {
+	edges := g.Edges[:0]
+	for _, edge := range g.Edges {
+		if keepEdge(edge) {
+			edges = append(edges, edge)
+			keep[edge.From] = true
+			keep[edge.To] = true
+		}
+	}
+	g.Edges = edges
+
+	packages := g.Packages[:0]
+	for _, pkg := range g.Packages {
+		if keep[pkg.Path] {
+			packages = append(packages, pkg)
+		}
+	}
+	g.Packages = packages
+}
+
+// HasLayer reports whether the architecture declares the layer.
+func (g *Graph) HasLayer(name string) bool {
+	return slices.Contains(g.Layers, name)
+}
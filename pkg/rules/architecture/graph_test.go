@@ -0,0 +1,59 @@
+package architecture
+
+import (
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGraph_ClassifiesPackagesAndMarksViolatingEdges(t *testing.T) {
+	project := rulestest.Project(t, layeredModule(map[string]string{
+		"services/user_service.go": `package services
+
+import (
+	"fmt"
+
+	"example.com/rulestest/handlers"
+	"example.com/rulestest/repository"
+)
+
+func Load() {
+	fmt.Println("load")
+	handlers.DoSomething()
+	repository.Find()
+}
+`,
+	}))
+
+	graph, err := BuildGraph(project)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"handler", "service", "repository"}, graph.Layers)
+	layers := map[string]string{}
+	for _, pkg := range graph.Packages {
+		layers[pkg.Dir] = pkg.Layer
+	}
+	assert.Equal(t, map[string]string{
+		"handlers": "handler", "services": "service", "repository": "repository", "models": "",
+	}, layers)
+
+	require.Len(t, graph.Edges, 2, "fmt is no package of the project")
+	assert.Equal(t, "example.com/rulestest/handlers", graph.Edges[0].To)
+	assert.True(t, graph.Edges[0].Violation)
+	assert.Equal(t, []string{"services/user_service.go:6"}, graph.Edges[0].Imports)
+	assert.False(t, graph.Edges[1].Violation)
+
+	graph.CountViolations([]*core.Violation{{File: "services/user_service.go"}, {File: "web/app.ts"}})
+	for _, pkg := range graph.Packages {
+		if pkg.Dir == "services" {
+			assert.Equal(t, 1, pkg.Violations)
+		}
+	}
+
+	graph.FilterLayer("repository")
+	assert.Len(t, graph.Edges, 1)
+	assert.Len(t, graph.Packages, 2)
+}
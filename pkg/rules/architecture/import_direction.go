@@ -1,8 +1,8 @@
 package architecture
 
 import (
-	"go/ast"
-	"strings"
+	"errors"
+	"fmt"
 
 	"github.com/aiseeq/glint/pkg/core"
 	"github.com/aiseeq/glint/pkg/rules"
@@ -12,8 +12,10 @@ func init() {
 	rules.Register(NewImportDirectionRule())
 }
 
-// ImportDirectionRule checks that imports respect layered architecture direction
-// Handler → Service → Repository (imports should flow downward)
+// ImportDirectionRule checks every import between the project's packages
+// against the dependencies the architecture allows. The layers come from the
+// `architecture` section of the config; without one, the layered preset
+// (Handler→Service→Repository, imports flow downward) applies.
 type ImportDirectionRule struct {
 	*rules.BaseRule
 }
@@ -24,103 +26,59 @@ func NewImportDirectionRule() *ImportDirectionRule {
 		BaseRule: rules.NewBaseRule(
 			"import-direction",
 			"architecture",
-			"Detects imports that violate layered architecture direction (Service→Handler, Repo→Service)",
+			"Detects imports between layers that the architecture does not allow (Service→Handler, Repo→Service)",
 			core.SeverityHigh,
 		),
 	}
 }
 
-// AnalyzeFile checks for import direction violations
-func (r *ImportDirectionRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
-	if !ctx.HasGoAST() || ctx.IsTestFile() {
-		return nil
-	}
-
-	currentLayer := determineLayerFromPath(ctx.RelPath)
-	if currentLayer == UnknownLayer {
-		return nil
-	}
-
-	var violations []*core.Violation
-
-	for _, imp := range ctx.GoAST.Imports {
-		if imp.Path == nil {
-			continue
-		}
+// RequiresSSA reports that the import graph of the typed packages is enough.
+func (r *ImportDirectionRule) RequiresSSA() bool { return false }
 
-		importPath := strings.Trim(imp.Path.Value, `"`)
-		importLayer := r.determineLayerFromImport(importPath)
-
-		if importLayer == UnknownLayer {
-			continue
-		}
-
-		// Check for wrong direction imports
-		if v := r.checkImportDirection(ctx, imp, currentLayer, importLayer, importPath); v != nil {
-			violations = append(violations, v)
-		}
-	}
-
-	return violations
-}
-
-func (r *ImportDirectionRule) determineLayerFromImport(importPath string) LayerType {
-	lowerPath := strings.ToLower(importPath)
-
-	if strings.Contains(lowerPath, "/handlers") || strings.Contains(lowerPath, "/handler/") ||
-		strings.Contains(lowerPath, "/routing/") {
-		return HandlerLayer
-	}
-	if strings.Contains(lowerPath, "/services") || strings.Contains(lowerPath, "/service/") {
-		return ServiceLayer
-	}
-	if strings.Contains(lowerPath, "/repository") || strings.Contains(lowerPath, "/repo/") ||
-		strings.Contains(lowerPath, "/repositories") {
-		return RepositoryLayer
-	}
-
-	return UnknownLayer
+// AnalyzeFile does nothing: the rule needs the import graph to know which
+// layer an imported package belongs to.
+func (r *ImportDirectionRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
 }
 
-func (r *ImportDirectionRule) checkImportDirection(
-	ctx *core.FileContext,
-	imp *ast.ImportSpec,
-	currentLayer, importLayer LayerType,
-	importPath string,
-) *core.Violation {
-	// Service importing from Handler (wrong direction)
-	if currentLayer == ServiceLayer && importLayer == HandlerLayer {
-		return r.createViolation(ctx, imp, "Service", "Handler", importPath)
+// AnalyzeGoProject reports each direct import of a layer the importing file's
+// layer may not depend on. Files and packages outside every layer are free.
+func (r *ImportDirectionRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("import direction: nil Go project context")
 	}
 
-	// Repository importing from Service (wrong direction)
-	if currentLayer == RepositoryLayer && importLayer == ServiceLayer {
-		return r.createViolation(ctx, imp, "Repository", "Service", importPath)
+	graph, err := newImportGraph(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	// Repository importing from Handler (wrong direction - skipping a layer)
-	if currentLayer == RepositoryLayer && importLayer == HandlerLayer {
-		return r.createViolation(ctx, imp, "Repository", "Handler", importPath)
+	var violations []*core.Violation
+	for _, node := range graph.ordered {
+		for _, spec := range node.specs {
+			target, internal := graph.packages[spec.target]
+			if !internal {
+				continue
+			}
+			from := graph.model.layerOfFile(spec.file.RelPath)
+			to := target.layer
+			if from == nil || to == nil || from.allows(to) {
+				continue
+			}
+			violations = append(violations, r.createViolation(spec, from, to))
+		}
 	}
-
-	return nil
+	return violations, nil
 }
 
-func (r *ImportDirectionRule) createViolation(
-	ctx *core.FileContext,
-	imp *ast.ImportSpec,
-	currentLayerName, importLayerName, importPath string,
-) *core.Violation {
-	pos := ctx.PositionFor(imp)
-
-	v := r.CreateViolation(ctx.RelPath, pos.Line,
-		currentLayerName+" imports from "+importLayerName+" (violates Handler→Service→Repository direction)")
-	v.WithCode(ctx.GetLine(pos.Line))
-	v.WithSuggestion("Imports should flow downward: Handler→Service→Repository. " +
-		"Consider restructuring to maintain proper dependency direction.")
-	v.WithContext("current_layer", currentLayerName)
-	v.WithContext("import_layer", importLayerName)
-	v.WithContext("import_path", importPath)
+func (r *ImportDirectionRule) createViolation(spec importSpec, from, to *layer) *core.Violation {
+	v := r.CreateViolation(spec.file.RelPath, spec.line,
+		fmt.Sprintf("Layer %q imports from layer %q (%s may import: %s)", from.name, to.name, from.name, from.allowedList()))
+	v.WithCode(spec.file.GetLine(spec.line))
+	v.WithSuggestion("Imports must follow the dependencies declared in the architecture config. " +
+		"Invert the dependency with an interface owned by " + from.name + ", or move the code to the layer it belongs to.")
+	v.WithContext("current_layer", from.name)
+	v.WithContext("import_layer", to.name)
+	v.WithContext("import_path", spec.target)
 
 	return v
 }
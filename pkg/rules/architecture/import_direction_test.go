@@ -4,10 +4,33 @@ import (
 	"testing"
 
 	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// layeredModule is a project in the layered preset's naming, with one empty
+// package per layer for the file under test to import.
+func layeredModule(files map[string]string) map[string]string {
+	module := map[string]string{
+		"handlers/handlers.go":     "package handlers\n\nfunc DoSomething() {}\n",
+		"services/services.go":     "package services\n\nfunc GetUser() {}\n",
+		"repository/repository.go": "package repository\n\nfunc Find() {}\n",
+		"models/models.go":         "package models\n\ntype User struct{}\n",
+	}
+	for name, content := range files {
+		module[name] = content
+	}
+	return module
+}
+
+func analyzeImportDirection(t *testing.T, project *core.GoProjectContext) []*core.Violation {
+	t.Helper()
+	violations, err := NewImportDirectionRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
 func TestImportDirectionRule_Metadata(t *testing.T) {
 	rule := NewImportDirectionRule()
 
@@ -17,209 +40,165 @@ func TestImportDirectionRule_Metadata(t *testing.T) {
 }
 
 func TestImportDirectionRule_ServiceImportsHandler(t *testing.T) {
-	rule := NewImportDirectionRule()
-
-	goCode := `package services
+	project := rulestest.Project(t, layeredModule(map[string]string{
+		"services/user_service.go": `package services
 
 import (
-	"myapp/handlers"
-	"myapp/models"
+	"example.com/rulestest/handlers"
+	"example.com/rulestest/models"
 )
 
-func GetUser() {
-	handlers.DoSomething()
-}
-`
-	ctx := createTestContext(t, "backend/services/user_service.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
+var _ models.User
 
-	require.NotEmpty(t, violations, "Expected violation for Service importing Handler")
-	assert.Contains(t, violations[0].Message, "Service imports from Handler")
+func Handle() {
+	handlers.DoSomething()
 }
+`,
+	}))
 
-func TestImportDirectionRule_RepoImportsService(t *testing.T) {
-	rule := NewImportDirectionRule()
-
-	goCode := `package repository
-
-import (
-	"myapp/services"
-)
+	violations := analyzeImportDirection(t, project)
 
-func SaveUser() {
-	services.Validate()
+	require.Len(t, violations, 1, "Expected violation for Service importing Handler")
+	assert.Equal(t, "services/user_service.go", violations[0].File)
+	assert.Equal(t, 4, violations[0].Line)
+	assert.Contains(t, violations[0].Message, `Layer "service" imports from layer "handler"`)
+	assert.Equal(t, "example.com/rulestest/handlers", violations[0].Context["import_path"])
 }
-`
-	ctx := createTestContext(t, "backend/repository/user_repository.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
 
-	require.NotEmpty(t, violations, "Expected violation for Repository importing Service")
-	assert.Contains(t, violations[0].Message, "Repository imports from Service")
-}
-
-func TestImportDirectionRule_RepoImportsHandler(t *testing.T) {
-	rule := NewImportDirectionRule()
-
-	goCode := `package repository
+func TestImportDirectionRule_RepoImportsUpperLayers(t *testing.T) {
+	project := rulestest.Project(t, layeredModule(map[string]string{
+		"repository/user_repository.go": `package repository
 
 import (
-	"myapp/handlers"
+	"example.com/rulestest/handlers"
+	"example.com/rulestest/services"
 )
 
 func SaveUser() {
-	handlers.GetContext()
+	services.GetUser()
+	handlers.DoSomething()
 }
-`
-	ctx := createTestContext(t, "backend/repo/user_repo.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
+`,
+	}))
 
-	require.NotEmpty(t, violations, "Expected violation for Repository importing Handler")
-	assert.Contains(t, violations[0].Message, "Repository imports from Handler")
+	violations := analyzeImportDirection(t, project)
+
+	require.Len(t, violations, 2)
+	assert.Contains(t, violations[0].Message, `Layer "repository" imports from layer "handler"`)
+	assert.Contains(t, violations[1].Message, `Layer "repository" imports from layer "service"`)
+	assert.Contains(t, violations[1].Message, "repository may import: no other layer")
 }
 
 func TestImportDirectionRule_CorrectDirection(t *testing.T) {
-	rule := NewImportDirectionRule()
+	project := rulestest.Project(t, layeredModule(map[string]string{
+		"handlers/user_handler.go": `package handlers
 
-	tests := []struct {
-		name string
-		path string
-		code string
-	}{
-		{
-			name: "handler imports service",
-			path: "backend/handlers/user_handler.go",
-			code: `package handlers
-
-import "myapp/services"
+import (
+	"example.com/rulestest/repository"
+	"example.com/rulestest/services"
+)
 
 func Handle() {
 	services.GetUser()
+	repository.Find() // skipping a level is allowed
 }
 `,
-		},
-		{
-			name: "service imports repository",
-			path: "backend/services/user_service.go",
-			code: `package services
+		"services/user_service.go": `package services
 
-import "myapp/repository"
+import "example.com/rulestest/repository"
 
-func GetUser() {
+func Load() {
 	repository.Find()
 }
 `,
-		},
-		{
-			name: "handler imports repository (skip level allowed)",
-			path: "backend/handlers/user_handler.go",
-			code: `package handlers
+		"shared/routing/admin_router.go": `package routing
 
-import "myapp/repository"
+import "example.com/rulestest/services"
 
-func Handle() {
-	repository.Find()
+func Setup() {
+	services.GetUser()
 }
 `,
-		},
-	}
+	}))
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ctx := createTestContext(t, tt.path, tt.code)
-			violations := rule.AnalyzeFile(ctx)
-			assert.Empty(t, violations, "Expected no violation for correct import direction")
-		})
-	}
+	assert.Empty(t, analyzeImportDirection(t, project), "Expected no violation for correct import direction")
 }
 
-func TestImportDirectionRule_ExternalImportsIgnored(t *testing.T) {
-	rule := NewImportDirectionRule()
-
-	goCode := `package services
+func TestImportDirectionRule_UnlayeredPackagesIgnored(t *testing.T) {
+	project := rulestest.Project(t, layeredModule(map[string]string{
+		"models/user.go": `package models
 
 import (
-	"fmt"
-	"net/http"
-	"github.com/lib/pq"
+	"example.com/rulestest/handlers"
+	"example.com/rulestest/services"
 )
 
-func GetUser() {
-	fmt.Println("test")
-}
-`
-	ctx := createTestContext(t, "backend/services/user_service.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
-
-	assert.Empty(t, violations, "External imports should not trigger violations")
+func Touch() {
+	handlers.DoSomething()
+	services.GetUser()
 }
-
-func TestImportDirectionRule_UnknownLayerIgnored(t *testing.T) {
-	rule := NewImportDirectionRule()
-
-	goCode := `package models
+`,
+		// "reports" contains "repo" as a substring but is not a repository package
+		"internal/reports/report.go": `package reports
 
 import (
-	"myapp/handlers"
-	"myapp/services"
-)
-
-type User struct{}
-`
-	ctx := createTestContext(t, "backend/models/user.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
-
-	assert.Empty(t, violations, "Unknown layers should not trigger violations")
-}
-
-func TestImportDirectionRule_TestFilesExcluded(t *testing.T) {
-	rule := NewImportDirectionRule()
-
-	goCode := `package services
+	"fmt"
 
-import "myapp/handlers"
+	"example.com/rulestest/services"
+)
 
-func TestGetUser() {
-	handlers.DoSomething()
+func Build() {
+	fmt.Println("report")
+	services.GetUser()
 }
-`
-	ctx := createTestContext(t, "backend/services/user_service_test.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
+`,
+	}))
 
-	assert.Empty(t, violations, "Test files should be excluded")
+	assert.Empty(t, analyzeImportDirection(t, project), "Unknown layers and external imports should not trigger violations")
 }
 
-func TestImportDirectionRule_ReportsPackageNotRepository(t *testing.T) {
-	rule := NewImportDirectionRule()
+func TestImportDirectionRule_ConfiguredHexagonalLayers(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"internal/domain/order.go": `package domain
 
-	// "reports" contains "repo" as a substring but is not a repository package
-	goCode := `package reports
+import "example.com/rulestest/internal/adapters/clock"
 
-import "myapp/services"
+type Order struct{}
 
-func Build() {
-	services.GetUser()
-}
-`
-	ctx := createTestContext(t, "internal/reports/report.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
+var _ = clock.Now
+`,
+		"internal/ports/ports.go": `package ports
 
-	assert.Empty(t, violations, "reports package must not be classified as repository layer")
-}
+import "example.com/rulestest/internal/domain"
 
-func TestImportDirectionRule_RoutingImportsHandler(t *testing.T) {
-	rule := NewImportDirectionRule()
+type Orders interface{ Get() domain.Order }
+`,
+		"internal/app/app.go": `package app
 
-	// Routing is handler-layer, importing from services is correct
-	goCode := `package routing
+import "example.com/rulestest/internal/ports"
 
-import "myapp/services"
+type App struct{ Orders ports.Orders }
+`,
+		"internal/adapters/http/server.go": `package http
 
-func Setup() {
-	services.GetUser()
-}
-`
-	ctx := createTestContext(t, "backend/shared/routing/admin_router.go", goCode)
-	violations := rule.AnalyzeFile(ctx)
+import "example.com/rulestest/internal/app"
 
-	assert.Empty(t, violations, "Routing (handler layer) importing services is correct")
+func Serve(*app.App) {}
+`,
+		"internal/adapters/clock/clock.go": "package clock\n\nfunc Now() int64 { return 0 }\n",
+	})
+	project.Config = core.DefaultConfig()
+	project.Config.Architecture = core.ArchitectureConfig{Layers: []core.LayerConfig{
+		{Name: "domain", Packages: []string{"internal/domain/**"}},
+		{Name: "ports", Packages: []string{"internal/ports/**"}, MayImport: []string{"domain"}},
+		{Name: "app", Packages: []string{"internal/app/**"}, MayImport: []string{"domain", "ports"}},
+		{Name: "adapters", Packages: []string{"internal/adapters/**"}, MayImport: []string{"app", "ports", "domain"}},
+	}}
+
+	violations := analyzeImportDirection(t, project)
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, "internal/domain/order.go", violations[0].File)
+	assert.Equal(t, 3, violations[0].Line)
+	assert.Contains(t, violations[0].Message, `Layer "domain" imports from layer "adapters" (domain may import: no other layer)`)
 }
@@ -0,0 +1,153 @@
+package architecture
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// importSpec is one import of a project package, as written in a file.
+type importSpec struct {
+	file   *core.FileContext
+	line   int
+	target string
+}
+
+// position renders where the import is written, as file:line.
+func (spec importSpec) position() string {
+	return spec.file.RelPath + ":" + strconv.Itoa(spec.line)
+}
+
+// describeChain renders a path of imports hop by hop, with the file:line of
+// each import.
+func describeChain(chain []importSpec) string {
+	hops := make([]string, len(chain))
+	for i, spec := range chain {
+		hops[i] = spec.position() + " imports " + spec.target
+	}
+	return strings.Join(hops, " → ")
+}
+
+// packageNode is a package of the project in the import graph.
+type packageNode struct {
+	path    string
+	relDir  string
+	layer   *layer
+	imports []string     // project packages only, sorted
+	specs   []importSpec // every import, the standard library's included
+}
+
+// importGraph is the import graph of the project's own packages, classified
+// into layers. Standard library and third-party packages are not nodes of it;
+// they appear only as the targets of specs.
+type importGraph struct {
+	model    *architectureModel
+	packages map[string]*packageNode
+	ordered  []*packageNode
+}
+
+// newImportGraph builds the graph from the loaded packages of a project.
+// Test files are left out: a test may reach across layers to set one up.
+func newImportGraph(ctx *core.GoProjectContext) (*importGraph, error) {
+	graph := &importGraph{
+		model:    newArchitectureModel(ctx.Config),
+		packages: make(map[string]*packageNode),
+	}
+	for _, pkgCtx := range ctx.Packages {
+		if pkgCtx == nil || pkgCtx.Package == nil || len(pkgCtx.Package.GoFiles) == 0 {
+			continue
+		}
+		pkg := pkgCtx.Package
+		if _, seen := graph.packages[pkg.PkgPath]; seen {
+			continue
+		}
+		relDir, err := filepath.Rel(ctx.ProjectRoot, filepath.Dir(pkg.GoFiles[0]))
+		if err != nil {
+			return nil, fmt.Errorf("import graph: locate package %s: %w", pkg.PkgPath, err)
+		}
+		node := &packageNode{
+			path:   pkg.PkgPath,
+			relDir: filepath.ToSlash(relDir),
+			layer:  graph.model.layerOfPackage(relDir, pkg.PkgPath),
+		}
+		// Only the analyzed files count: those the configuration excludes
+		// are loaded for type checking but have no file context.
+		for _, fileCtx := range pkgCtx.Files {
+			if fileCtx.IsTestFile() || !fileCtx.HasGoAST() {
+				continue
+			}
+			for _, imp := range fileCtx.GoAST.Imports {
+				target, err := strconv.Unquote(imp.Path.Value)
+				if err != nil {
+					return nil, fmt.Errorf("import graph: %s: import path %s: %w", fileCtx.RelPath, imp.Path.Value, err)
+				}
+				node.specs = append(node.specs, importSpec{
+					file:   fileCtx,
+					line:   ctx.FileSet.Position(imp.Pos()).Line,
+					target: target,
+				})
+			}
+		}
+		graph.packages[pkg.PkgPath] = node
+		graph.ordered = append(graph.ordered, node)
+	}
+
+	for _, node := range graph.ordered {
+		seen := make(map[string]bool)
+		for _, spec := range node.specs {
+			if _, internal := graph.packages[spec.target]; internal && !seen[spec.target] {
+				seen[spec.target] = true
+				node.imports = append(node.imports, spec.target)
+			}
+		}
+		sort.Strings(node.imports)
+	}
+	sort.Slice(graph.ordered, func(i, j int) bool { return graph.ordered[i].path < graph.ordered[j].path })
+	return graph, nil
+}
+
+// firstSpec returns the first import of target written in node's files.
+func (node *packageNode) firstSpec(target string) (importSpec, bool) {
+	for _, spec := range node.specs {
+		if spec.target == target {
+			return spec, true
+		}
+	}
+	return importSpec{}, false
+}
+
+// forbiddenReach follows imports breadth-first from start through packages
+// outside every layer and returns the first layer reached that from may not
+// depend on, with the import path chain leading to it.
+func (g *importGraph) forbiddenReach(from *layer, start *packageNode) (*layer, []string) {
+	parent := map[string]string{start.path: ""}
+	queue := []*packageNode{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range node.imports {
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			parent[next] = node.path
+			target := g.packages[next]
+			if target.layer == nil {
+				queue = append(queue, target)
+				continue
+			}
+			if from.allows(target.layer) {
+				continue
+			}
+			var chain []string
+			for p := next; p != ""; p = parent[p] {
+				chain = append([]string{p}, chain...)
+			}
+			return target.layer, chain
+		}
+	}
+	return nil, nil
+}
@@ -1,6 +1,8 @@
 package architecture
 
 import (
+	"errors"
+	"fmt"
 	"go/ast"
 	"go/token"
 	"regexp"
@@ -14,7 +16,12 @@ func init() {
 	rules.Register(NewLayerViolationRule())
 }
 
-// LayerViolationRule detects architecture violations (Handler→Service→Repository)
+// LayerViolationRule detects code that does not belong in its layer — SQL in
+// handlers and services, HTTP in repositories — and dependencies on a
+// forbidden layer that go around import-direction through packages outside
+// every layer. Layers and their roles come from the `architecture` section of
+// the config; without one, the layered preset (Handler→Service→Repository)
+// applies.
 type LayerViolationRule struct {
 	*rules.BaseRule
 }
@@ -25,14 +32,46 @@ func NewLayerViolationRule() *LayerViolationRule {
 		BaseRule: rules.NewBaseRule(
 			"layer-violation",
 			"architecture",
-			"Detects violations of layered architecture (Handler→Service→Repository)",
+			"Detects violations of layered architecture (Handler→Service→Repository, or the layers of the config)",
 			core.SeverityCritical,
 		),
 	}
 }
 
-// AnalyzeFile checks for architecture violations
+// RequiresSSA reports that the import graph of the typed packages is enough.
+func (r *LayerViolationRule) RequiresSSA() bool { return false }
+
+// AnalyzeGoProject checks the code of every file against its layer's role,
+// then follows imports through unlayered packages to the layers they reach.
+func (r *LayerViolationRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("layer violation: nil Go project context")
+	}
+
+	graph, err := newImportGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fileCtx := range ctx.Files {
+		violations = append(violations, r.checkFile(fileCtx, graph.model)...)
+	}
+	for _, node := range graph.ordered {
+		for _, spec := range node.specs {
+			if v := r.checkReach(graph, spec); v != nil {
+				violations = append(violations, v)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// AnalyzeFile checks one file's code against the role of its layer.
 func (r *LayerViolationRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
+	return r.checkFile(ctx, newArchitectureModel(ctx.Config))
+}
+
+func (r *LayerViolationRule) checkFile(ctx *core.FileContext, model *architectureModel) []*core.Violation {
 	if !ctx.HasGoAST() || ctx.IsTestFile() {
 		return nil
 	}
@@ -42,19 +81,19 @@ func (r *LayerViolationRule) AnalyzeFile(ctx *core.FileContext) []*core.Violatio
 		return nil
 	}
 
-	layer := determineLayerFromPath(ctx.RelPath)
-	if layer == UnknownLayer {
+	l := model.layerOfFile(ctx.RelPath)
+	if l == nil {
 		return nil
 	}
 
 	var violations []*core.Violation
 
 	// Check for layer-specific violations
-	switch layer {
+	switch l.role {
 	case HandlerLayer:
-		violations = append(violations, r.checkHandlerViolations(ctx)...)
+		violations = append(violations, r.checkSQLViolations(ctx, "Handler")...)
 	case ServiceLayer:
-		violations = append(violations, r.checkServiceViolations(ctx)...)
+		violations = append(violations, r.checkSQLViolations(ctx, "Service")...)
 	case RepositoryLayer:
 		violations = append(violations, r.checkRepositoryViolations(ctx)...)
 	}
@@ -62,42 +101,51 @@ func (r *LayerViolationRule) AnalyzeFile(ctx *core.FileContext) []*core.Violatio
 	return violations
 }
 
-// checkHandlerViolations checks for violations in handler layer
-func (r *LayerViolationRule) checkHandlerViolations(ctx *core.FileContext) []*core.Violation {
-	var violations []*core.Violation
+// checkReach reports the shortest path from an import of an unlayered
+// package to a layer the importing file's layer may not depend on. Direct
+// imports are import-direction's findings.
+func (r *LayerViolationRule) checkReach(graph *importGraph, spec importSpec) *core.Violation {
+	from := graph.model.layerOfFile(spec.file.RelPath)
+	start, internal := graph.packages[spec.target]
+	if from == nil || !internal || start.layer != nil {
+		return nil
+	}
+	reached, chain := graph.forbiddenReach(from, start)
+	if reached == nil {
+		return nil
+	}
+	return r.indirectViolation(spec, from, reached, chain)
+}
 
-	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.CallExpr:
-			if v := r.checkDirectSQLCall(ctx, node, "Handler"); v != nil {
-				violations = append(violations, v)
-			}
-		case *ast.BasicLit:
-			if node.Kind == token.STRING {
-				if v := r.checkSQLString(ctx, node, "Handler"); v != nil {
-					violations = append(violations, v)
-				}
-			}
-		}
-		return true
-	})
+func (r *LayerViolationRule) indirectViolation(spec importSpec, from, to *layer, chain []string) *core.Violation {
+	v := r.CreateViolation(spec.file.RelPath, spec.line,
+		fmt.Sprintf("Layer %q reaches layer %q through packages outside every layer: %s",
+			from.name, to.name, strings.Join(chain, " → ")))
+	v.WithCode(spec.file.GetLine(spec.line))
+	v.WithSuggestion("Assign the intermediate packages to a layer, or cut the dependency on " + to.name +
+		" (" + from.name + " may import: " + from.allowedList() + ")")
+	v.WithContext("layer", from.name)
+	v.WithContext("reached_layer", to.name)
+	v.WithContext("path", strings.Join(chain, " → "))
+	v.WithContext("pattern", "indirect_import")
 
-	return violations
+	return v
 }
 
-// checkServiceViolations checks for violations in service layer
-func (r *LayerViolationRule) checkServiceViolations(ctx *core.FileContext) []*core.Violation {
+// checkSQLViolations checks for SQL in a handler or service layer; layer
+// names it in the message.
+func (r *LayerViolationRule) checkSQLViolations(ctx *core.FileContext, layer string) []*core.Violation {
 	var violations []*core.Violation
 
 	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.CallExpr:
-			if v := r.checkDirectSQLCall(ctx, node, "Service"); v != nil {
+			if v := r.checkDirectSQLCall(ctx, node, layer); v != nil {
 				violations = append(violations, v)
 			}
 		case *ast.BasicLit:
 			if node.Kind == token.STRING {
-				if v := r.checkSQLString(ctx, node, "Service"); v != nil {
+				if v := r.checkSQLString(ctx, node, layer); v != nil {
 					violations = append(violations, v)
 				}
 			}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -203,3 +204,64 @@ func splitLines(s string) []string {
 	}
 	return lines
 }
+
+func TestLayerViolationRule_IndirectImportThroughUnlayeredPackage(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"internal/domain/order.go": `package domain
+
+import "example.com/rulestest/internal/util"
+
+var Name = util.Name
+`,
+		"internal/util/util.go": `package util
+
+import "example.com/rulestest/internal/convert"
+
+var Name = convert.Name
+`,
+		"internal/convert/convert.go": `package convert
+
+import "example.com/rulestest/internal/adapters/http"
+
+var Name = http.Name
+`,
+		"internal/adapters/http/server.go": "package http\n\nvar Name = \"http\"\n",
+	})
+	project.Config = core.DefaultConfig()
+	project.Config.Architecture = core.ArchitectureConfig{Layers: []core.LayerConfig{
+		{Name: "domain", Packages: []string{"internal/domain/**"}},
+		{Name: "adapters", Packages: []string{"internal/adapters/**"}, MayImport: []string{"domain"}},
+	}}
+
+	violations, err := NewLayerViolationRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, "internal/domain/order.go", violations[0].File)
+	assert.Equal(t, 3, violations[0].Line)
+	assert.Equal(t, "example.com/rulestest/internal/util → example.com/rulestest/internal/convert → "+
+		"example.com/rulestest/internal/adapters/http", violations[0].Context["path"])
+}
+
+func TestLayerViolationRule_ConfiguredRole(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"internal/adapters/http/user.go": `package http
+
+import "database/sql"
+
+func GetUser(db *sql.DB) {
+	db.Query("SELECT * FROM users")
+}
+`,
+	})
+	project.Config = core.DefaultConfig()
+	project.Config.Architecture = core.ArchitectureConfig{Layers: []core.LayerConfig{
+		{Name: "adapters-http", Packages: []string{"internal/adapters/http"}, Role: core.LayerRoleHandler},
+	}}
+
+	violations, err := NewLayerViolationRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, violations, "a layer with the handler role must not run SQL")
+	assert.Equal(t, "internal/adapters/http/user.go", violations[0].File)
+}
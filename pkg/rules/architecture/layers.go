@@ -1,12 +1,23 @@
 package architecture
 
-import "strings"
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 
-// LayerType represents an architectural layer
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// LayerType is the role of an architectural layer: what layer-violation
+// expects its code not to contain.
 type LayerType int
 
 const (
-	// UnknownLayer is the layer of a file that matches no known convention.
+	// UnknownLayer is the role of a file outside every layer, or of a layer
+	// declared without a role.
 	UnknownLayer LayerType = iota
 	// HandlerLayer is the transport layer: HTTP handlers, routers, controllers.
 	HandlerLayer
@@ -16,43 +27,133 @@ const (
 	RepositoryLayer
 )
 
-// determineLayerFromPath determines the architectural layer of a file path.
-// It is the single source of layer classification for the rules of this
-// package (layer-violation, import-direction).
-func determineLayerFromPath(path string) LayerType {
-	lowerPath := strings.ToLower(path)
+// String returns the name findings use for the role.
+func (t LayerType) String() string {
+	switch t {
+	case HandlerLayer:
+		return "Handler"
+	case ServiceLayer:
+		return "Service"
+	case RepositoryLayer:
+		return "Repository"
+	}
+	return "Unknown"
+}
 
-	if strings.Contains(lowerPath, "handler") || strings.Contains(lowerPath, "/routing/") {
+func layerTypeFromRole(role string) LayerType {
+	switch role {
+	case core.LayerRoleHandler:
 		return HandlerLayer
-	}
-	if strings.Contains(lowerPath, "service") {
+	case core.LayerRoleService:
 		return ServiceLayer
-	}
-	if isRepositoryPath(lowerPath) {
+	case core.LayerRoleRepository:
 		return RepositoryLayer
 	}
-
 	return UnknownLayer
 }
 
-// isRepositoryPath reports whether a path belongs to the repository layer.
-// It matches whole path segments, not substrings: "internal/reports/" must
-// not be classified as a repository just because "reports" contains "repo".
-func isRepositoryPath(lowerPath string) bool {
-	for _, segment := range strings.Split(lowerPath, "/") {
-		if isRepositorySegment(strings.TrimSuffix(segment, ".go")) {
-			return true
+// layer is one layer of the configured architecture.
+type layer struct {
+	name      string
+	patterns  []string
+	mayImport map[string]bool
+	role      LayerType
+}
+
+// allows reports whether code of this layer may import a package of other.
+func (l *layer) allows(other *layer) bool {
+	return l.name == other.name || l.mayImport[other.name]
+}
+
+// allowedList names the layers this one may import, for messages.
+func (l *layer) allowedList() string {
+	names := make([]string, 0, len(l.mayImport))
+	for name := range l.mayImport {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "no other layer"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// architectureModel is the layer graph of the project's configuration. It is
+// the single source of layer classification for the rules of this package
+// (layer-violation, import-direction).
+type architectureModel struct {
+	layers []*layer
+}
+
+// newArchitectureModel resolves the architecture of cfg; a nil config or one
+// without an architecture section gets the layered preset.
+func newArchitectureModel(cfg *core.Config) *architectureModel {
+	var arch core.ArchitectureConfig
+	if cfg != nil {
+		arch = cfg.Architecture
+	}
+	model := &architectureModel{}
+	for _, declared := range arch.ResolvedLayers() {
+		l := &layer{
+			name:      declared.Name,
+			patterns:  declared.Packages,
+			mayImport: make(map[string]bool, len(declared.MayImport)),
+			role:      layerTypeFromRole(declared.Role),
+		}
+		for _, target := range declared.MayImport {
+			l.mayImport[target] = true
+		}
+		model.layers = append(model.layers, l)
+	}
+	return model
+}
+
+// match returns the first layer with a glob matching one of the candidates.
+func (m *architectureModel) match(candidates ...string) *layer {
+	for _, l := range m.layers {
+		if matchesAny(l.patterns, candidates...) {
+			return l
+		}
+	}
+	return nil
+}
+
+// matchesAny reports whether a glob matches one of the candidate paths. The
+// project root itself, ".", is no candidate: `**` would match it.
+func matchesAny(patterns []string, candidates ...string) bool {
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if candidate == "" || candidate == "." {
+				continue
+			}
+			if ok, err := doublestar.Match(pattern, candidate); err == nil && ok {
+				return true
+			}
 		}
 	}
 	return false
 }
 
-// isRepositorySegment reports whether a single path segment (directory name
-// or file name without extension) denotes the repository layer.
-func isRepositorySegment(segment string) bool {
-	switch segment {
-	case "repo", "repository", "repositories":
-		return true
+// layerOfPackage classifies a package by its directory relative to the
+// project root and by its import path.
+func (m *architectureModel) layerOfPackage(relDir, importPath string) *layer {
+	return m.match(filepath.ToSlash(relDir), importPath)
+}
+
+// layerOfFile classifies a file by its package directory and, failing that,
+// by its own path.
+func (m *architectureModel) layerOfFile(relPath string) *layer {
+	slashed := filepath.ToSlash(relPath)
+	if l := m.match(path.Dir(slashed)); l != nil {
+		return l
 	}
-	return strings.HasSuffix(segment, "_repo") || strings.HasSuffix(segment, "_repository")
+	return m.match(slashed)
+}
+
+// determineLayerFromPath returns the role of a file under the layered preset.
+func determineLayerFromPath(relPath string) LayerType {
+	if l := newArchitectureModel(nil).layerOfFile(relPath); l != nil {
+		return l.role
+	}
+	return UnknownLayer
 }
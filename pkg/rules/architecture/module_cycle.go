@@ -0,0 +1,246 @@
+package architecture
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewModuleCycleRule())
+}
+
+// ModuleCycleRule reports import cycles between the modules declared in the
+// `architecture.modules` section of the config. Go forbids cycles between
+// packages, but not between groups of them: billing/invoice may import
+// users/profile while users/notify imports billing/api, and the two modules
+// can no longer be built, tested or split apart one without the other.
+type ModuleCycleRule struct {
+	*rules.BaseRule
+}
+
+// NewModuleCycleRule creates the rule
+func NewModuleCycleRule() *ModuleCycleRule {
+	return &ModuleCycleRule{
+		BaseRule: rules.NewBaseRule(
+			"module-cycle",
+			"architecture",
+			"Detects import cycles between the package groups declared as modules in the architecture config",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// RequiresSSA reports that the import graph of the typed packages is enough.
+func (r *ModuleCycleRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile does nothing: a cycle is a property of the whole graph.
+func (r *ModuleCycleRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// moduleGraph has one edge per pair of modules with an import between them,
+// carried by the first such import in package order.
+type moduleGraph map[string]map[string]importSpec
+
+// AnalyzeGoProject reports the shortest cycle of every group of modules that
+// depend on each other, at its first import.
+func (r *ModuleCycleRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil || ctx.Config == nil {
+		return nil, errors.New("module cycle: Go project context without config")
+	}
+
+	graph, err := newImportGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	modules := ctx.Config.Architecture.Modules
+	moduleOf := make(map[string]string, len(graph.ordered))
+	for _, node := range graph.ordered {
+		for _, module := range modules {
+			if matchesAny(module.Packages, node.relDir, node.path) {
+				moduleOf[node.path] = module.Name
+				break
+			}
+		}
+	}
+
+	edges := make(moduleGraph)
+	for _, node := range graph.ordered {
+		from, ok := moduleOf[node.path]
+		if !ok {
+			continue
+		}
+		for _, spec := range node.specs {
+			to, ok := moduleOf[spec.target]
+			if !ok || to == from {
+				continue
+			}
+			if edges[from] == nil {
+				edges[from] = make(map[string]importSpec)
+			}
+			if _, seen := edges[from][to]; !seen {
+				edges[from][to] = spec
+			}
+		}
+	}
+
+	var violations []*core.Violation
+	for _, component := range edges.stronglyConnected() {
+		if len(component) < 2 {
+			continue
+		}
+		if cycle := edges.shortestCycle(component); cycle != nil {
+			violations = append(violations, r.createViolation(moduleOf, cycle))
+		}
+	}
+	return violations, nil
+}
+
+func (r *ModuleCycleRule) createViolation(moduleOf map[string]string, cycle []importSpec) *core.Violation {
+	names := []string{moduleOf[cycle[len(cycle)-1].target]}
+	for _, spec := range cycle {
+		names = append(names, moduleOf[spec.target])
+	}
+	path := strings.Join(names, " → ")
+
+	first := cycle[0]
+	v := r.CreateViolation(first.file.RelPath, first.line,
+		"Import cycle between modules "+path+": "+describeChain(cycle))
+	v.WithCode(first.file.GetLine(first.line))
+	v.WithSuggestion("Break the cycle at one import: move the shared code into a module both can depend on, " +
+		"or invert the dependency with an interface owned by the importing module")
+	v.WithContext("cycle", path)
+
+	return v
+}
+
+// sortedModules returns the modules with outgoing edges, in name order.
+func (g moduleGraph) sortedModules() []string {
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// targets returns the modules a module imports, in name order.
+func (g moduleGraph) targets(from string) []string {
+	names := make([]string, 0, len(g[from]))
+	for name := range g[from] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// (Tarjan), each sorted by name, in a stable order.
+func (g moduleGraph) stronglyConnected() [][]string {
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var visit func(string)
+	visit = func(module string) {
+		index[module] = len(index)
+		lowlink[module] = index[module]
+		stack = append(stack, module)
+		onStack[module] = true
+
+		for _, next := range g.targets(module) {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				lowlink[module] = min(lowlink[module], lowlink[next])
+			} else if onStack[next] {
+				lowlink[module] = min(lowlink[module], index[next])
+			}
+		}
+
+		if lowlink[module] != index[module] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == module {
+				break
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+
+	for _, module := range g.sortedModules() {
+		if _, visited := index[module]; !visited {
+			visit(module)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// shortestCycle returns the imports of the shortest cycle through the
+// component, searched breadth-first from each of its modules in name order.
+func (g moduleGraph) shortestCycle(component []string) []importSpec {
+	inComponent := make(map[string]bool, len(component))
+	for _, module := range component {
+		inComponent[module] = true
+	}
+
+	var best []importSpec
+	for _, start := range component {
+		parent := map[string]string{start: ""}
+		queue := []string{start}
+		for len(queue) > 0 {
+			module := queue[0]
+			queue = queue[1:]
+			found := false
+			for _, next := range g.targets(module) {
+				if !inComponent[next] {
+					continue
+				}
+				if next == start {
+					cycle := g.walkBack(parent, module, start)
+					if best == nil || len(cycle) < len(best) {
+						best = cycle
+					}
+					found = true
+					break
+				}
+				if _, seen := parent[next]; !seen {
+					parent[next] = module
+					queue = append(queue, next)
+				}
+			}
+			if found {
+				break
+			}
+		}
+	}
+	return best
+}
+
+// walkBack turns the BFS parents from start to last, closed by the import
+// from last back to start, into the cycle's imports.
+func (g moduleGraph) walkBack(parent map[string]string, last, start string) []importSpec {
+	modules := []string{last}
+	for module := last; module != start; {
+		module = parent[module]
+		modules = append([]string{module}, modules...)
+	}
+	cycle := make([]importSpec, 0, len(modules))
+	for i := 0; i+1 < len(modules); i++ {
+		cycle = append(cycle, g[modules[i]][modules[i+1]])
+	}
+	return append(cycle, g[last][start])
+}
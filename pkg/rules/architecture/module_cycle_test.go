@@ -0,0 +1,88 @@
+package architecture
+
+import (
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withModules(project *core.GoProjectContext, modules ...core.ModuleConfig) {
+	project.Config = core.DefaultConfig()
+	project.Config.Architecture.Modules = modules
+}
+
+func TestModuleCycleRule_ReportsShortestCycle(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"internal/billing/invoice/invoice.go": `package invoice
+
+import "example.com/rulestest/internal/users/profile"
+
+var Owner = profile.Name
+`,
+		"internal/billing/api/api.go":       "package api\n\nvar Plan = \"pro\"\n",
+		"internal/users/profile/profile.go": "package profile\n\nvar Name = \"user\"\n",
+		"internal/users/notify/notify.go": `package notify
+
+import "example.com/rulestest/internal/billing/api"
+
+var Plan = api.Plan
+`,
+		"internal/audit/audit.go": `package audit
+
+import "example.com/rulestest/internal/users/profile"
+
+var Name = profile.Name
+`,
+	})
+	withModules(project,
+		core.ModuleConfig{Name: "billing", Packages: []string{"internal/billing/**"}},
+		core.ModuleConfig{Name: "users", Packages: []string{"internal/users/**"}},
+		core.ModuleConfig{Name: "audit", Packages: []string{"internal/audit/**"}},
+	)
+
+	violations, err := NewModuleCycleRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 1, "audit only imports users and is no part of the cycle")
+	v := violations[0]
+	assert.Equal(t, "internal/billing/invoice/invoice.go", v.File)
+	assert.Equal(t, 3, v.Line)
+	assert.Equal(t, "billing → users → billing", v.Context["cycle"])
+	assert.Contains(t, v.Message, "internal/billing/invoice/invoice.go:3 imports example.com/rulestest/internal/users/profile")
+	assert.Contains(t, v.Message, "internal/users/notify/notify.go:3 imports example.com/rulestest/internal/billing/api")
+}
+
+func TestModuleCycleRule_PicksShortestCycleInComponent(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"a/a.go":             "package a\n\nimport \"example.com/rulestest/b\"\n\nvar X = b.X\n",
+		"b/b.go":             "package b\n\nimport \"example.com/rulestest/c/one\"\n\nvar X = one.X\n",
+		"c/one/one.go":       "package one\n\nvar X = 1\n",
+		"c/two/two.go":       "package two\n\nimport (\n\t\"example.com/rulestest/a/inner\"\n\t\"example.com/rulestest/b/inner2\"\n)\n\nvar X = inner.X + inner2.X\n",
+		"a/inner/inner.go":   "package inner\n\nvar X = 1\n",
+		"b/inner2/inner2.go": "package inner2\n\nvar X = 1\n",
+	})
+	withModules(project,
+		core.ModuleConfig{Name: "a", Packages: []string{"a/**"}},
+		core.ModuleConfig{Name: "b", Packages: []string{"b/**"}},
+		core.ModuleConfig{Name: "c", Packages: []string{"c/**"}},
+	)
+
+	violations, err := NewModuleCycleRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 1, "one strongly connected component, one finding")
+	assert.Equal(t, "b → c → b", violations[0].Context["cycle"])
+}
+
+func TestModuleCycleRule_NoModulesNoFindings(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{
+		"a/a.go": "package a\n\nvar X = 1\n",
+	})
+
+	violations, err := NewModuleCycleRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,109 @@
+package architecture
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/metrics"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+const (
+	defaultMaxEfferentCoupling = 15
+	// defaultMaxDistance of 0 leaves the main-sequence check off: most
+	// projects have concrete packages everything depends on, and only a team
+	// that has decided otherwise should hear about each of them.
+	defaultMaxDistance = 0.0
+)
+
+func init() {
+	rules.Register(NewPackageCouplingRule())
+}
+
+// PackageCouplingRule reports packages that depend on too many others of the
+// project (efferent coupling) and, when max_distance is set, packages far from
+// the main sequence: stable and concrete, or abstract and unused.
+type PackageCouplingRule struct {
+	*rules.BaseRule
+	maxEfferent int
+	maxDistance float64
+}
+
+// NewPackageCouplingRule creates the rule
+func NewPackageCouplingRule() *PackageCouplingRule {
+	return &PackageCouplingRule{
+		BaseRule: rules.NewBaseRule(
+			"package-coupling",
+			"architecture",
+			"Detects packages with too many dependencies on other project packages, or far from the main sequence",
+			core.SeverityMedium,
+		),
+		maxEfferent: defaultMaxEfferentCoupling,
+		maxDistance: defaultMaxDistance,
+	}
+}
+
+// Configure sets rule settings
+func (r *PackageCouplingRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	r.maxEfferent = r.GetIntSetting("max_efferent_coupling", defaultMaxEfferentCoupling)
+	r.maxDistance = r.GetFloat64Setting("max_distance", defaultMaxDistance)
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *PackageCouplingRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile does nothing: coupling is a property of the import graph.
+func (r *PackageCouplingRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// AnalyzeGoProject reports each package over a threshold at its package clause.
+func (r *PackageCouplingRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("package coupling: nil Go project context")
+	}
+
+	var violations []*core.Violation
+	for _, pkg := range metrics.Compute(ctx).Packages {
+		if pkg.File == "" {
+			continue
+		}
+		if pkg.Efferent > r.maxEfferent {
+			v := r.CreateViolation(pkg.File, pkg.Line,
+				fmt.Sprintf("Package %s depends on %d project packages (max: %d)", pkg.Path, pkg.Efferent, r.maxEfferent))
+			v.WithSuggestion("Split the package along the dependencies its parts actually use, or depend on interfaces of one package instead of many")
+			r.withMetrics(v, pkg)
+			violations = append(violations, v)
+		}
+		if r.maxDistance > 0 && pkg.Distance > r.maxDistance {
+			v := r.CreateViolation(pkg.File, pkg.Line,
+				fmt.Sprintf("Package %s is %.2f from the main sequence (max: %.2f): %s",
+					pkg.Path, pkg.Distance, r.maxDistance, zoneOf(pkg)))
+			v.WithSuggestion("Balance abstractness against stability: extract interfaces from a package many depend on, or drop abstractions nobody uses")
+			r.withMetrics(v, pkg)
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+func (r *PackageCouplingRule) withMetrics(v *core.Violation, pkg metrics.PackageMetrics) {
+	v.WithContext("afferent", pkg.Afferent)
+	v.WithContext("efferent", pkg.Efferent)
+	v.WithContext("instability", pkg.Instability)
+	v.WithContext("abstractness", pkg.Abstractness)
+	v.WithContext("distance", pkg.Distance)
+}
+
+// zoneOf names the side of the main sequence a package strays to.
+func zoneOf(pkg metrics.PackageMetrics) string {
+	if pkg.Abstractness+pkg.Instability < 1 {
+		return "stable and concrete, painful to change"
+	}
+	return "abstract and unstable, abstractions nobody depends on"
+}
@@ -0,0 +1,72 @@
+package architecture
+
+import (
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func couplingProject(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"a/a.go": "package a\n\nvar A = 1\n",
+		"b/b.go": "package b\n\nvar B = 2\n",
+		"c/c.go": "package c\n\nvar C = 3\n",
+		"hub/hub.go": `package hub
+
+import (
+	"example.com/rulestest/a"
+	"example.com/rulestest/b"
+	"example.com/rulestest/c"
+)
+
+var Sum = a.A + b.B + c.C
+`,
+	}
+}
+
+func TestPackageCouplingRule_ReportsEfferentCoupling(t *testing.T) {
+	project := rulestest.Project(t, couplingProject(t))
+	rule := NewPackageCouplingRule()
+	require.NoError(t, rule.Configure(map[string]any{"max_efferent_coupling": 2}))
+
+	violations, err := rule.AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "hub/hub.go", v.File)
+	assert.Equal(t, 1, v.Line)
+	assert.Contains(t, v.Message, "depends on 3 project packages (max: 2)")
+	assert.Equal(t, 3, v.Context["efferent"])
+}
+
+func TestPackageCouplingRule_DistanceOffByDefault(t *testing.T) {
+	project := rulestest.Project(t, couplingProject(t))
+
+	violations, err := NewPackageCouplingRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	assert.Empty(t, violations, "a, b and c are stable and concrete, but max_distance is unset")
+}
+
+func TestPackageCouplingRule_ReportsDistance(t *testing.T) {
+	project := rulestest.Project(t, couplingProject(t))
+	rule := NewPackageCouplingRule()
+	require.NoError(t, rule.Configure(map[string]any{"max_distance": 0.5}))
+
+	violations, err := rule.AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 3)
+	for _, v := range violations {
+		assert.Contains(t, v.Message, "1.00 from the main sequence (max: 0.50): stable and concrete")
+		assert.Equal(t, 1.0, v.Context["distance"])
+	}
+}
+
+func TestPackageCouplingRule_NilContext(t *testing.T) {
+	_, err := NewPackageCouplingRule().AnalyzeGoProject(nil)
+	assert.Error(t, err)
+}
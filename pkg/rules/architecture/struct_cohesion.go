@@ -0,0 +1,78 @@
+package architecture
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/metrics"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+const defaultMaxLCOM = 2
+
+func init() {
+	rules.Register(NewStructCohesionRule())
+}
+
+// StructCohesionRule reports structs whose methods fall apart into groups that
+// share no field and call no method of one another (LCOM4). Unlike the
+// method-name buckets of solid-srp, the groups are measured, and the finding
+// names how many there are.
+type StructCohesionRule struct {
+	*rules.BaseRule
+	maxLCOM int
+}
+
+// NewStructCohesionRule creates the rule
+func NewStructCohesionRule() *StructCohesionRule {
+	return &StructCohesionRule{
+		BaseRule: rules.NewBaseRule(
+			"struct-cohesion",
+			"architecture",
+			"Detects structs whose methods form unrelated groups (LCOM4) and could be split",
+			core.SeverityMedium,
+		),
+		maxLCOM: defaultMaxLCOM,
+	}
+}
+
+// Configure sets rule settings
+func (r *StructCohesionRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	r.maxLCOM = r.GetIntSetting("max_lcom", defaultMaxLCOM)
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *StructCohesionRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile does nothing: a struct's methods may span files.
+func (r *StructCohesionRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// AnalyzeGoProject reports each struct over the threshold at its declaration.
+func (r *StructCohesionRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("struct cohesion: nil Go project context")
+	}
+
+	var violations []*core.Violation
+	for _, st := range metrics.Compute(ctx).Structs {
+		if st.LCOM <= r.maxLCOM {
+			continue
+		}
+		v := r.CreateViolation(st.File, st.Line,
+			fmt.Sprintf("Struct '%s' has %d unrelated method groups (LCOM4 %d, max: %d) over %d methods and %d fields",
+				st.Name, st.LCOM, st.LCOM, r.maxLCOM, st.Methods, st.Fields))
+		v.WithSuggestion("Methods that share no state are separate responsibilities; move each group to a type of its own")
+		v.WithContext("lcom", st.LCOM)
+		v.WithContext("methods", st.Methods)
+		v.WithContext("fields", st.Fields)
+		violations = append(violations, v)
+	}
+	return violations, nil
+}
@@ -0,0 +1,49 @@
+package architecture
+
+import (
+	"testing"
+
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const splitStruct = `package store
+
+type Store struct {
+	users  map[string]string
+	orders []string
+	hits   int
+	limit  int
+}
+
+func (s *Store) User(id string) string  { s.hits++; return s.users[id] }
+func (s *Store) Forget(id string)       { delete(s.users, id); s.hits++ }
+func (s *Store) Order(i int) string     { return s.orders[min(i, s.limit)] }
+func (s *Store) Orders() []string       { return append([]string{}, s.orders...) }
+func (s *Store) Name() string           { return "store" }
+`
+
+func TestStructCohesionRule_ReportsSplitStruct(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{"store/store.go": splitStruct})
+	rule := NewStructCohesionRule()
+	require.NoError(t, rule.Configure(map[string]any{"max_lcom": 1}))
+
+	violations, err := rule.AnalyzeGoProject(project)
+	require.NoError(t, err)
+
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "store/store.go", v.File)
+	assert.Equal(t, 3, v.Line)
+	assert.Contains(t, v.Message, "Struct 'Store' has 2 unrelated method groups")
+	assert.Equal(t, 2, v.Context["lcom"])
+}
+
+func TestStructCohesionRule_DefaultThreshold(t *testing.T) {
+	project := rulestest.Project(t, map[string]string{"store/store.go": splitStruct})
+
+	violations, err := NewStructCohesionRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	assert.Empty(t, violations, "two groups are within the default max_lcom")
+}
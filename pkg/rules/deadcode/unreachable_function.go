@@ -0,0 +1,351 @@
+package deadcode
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewUnreachableFunctionRule())
+}
+
+// UnreachableFunctionRule reports functions and methods of the project that
+// no call chain reaches from an entry point. Reachability is Rapid Type
+// Analysis over the SSA program: a dynamic call reaches the methods of the
+// types the reachable code converts to interfaces, and functions taken as
+// values are reachable once the code taking them is.
+//
+// Entry points are main and every package initializer (variable
+// initializers and init functions), the functions and methods _test.go
+// files name — test packages are outside the typed load, so the name stands
+// for the test that calls it — and the roots setting: glob patterns over
+// "<import path>.<Func>" or "<import path>.<Type>.<Method>", for functions
+// called from outside the analyzed code (plugins, linker tricks). A project
+// without a main package is a library: its exported API is a root as well.
+//
+// Unlike unused-symbol and unused-internal-export, a reference is not
+// enough: a subsystem whose only callers are themselves dead is reported
+// whole. Functions nothing refers to at all are left to those two rules.
+type UnreachableFunctionRule struct {
+	*rules.BaseRule
+	roots []string
+}
+
+// NewUnreachableFunctionRule creates the rule
+func NewUnreachableFunctionRule() *UnreachableFunctionRule {
+	return &UnreachableFunctionRule{
+		BaseRule: rules.NewBaseRule(
+			"unreachable-function",
+			"deadcode",
+			"Detects functions and methods no call chain reaches from main, init, the tests or a configured root",
+			core.SeverityMedium,
+		),
+	}
+}
+
+// Configure accepts the extra roots.
+func (r *UnreachableFunctionRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	raw, ok := settings["roots"]
+	if !ok {
+		return nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("configure unreachable-function: roots must be a list, got %T", raw)
+	}
+	roots := make([]string, 0, len(list))
+	for i, item := range list {
+		pattern, ok := item.(string)
+		if !ok {
+			return fmt.Errorf("configure unreachable-function: roots item %d must be a string, got %T", i, item)
+		}
+		if strings.TrimSpace(pattern) == "" || !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("configure unreachable-function: roots item %d is not a valid glob: %q", i, pattern)
+		}
+		roots = append(roots, pattern)
+	}
+	r.roots = roots
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *UnreachableFunctionRule) RequiresSSA() bool { return true }
+
+// AnalyzeFile is a no-op: reachability is a property of the whole program.
+func (r *UnreachableFunctionRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// AnalyzeGoProject reports the declared functions RTA does not reach.
+func (r *UnreachableFunctionRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("unreachable function: nil Go project context")
+	}
+	if ctx.Program == nil {
+		return nil, errors.New("unreachable function: Go project has no SSA program")
+	}
+
+	var packages []*core.GoPackageContext
+	inProject := make(map[*ssa.Package]bool)
+	files := make(map[string]*core.FileContext)
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.SSA == nil {
+			continue
+		}
+		packages = append(packages, pkg)
+		inProject[pkg.SSA] = true
+		for _, fileCtx := range pkg.Files {
+			files[filepath.Clean(fileCtx.Path)] = fileCtx
+		}
+	}
+	var declared []*ssa.Function
+	for fn := range ssautil.AllFunctions(ctx.Program) {
+		if inProject[fn.Pkg] && fn.Synthetic == "" && fn.Object() != nil && fn.Origin() == nil {
+			declared = append(declared, fn)
+		}
+	}
+	sort.Slice(declared, func(i, j int) bool { return declared[i].Pos() < declared[j].Pos() })
+
+	roots, err := r.entryPoints(packages, declared)
+	if err != nil {
+		return nil, err
+	}
+	reachable := make(map[types.Object]bool)
+	if len(roots) > 0 {
+		for fn := range rta.Analyze(roots, false).Reachable {
+			if origin := fn.Origin(); origin != nil {
+				fn = origin
+			}
+			if obj := fn.Object(); obj != nil {
+				reachable[obj] = true
+			}
+		}
+	}
+
+	referenced := referencedFunctions(ctx, declared)
+	var violations []*core.Violation
+	for _, fn := range declared {
+		if reachable[fn.Object()] {
+			continue
+		}
+		if !referenced[fn.Object()] && leftToSyntacticRules(fn) {
+			continue
+		}
+		// Files the config excludes from analysis are not in the map.
+		fileCtx := files[filepath.Clean(ctx.FileSet.PositionFor(fn.Pos(), false).Filename)]
+		if fileCtx == nil {
+			continue
+		}
+		violations = append(violations, r.report(ctx, fileCtx, fn, referenced[fn.Object()]))
+	}
+	return violations, nil
+}
+
+func (r *UnreachableFunctionRule) report(ctx *core.GoProjectContext, fileCtx *core.FileContext, fn *ssa.Function, referenced bool) *core.Violation {
+	line := ctx.FileSet.Position(fn.Object().Pos()).Line
+	name := displayName(fn)
+	message := fmt.Sprintf("Function '%s' is unreachable: no call chain leads to it from main, init, the tests or a configured root", name)
+	if referenced {
+		message += " — its callers are dead code themselves"
+	}
+	v := r.CreateViolation(fileCtx.RelPath, line, message)
+	v.WithCode(strings.TrimSpace(fileCtx.GetLine(line)))
+	v.WithSuggestion("Remove it with the code only it reaches, or list it in the rule's roots setting if something outside the analyzed code calls it")
+	v.WithContext("function", name)
+	v.WithContext("package", fn.Pkg.Pkg.Path())
+	v.WithContext("root_pattern", qualifiedName(fn))
+	return v
+}
+
+// entryPoints collects the roots of the reachability analysis.
+func (r *UnreachableFunctionRule) entryPoints(packages []*core.GoPackageContext, declared []*ssa.Function) ([]*ssa.Function, error) {
+	var roots []*ssa.Function
+	hasMain := false
+	for _, pkg := range packages {
+		ssaPkg := pkg.SSA
+		if init := ssaPkg.Func("init"); init != nil {
+			roots = append(roots, init)
+		}
+		if ssaPkg.Pkg.Name() == "main" {
+			if main := ssaPkg.Func("main"); main != nil {
+				roots = append(roots, main)
+				hasMain = true
+			}
+		}
+	}
+
+	// Any package's tests may call an exported function; only its own
+	// package's tests an unexported one. A test that names a type may hand
+	// its values to code expecting an interface, out of RTA's sight, so the
+	// methods of a named type are roots too.
+	mentions := make(map[*ssa.Package]map[string]bool, len(packages))
+	anyTest := make(map[string]bool)
+	for _, pkg := range packages {
+		names, err := testFileNames(pkg)
+		if err != nil {
+			return nil, err
+		}
+		mentions[pkg.SSA] = names
+		for name := range names {
+			anyTest[name] = true
+		}
+	}
+	mentioned := func(fn *ssa.Function, name string) bool {
+		return mentions[fn.Pkg][name] || ast.IsExported(name) && anyTest[name]
+	}
+	for _, fn := range declared {
+		switch {
+		case mentioned(fn, fn.Name()), mentioned(fn, receiverName(fn)):
+		case matchesRoot(r.roots, qualifiedName(fn)):
+		case !hasMain && isPublicAPI(fn):
+		default:
+			continue
+		}
+		roots = append(roots, fn)
+	}
+	return roots, nil
+}
+
+// testFileNames collects the identifiers of a package's _test.go files. They
+// are read from the package directory rather than taken from the analyzed
+// files: excluding tests from the findings must not make their callees dead.
+func testFileNames(pkg *core.GoPackageContext) (map[string]bool, error) {
+	names := make(map[string]bool)
+	if len(pkg.Package.GoFiles) == 0 {
+		return names, nil
+	}
+	dir := filepath.Dir(pkg.Package.GoFiles[0])
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unreachable function: read package directory %q: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unreachable function: read test file: %w", err)
+		}
+		collectIdentifierWords(string(content), names)
+	}
+	return names, nil
+}
+
+func matchesRoot(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := doublestar.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isPublicAPI reports whether other modules can call fn: an exported function,
+// or an exported method of an exported type, outside internal/ and main.
+func isPublicAPI(fn *ssa.Function) bool {
+	pkg := fn.Pkg.Pkg
+	if pkg.Name() == "main" || isInternalPackage(pkg.Path()) || !ast.IsExported(fn.Name()) {
+		return false
+	}
+	if recv := fn.Signature.Recv(); recv != nil {
+		named := namedOf(recv.Type())
+		return named != nil && named.Obj().Exported()
+	}
+	return true
+}
+
+// leftToSyntacticRules reports whether unused-symbol or unused-internal-export
+// already cover an unreferenced function.
+func leftToSyntacticRules(fn *ssa.Function) bool {
+	if !ast.IsExported(fn.Name()) || isInternalPackage(fn.Pkg.Pkg.Path()) {
+		return true
+	}
+	if recv := fn.Signature.Recv(); recv != nil {
+		named := namedOf(recv.Type())
+		return named == nil || !named.Obj().Exported()
+	}
+	return false
+}
+
+// referencedFunctions returns the declared functions some code refers to
+// outside their own body.
+func referencedFunctions(ctx *core.GoProjectContext, declared []*ssa.Function) map[types.Object]bool {
+	spans := make(map[types.Object][2]token.Pos, len(declared))
+	for _, fn := range declared {
+		if syntax := fn.Syntax(); syntax != nil {
+			spans[fn.Object()] = [2]token.Pos{syntax.Pos(), syntax.End()}
+		}
+	}
+	referenced := make(map[types.Object]bool)
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.Package == nil || pkg.Package.TypesInfo == nil {
+			continue
+		}
+		for ident, obj := range pkg.Package.TypesInfo.Uses {
+			obj = originOf(obj)
+			span, ok := spans[obj]
+			if !ok || (ident.Pos() >= span[0] && ident.Pos() < span[1]) {
+				continue
+			}
+			referenced[obj] = true
+		}
+	}
+	return referenced
+}
+
+// qualifiedName is the name roots patterns match:
+// "example.com/app/plugins.Register" or "example.com/app/plugins.Store.Save".
+func qualifiedName(fn *ssa.Function) string {
+	prefix := fn.Pkg.Pkg.Path() + "."
+	if recv := fn.Signature.Recv(); recv != nil {
+		if named := namedOf(recv.Type()); named != nil {
+			return prefix + named.Obj().Name() + "." + fn.Name()
+		}
+	}
+	return prefix + fn.Name()
+}
+
+// receiverName returns the name of a method's receiver type, "" for a function.
+func receiverName(fn *ssa.Function) string {
+	if recv := fn.Signature.Recv(); recv != nil {
+		if named := namedOf(recv.Type()); named != nil {
+			return named.Obj().Name()
+		}
+	}
+	return ""
+}
+
+// displayName renders F, T.M or (*T).M.
+func displayName(fn *ssa.Function) string {
+	recv := fn.Signature.Recv()
+	if recv == nil {
+		return fn.Name()
+	}
+	named := namedOf(recv.Type())
+	if named == nil {
+		return fn.Name()
+	}
+	if _, pointer := recv.Type().(*types.Pointer); pointer {
+		return "(*" + named.Obj().Name() + ")." + fn.Name()
+	}
+	return named.Obj().Name() + "." + fn.Name()
+}
@@ -0,0 +1,173 @@
+package deadcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeUnreachable(t *testing.T, rule *UnreachableFunctionRule, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := rule.AnalyzeGoProject(rulestest.ProjectWithSSA(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func unreachableNames(violations []*core.Violation) []string {
+	names := make([]string, 0, len(violations))
+	for _, v := range violations {
+		names = append(names, v.Context["function"].(string))
+	}
+	return names
+}
+
+const reportApp = `package report
+
+import "fmt"
+
+type Renderer interface{ Render() string }
+
+type Text struct{ Body string }
+
+func (t *Text) Render() string { return t.Body }
+
+// HTML implements Renderer too, but no reachable code ever makes one.
+type HTML struct{ Body string }
+
+func (h *HTML) Render() string { return wrap(h.Body) }
+
+func wrap(body string) string { return "<p>" + body + "</p>" }
+
+func Print(r Renderer) { fmt.Println(r.Render()) }
+
+// Legacy is called only by legacyExport, which nothing calls.
+func Legacy() string { return "legacy" }
+
+func legacyExport() string { return Legacy() }
+`
+
+func TestUnreachableFunctionReportsDeadSubsystem(t *testing.T) {
+	violations := analyzeUnreachable(t, NewUnreachableFunctionRule(), map[string]string{
+		"report/report.go": reportApp,
+		"main.go": `package main
+
+import "example.com/rulestest/report"
+
+func main() { report.Print(&report.Text{Body: "hi"}) }
+`,
+	})
+
+	// legacyExport is unexported and named by nothing: unused-symbol's finding.
+	assert.Equal(t, []string{"(*HTML).Render", "wrap", "Legacy"}, unreachableNames(violations))
+	for _, v := range violations {
+		assert.Equal(t, "report/report.go", v.File)
+	}
+	assert.Equal(t, 14, violations[0].Line)
+	assert.Contains(t, violations[2].Message, "its callers are dead code themselves")
+	assert.Equal(t, "example.com/rulestest/report.Legacy", violations[2].Context["root_pattern"])
+}
+
+func TestUnreachableFunctionFollowsFunctionValuesAndInit(t *testing.T) {
+	violations := analyzeUnreachable(t, NewUnreachableFunctionRule(), map[string]string{
+		"main.go": `package main
+
+import (
+	"net/http"
+	"sort"
+)
+
+var handlers = map[string]http.HandlerFunc{}
+
+func init() { handlers["/"] = index }
+
+func index(w http.ResponseWriter, _ *http.Request) { w.Write(sorted()) }
+
+type bytesAsc []byte
+
+func (b bytesAsc) Len() int           { return len(b) }
+func (b bytesAsc) Less(i, j int) bool { return b[i] < b[j] }
+func (b bytesAsc) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+func sorted() []byte {
+	b := bytesAsc("cba")
+	sort.Sort(b)
+	return b
+}
+
+func main() { http.ListenAndServe(":8080", nil) }
+`,
+	})
+	assert.Empty(t, violations, "index is stored by init; sort.Sort reaches the methods of the type it receives")
+}
+
+func TestUnreachableFunctionTestsAreEntryPoints(t *testing.T) {
+	violations := analyzeUnreachable(t, NewUnreachableFunctionRule(), map[string]string{
+		"report/report.go": reportApp,
+		"report/report_test.go": `package report
+
+import "testing"
+
+func TestHTML(t *testing.T) { Print(&HTML{Body: legacyExport()}) }
+`,
+		"main.go": "package main\n\nfunc main() {}\n",
+	})
+	assert.Equal(t, []string{"(*Text).Render"}, unreachableNames(violations),
+		"the test names HTML, Print and legacyExport; Text only main could have made")
+}
+
+func TestUnreachableFunctionConfiguredRoots(t *testing.T) {
+	rule := NewUnreachableFunctionRule()
+	require.NoError(t, rule.Configure(map[string]any{
+		"roots": []any{"**/report.Legacy", "example.com/rulestest/report.HTML.*"},
+	}))
+
+	violations := analyzeUnreachable(t, rule, map[string]string{
+		"report/report.go": reportApp,
+		"main.go":          "package main\n\nfunc main() {}\n",
+	})
+	assert.Equal(t, []string{"(*Text).Render", "Print"}, unreachableNames(violations),
+		"the HTML root keeps wrap alive too")
+}
+
+func TestUnreachableFunctionLibraryExportsAreRoots(t *testing.T) {
+	violations := analyzeUnreachable(t, NewUnreachableFunctionRule(), map[string]string{
+		"report/report.go": reportApp,
+		"internal/text/text.go": `package text
+
+func Upper(s string) string { return s }
+
+func Lower(s string) string { return s }
+`,
+		"report/case.go": `package report
+
+import "example.com/rulestest/internal/text"
+
+func Shout(s string) string { return text.Upper(s) }
+
+func whisper(s string) string { return text.Lower(s) }
+`,
+	})
+	assert.Equal(t, []string{"Lower"}, unreachableNames(violations),
+		"a project without main exports its API; internal/ is no API and whisper is dead")
+}
+
+func TestUnreachableFunctionRejectsInvalidRoots(t *testing.T) {
+	rule := NewUnreachableFunctionRule()
+	assert.Error(t, rule.Configure(map[string]any{"roots": "main.*"}))
+	assert.Error(t, rule.Configure(map[string]any{"roots": []any{"[broken"}}))
+	assert.Error(t, rule.Configure(map[string]any{"roots": []any{42}}))
+}
+
+func TestUnreachableFunctionRequiresSSA(t *testing.T) {
+	rule := NewUnreachableFunctionRule()
+	assert.True(t, rule.RequiresSSA())
+
+	_, err := rule.AnalyzeGoProject(nil)
+	assert.Error(t, err)
+	_, err = rule.AnalyzeGoProject(rulestest.Project(t, map[string]string{"main.go": "package main\n\nfunc main() {}\n"}))
+	assert.Error(t, err, "a project loaded without SSA")
+}
@@ -1,33 +1,37 @@
 package deadcode
 
 import (
-	"fmt"
 	"go/ast"
-	"maps"
-	"os"
-	"path/filepath"
-	"slices"
+	"go/token"
+	"go/types"
+	"sort"
 	"strings"
-	"sync"
 
 	"github.com/aiseeq/glint/pkg/core"
 	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/rules/helpers"
 )
 
 func init() {
 	rules.Register(NewUnusedSymbolsRule())
 }
 
-// UnusedSymbolsRule detects unexported symbols that appear unused within their file
+// UnusedSymbolsRule detects unexported functions, methods, types, constants and
+// variables that nothing in the project uses. References are resolved through
+// types.Info across every loaded package, so a use in a sibling file or under a
+// shadowing local of the same name is told apart.
+//
+// A method also counts as used when its type satisfies an interface that has
+// it — from the project or any package the project imports — and an exported
+// method of an unexported type when a value of the type is passed as `any`,
+// where reflection (rpc.Register, templates, encoders) may call it. A symbol
+// named by a //go:linkname directive is used by the linker.
+//
+// Uses inside the symbol's own declaration (recursion, self-referencing types)
+// do not count. Test files are outside the typed load; a symbol a _test.go file
+// of its package mentions is not dead code.
 type UnusedSymbolsRule struct {
 	*rules.BaseRule
-
-	// identCounts caches, per package directory, how often each identifier
-	// appears in it. Without the cache every file reparsed all of its siblings,
-	// which made the work quadratic in the size of a package — on projectA this rule
-	// alone took 7.7s of the 17s all file rules needed together.
-	mu          sync.Mutex
-	identCounts map[string]map[string]int
 }
 
 // NewUnusedSymbolsRule creates the rule
@@ -36,253 +40,156 @@ func NewUnusedSymbolsRule() *UnusedSymbolsRule {
 		BaseRule: rules.NewBaseRule(
 			"unused-symbol",
 			"deadcode",
-			"Detects unexported functions, types, and variables that appear unused within their file",
+			"Detects unexported functions, methods, types, constants and variables that nothing in the project uses",
 			core.SeverityLow,
 		),
-		identCounts: make(map[string]map[string]int),
 	}
 }
 
-// ResetState drops the per-directory cache, so a second project root never
-// inherits the counts of the first.
-func (r *UnusedSymbolsRule) ResetState() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.identCounts = make(map[string]map[string]int)
-}
+// RequiresSSA reports that typed syntax is enough for this rule.
+func (r *UnusedSymbolsRule) RequiresSSA() bool { return false }
 
-// symbolInfo tracks a declared symbol
-type symbolInfo struct {
-	kind   string // "func", "type", "const", "var"
-	line   int
-	node   ast.Node
-	usages int
+// AnalyzeFile is a no-op: the users of a symbol may live in any package.
+func (r *UnusedSymbolsRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
 }
 
-// AnalyzeFile checks for unused symbols
-func (r *UnusedSymbolsRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
-	if !ctx.IsGoFile() || ctx.GoAST == nil {
-		return nil
-	}
-
-	// Skip test files - they often have helper functions
-	if ctx.IsTestFile() {
-		return nil
-	}
-
-	// Collect all unexported symbol declarations
-	symbols := make(map[string]*symbolInfo)
-
-	// First pass: collect declarations
-	for _, decl := range ctx.GoAST.Decls {
-		switch d := decl.(type) {
-		case *ast.FuncDecl:
-			r.collectFunc(ctx, d, symbols)
-
-		case *ast.GenDecl:
-			r.collectGenDecl(ctx, d, symbols)
-		}
-	}
-
-	// If no symbols to check, return early
-	if len(symbols) == 0 {
-		return nil
-	}
+// declaredSymbol is a candidate declaration. start and end span the whole
+// declaration, so that uses inside it can be told from uses elsewhere.
+type declaredSymbol struct {
+	obj        types.Object
+	kind       string
+	name       string
+	fileCtx    *core.FileContext
+	line       int
+	start, end token.Pos
+}
 
-	// Second pass: count usages in current file
-	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
-		if ident, ok := n.(*ast.Ident); ok {
-			if sym, exists := symbols[ident.Name]; exists {
-				// Check if this is not the declaration itself
-				if !r.isDeclaration(ident, sym) {
-					sym.usages++
-				}
-			}
-		}
-		return true
+// AnalyzeGoProject reports the candidates no compiled file uses.
+func (r *UnusedSymbolsRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	var declared []*declaredSymbol
+	err := helpers.ForEachTypedFile(ctx, "unused symbol", func(fileCtx *core.FileContext, info *types.Info) {
+		declared = append(declared, collectSymbols(fileCtx, info)...)
 	})
-
-	// Third pass: check usages in sibling files (same package)
-	// This catches cross-file usage within the same Go package
-	if err := r.checkSiblingFileUsages(ctx, symbols); err != nil {
-		v := r.CreateViolation(ctx.RelPath, 1, "Unused-symbol analysis failed: "+err.Error())
-		v.Severity = core.SeverityCritical
-		v.WithCode(ctx.RelPath)
-		v.WithSuggestion("Fix filesystem access or invalid sibling Go source, then rerun analysis")
-		return []*core.Violation{v}
+	if err != nil {
+		return nil, err
 	}
+	live := newLiveness(ctx, declared)
+	mentions := newTestMentions(ctx.Files)
 
-	// Generate violations for unused symbols
 	var violations []*core.Violation
-	for _, name := range slices.Sorted(maps.Keys(symbols)) {
-		sym := symbols[name]
-		if sym.usages == 0 {
-			v := r.CreateViolation(ctx.RelPath, sym.line,
-				"Unexported "+sym.kind+" '"+name+"' appears to be unused")
-			v.WithCode(ctx.GetLine(sym.line))
-			v.WithSuggestion("Remove unused " + sym.kind + " or export it if intended for external use")
-			v.WithContext("symbol", name)
-			v.WithContext("kind", sym.kind)
-			violations = append(violations, v)
+	for _, sym := range declared {
+		if live.alive(sym) || mentions.mentioned(sym.fileCtx, sym.obj.Name()) {
+			continue
 		}
+		violations = append(violations, r.report(sym))
 	}
 
-	return violations
-}
-
-// checkSiblingFileUsages adds the usages a symbol has in the other files of its
-// package. The counts come from a per-directory cache built once, rather than by
-// reparsing every sibling for every file.
-func (r *UnusedSymbolsRule) checkSiblingFileUsages(ctx *core.FileContext, symbols map[string]*symbolInfo) error {
-	dir := filepath.Dir(ctx.Path)
-	counts, err := r.directoryIdentCounts(dir)
-	if err != nil {
-		return fmt.Errorf("count identifiers of package %q: %w", dir, err)
-	}
-
-	own := identCountsOf(ctx.GoAST)
-	for name, sym := range symbols {
-		if siblings := counts[name] - own[name]; siblings > 0 {
-			sym.usages += siblings
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
 		}
-	}
-	return nil
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, nil
 }
 
-// directoryIdentCounts returns how often each identifier appears across the Go
-// files of a directory, parsing them once. Test files are counted too: an
-// unexported symbol used only by tests is not dead code.
-//
-// Siblings go through core.SharedParser: the walker parses every analyzed file
-// into the same content-keyed cache, so this sweep reuses those ASTs instead
-// of parsing the whole project a second time.
-func (r *UnusedSymbolsRule) directoryIdentCounts(dir string) (map[string]int, error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if counts, ok := r.identCounts[dir]; ok {
-		return counts, nil
-	}
-
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		return nil, fmt.Errorf("read package directory %q: %w", dir, err)
-	}
+func (r *UnusedSymbolsRule) report(sym *declaredSymbol) *core.Violation {
+	subject := "Unexported " + sym.kind
+	if sym.kind == "method" {
+		subject = "Method"
+	}
+	v := r.CreateViolation(sym.fileCtx.RelPath, sym.line,
+		subject+" '"+sym.name+"' is never used")
+	v.WithCode(strings.TrimSpace(sym.fileCtx.GetLine(sym.line)))
+	v.WithSuggestion("Remove the unused " + sym.kind + ", or export it if it is meant for use outside the package")
+	v.WithContext("symbol", sym.name)
+	v.WithContext("kind", sym.kind)
+	return v
+}
 
-	counts := make(map[string]int)
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
-			continue
-		}
-		path := filepath.Join(dir, entry.Name())
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil, fmt.Errorf("read sibling file %q: %w", path, err)
-		}
-		_, file, err := core.SharedParser().ParseGoFile(path, content)
-		if err != nil {
-			return nil, fmt.Errorf("parse sibling file %q: %w", path, err)
-		}
-		for name, count := range identCountsOf(file) {
-			counts[name] += count
+// collectSymbols returns the candidates one file declares: unexported
+// package-level symbols, unexported methods, and every method of an
+// unexported type — such a method is reachable from outside the package only
+// through an interface or reflection, which the liveness pass checks.
+func collectSymbols(fileCtx *core.FileContext, info *types.Info) []*declaredSymbol {
+	c := &symbolCollector{fileCtx: fileCtx, info: info}
+	for _, decl := range fileCtx.GoAST.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			c.collectFunc(d)
+		case *ast.GenDecl:
+			c.collectGenDecl(d)
 		}
 	}
-
-	r.identCounts[dir] = counts
-	return counts, nil
+	return c.declared
 }
 
-// identCountsOf counts every identifier occurrence in a syntax tree.
-func identCountsOf(file *ast.File) map[string]int {
-	counts := make(map[string]int)
-	ast.Inspect(file, func(n ast.Node) bool {
-		if ident, ok := n.(*ast.Ident); ok {
-			counts[ident.Name]++
-		}
-		return true
-	})
-	return counts
+type symbolCollector struct {
+	fileCtx  *core.FileContext
+	info     *types.Info
+	declared []*declaredSymbol
 }
 
-// collectFunc collects function declarations
-func (r *UnusedSymbolsRule) collectFunc(ctx *core.FileContext, fn *ast.FuncDecl, symbols map[string]*symbolInfo) {
-	name := fn.Name.Name
-
-	// Skip exported functions
-	if ast.IsExported(name) {
+func (c *symbolCollector) add(ident *ast.Ident, kind, name string, span ast.Node) {
+	obj := c.info.Defs[ident]
+	if obj == nil {
 		return
 	}
+	c.declared = append(c.declared, &declaredSymbol{
+		obj:     obj,
+		kind:    kind,
+		name:    name,
+		fileCtx: c.fileCtx,
+		line:    c.fileCtx.PositionFor(ident).Line,
+		start:   span.Pos(),
+		end:     span.End(),
+	})
+}
 
-	// Skip main, init, and test functions
-	if name == "main" || name == "init" {
+func (c *symbolCollector) collectFunc(fn *ast.FuncDecl) {
+	name := fn.Name.Name
+	if fn.Recv == nil {
+		if !ast.IsExported(name) && name != "main" && name != "init" && name != "_" {
+			c.add(fn.Name, "function", name, fn)
+		}
 		return
 	}
-
-	// Skip methods - they might implement interfaces
-	if fn.Recv != nil {
+	if len(fn.Recv.List) == 0 {
 		return
 	}
-
-	pos := ctx.PositionFor(fn.Name)
-	symbols[name] = &symbolInfo{
-		kind: "function",
-		line: pos.Line,
-		node: fn,
+	recv := fn.Recv.List[0]
+	if !ast.IsExported(name) || !ast.IsExported(receiverTypeName(recv)) {
+		c.add(fn.Name, "method", methodDisplayName(recv, name), fn)
 	}
 }
 
-// collectGenDecl collects type, const, and var declarations
-func (r *UnusedSymbolsRule) collectGenDecl(ctx *core.FileContext, decl *ast.GenDecl, symbols map[string]*symbolInfo) {
+func (c *symbolCollector) collectGenDecl(decl *ast.GenDecl) {
+	kind := "variable"
+	if decl.Tok == token.CONST {
+		kind = "constant"
+	}
 	for _, spec := range decl.Specs {
 		switch s := spec.(type) {
 		case *ast.TypeSpec:
-			name := s.Name.Name
-			if !ast.IsExported(name) {
-				pos := ctx.PositionFor(s.Name)
-				symbols[name] = &symbolInfo{
-					kind: "type",
-					line: pos.Line,
-					node: s,
-				}
+			if !ast.IsExported(s.Name.Name) && s.Name.Name != "_" {
+				c.add(s.Name, "type", s.Name.Name, s)
 			}
-
 		case *ast.ValueSpec:
 			for _, ident := range s.Names {
-				name := ident.Name
-				// Skip blank identifier and exported names
-				if name == "_" || ast.IsExported(name) {
-					continue
-				}
-
-				pos := ctx.PositionFor(ident)
-				kind := "variable"
-				if decl.Tok.String() == "const" {
-					kind = "constant"
-				}
-
-				symbols[name] = &symbolInfo{
-					kind: kind,
-					line: pos.Line,
-					node: s,
+				if !ast.IsExported(ident.Name) && ident.Name != "_" {
+					c.add(ident, kind, ident.Name, s)
 				}
 			}
 		}
 	}
 }
 
-// isDeclaration checks if an identifier is the declaration itself
-func (r *UnusedSymbolsRule) isDeclaration(ident *ast.Ident, sym *symbolInfo) bool {
-	switch node := sym.node.(type) {
-	case *ast.FuncDecl:
-		return ident == node.Name
-	case *ast.TypeSpec:
-		return ident == node.Name
-	case *ast.ValueSpec:
-		for _, name := range node.Names {
-			if ident == name {
-				return true
-			}
-		}
+// methodDisplayName renders T.m or (*T).m.
+func methodDisplayName(recv *ast.Field, name string) string {
+	typeName := receiverTypeName(recv)
+	if _, pointer := recv.Type.(*ast.StarExpr); pointer {
+		return "(*" + typeName + ")." + name
 	}
-	return false
+	return typeName + "." + name
 }
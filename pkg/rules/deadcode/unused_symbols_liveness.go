@@ -0,0 +1,291 @@
+package deadcode
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// liveness knows which candidate declarations are used. Objects are keyed by
+// declaration position: a use through an instantiated generic type names a
+// different *types.Func or *types.Var than the declaration.
+type liveness struct {
+	used map[token.Pos]bool
+	// reflected holds the type names whose values are passed as `any`.
+	reflected map[token.Pos]bool
+}
+
+func newLiveness(ctx *core.GoProjectContext, declared []*declaredSymbol) *liveness {
+	byPos := make(map[token.Pos]*declaredSymbol, len(declared))
+	for _, sym := range declared {
+		byPos[sym.obj.Pos()] = sym
+	}
+	live := &liveness{used: make(map[token.Pos]bool), reflected: make(map[token.Pos]bool)}
+
+	for _, pkg := range ctx.Packages {
+		info := pkg.Package.TypesInfo
+		var receivers receiverSpanSet
+		for _, file := range pkg.Package.Syntax {
+			receivers = append(receivers, receiverSpans(file)...)
+			live.markLinknamed(file, pkg.Package.Types)
+			live.markReflected(file, info)
+		}
+		for ident, obj := range info.Uses {
+			sym := byPos[originOf(obj).Pos()]
+			if sym == nil || (ident.Pos() >= sym.start && ident.Pos() < sym.end) || receivers.contain(ident.Pos()) {
+				continue
+			}
+			live.used[sym.obj.Pos()] = true
+		}
+	}
+	live.markInterfaceMethods(ctx, byPos)
+	return live
+}
+
+// alive reports whether a candidate has a use that keeps it alive.
+func (l *liveness) alive(sym *declaredSymbol) bool {
+	if l.used[sym.obj.Pos()] {
+		return true
+	}
+	fn, ok := sym.obj.(*types.Func)
+	if !ok || !fn.Exported() {
+		return false
+	}
+	named := receiverNamed(fn)
+	return named != nil && l.reflected[named.Obj().Pos()]
+}
+
+// markLinknamed marks the local names of //go:linkname directives: the linker
+// references them, or binds them to a body elsewhere.
+func (l *liveness) markLinknamed(file *ast.File, pkg *types.Package) {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			rest, ok := strings.CutPrefix(comment.Text, "//go:linkname ")
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(rest)
+			if len(fields) == 0 {
+				continue
+			}
+			if obj := pkg.Scope().Lookup(fields[0]); obj != nil {
+				l.used[obj.Pos()] = true
+			}
+		}
+	}
+}
+
+// markReflected records the named types whose values are passed to a
+// parameter of empty interface type: rpc.Register, template.Execute and the
+// encoders reach their exported methods through reflection.
+func (l *liveness) markReflected(file *ast.File, info *types.Info) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		tv, ok := info.Types[call.Fun]
+		if !ok || tv.IsType() {
+			return true
+		}
+		sig, ok := tv.Type.Underlying().(*types.Signature)
+		if !ok {
+			return true
+		}
+		for i, arg := range call.Args {
+			param := parameterType(sig, i, call.Ellipsis.IsValid())
+			iface, ok := param.(*types.Interface)
+			if !ok || !iface.Empty() {
+				continue
+			}
+			if named := namedOf(info.TypeOf(arg)); named != nil {
+				l.reflected[named.Origin().Obj().Pos()] = true
+			}
+		}
+		return true
+	})
+}
+
+// markInterfaceMethods marks every method through which a project type
+// satisfies an interface of the project or of a package it imports. Only
+// interfaces that have a candidate method's name are checked.
+func (l *liveness) markInterfaceMethods(ctx *core.GoProjectContext, byPos map[token.Pos]*declaredSymbol) {
+	interfaces := interfacesByMethod(ctx)
+	for _, pkg := range ctx.Packages {
+		scope := pkg.Package.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() || types.IsInterface(typeName.Type()) {
+				continue
+			}
+			named, ok := typeName.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			l.markImplementations(named, interfaces, byPos)
+		}
+	}
+}
+
+func (l *liveness) markImplementations(named *types.Named, interfaces map[string][]*types.Interface, byPos map[token.Pos]*declaredSymbol) {
+	ptr := types.NewPointer(named)
+	methods := types.NewMethodSet(ptr)
+	checked := make(map[*types.Interface]bool)
+	for i := range methods.Len() {
+		method := methods.At(i).Obj()
+		if byPos[originOf(method).Pos()] == nil {
+			continue
+		}
+		for _, iface := range interfaces[method.Name()] {
+			if checked[iface] {
+				continue
+			}
+			checked[iface] = true
+			// Implements is unspecified for uninstantiated generic types; a
+			// method that matches by name is kept alive instead.
+			if named.TypeParams().Len() > 0 {
+				l.used[originOf(method).Pos()] = true
+				continue
+			}
+			if !types.Implements(ptr, iface) {
+				continue
+			}
+			for j := range iface.NumMethods() {
+				wanted := iface.Method(j)
+				obj, _, _ := types.LookupFieldOrMethod(ptr, false, wanted.Pkg(), wanted.Name())
+				if impl, ok := obj.(*types.Func); ok {
+					l.used[impl.Origin().Pos()] = true
+				}
+			}
+		}
+	}
+}
+
+// interfacesByMethod indexes the non-empty interfaces the project can see —
+// named ones of the project and its imports, and interface literals of the
+// project — by the names of their methods.
+func interfacesByMethod(ctx *core.GoProjectContext) map[string][]*types.Interface {
+	index := make(map[string][]*types.Interface)
+	seen := make(map[*types.Interface]bool)
+	add := func(t types.Type) {
+		iface, ok := t.Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 || seen[iface] {
+			return
+		}
+		seen[iface] = true
+		for i := range iface.NumMethods() {
+			name := iface.Method(i).Name()
+			index[name] = append(index[name], iface)
+		}
+	}
+
+	visited := make(map[*types.Package]bool)
+	var visit func(*types.Package)
+	visit = func(pkg *types.Package) {
+		if visited[pkg] {
+			return
+		}
+		visited[pkg] = true
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			if typeName, ok := scope.Lookup(name).(*types.TypeName); ok {
+				add(typeName.Type())
+			}
+		}
+		for _, imported := range pkg.Imports() {
+			visit(imported)
+		}
+	}
+	for _, pkg := range ctx.Packages {
+		visit(pkg.Package.Types)
+		for _, tv := range pkg.Package.TypesInfo.Types {
+			if tv.IsType() {
+				add(tv.Type)
+			}
+		}
+	}
+	return index
+}
+
+// receiverSpanSet holds the receiver lists of a package's methods: naming a
+// type there declares a method on it, it does not use it.
+type receiverSpanSet [][2]token.Pos
+
+func receiverSpans(file *ast.File) receiverSpanSet {
+	var spans receiverSpanSet
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			spans = append(spans, [2]token.Pos{fn.Recv.Pos(), fn.Recv.End()})
+		}
+	}
+	return spans
+}
+
+func (s receiverSpanSet) contain(pos token.Pos) bool {
+	for _, span := range s {
+		if pos >= span[0] && pos < span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// originOf maps members of instantiated generic types back to their
+// declaration.
+func originOf(obj types.Object) types.Object {
+	switch o := obj.(type) {
+	case *types.Func:
+		return o.Origin()
+	case *types.Var:
+		return o.Origin()
+	}
+	return obj
+}
+
+// parameterType returns the type argument i is assigned to, unwrapping the
+// variadic slice unless the call spreads one with `...`.
+func parameterType(sig *types.Signature, i int, spread bool) types.Type {
+	params := sig.Params()
+	if params.Len() == 0 {
+		return nil
+	}
+	last := params.Len() - 1
+	if sig.Variadic() && i >= last {
+		slice := params.At(last).Type()
+		if spread {
+			return slice.Underlying()
+		}
+		if s, ok := slice.Underlying().(*types.Slice); ok {
+			return s.Elem().Underlying()
+		}
+		return nil
+	}
+	if i > last {
+		return nil
+	}
+	return params.At(i).Type().Underlying()
+}
+
+// namedOf returns the named type behind t and one level of pointer.
+func namedOf(t types.Type) *types.Named {
+	if t == nil {
+		return nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+// receiverNamed returns the named type a method is declared on.
+func receiverNamed(fn *types.Func) *types.Named {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return nil
+	}
+	return namedOf(sig.Recv().Type())
+}
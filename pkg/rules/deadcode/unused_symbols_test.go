@@ -1,248 +1,217 @@
 package deadcode
 
 import (
-	"os"
-	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
 )
 
+func analyzeUnusedSymbols(t *testing.T, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := NewUnusedSymbolsRule().AnalyzeGoProject(rulestest.Project(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func reportedSymbols(violations []*core.Violation) []string {
+	names := make([]string, 0, len(violations))
+	for _, v := range violations {
+		names = append(names, v.Context["symbol"].(string))
+	}
+	return names
+}
+
 func TestUnusedSymbolsRule(t *testing.T) {
 	tests := []struct {
-		name           string
-		code           string
-		wantViolations int
+		name string
+		code string
+		want []string
 	}{
 		{
 			name: "unused private function",
-			code: `package main
-
-func main() {}
-
-func unusedHelper() {}`,
-			wantViolations: 1,
+			code: "package main\n\nfunc main() {}\n\nfunc unusedHelper() {}\n",
+			want: []string{"unusedHelper"},
 		},
 		{
 			name: "used private function",
-			code: `package main
-
-func main() {
-	helper()
-}
-
-func helper() {}`,
-			wantViolations: 0,
+			code: "package main\n\nfunc main() { helper() }\n\nfunc helper() {}\n",
 		},
 		{
 			name: "unused private type",
-			code: `package main
-
-type unusedType struct{}
-
-func main() {}`,
-			wantViolations: 1,
+			code: "package main\n\ntype unusedType struct{}\n\nfunc main() {}\n",
+			want: []string{"unusedType"},
 		},
 		{
 			name: "used private type",
-			code: `package main
-
-type myType struct{}
-
-func main() {
-	var _ myType
-}`,
-			wantViolations: 0,
+			code: "package main\n\ntype myType struct{}\n\nfunc main() { var _ myType }\n",
 		},
 		{
-			name: "unused private constant",
-			code: `package main
-
-const unusedConst = 42
-
-func main() {}`,
-			wantViolations: 1,
+			name: "unused constant and variable",
+			code: "package main\n\nconst unusedConst = 42\n\nvar unusedVar = \"hello\"\n\nfunc main() {}\n",
+			want: []string{"unusedConst", "unusedVar"},
 		},
 		{
-			name: "used private constant",
-			code: `package main
-
-const myConst = 42
-
-func main() {
-	_ = myConst
-}`,
-			wantViolations: 0,
+			name: "exported and special names are skipped",
+			code: "package main\n\nfunc init() {}\n\nfunc main() {}\n\nfunc ExportedHelper() {}\n\ntype ExportedType struct{}\n\nvar _ = func() {}\n",
 		},
 		{
-			name: "unused private variable",
-			code: `package main
-
-var unusedVar = "hello"
-
-func main() {}`,
-			wantViolations: 1,
+			name: "recursion does not keep a function alive",
+			code: "package main\n\nfunc main() {}\n\nfunc countdown(n int) {\n\tif n > 0 {\n\t\tcountdown(n - 1)\n\t}\n}\n",
+			want: []string{"countdown"},
 		},
 		{
-			name: "used private variable",
-			code: `package main
-
-var myVar = "hello"
-
-func main() {
-	println(myVar)
-}`,
-			wantViolations: 0,
+			name: "a local of the same name is no use",
+			code: "package main\n\nfunc helper() {}\n\nfunc main() {\n\thelper := 1\n\t_ = helper\n}\n",
+			want: []string{"helper"},
 		},
 		{
-			name: "exported function - skip",
-			code: `package main
-
-func main() {}
-
-func ExportedHelper() {}`,
-			wantViolations: 0,
+			name: "unused method of a used type",
+			code: "package main\n\ntype counter struct{ n int }\n\nfunc (c *counter) inc() { c.n++ }\n\nfunc (c *counter) reset() { c.n = 0 }\n\nfunc main() {\n\tc := &counter{}\n\tc.inc()\n}\n",
+			want: []string{"(*counter).reset"},
 		},
 		{
-			name: "exported type - skip",
-			code: `package main
+			name: "a type named only by its own methods is unused",
+			code: "package main\n\ntype orphan struct{}\n\nfunc (orphan) Run() {}\n\nfunc main() {}\n",
+			want: []string{"orphan", "orphan.Run"},
+		},
+	}
 
-type ExportedType struct{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := analyzeUnusedSymbols(t, map[string]string{"main.go": tt.code})
+			if len(tt.want) == 0 {
+				assert.Empty(t, reportedSymbols(violations))
+				return
+			}
+			assert.Equal(t, tt.want, reportedSymbols(violations))
+		})
+	}
+}
 
-func main() {}`,
-			wantViolations: 0,
-		},
-		{
-			name: "init function - skip",
-			code: `package main
+func TestUnusedSymbolsCountsUsesAcrossFilesAndPackages(t *testing.T) {
+	violations := analyzeUnusedSymbols(t, map[string]string{
+		"demo/main.go":  "package demo\n\nfunc helper() int { return limit }\n",
+		"demo/other.go": "package demo\n\nconst limit = 3\n\nfunc Caller() int { return helper() }\n",
+		"app/app.go":    "package app\n\nimport \"example.com/rulestest/demo\"\n\nvar Result = demo.Caller()\n",
+	})
+	assert.Empty(t, violations, "helper and limit are used from a sibling file of the same package")
+}
 
-func init() {}
+func TestUnusedSymbolsKeepsInterfaceMethods(t *testing.T) {
+	violations := analyzeUnusedSymbols(t, map[string]string{
+		"shape/shape.go": `package shape
 
-func main() {}`,
-			wantViolations: 0,
-		},
-		{
-			name: "method - skip (might implement interface)",
-			code: `package main
+import "fmt"
 
-type myType struct{}
+type area interface{ size() int }
 
-func (m *myType) unusedMethod() {}
+type square struct{ side int }
 
-func main() {
-	var _ myType
-}`,
-			wantViolations: 0,
-		},
-		{
-			name: "blank identifier - skip",
-			code: `package main
+func (s square) size() int { return s.side * s.side }
 
-var _ = func() {}
+func (s square) String() string { return fmt.Sprint(s.side) }
 
-func main() {}`,
-			wantViolations: 0,
-		},
-		{
-			name: "multiple unused symbols",
-			code: `package main
+func (s square) perimeter() int { return 4 * s.side }
 
-func unusedFunc1() {}
-func unusedFunc2() {}
-type unusedType struct{}
-const unusedConst = 1
+func Total() int {
+	var shapes []area
+	shapes = append(shapes, square{side: 2})
+	return shapes[0].size()
+}
+`,
+	})
+	require.Len(t, violations, 1, "size satisfies area and String fmt.Stringer; perimeter satisfies nothing")
+	assert.Equal(t, "square.perimeter", violations[0].Context["symbol"])
+	assert.Equal(t, "shape/shape.go", violations[0].File)
+	assert.Equal(t, 13, violations[0].Line)
+	assert.Contains(t, violations[0].Message, "Method 'square.perimeter' is never used")
+}
 
-func main() {}`,
-			wantViolations: 4,
-		},
-		{
-			name: "function used in another function",
-			code: `package main
+func TestUnusedSymbolsKeepsInterfaceMethodsImplementedAcrossPackages(t *testing.T) {
+	violations := analyzeUnusedSymbols(t, map[string]string{
+		"port/port.go": "package port\n\ntype Store interface{ Save(string) error }\n\nfunc Use(s Store) error { return s.Save(\"x\") }\n",
+		"adapter/mem.go": `package adapter
+
+import "example.com/rulestest/port"
 
-func helper1() {
-	helper2()
+type memStore struct{ items []string }
+
+func (m *memStore) Save(item string) error { m.items = append(m.items, item); return nil }
+
+func Wire() error { return port.Use(&memStore{}) }
+`,
+	})
+	assert.Empty(t, violations)
 }
 
-func helper2() {}
+func TestUnusedSymbolsKeepsReflectionRegisteredMethods(t *testing.T) {
+	violations := analyzeUnusedSymbols(t, map[string]string{
+		"rpcsvc/rpc.go": `package rpcsvc
 
-func main() {
-	helper1()
-}`,
-			wantViolations: 0,
-		},
-	}
+import "net/rpc"
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rule := NewUnusedSymbolsRule()
-			dir := t.TempDir()
-			path := filepath.Join(dir, "main.go")
-			require.NoError(t, os.WriteFile(path, []byte(tt.code), 0644))
-
-			parser := core.NewParser()
-			ctx := core.NewFileContext(path, dir, []byte(tt.code), core.DefaultConfig())
-			fset, astFile, err := parser.ParseGoFile(path, []byte(tt.code))
-			if err != nil {
-				t.Fatalf("Parse error: %v", err)
-			}
-			ctx.SetGoAST(fset, astFile)
+type arith struct{}
 
-			violations := rule.AnalyzeFile(ctx)
+type Args struct{ A, B int }
 
-			assert.Len(t, violations, tt.wantViolations, "Code:\n%s", tt.code)
-		})
-	}
+func (arith) Multiply(args Args, reply *int) error { *reply = args.A * args.B; return nil }
+
+func (arith) helper() int { return 0 }
+
+func Register() error { return rpc.Register(arith{}) }
+`,
+	})
+	assert.Equal(t, []string{"arith.helper"}, reportedSymbols(violations),
+		"Multiply is called by net/rpc through reflection; an unexported method never is")
 }
 
-func TestUnusedSymbolsCountsSiblingUsage(t *testing.T) {
-	dir := t.TempDir()
-	mainCode := "package demo\n\nfunc helper() {}\n"
-	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(mainCode), 0644))
-	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.go"),
-		[]byte("package demo\n\nfunc Caller() { helper() }\n"), 0644))
+func TestUnusedSymbolsKeepsLinknameTargets(t *testing.T) {
+	violations := analyzeUnusedSymbols(t, map[string]string{
+		"clock/clock.go": `package clock
 
-	path := filepath.Join(dir, "main.go")
-	ctx := core.NewFileContext(path, dir, []byte(mainCode), core.DefaultConfig())
-	fset, astFile, err := core.SharedParser().ParseGoFile(path, []byte(mainCode))
-	require.NoError(t, err)
-	ctx.SetGoAST(fset, astFile)
+import _ "unsafe"
 
-	violations := NewUnusedSymbolsRule().AnalyzeFile(ctx)
-	assert.Empty(t, violations, "helper is used from a sibling file of the same package")
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+func unused() {}
+`,
+	})
+	assert.Equal(t, []string{"unused"}, reportedSymbols(violations))
 }
 
-func TestUnusedSymbolsCountsSiblingTestFileUsage(t *testing.T) {
-	dir := t.TempDir()
-	mainCode := "package demo\n\nfunc testedHelper() {}\n"
-	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(mainCode), 0644))
-	require.NoError(t, os.WriteFile(filepath.Join(dir, "demo_test.go"),
-		[]byte("package demo\n\nimport \"testing\"\n\nfunc TestOnly(t *testing.T) { testedHelper() }\n"), 0644))
+func TestUnusedSymbolsCountsTestFileUsage(t *testing.T) {
+	violations := analyzeUnusedSymbols(t, map[string]string{
+		"demo/main.go": "package demo\n\nfunc testedHelper() {}\n",
+		"demo/demo_test.go": `package demo
 
-	path := filepath.Join(dir, "main.go")
-	ctx := core.NewFileContext(path, dir, []byte(mainCode), core.DefaultConfig())
-	fset, astFile, err := core.SharedParser().ParseGoFile(path, []byte(mainCode))
-	require.NoError(t, err)
-	ctx.SetGoAST(fset, astFile)
+import "testing"
 
-	violations := NewUnusedSymbolsRule().AnalyzeFile(ctx)
+func TestOnly(t *testing.T) { testedHelper() }
+`,
+	})
 	assert.Empty(t, violations, "a symbol used only by package tests is not dead code")
 }
 
-func TestUnusedSymbolsReportsSiblingReadError(t *testing.T) {
-	code := "package main\nfunc unused() {}"
-	path := filepath.Join(t.TempDir(), "missing", "main.go")
-	ctx := core.NewFileContext(path, filepath.Dir(path), []byte(code), core.DefaultConfig())
-	parser := core.NewParser()
-	fset, astFile, err := parser.ParseGoFile(path, []byte(code))
-	require.NoError(t, err)
-	ctx.SetGoAST(fset, astFile)
+func TestUnusedSymbolsKeepsGenericMembers(t *testing.T) {
+	violations := analyzeUnusedSymbols(t, map[string]string{
+		"box/box.go": `package box
+
+type box[T any] struct{ value T }
 
-	violations := NewUnusedSymbolsRule().AnalyzeFile(ctx)
-	require.Len(t, violations, 1)
-	require.Equal(t, core.SeverityCritical, violations[0].Severity)
+func (b *box[T]) get() T { return b.value }
+
+func Value() int {
+	b := &box[int]{value: 1}
+	return b.get()
+}
+`,
+	})
+	assert.Empty(t, violations, "uses through an instantiation count for the generic declaration")
 }
 
 func TestUnusedSymbolsRuleMetadata(t *testing.T) {
@@ -251,26 +220,8 @@ func TestUnusedSymbolsRuleMetadata(t *testing.T) {
 	assert.Equal(t, "unused-symbol", rule.Name())
 	assert.Equal(t, "deadcode", rule.Category())
 	assert.Equal(t, core.SeverityLow, rule.DefaultSeverity())
-}
-
-func TestUnusedSymbolsSkipsTestFiles(t *testing.T) {
-	rule := NewUnusedSymbolsRule()
-
-	code := `package main
-
-func unusedHelper() {}
-
-func TestSomething(t *testing.T) {}`
-
-	parser := core.NewParser()
-	ctx := core.NewFileContext("/src/main_test.go", "/src", []byte(code), core.DefaultConfig())
-	fset, astFile, err := parser.ParseGoFile("/src/main_test.go", []byte(code))
-	if err != nil {
-		t.Fatalf("Parse error: %v", err)
-	}
-	ctx.SetGoAST(fset, astFile)
-
-	violations := rule.AnalyzeFile(ctx)
+	assert.False(t, rule.RequiresSSA())
 
-	assert.Empty(t, violations, "Should skip test files")
+	_, err := rule.AnalyzeGoProject(nil)
+	assert.Error(t, err)
 }
@@ -0,0 +1,429 @@
+package duplication
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Token-based clone detection shared by the code-clone rule and `glint dupes`.
+//
+// Every window of minTokens normalized tokens is hashed with a rolling hash;
+// the windows are sorted by hash, and a run of equal hashes whose windows are
+// verified token by token is a group of instances. A group whose instances
+// all extend one token to the left with the same membership is the tail of
+// another group and is skipped; every other group is extended to the right
+// while all instances agree, and becomes one clone class.
+
+// DefaultCloneTokens is how many normalized tokens a clone spans at least.
+// About ten lines of Go: shorter runs of equal structure are mostly idiom.
+const DefaultCloneTokens = 100
+
+// CloneFragment is one place a clone occurs.
+type CloneFragment struct {
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// CloneClass is a token sequence that occurs in several places, with all of
+// them, ordered by file and line.
+type CloneClass struct {
+	Tokens    int             `json:"tokens"`
+	Fragments []CloneFragment `json:"fragments"`
+}
+
+// tokenStream is the interned token stream of one declaration of a
+// candidate file.
+type tokenStream struct {
+	ctx   *core.FileContext
+	ids   []int32
+	lines []int
+}
+
+// windowStart is a window of minTokens tokens beginning at offset of a
+// stream.
+type windowStart struct {
+	hash   windowHash
+	stream int32
+	offset int32
+}
+
+// FindClones returns the clone classes of at least minTokens tokens across
+// the candidate files, in the order of their first fragment.
+func FindClones(files []*core.FileContext, minTokens int) []CloneClass {
+	if minTokens <= 0 {
+		minTokens = DefaultCloneTokens
+	}
+	streams := tokenizeCandidates(files)
+	groups := verifiedGroups(streams, hashWindows(streams, minTokens), minTokens)
+
+	groupSize := make([][]int32, len(streams))
+	for i, stream := range streams {
+		groupSize[i] = make([]int32, len(stream.ids))
+	}
+	for _, group := range groups {
+		for _, member := range group {
+			groupSize[member.stream][member.offset] = int32(len(group))
+		}
+	}
+
+	var classes []CloneClass
+	for _, group := range groups {
+		if continuesLeft(streams, groupSize, group) || isPeriodic(group, minTokens) {
+			continue
+		}
+		length := extendRight(streams, group, minTokens)
+		first := group[0]
+		if structuralLength(streams[first.stream].ids[first.offset:int(first.offset)+length]) < minTokens {
+			continue
+		}
+		classes = append(classes, buildClass(streams, group, length))
+	}
+	classes = dropCoveredClasses(classes)
+	sort.SliceStable(classes, func(i, j int) bool {
+		a, b := classes[i].Fragments[0], classes[j].Fragments[0]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+	return classes
+}
+
+// isCloneCandidate reports whether a file takes part in clone detection: a
+// language cloneTokens tokenizes, outside the tests, which repeat their setup
+// on purpose.
+func isCloneCandidate(ctx *core.FileContext) bool {
+	return ctx != nil && ctx.IsGoFile() && !ctx.IsTestFile()
+}
+
+// tokenizeCandidates tokenizes the candidate files in path order and interns
+// the tokens, so windows are compared as integers.
+func tokenizeCandidates(files []*core.FileContext) []tokenStream {
+	candidates := make([]*core.FileContext, 0, len(files))
+	for _, ctx := range files {
+		if isCloneCandidate(ctx) {
+			candidates = append(candidates, ctx)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].RelPath < candidates[j].RelPath })
+
+	ids := make(map[string]int32)
+	var streams []tokenStream
+	for _, ctx := range candidates {
+		for _, tokens := range cloneTokens(ctx) {
+			stream := tokenStream{ctx: ctx, ids: make([]int32, len(tokens)), lines: make([]int, len(tokens))}
+			for i, tok := range tokens {
+				id, ok := ids[tok.text]
+				if !ok {
+					id = int32(len(ids))
+					ids[tok.text] = id
+				}
+				stream.ids[i] = id
+				stream.lines[i] = tok.line
+			}
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
+
+// hashWindows hashes every window of size tokens with a polynomial rolling
+// hash and returns the windows sorted by hash, then by position.
+func hashWindows(streams []tokenStream, size int) []windowStart {
+	power := windowHash(1) // fnvPrime64^(size-1)
+	for range size - 1 {
+		power *= fnvPrime64
+	}
+	var starts []windowStart
+	for index, stream := range streams {
+		if len(stream.ids) < size {
+			continue
+		}
+		var hash windowHash
+		for i := range size {
+			hash = hash*fnvPrime64 + tokenWeight(stream.ids[i])
+		}
+		for offset := 0; ; offset++ {
+			starts = append(starts, windowStart{hash: hash, stream: int32(index), offset: int32(offset)})
+			next := offset + size
+			if next == len(stream.ids) {
+				break
+			}
+			hash = (hash-tokenWeight(stream.ids[offset])*power)*fnvPrime64 + tokenWeight(stream.ids[next])
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		a, b := starts[i], starts[j]
+		if a.hash != b.hash {
+			return a.hash < b.hash
+		}
+		if a.stream != b.stream {
+			return a.stream < b.stream
+		}
+		return a.offset < b.offset
+	})
+	return starts
+}
+
+// tokenWeight spreads the small interned ids over the hash space.
+func tokenWeight(id int32) windowHash {
+	return windowHash(id+1) * 0x9E3779B97F4A7C15
+}
+
+// verifiedGroups splits the runs of equal hashes into groups of windows that
+// are equal token by token — a collision never makes a clone — and keeps the
+// groups of two or more, ordered by their first window.
+func verifiedGroups(streams []tokenStream, starts []windowStart, size int) [][]windowStart {
+	var groups [][]windowStart
+	for begin := 0; begin < len(starts); {
+		end := begin + 1
+		for end < len(starts) && starts[end].hash == starts[begin].hash {
+			end++
+		}
+		pending := starts[begin:end]
+		for len(pending) > 1 {
+			first := pending[0]
+			group := []windowStart{first}
+			var rest []windowStart
+			for _, other := range pending[1:] {
+				if tokensEqual(streams, first, other, size) {
+					group = append(group, other)
+				} else {
+					rest = append(rest, other)
+				}
+			}
+			if len(group) > 1 {
+				groups = append(groups, group)
+			}
+			pending = rest
+		}
+		begin = end
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i][0], groups[j][0]
+		if a.stream != b.stream {
+			return a.stream < b.stream
+		}
+		return a.offset < b.offset
+	})
+	return groups
+}
+
+func tokensEqual(streams []tokenStream, a, b windowStart, size int) bool {
+	x := streams[a.stream].ids[a.offset : int(a.offset)+size]
+	y := streams[b.stream].ids[b.offset : int(b.offset)+size]
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// continuesLeft reports whether the group is the same set of instances as the
+// group one token to the left: that group reports the longer clone.
+func continuesLeft(streams []tokenStream, groupSize [][]int32, group []windowStart) bool {
+	first := group[0]
+	if first.offset == 0 {
+		return false
+	}
+	previous := streams[first.stream].ids[first.offset-1]
+	for _, member := range group {
+		if member.offset == 0 ||
+			groupSize[member.stream][member.offset-1] != int32(len(group)) ||
+			streams[member.stream].ids[member.offset-1] != previous {
+			return false
+		}
+	}
+	return true
+}
+
+// isPeriodic reports whether two instances overlap. Only a region repeating
+// itself at a short period does that — a table of literals, a run of similar
+// statements — and it is a list, not a copy.
+func isPeriodic(group []windowStart, size int) bool {
+	for i := 1; i < len(group); i++ {
+		previous, member := group[i-1], group[i]
+		if member.stream == previous.stream && int(member.offset) < int(previous.offset)+size {
+			return true
+		}
+	}
+	return false
+}
+
+// maxRepeatUnit is the longest token unit structuralLength collapses: a
+// table row or a short statement.
+const maxRepeatUnit = 16
+
+// structuralLength is the length of a token sequence once every run of a
+// repeated unit — rows of a literal table, field-by-field assignments — is
+// collapsed to one occurrence. Two tables whose rows normalize alike are not
+// copies of each other, however long they are.
+func structuralLength(ids []int32) int {
+	length := 0
+	for i := 0; i < len(ids); {
+		step := 1
+		for unit := 1; unit <= maxRepeatUnit && i+2*unit <= len(ids); unit++ {
+			end := i + unit
+			for end+unit <= len(ids) && slicesEqual(ids[end:end+unit], ids[i:i+unit]) {
+				end += unit
+			}
+			if end > i+unit {
+				step = end - i
+				length += unit
+				break
+			}
+		}
+		if step == 1 {
+			length++
+		}
+		i += step
+	}
+	return length
+}
+
+func slicesEqual(a, b []int32) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// extendRight returns how many tokens all instances share from their starts,
+// never letting an instance run into the next one of the same declaration.
+func extendRight(streams []tokenStream, group []windowStart, size int) int {
+	length := size
+	for {
+		first := streams[group[0].stream].ids
+		next := int(group[0].offset) + length
+		for i, member := range group {
+			limit := len(streams[member.stream].ids)
+			if i+1 < len(group) && group[i+1].stream == member.stream {
+				limit = int(group[i+1].offset)
+			}
+			at := int(member.offset) + length
+			if at >= limit || streams[member.stream].ids[at] != first[next] {
+				return length
+			}
+		}
+		length++
+	}
+}
+
+func buildClass(streams []tokenStream, group []windowStart, length int) CloneClass {
+	class := CloneClass{Tokens: length, Fragments: make([]CloneFragment, 0, len(group))}
+	for _, member := range group {
+		stream := streams[member.stream]
+		class.Fragments = append(class.Fragments, CloneFragment{
+			File:      stream.ctx.RelPath,
+			StartLine: stream.lines[member.offset],
+			EndLine:   stream.lines[int(member.offset)+length-1],
+		})
+	}
+	return class
+}
+
+// dropCoveredClasses removes the classes that name no new place. Instances
+// of one copied block that were edited in different spots share differently
+// long prefixes; each subset is a class of its own, but after the largest
+// is reported the others only repeat its fragments.
+func dropCoveredClasses(classes []CloneClass) []CloneClass {
+	sort.SliceStable(classes, func(i, j int) bool {
+		return classes[i].Tokens*len(classes[i].Fragments) > classes[j].Tokens*len(classes[j].Fragments)
+	})
+	reported := make(map[string][]CloneFragment)
+	kept := classes[:0]
+	for _, class := range classes {
+		if fragmentsCovered(class.Fragments, reported) {
+			continue
+		}
+		for _, fragment := range class.Fragments {
+			reported[fragment.File] = append(reported[fragment.File], fragment)
+		}
+		kept = append(kept, class)
+	}
+	return kept
+}
+
+// fragmentsCovered reports whether every fragment overlaps a reported one.
+func fragmentsCovered(fragments []CloneFragment, reported map[string][]CloneFragment) bool {
+	for _, fragment := range fragments {
+		covered := false
+		for _, other := range reported[fragment.File] {
+			if fragment.StartLine <= other.EndLine && other.StartLine <= fragment.EndLine {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// PackageDuplication is the share of a package directory's lines that lie in
+// a clone fragment.
+type PackageDuplication struct {
+	Package         string  `json:"package"`
+	Files           int     `json:"files"`
+	Lines           int     `json:"lines"`
+	DuplicatedLines int     `json:"duplicated_lines"`
+	Percent         float64 `json:"percent"`
+}
+
+// DuplicationByPackage totals the candidate files and their cloned lines by
+// directory, in path order. A line in several fragments counts once.
+func DuplicationByPackage(files []*core.FileContext, classes []CloneClass) []PackageDuplication {
+	covered := make(map[string][][2]int)
+	for _, class := range classes {
+		for _, fragment := range class.Fragments {
+			covered[fragment.File] = append(covered[fragment.File], [2]int{fragment.StartLine, fragment.EndLine})
+		}
+	}
+
+	byPackage := make(map[string]*PackageDuplication)
+	for _, ctx := range files {
+		if !isCloneCandidate(ctx) {
+			continue
+		}
+		dir := filepath.ToSlash(filepath.Dir(ctx.RelPath))
+		pkg := byPackage[dir]
+		if pkg == nil {
+			pkg = &PackageDuplication{Package: dir}
+			byPackage[dir] = pkg
+		}
+		pkg.Files++
+		pkg.Lines += len(ctx.Lines)
+		pkg.DuplicatedLines += coveredLines(covered[ctx.RelPath])
+	}
+
+	result := make([]PackageDuplication, 0, len(byPackage))
+	for _, pkg := range byPackage {
+		if pkg.Lines > 0 {
+			pkg.Percent = 100 * float64(pkg.DuplicatedLines) / float64(pkg.Lines)
+		}
+		result = append(result, *pkg)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Package < result[j].Package })
+	return result
+}
+
+// coveredLines counts the lines of the union of inclusive line ranges.
+func coveredLines(ranges [][2]int) int {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	total, through := 0, 0
+	for _, r := range ranges {
+		start := max(r[0], through+1)
+		if r[1] >= start {
+			total += r[1] - start + 1
+			through = r[1]
+		}
+	}
+	return total
+}
@@ -0,0 +1,94 @@
+package duplication
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+func cloneFile(relPath, source string) *core.FileContext {
+	return &core.FileContext{
+		Path:    "/project/" + relPath,
+		RelPath: relPath,
+		Content: []byte(source),
+		Lines:   strings.Split(source, "\n"),
+	}
+}
+
+func tokenTexts(declarations [][]cloneToken) [][]string {
+	texts := make([][]string, 0, len(declarations))
+	for _, declaration := range declarations {
+		var words []string
+		for _, tok := range declaration {
+			words = append(words, tok.text)
+		}
+		texts = append(texts, words)
+	}
+	return texts
+}
+
+func TestGoCloneTokensNormalizeNamesLiteralsAndWrapping(t *testing.T) {
+	a := goCloneTokens([]byte("package a\n\nimport \"fmt\"\n\nfunc f(x int) { fmt.Println(x, \"a\") }\n"))
+	b := goCloneTokens([]byte("package b\n\nimport (\n\t\"log\"\n)\n\nfunc g(y int) {\n\tlog.Println(y,\n\t\t42)\n}\n"))
+
+	assert.Equal(t, tokenTexts(a), tokenTexts(b))
+	assert.Equal(t, [][]string{{"func", "$id", "(", "$id", "$id", ")", "{", "$id", ".", "$id", "(", "$id", ",", "$lit", ")", "}"}}, tokenTexts(a))
+}
+
+func TestGoCloneTokensSplitTopLevelDeclarations(t *testing.T) {
+	declarations := goCloneTokens([]byte("package a\n\nvar x = 1\n\nfunc f() {\n\tx++\n\tx--\n}\n\ntype t struct{ n int }\n"))
+
+	require.Len(t, declarations, 3)
+	assert.Equal(t, []string{"func", "$id", "(", ")", "{", "$id", "++", "$id", "--", "}"}, tokenTexts(declarations)[1],
+		"statement ends inside a function are no boundary")
+	assert.Equal(t, 5, declarations[1][0].line)
+}
+
+func TestStructuralLengthCollapsesRepeatedUnits(t *testing.T) {
+	assert.Equal(t, 4, structuralLength([]int32{1, 2, 3, 4}))
+	assert.Equal(t, 2, structuralLength([]int32{1, 2, 1, 2, 1, 2, 1, 2}))
+	assert.Equal(t, 5, structuralLength([]int32{9, 1, 2, 1, 2, 1, 2, 8, 7}))
+}
+
+func TestFindClonesSkipsPeriodicCode(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("package flags\n\nfunc register() {\n")
+	for i := range 40 {
+		b.WriteString("\tcmd.Flags().StringVar(&flag" + strings.Repeat("x", i+1) + ", \"name\", \"\", \"usage\")\n")
+	}
+	b.WriteString("}\n")
+
+	assert.Empty(t, FindClones([]*core.FileContext{cloneFile("flags/flags.go", b.String())}, 50),
+		"a run of similar statements repeats itself, it was not copied")
+}
+
+func TestDuplicationByPackage(t *testing.T) {
+	files := []*core.FileContext{
+		cloneFile("api/users.go", userHandler),
+		cloneFile("api/orders.go", orderHandler),
+		cloneFile("api/types.go", handlerTypes),
+		cloneFile("store/store.go", "package store\n\nfunc Open() {}\n"),
+		cloneFile("api/users_test.go", userHandler),
+	}
+	classes := FindClones(files, DefaultCloneTokens)
+	require.Len(t, classes, 1)
+
+	packages := DuplicationByPackage(files, classes)
+	require.Len(t, packages, 2)
+	api := packages[0]
+	assert.Equal(t, "api", api.Package)
+	assert.Equal(t, 3, api.Files, "test files are no candidates")
+	assert.Equal(t, 19+18, api.DuplicatedLines)
+	assert.InDelta(t, 100*float64(37)/float64(api.Lines), api.Percent, 0.001)
+	assert.Equal(t, PackageDuplication{Package: "store", Files: 1, Lines: 4}, packages[1])
+}
+
+func TestCoveredLinesCountsOverlapsOnce(t *testing.T) {
+	assert.Equal(t, 0, coveredLines(nil))
+	assert.Equal(t, 10, coveredLines([][2]int{{1, 5}, {3, 8}, {8, 10}}))
+	assert.Equal(t, 6, coveredLines([][2]int{{20, 22}, {1, 3}}))
+}
@@ -0,0 +1,105 @@
+package duplication
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewCodeCloneRule())
+}
+
+// CodeCloneRule detects Type-2 clones: code repeated with renamed identifiers,
+// changed literals or different line wrapping, which the line windows of
+// duplicate-block and cross-file-duplicate cannot see. Each clone class is
+// reported once, at its first fragment, naming every other place it occurs.
+//
+// The rule works on the whole file set rather than file by file: a class is
+// only complete once every file has been tokenized.
+type CodeCloneRule struct {
+	*rules.BaseRule
+	minTokens int
+}
+
+// NewCodeCloneRule creates the rule
+func NewCodeCloneRule() *CodeCloneRule {
+	return &CodeCloneRule{
+		BaseRule: rules.NewBaseRule(
+			"code-clone",
+			"duplication",
+			"Detects code repeated with renamed identifiers or changed literals (token-based clone classes)",
+			core.SeverityMedium,
+		),
+		minTokens: DefaultCloneTokens,
+	}
+}
+
+// Configure configures the rule
+func (r *CodeCloneRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	minTokens := r.GetIntSetting("min_tokens", DefaultCloneTokens)
+	if minTokens <= 0 {
+		return fmt.Errorf("configure code-clone: min_tokens must be positive, got %d", minTokens)
+	}
+	r.minTokens = minTokens
+	return nil
+}
+
+// RequiresSSA reports that the rule needs neither types nor SSA, only the
+// complete file set.
+func (r *CodeCloneRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile is a no-op: a clone class spans files.
+func (r *CodeCloneRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// AnalyzeGoProject reports every clone class of the project.
+func (r *CodeCloneRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("code clone: nil Go project context")
+	}
+	lines := make(map[string]func(int) string, len(ctx.Files))
+	for _, fileCtx := range ctx.Files {
+		if fileCtx != nil {
+			lines[fileCtx.RelPath] = fileCtx.GetLine
+		}
+	}
+
+	classes := FindClones(ctx.Files, r.minTokens)
+	violations := make([]*core.Violation, 0, len(classes))
+	for _, class := range classes {
+		violations = append(violations, r.report(class, lines[class.Fragments[0].File]))
+	}
+	return violations, nil
+}
+
+func (r *CodeCloneRule) report(class CloneClass, getLine func(int) string) *core.Violation {
+	first := class.Fragments[0]
+	instances := make([]string, 0, len(class.Fragments))
+	for _, fragment := range class.Fragments {
+		instances = append(instances, fragmentLocation(fragment))
+	}
+
+	v := r.CreateViolation(first.File, first.StartLine,
+		"Code clone of "+strconv.Itoa(class.Tokens)+" tokens (lines "+
+			strconv.Itoa(first.StartLine)+"-"+strconv.Itoa(first.EndLine)+") occurs "+
+			strconv.Itoa(len(class.Fragments))+" times, names and literals aside: also at "+
+			strings.Join(instances[1:], ", "))
+	v.WithCode(strings.TrimSpace(getLine(first.StartLine)))
+	v.WithSuggestion("Extract the shared logic into one function that takes what differs as parameters")
+	v.WithContext("clone_tokens", class.Tokens)
+	v.WithContext("clone_instances", instances)
+	return v
+}
+
+func fragmentLocation(fragment CloneFragment) string {
+	return fragment.File + ":" + strconv.Itoa(fragment.StartLine) + "-" + strconv.Itoa(fragment.EndLine)
+}
@@ -0,0 +1,197 @@
+package duplication
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+const userHandler = `package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	var user User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		http.Error(w, "invalid user payload", http.StatusBadRequest)
+		return
+	}
+	if user.Name == "" || len(user.Name) > 64 {
+		http.Error(w, "user name is required", http.StatusUnprocessableEntity)
+		return
+	}
+	saved, err := users.Save(r.Context(), user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+`
+
+// orderHandler is userHandler copied, renamed, re-worded and re-wrapped.
+const orderHandler = `package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func CreateOrder(rw http.ResponseWriter, req *http.Request) {
+	var order Order
+	if err := json.NewDecoder(req.Body).Decode(&order); err != nil {
+		http.Error(rw, "bad order", http.StatusBadRequest)
+		return
+	}
+	if order.Item == "" || len(order.Item) > 128 {
+		http.Error(rw, "order item is required",
+			http.StatusUnprocessableEntity)
+		return
+	}
+	created, err := orders.Save(req.Context(), order)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusCreated)
+	json.NewEncoder(rw).Encode(created)
+}
+`
+
+const handlerTypes = `package api
+
+import "context"
+
+type User struct{ Name string }
+
+type Order struct{ Item string }
+
+type userStore struct{}
+
+func (userStore) Save(_ context.Context, u User) (User, error) { return u, nil }
+
+type orderStore struct{}
+
+func (orderStore) Save(_ context.Context, o Order) (Order, error) { return o, nil }
+
+var (
+	users  userStore
+	orders orderStore
+)
+`
+
+func analyzeClones(t *testing.T, rule *CodeCloneRule, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := rule.AnalyzeGoProject(rulestest.Project(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func TestCodeCloneReportsRenamedCopy(t *testing.T) {
+	violations := analyzeClones(t, NewCodeCloneRule(), map[string]string{
+		"api/types.go":  handlerTypes,
+		"api/users.go":  userHandler,
+		"api/orders.go": orderHandler,
+	})
+
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "api/orders.go", v.File, "a class is reported at its first fragment in path order")
+	assert.Equal(t, 8, v.Line)
+	assert.Equal(t, []string{"api/orders.go:8-26", "api/users.go:8-25"}, v.Context["clone_instances"])
+	assert.Contains(t, v.Message, "occurs 2 times")
+	assert.Contains(t, v.Message, "also at api/users.go:8-25")
+}
+
+func TestCodeCloneReportsOneClassForAllInstances(t *testing.T) {
+	invoiceHandler := strings.NewReplacer("CreateUser", "CreateInvoice", "user", "invoice").Replace(userHandler)
+	violations := analyzeClones(t, NewCodeCloneRule(), map[string]string{
+		"api/types.go":    handlerTypes + "\ntype invoiceStore = userStore\n\nvar invoices invoiceStore\n\ntype Invoice = User\n",
+		"api/users.go":    userHandler,
+		"api/orders.go":   orderHandler,
+		"api/invoices.go": invoiceHandler,
+	})
+
+	require.Len(t, violations, 1, "three copies are one clone class, not three pairs")
+	assert.Len(t, violations[0].Context["clone_instances"], 3)
+}
+
+func TestCodeCloneIgnoresDifferentStructure(t *testing.T) {
+	violations := analyzeClones(t, NewCodeCloneRule(), map[string]string{
+		"api/types.go": handlerTypes,
+		"api/users.go": userHandler,
+		"api/orders.go": `package api
+
+import "net/http"
+
+func CreateOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+`,
+	})
+	assert.Empty(t, violations)
+}
+
+func TestCodeCloneIgnoresLiteralTables(t *testing.T) {
+	table := func(name string, keys ...string) string {
+		var b strings.Builder
+		b.WriteString("package codes\n\nvar " + name + " = map[string]int{\n")
+		for i, key := range keys {
+			b.WriteString("\t\"" + key + "\": " + strconv.Itoa(i) + ",\n")
+		}
+		b.WriteString("}\n")
+		return b.String()
+	}
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z aa bb cc dd ee ff")
+	violations := analyzeClones(t, NewCodeCloneRule(), map[string]string{
+		"codes/http.go": table("httpCodes", keys...),
+		"codes/grpc.go": table("grpcCodes", keys...),
+	})
+	assert.Empty(t, violations, "rows that normalize alike make two tables look equal, not copied")
+}
+
+func TestCodeCloneSkipsTestFiles(t *testing.T) {
+	violations := analyzeClones(t, NewCodeCloneRule(), map[string]string{
+		"api/types.go":         handlerTypes,
+		"api/users.go":         userHandler,
+		"api/handlers_test.go": strings.Replace(orderHandler, "package api", "package api_test", 1),
+	})
+	assert.Empty(t, violations)
+}
+
+func TestCodeCloneMinTokens(t *testing.T) {
+	rule := NewCodeCloneRule()
+	require.NoError(t, rule.Configure(map[string]any{"min_tokens": 400}))
+	violations := analyzeClones(t, rule, map[string]string{
+		"api/types.go":  handlerTypes,
+		"api/users.go":  userHandler,
+		"api/orders.go": orderHandler,
+	})
+	assert.Empty(t, violations)
+
+	assert.Error(t, rule.Configure(map[string]any{"min_tokens": 0}))
+}
+
+func TestCodeCloneRuleMetadata(t *testing.T) {
+	rule := NewCodeCloneRule()
+	assert.Equal(t, "code-clone", rule.Name())
+	assert.Equal(t, "duplication", rule.Category())
+	assert.False(t, rule.RequiresSSA())
+
+	_, err := rule.AnalyzeGoProject(nil)
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package duplication
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,7 +27,9 @@ type BlockLocation struct {
 	Content   []string
 }
 
-// CrossFileDuplicateRule detects duplicate code blocks across different files
+// CrossFileDuplicateRule detects duplicate code blocks across different files,
+// and literal tables or functions a Go backend and a TypeScript frontend both
+// keep (see cross_language.go).
 type CrossFileDuplicateRule struct {
 	*rules.BaseRule
 	minBlockSize int
@@ -37,6 +40,14 @@ type CrossFileDuplicateRule struct {
 	mu        sync.Mutex
 	firstSeen map[windowHash]BlockLocation
 	reported  map[windowHash]bool
+
+	// Cross-language comparison of Go against TypeScript: literal tables and
+	// function skeletons of every file analyzed so far.
+	crossLanguage     bool
+	minTableLiterals  int
+	minFunctionTokens int
+	tables            []languageUnit
+	functions         map[string][]languageUnit
 }
 
 // NewCrossFileDuplicateRule creates the rule
@@ -48,9 +59,13 @@ func NewCrossFileDuplicateRule() *CrossFileDuplicateRule {
 			"Detects duplicate code blocks across different files",
 			core.SeverityHigh,
 		),
-		minBlockSize: defaultCrossFileBlockSize,
-		firstSeen:    make(map[windowHash]BlockLocation),
-		reported:     make(map[windowHash]bool),
+		minBlockSize:      defaultCrossFileBlockSize,
+		firstSeen:         make(map[windowHash]BlockLocation),
+		reported:          make(map[windowHash]bool),
+		crossLanguage:     true,
+		minTableLiterals:  defaultMinTableLiterals,
+		minFunctionTokens: defaultMinFunctionTokens,
+		functions:         make(map[string][]languageUnit),
 	}
 }
 
@@ -60,6 +75,16 @@ func (r *CrossFileDuplicateRule) Configure(settings map[string]any) error {
 		return err
 	}
 	r.minBlockSize = r.GetIntSetting("min_block_size", defaultCrossFileBlockSize)
+	r.crossLanguage = r.GetBoolSetting("cross_language", true)
+	minTableLiterals := r.GetIntSetting("min_table_literals", defaultMinTableLiterals)
+	if minTableLiterals <= 0 {
+		return fmt.Errorf("configure cross-file-duplicate: min_table_literals must be positive, got %d", minTableLiterals)
+	}
+	minFunctionTokens := r.GetIntSetting("min_function_tokens", defaultMinFunctionTokens)
+	if minFunctionTokens <= 0 {
+		return fmt.Errorf("configure cross-file-duplicate: min_function_tokens must be positive, got %d", minFunctionTokens)
+	}
+	r.minTableLiterals, r.minFunctionTokens = minTableLiterals, minFunctionTokens
 	return nil
 }
 
@@ -70,20 +95,27 @@ func (r *CrossFileDuplicateRule) ResetState() {
 	defer r.mu.Unlock()
 	r.firstSeen = make(map[windowHash]BlockLocation)
 	r.reported = make(map[windowHash]bool)
+	r.tables = nil
+	r.functions = make(map[string][]languageUnit)
 }
 
-// AnalyzeFile collects blocks and detects cross-file duplicates
+// AnalyzeFile collects blocks and detects cross-file duplicates, and compares
+// the file's literal tables and functions with those of the other language.
 func (r *CrossFileDuplicateRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
 	if !isDuplicationCandidate(ctx) || ctx.IsTestFile() {
 		return nil
 	}
 
+	var violations []*core.Violation
+	if r.crossLanguage {
+		violations = r.compareAcrossLanguages(ctx)
+	}
 	if len(ctx.Lines) < r.minBlockSize {
-		return nil
+		return violations
 	}
 
 	// Collect blocks from this file and check for duplicates
-	return r.processFile(ctx, normalizeFileLines(ctx.Lines))
+	return append(violations, r.processFile(ctx, normalizeFileLines(ctx.Lines))...)
 }
 
 func (r *CrossFileDuplicateRule) processFile(ctx *core.FileContext, normalized []string) []*core.Violation {
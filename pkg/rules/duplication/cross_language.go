@@ -0,0 +1,676 @@
+package duplication
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Cross-language comparison: a Go backend and a TypeScript frontend that both
+// encode a business rule drift apart one edit at a time. Two shapes are
+// compared. Literal tables — enums, error codes, limits — match on the values
+// they share. Validation functions match on a neutral skeleton that sets aside
+// names, parentheses and the way each language spells equality or a failure.
+
+const (
+	defaultMinTableLiterals  = 3
+	defaultMinFunctionTokens = 20
+	// maxDriftValues caps the values a finding lists per side.
+	maxDriftValues = 5
+)
+
+// languageUnit is a literal table or a function of one file, kept until the
+// end of the run so that files of the other language are compared against it.
+type languageUnit struct {
+	language  string
+	name      string
+	file      string
+	startLine int
+	endLine   int
+	// literals are the distinct non-trivial literal values in source order.
+	literals []string
+	// skeleton and size describe a function's neutral structure.
+	skeleton string
+	size     int
+}
+
+// otherLanguage reports whether two units come from different sides of the
+// Go/TypeScript boundary; TypeScript and JavaScript are one side.
+func (u languageUnit) otherLanguage(other languageUnit) bool {
+	return (u.language == "Go") != (other.language == "Go")
+}
+
+// languageUnits extracts the literal tables and the functions of a file.
+func languageUnits(ctx *core.FileContext, minTableLiterals int) (tables, functions []languageUnit) {
+	language := sourceLanguage(ctx)
+	isGo := language == "Go"
+	unit := func(name string, start, end int, literals []string) languageUnit {
+		return languageUnit{language: language, name: name, file: ctx.RelPath, startLine: start, endLine: end, literals: literals}
+	}
+
+	// run gathers consecutive one-value declarations — a constant per line
+	// rather than a block — into one table.
+	var run []languageUnit
+	flushRun := func() {
+		if len(run) > 1 {
+			table := unit(run[0].name, run[0].startLine, run[len(run)-1].endLine, nil)
+			for _, member := range run {
+				table.literals = appendDistinct(table.literals, member.literals...)
+			}
+			tables = append(tables, table)
+		}
+		run = nil
+	}
+
+	for _, stmt := range topLevelStatements(sourceTokens(ctx), !isGo) {
+		var fn functionDecl
+		var isFunction bool
+		if isGo {
+			fn, isFunction = goFunction(stmt)
+		} else {
+			fn, isFunction = tsFunction(stmt)
+		}
+		if isFunction {
+			flushRun()
+			skeleton := functionSkeleton(fn.body, fn.expression)
+			function := unit(fn.name, fn.startLine, fn.endLine, bodyLiterals(fn.body))
+			function.skeleton, function.size = strings.Join(skeleton, " "), len(skeleton)
+			functions = append(functions, function)
+			continue
+		}
+
+		name, isTable := tableDeclaration(stmt, isGo)
+		if !isTable {
+			flushRun()
+			continue
+		}
+		table := unit(name, stmt[0].line, stmt[len(stmt)-1].line, bodyLiterals(stmt))
+		switch {
+		case len(table.literals) == 1:
+			run = append(run, table)
+		case len(table.literals) >= minTableLiterals:
+			flushRun()
+			tables = append(tables, table)
+		default:
+			flushRun()
+		}
+	}
+	flushRun()
+
+	kept := tables[:0]
+	for _, table := range tables {
+		if len(table.literals) >= minTableLiterals {
+			kept = append(kept, table)
+		}
+	}
+	return kept, functions
+}
+
+// tsStatementStarts are the keywords that begin a new top-level statement
+// when they open a line; TypeScript does not need semicolons between them.
+var tsStatementStarts = map[string]bool{
+	"export": true, "import": true, "const": true, "let": true, "var": true, "function": true,
+	"class": true, "interface": true, "type": true, "enum": true, "async": true,
+	"declare": true, "abstract": true,
+}
+
+// topLevelStatements splits a file into its top-level statements. Go ends
+// each with a semicolon, inserted or not; TypeScript may also end one with a
+// line break before a statement keyword.
+func topLevelStatements(tokens []sourceToken, newlineEnds bool) [][]sourceToken {
+	var statements [][]sourceToken
+	start, depth := 0, 0
+	for i, tok := range tokens {
+		if depth == 0 && newlineEnds && tok.newline && tok.kind != literalToken && tsStatementStarts[tok.text] {
+			if i > start {
+				statements = append(statements, tokens[start:i])
+			}
+			start = i
+		}
+		if tok.kind != punctToken {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth = max(depth-1, 0)
+		case ";":
+			if depth == 0 {
+				if i > start {
+					statements = append(statements, tokens[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	if start < len(tokens) {
+		statements = append(statements, tokens[start:])
+	}
+	return statements
+}
+
+// closing returns the index of the bracket closing the one at open, or the
+// last index when it is never closed.
+func closing(tokens []sourceToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].kind != punctToken {
+			continue
+		}
+		switch tokens[i].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+func isPunct(tokens []sourceToken, i int, text string) bool {
+	return i < len(tokens) && tokens[i].kind == punctToken && tokens[i].text == text
+}
+
+func isIdent(tokens []sourceToken, i int) bool {
+	return i < len(tokens) && tokens[i].kind == identToken
+}
+
+// functionDecl is a function found at the top level. An arrow function with
+// an expression body has that expression as its body.
+type functionDecl struct {
+	name       string
+	body       []sourceToken
+	expression bool
+	startLine  int
+	endLine    int
+}
+
+// goFunction recognizes a function or method declaration.
+func goFunction(stmt []sourceToken) (functionDecl, bool) {
+	if len(stmt) < 2 || stmt[0].kind != keywordToken || stmt[0].text != "func" {
+		return functionDecl{}, false
+	}
+	i := 1
+	if isPunct(stmt, i, "(") {
+		i = closing(stmt, i) + 1 // receiver
+	}
+	if !isIdent(stmt, i) {
+		return functionDecl{}, false
+	}
+	name := stmt[i].text
+	for j := i + 1; j < len(stmt); j++ {
+		if stmt[j].kind != punctToken {
+			continue
+		}
+		switch stmt[j].text {
+		case "(", "[":
+			j = closing(stmt, j)
+		case "{":
+			// struct{} and interface{} in the signature are types, not the body.
+			if stmt[j-1].text == "struct" || stmt[j-1].text == "interface" {
+				j = closing(stmt, j)
+				continue
+			}
+			end := closing(stmt, j)
+			return functionDecl{name: name, body: stmt[j+1 : end], startLine: stmt[0].line, endLine: stmt[end].line}, true
+		}
+	}
+	return functionDecl{}, false
+}
+
+// tsFunction recognizes a function declaration or a variable initialized
+// with a function expression or an arrow function.
+func tsFunction(stmt []sourceToken) (functionDecl, bool) {
+	stmt = stripTSModifiers(stmt)
+	if len(stmt) < 3 || stmt[0].kind != keywordToken {
+		return functionDecl{}, false
+	}
+	startLine := stmt[0].line
+	switch stmt[0].text {
+	case "function":
+		i := 1
+		if isPunct(stmt, i, "*") {
+			i++
+		}
+		if !isIdent(stmt, i) {
+			return functionDecl{}, false
+		}
+		return tsFunctionRest(stmt, i+1, stmt[i].text, startLine)
+	case "const", "let", "var":
+		if !isIdent(stmt, 1) {
+			return functionDecl{}, false
+		}
+		name := stmt[1].text
+		i := skipTSType(stmt, 2, "=")
+		if !isPunct(stmt, i, "=") {
+			return functionDecl{}, false
+		}
+		i++
+		if i < len(stmt) && stmt[i].text == "async" {
+			i++
+		}
+		if i < len(stmt) && stmt[i].kind == keywordToken && stmt[i].text == "function" {
+			i++
+			if isPunct(stmt, i, "*") {
+				i++
+			}
+			if isIdent(stmt, i) {
+				i++
+			}
+			return tsFunctionRest(stmt, i, name, startLine)
+		}
+		return tsArrow(stmt, i, name, startLine)
+	}
+	return functionDecl{}, false
+}
+
+// stripTSModifiers drops the keywords that may precede a declaration.
+func stripTSModifiers(stmt []sourceToken) []sourceToken {
+	for len(stmt) > 0 {
+		switch stmt[0].text {
+		case "export", "default", "declare", "async":
+			stmt = stmt[1:]
+		default:
+			return stmt
+		}
+	}
+	return stmt
+}
+
+// tsFunctionRest reads type parameters, parameters, a return type and the
+// body of a function from its parameter list on.
+func tsFunctionRest(stmt []sourceToken, i int, name string, startLine int) (functionDecl, bool) {
+	i = skipAngles(stmt, i)
+	if !isPunct(stmt, i, "(") {
+		return functionDecl{}, false
+	}
+	i = closing(stmt, i) + 1
+	if isPunct(stmt, i, ":") {
+		i = skipTSType(stmt, i+1, "{")
+	}
+	if !isPunct(stmt, i, "{") {
+		return functionDecl{}, false
+	}
+	end := closing(stmt, i)
+	return functionDecl{name: name, body: stmt[i+1 : end], startLine: startLine, endLine: stmt[end].line}, true
+}
+
+func tsArrow(stmt []sourceToken, i int, name string, startLine int) (functionDecl, bool) {
+	i = skipAngles(stmt, i)
+	switch {
+	case isPunct(stmt, i, "("):
+		i = closing(stmt, i) + 1
+	case isIdent(stmt, i):
+		i++
+	default:
+		return functionDecl{}, false
+	}
+	if isPunct(stmt, i, ":") {
+		i = skipTSType(stmt, i+1, "=>")
+	}
+	if !isPunct(stmt, i, "=>") || i+1 >= len(stmt) {
+		return functionDecl{}, false
+	}
+	i++
+	if isPunct(stmt, i, "{") {
+		end := closing(stmt, i)
+		return functionDecl{name: name, body: stmt[i+1 : end], startLine: startLine, endLine: stmt[end].line}, true
+	}
+	return functionDecl{name: name, body: stmt[i:], expression: true, startLine: startLine, endLine: stmt[len(stmt)-1].line}, true
+}
+
+// skipAngles skips type parameters when they start at i.
+func skipAngles(stmt []sourceToken, i int) int {
+	if !isPunct(stmt, i, "<") {
+		return i
+	}
+	depth := 0
+	for ; i < len(stmt); i++ {
+		switch {
+		case isPunct(stmt, i, "<"):
+			depth++
+		case isPunct(stmt, i, ">"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// skipTSType returns the index of the token that ends a type annotation
+// starting at i: stop at the top level. A brace right after ":", "|", "&",
+// "," or "=>" opens an object type rather than ending the type.
+func skipTSType(stmt []sourceToken, i int, stop string) int {
+	angles := 0
+	for ; i < len(stmt); i++ {
+		tok := stmt[i]
+		if tok.kind != punctToken {
+			continue
+		}
+		switch tok.text {
+		case "<":
+			angles++
+		case ">":
+			angles = max(angles-1, 0)
+		case "(", "[":
+			i = closing(stmt, i)
+		case "{":
+			if angles == 0 && stop == "{" && !opensObjectType(stmt[i-1]) {
+				return i
+			}
+			i = closing(stmt, i)
+		default:
+			if angles == 0 && tok.text == stop {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+func opensObjectType(previous sourceToken) bool {
+	switch previous.text {
+	case ":", "|", "&", ",", "=>", "<":
+		return previous.kind == punctToken
+	}
+	return false
+}
+
+// tableDeclaration recognizes a top-level declaration that may hold a
+// literal table: Go const and var, TypeScript enums, variables and type
+// aliases (a union of string literals is an enum).
+func tableDeclaration(stmt []sourceToken, isGo bool) (string, bool) {
+	if !isGo {
+		stmt = stripTSModifiers(stmt)
+		if len(stmt) > 1 && stmt[0].text == "const" && stmt[1].text == "enum" {
+			stmt = stmt[1:]
+		}
+	}
+	if len(stmt) < 2 || stmt[0].kind != keywordToken {
+		return "", false
+	}
+	switch stmt[0].text {
+	case "const", "var":
+	case "let", "enum", "type":
+		if isGo {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	for _, tok := range stmt[1:] {
+		if tok.kind == identToken {
+			return tok.text, true
+		}
+	}
+	return "", false
+}
+
+// isTrivialLiteral reports values too common to say anything about a match.
+func isTrivialLiteral(value string) bool {
+	switch value {
+	case "", "0", "1", "true", "false":
+		return true
+	}
+	return false
+}
+
+func bodyLiterals(tokens []sourceToken) []string {
+	var literals []string
+	for _, tok := range tokens {
+		if tok.kind == literalToken && !isTrivialLiteral(tok.text) {
+			literals = appendDistinct(literals, tok.text)
+		}
+	}
+	return literals
+}
+
+func appendDistinct(list []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// functionSkeleton reduces a function body to the structure both languages
+// share. Each operand — a name, a member chain, a call, an index — becomes
+// $op, a literal $lit and nil, null or undefined $nil. Parentheses,
+// semicolons, declarations, new and await are dropped; === and !== read as
+// == and !=, := as =, and throw as return. Loop and switch headers collapse
+// to their keyword, and a bare return closing the body is dropped, since Go
+// spells out what TypeScript leaves implicit.
+func functionSkeleton(body []sourceToken, expression bool) []string {
+	var out []string
+	if expression {
+		out = append(out, "return")
+	}
+	for i := 0; i < len(body); i++ {
+		tok := body[i]
+		switch tok.kind {
+		case identToken, literalToken:
+			i = appendOperand(&out, body, i)
+		case keywordToken:
+			switch tok.text {
+			case "nil", "this", "super":
+				i = appendOperand(&out, body, i)
+			case "const", "let", "var", "new", "await", "break":
+			case "throw":
+				out = append(out, "return")
+			case "for", "while", "switch":
+				out = append(out, tok.text)
+				i = blockStart(body, i+1) - 1
+			case "as":
+				i = skipTSType(body, i+1, ")") - 1
+			default:
+				out = append(out, tok.text)
+			}
+		case punctToken:
+			switch tok.text {
+			case "(", ")", ";":
+			case "===":
+				out = append(out, "==")
+			case "!==":
+				out = append(out, "!=")
+			case ":=":
+				out = append(out, "=")
+			default:
+				out = append(out, tok.text)
+			}
+		}
+	}
+	switch {
+	case len(out) > 0 && out[len(out)-1] == "return":
+		out = out[:len(out)-1]
+	case len(out) > 1 && out[len(out)-2] == "return" && out[len(out)-1] == "$nil":
+		out = out[:len(out)-2]
+	}
+	return out
+}
+
+// appendOperand appends the placeholder of the operand starting at i and
+// returns the index of its last token.
+func appendOperand(out *[]string, body []sourceToken, i int) int {
+	start := i
+	for i+1 < len(body) {
+		next := body[i+1]
+		switch {
+		case next.kind != punctToken:
+			return finishOperand(out, body, start, i)
+		case (next.text == "." || next.text == "?.") && i+2 < len(body) && body[i+2].kind != punctToken:
+			i += 2
+		case next.text == "." && isPunct(body, i+2, "("):
+			i = closing(body, i+2) // type assertion
+		case next.text == "!" && isPunct(body, i+2, "."):
+			i++ // non-null assertion
+		case next.text == "(" || next.text == "[":
+			i = closing(body, i+1)
+		default:
+			return finishOperand(out, body, start, i)
+		}
+	}
+	return finishOperand(out, body, start, i)
+}
+
+func finishOperand(out *[]string, body []sourceToken, start, end int) int {
+	switch {
+	case start < end:
+		*out = append(*out, "$op")
+	case body[start].kind == literalToken:
+		*out = append(*out, "$lit")
+	case body[start].text == "nil":
+		*out = append(*out, "$nil")
+	default:
+		*out = append(*out, "$op")
+	}
+	return end
+}
+
+// blockStart returns the index of the brace opening the block after a loop
+// or switch header that starts at i.
+func blockStart(body []sourceToken, i int) int {
+	for ; i < len(body); i++ {
+		switch {
+		case isPunct(body, i, "("), isPunct(body, i, "["):
+			i = closing(body, i)
+		case isPunct(body, i, "{"):
+			return i
+		}
+	}
+	return i
+}
+
+// compareAcrossLanguages reports the tables and functions of the file that
+// repeat a unit of the other language seen earlier in the run, then keeps
+// the file's units for the files still to come.
+func (r *CrossFileDuplicateRule) compareAcrossLanguages(ctx *core.FileContext) []*core.Violation {
+	tables, functions := languageUnits(ctx, r.minTableLiterals)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var violations []*core.Violation
+	for _, table := range tables {
+		if match, shared, ok := r.matchTable(table); ok {
+			message := fmt.Sprintf("Literal table %s repeats %s table %s at %s (%d shared values)",
+				table.name, match.language, match.name, unitRange(match), shared)
+			violations = append(violations, r.reportAcrossLanguages(ctx, table, match, message))
+		}
+	}
+	for _, function := range functions {
+		if match, ok := r.matchFunction(function); ok {
+			message := fmt.Sprintf("Function %s has the same structure as %s function %s at %s",
+				function.name, match.language, match.name, unitRange(match))
+			violations = append(violations, r.reportAcrossLanguages(ctx, function, match, message))
+		}
+	}
+
+	r.tables = append(r.tables, tables...)
+	for _, function := range functions {
+		if function.size >= r.minFunctionTokens {
+			r.functions[function.skeleton] = append(r.functions[function.skeleton], function)
+		}
+	}
+	return violations
+}
+
+// matchTable returns the table of the other language sharing the most values
+// with table, when they share at least minTableLiterals values and at least
+// half of the smaller table.
+func (r *CrossFileDuplicateRule) matchTable(table languageUnit) (languageUnit, int, bool) {
+	var best languageUnit
+	bestShared := 0
+	for _, other := range r.tables {
+		if !table.otherLanguage(other) {
+			continue
+		}
+		shared := len(table.literals) - len(missingFrom(table.literals, other.literals))
+		if shared < r.minTableLiterals || 2*shared < min(len(table.literals), len(other.literals)) {
+			continue
+		}
+		if shared > bestShared {
+			best, bestShared = other, shared
+		}
+	}
+	return best, bestShared, bestShared > 0
+}
+
+// matchFunction returns the function of the other language with the same
+// skeleton that shares the most literals with function. Sharing none, two
+// functions are more likely alike by chance than by copying.
+func (r *CrossFileDuplicateRule) matchFunction(function languageUnit) (languageUnit, bool) {
+	if function.size < r.minFunctionTokens {
+		return languageUnit{}, false
+	}
+	var best languageUnit
+	bestShared := 0
+	for _, other := range r.functions[function.skeleton] {
+		if !function.otherLanguage(other) {
+			continue
+		}
+		if shared := len(function.literals) - len(missingFrom(function.literals, other.literals)); shared > bestShared {
+			best, bestShared = other, shared
+		}
+	}
+	return best, bestShared > 0
+}
+
+// reportAcrossLanguages reports unit of the analyzed file against the unit
+// of the other language it repeats, listing the values only one side has.
+func (r *CrossFileDuplicateRule) reportAcrossLanguages(ctx *core.FileContext, unit, match languageUnit, message string) *core.Violation {
+	onlyHere := missingFrom(unit.literals, match.literals)
+	onlyThere := missingFrom(match.literals, unit.literals)
+	if len(onlyHere) > 0 {
+		message += "; only here: " + quoteValues(onlyHere)
+	}
+	if len(onlyThere) > 0 {
+		message += "; only in " + match.language + ": " + quoteValues(onlyThere)
+	}
+
+	v := r.CreateViolation(ctx.RelPath, unit.startLine, message)
+	v.WithCode(ctx.GetLine(unit.startLine))
+	v.WithSuggestion("Keep one source of truth: generate one side from the other or serve the values from the backend")
+	v.WithContext("original_file", match.file)
+	v.WithContext("original_start", match.startLine)
+	v.WithContext("original_end", match.endLine)
+	v.WithContext("original_language", match.language)
+	v.WithContext("only_here", onlyHere)
+	v.WithContext("only_there", onlyThere)
+	return v
+}
+
+// missingFrom returns the values of list that other lacks.
+func missingFrom(list, other []string) []string {
+	var missing []string
+	for _, value := range list {
+		if !slices.Contains(other, value) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+func quoteValues(values []string) string {
+	quoted := make([]string, 0, min(len(values), maxDriftValues))
+	for _, value := range values[:min(len(values), maxDriftValues)] {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	if len(values) > maxDriftValues {
+		quoted = append(quoted, fmt.Sprintf("and %d more", len(values)-maxDriftValues))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func unitRange(unit languageUnit) string {
+	return unit.file + ":" + strconv.Itoa(unit.startLine) + "-" + strconv.Itoa(unit.endLine)
+}
@@ -0,0 +1,167 @@
+package duplication
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goPayments = `package payments
+
+import "fmt"
+
+const (
+	CurrencyUSD      = "USD"
+	CurrencyEUR      = "EUR"
+	CurrencyGBP      = "GBP"
+	MaxTransferCents = 1_000_000
+)
+
+func ValidateTransfer(amount int64, currency string, memo string) error {
+	if amount <= 0 || amount > MaxTransferCents {
+		return fmt.Errorf("amount out of range")
+	}
+	if currency != "USD" && currency != "EUR" && currency != "GBP" {
+		return fmt.Errorf("unsupported currency")
+	}
+	if len(memo) > 140 {
+		return fmt.Errorf("memo too long")
+	}
+	return nil
+}
+`
+
+const tsPayments = `export enum Currency {
+  USD = 'USD',
+  EUR = 'EUR',
+  GBP = 'GBP',
+}
+
+export function validateTransfer(amount: number, currency: string, memo: string): void {
+  if (amount <= 0 || amount > MAX_TRANSFER_CENTS) {
+    throw new Error('amount out of range');
+  }
+  if (currency !== 'USD' && currency !== 'EUR' && currency !== 'GBP') {
+    throw new Error('unsupported currency');
+  }
+  if (memo.length > 280) {
+    throw new Error('memo too long');
+  }
+}
+`
+
+func TestCrossFileDuplicateComparesGoAndTypeScript(t *testing.T) {
+	rule := NewCrossFileDuplicateRule()
+	backend := createTestContext(t, "internal/payments/validate.go", goPayments)
+	frontend := createTestContext(t, "web/src/payments.ts", tsPayments)
+
+	assert.Empty(t, rule.AnalyzeFile(backend))
+	violations := rule.AnalyzeFile(frontend)
+	require.Len(t, violations, 2)
+
+	table := violations[0]
+	assert.Equal(t, 1, table.Line)
+	assert.Contains(t, table.Message, "Literal table Currency repeats Go table CurrencyUSD at internal/payments/validate.go:5-10 (3 shared values)")
+	assert.Contains(t, table.Message, `only in Go: "1000000"`)
+
+	function := violations[1]
+	assert.Equal(t, 7, function.Line)
+	assert.Contains(t, function.Message, "Function validateTransfer has the same structure as Go function ValidateTransfer at internal/payments/validate.go:12-23")
+	assert.Contains(t, function.Message, `only here: "280"; only in Go: "140"`)
+	assert.Equal(t, "internal/payments/validate.go", function.Context["original_file"])
+	assert.Equal(t, "Go", function.Context["original_language"])
+}
+
+func TestCrossFileDuplicateReportsTheLaterLanguage(t *testing.T) {
+	rule := NewCrossFileDuplicateRule()
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "web/src/payments.ts", tsPayments)))
+
+	violations := rule.AnalyzeFile(createTestContext(t, "internal/payments/validate.go", goPayments))
+	require.Len(t, violations, 2)
+	assert.Contains(t, violations[1].Message, "Function ValidateTransfer has the same structure as TypeScript function validateTransfer at web/src/payments.ts:7-17")
+}
+
+func TestCrossFileDuplicateCrossLanguageNeedsSharedEvidence(t *testing.T) {
+	rule := NewCrossFileDuplicateRule()
+	// Same skeleton, but not one literal in common: alike by chance.
+	renamed := strings.NewReplacer("out of range", "invalid", "unsupported currency", "bad code",
+		"memo too long", "memo", "'USD'", "'A'", "'EUR'", "'B'", "'GBP'", "'C'").Replace(tsPayments)
+	// Same literals, different structure.
+	reordered := `export const isValid = (amount: number): boolean => amount > 0 && ['USD', 'EUR', 'GBP'].includes('x')
+`
+
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "internal/payments/validate.go", goPayments)))
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "web/src/renamed.ts", renamed)))
+	violations := rule.AnalyzeFile(createTestContext(t, "web/src/reordered.ts", reordered))
+	assert.Empty(t, violations, "an arrow function of another structure is no copy")
+}
+
+func TestCrossFileDuplicateComparesOnlyAcrossLanguages(t *testing.T) {
+	rule := NewCrossFileDuplicateRule()
+	rule.minBlockSize = 100 // leave the line windows out of it
+
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "internal/a/validate.go", goPayments)))
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "internal/b/validate.go", goPayments)))
+}
+
+func TestCrossFileDuplicateGroupsConstantRuns(t *testing.T) {
+	rule := NewCrossFileDuplicateRule()
+	constants := `export const STATUS_PENDING = "pending";
+export const STATUS_SETTLED = "settled";
+export const STATUS_FAILED = "failed";
+export const STATUS_REFUNDED = "refunded";
+`
+	statuses := `package ledger
+
+type Status string
+
+const StatusPending Status = "pending"
+const StatusSettled Status = "settled"
+const StatusFailed Status = "failed"
+`
+
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "web/src/status.ts", constants)))
+	violations := rule.AnalyzeFile(createTestContext(t, "internal/ledger/status.go", statuses))
+	require.Len(t, violations, 1)
+	assert.Contains(t, violations[0].Message, "Literal table StatusPending repeats TypeScript table STATUS_PENDING at web/src/status.ts:1-4")
+	assert.Contains(t, violations[0].Message, `only in TypeScript: "refunded"`)
+}
+
+func TestCrossFileDuplicateCrossLanguageSettings(t *testing.T) {
+	rule := NewCrossFileDuplicateRule()
+	require.NoError(t, rule.Configure(map[string]any{"cross_language": false}))
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "internal/payments/validate.go", goPayments)))
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "web/src/payments.ts", tsPayments)))
+
+	assert.Error(t, NewCrossFileDuplicateRule().Configure(map[string]any{"min_table_literals": 0}))
+	assert.Error(t, NewCrossFileDuplicateRule().Configure(map[string]any{"min_function_tokens": -1}))
+
+	rule = NewCrossFileDuplicateRule()
+	rule.AnalyzeFile(createTestContext(t, "internal/payments/validate.go", goPayments))
+	rule.ResetState()
+	assert.Empty(t, rule.AnalyzeFile(createTestContext(t, "web/src/payments.ts", tsPayments)))
+}
+
+func TestFunctionSkeletonIsLanguageNeutral(t *testing.T) {
+	goFn, ok := goFunction(topLevelStatements(goSourceTokens([]byte(
+		"func f(items []Item) error {\n\tfor _, item := range items {\n\t\tif item.Price == nil {\n\t\t\treturn errors.New(\"x\")\n\t\t}\n\t}\n\treturn nil\n}\n")), false)[0])
+	require.True(t, ok)
+	tsFn, ok := tsFunction(topLevelStatements(tsSourceTokens([]byte(
+		"const f = async (items: Item[]): Promise<void> => {\n  for (const item of items) {\n    if (item!.price === undefined) {\n      throw new Error(`x`);\n    }\n  }\n};\n")), true)[0])
+	require.True(t, ok)
+
+	want := []string{"for", "{", "if", "$op", "==", "$nil", "{", "return", "$op", "}", "}"}
+	assert.Equal(t, want, functionSkeleton(goFn.body, goFn.expression))
+	assert.Equal(t, want, functionSkeleton(tsFn.body, tsFn.expression))
+}
+
+func TestSourceTokenLiteralValues(t *testing.T) {
+	for lit, want := range map[string]string{"1_000_000": "1000000", "0xFF": "255", "2.50": "2.5", "10n": "10"} {
+		assert.Equal(t, want, canonicalNumber(lit), lit)
+	}
+	assert.Equal(t, "it's", unquoteTS(`'it\'s'`))
+	assert.Equal(t, "a\tb", unquoteTS(`"a\tb"`))
+	assert.Equal(t, "pending", unquoteTS("`pending`"))
+}
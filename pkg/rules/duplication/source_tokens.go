@@ -0,0 +1,161 @@
+package duplication
+
+import (
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Source tokens are the raw token streams cross-language comparison starts
+// from: Go and TypeScript mapped onto one small vocabulary, with literal
+// values decoded so that "pending", 'pending' and `pending`, or 1_000_000 and
+// 1000000, compare equal.
+
+type sourceTokenKind int
+
+const (
+	identToken sourceTokenKind = iota
+	keywordToken
+	literalToken
+	punctToken
+)
+
+// sourceToken is one token: an identifier, keyword or punctuator as written,
+// a literal by its decoded value. nil, null and undefined are the keyword
+// "nil"; a statement end, explicit or inserted, is the punctuator ";".
+type sourceToken struct {
+	kind sourceTokenKind
+	text string
+	line int
+	// newline reports a line break before the token, which ends a
+	// TypeScript statement at the top level.
+	newline bool
+}
+
+// sourceLanguage names the language of a file as findings mention it, ""
+// for a file cross-language comparison does not read.
+func sourceLanguage(ctx *core.FileContext) string {
+	switch {
+	case ctx.IsGoFile():
+		return "Go"
+	case ctx.IsTypeScriptFile():
+		return "TypeScript"
+	case ctx.IsJavaScriptFile():
+		return "JavaScript"
+	}
+	return ""
+}
+
+func sourceTokens(ctx *core.FileContext) []sourceToken {
+	if ctx.IsGoFile() {
+		return goSourceTokens(ctx.Content)
+	}
+	return tsSourceTokens(ctx.Content)
+}
+
+func goSourceTokens(src []byte) []sourceToken {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+
+	var tokens []sourceToken
+	lastLine := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return tokens
+		}
+		line := file.Line(pos)
+		st := sourceToken{text: tok.String(), line: line, newline: line > lastLine && lastLine > 0}
+		lastLine = line
+		switch {
+		case tok == token.IDENT && (lit == "true" || lit == "false"):
+			st.kind, st.text = literalToken, lit
+		case tok == token.IDENT && lit == "nil":
+			st.kind, st.text = keywordToken, "nil"
+		case tok == token.IDENT:
+			st.kind, st.text = identToken, lit
+		case tok == token.STRING || tok == token.CHAR:
+			st.kind, st.text = literalToken, unquoteGo(lit)
+		case tok.IsLiteral():
+			st.kind, st.text = literalToken, canonicalNumber(lit)
+		case tok.IsKeyword():
+			st.kind = keywordToken
+		default:
+			st.kind = punctToken
+		}
+		tokens = append(tokens, st)
+	}
+}
+
+func tsSourceTokens(src []byte) []sourceToken {
+	var tokens []sourceToken
+	for _, tok := range core.LexTypeScript(src) {
+		st := sourceToken{text: tok.Text, line: tok.Line, newline: tok.NewlineBefore}
+		switch tok.Kind {
+		case core.TSComment:
+			continue
+		case core.TSIdent:
+			switch {
+			case tok.Text == "true" || tok.Text == "false":
+				st.kind = literalToken
+			case tok.Text == "null" || tok.Text == "undefined":
+				st.kind, st.text = keywordToken, "nil"
+			case core.TSKeywords[tok.Text]:
+				st.kind = keywordToken
+			default:
+				st.kind = identToken
+			}
+		case core.TSString, core.TSTemplate:
+			st.kind, st.text = literalToken, unquoteTS(tok.Text)
+		case core.TSNumber:
+			st.kind, st.text = literalToken, canonicalNumber(tok.Text)
+		case core.TSRegExp:
+			st.kind = literalToken
+		default:
+			st.kind = punctToken
+		}
+		tokens = append(tokens, st)
+	}
+	return tokens
+}
+
+func unquoteGo(lit string) string {
+	if value, err := strconv.Unquote(lit); err == nil {
+		return value
+	}
+	return lit
+}
+
+// unquoteTS strips the quotes of a string or template literal and decodes
+// the escapes Go shares with it.
+func unquoteTS(lit string) string {
+	if len(lit) < 2 {
+		return lit
+	}
+	inner := lit[1 : len(lit)-1]
+	if !strings.ContainsRune(inner, '\\') {
+		return inner
+	}
+	if value, err := strconv.Unquote(`"` + strings.ReplaceAll(inner, `\'`, `'`) + `"`); err == nil {
+		return value
+	}
+	return inner
+}
+
+// canonicalNumber writes a number the same way whatever its notation:
+// separators, a BigInt suffix, hex or a trailing ".0" make no difference.
+func canonicalNumber(lit string) string {
+	clean := strings.TrimSuffix(strings.ReplaceAll(lit, "_", ""), "n")
+	if value, err := strconv.ParseInt(clean, 0, 64); err == nil {
+		return strconv.FormatInt(value, 10)
+	}
+	if value, err := strconv.ParseFloat(clean, 64); err == nil {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return lit
+}
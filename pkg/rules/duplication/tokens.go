@@ -0,0 +1,116 @@
+package duplication
+
+import (
+	"go/scanner"
+	"go/token"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Token streams for the clone detector. Identifiers and literals are replaced
+// by placeholders, so two fragments that differ only in names and constants —
+// a handler copied and renamed — produce the same stream (a Type-2 clone).
+// Keywords, operators and punctuation are kept: they are the structure.
+
+const (
+	identPlaceholder   = "$id"
+	literalPlaceholder = "$lit"
+)
+
+// cloneToken is one normalized token and the line it starts on.
+type cloneToken struct {
+	text string
+	line int
+}
+
+// cloneTokens returns the normalized token streams of a file, one per
+// top-level declaration, or nil for a language the detector does not
+// tokenize. A clone never spans two declarations: what files of one kind
+// share from one declaration into the next — the init, the type and the
+// constructor of a rule — is the shape of the file, not a copy.
+func cloneTokens(ctx *core.FileContext) [][]cloneToken {
+	if ctx.IsGoFile() {
+		return goCloneTokens(ctx.Content)
+	}
+	return nil
+}
+
+// goCloneTokens scans Go source. The package clause and the imports are left
+// out: every file has them and they say nothing about duplicated logic.
+// Semicolons the scanner inserts at line ends are dropped too, so that the
+// way a statement is wrapped does not change its stream; at the top level
+// they end a declaration.
+func goCloneTokens(src []byte) [][]cloneToken {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(src))
+	var s scanner.Scanner
+	// Malformed source still yields a stream; ILLEGAL tokens simply never match.
+	s.Init(file, src, nil, 0)
+
+	var declarations [][]cloneToken
+	var current []cloneToken
+	depth := 0
+	skipUntil := token.ILLEGAL // token closing a package clause or import
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			if len(current) > 0 {
+				declarations = append(declarations, current)
+			}
+			return declarations
+		}
+		if skipUntil != token.ILLEGAL {
+			if tok == skipUntil {
+				skipUntil = token.ILLEGAL
+			}
+			continue
+		}
+		switch tok {
+		case token.PACKAGE:
+			skipUntil = token.SEMICOLON
+			continue
+		case token.IMPORT:
+			skipUntil = importEnd(&s)
+			continue
+		case token.LPAREN, token.LBRACE, token.LBRACK:
+			depth++
+		case token.RPAREN, token.RBRACE, token.RBRACK:
+			depth--
+		case token.SEMICOLON:
+			if depth <= 0 {
+				if len(current) > 0 {
+					declarations = append(declarations, current)
+				}
+				current = nil
+				continue
+			}
+			if lit == "\n" {
+				continue
+			}
+		}
+		current = append(current, cloneToken{text: normalizeGoToken(tok, lit), line: file.Line(pos)})
+	}
+}
+
+// importEnd consumes the token after the import keyword and returns the token
+// that ends the declaration: ")" for a group, the semicolon otherwise.
+func importEnd(s *scanner.Scanner) token.Token {
+	_, tok, _ := s.Scan()
+	if tok == token.LPAREN {
+		return token.RPAREN
+	}
+	return token.SEMICOLON
+}
+
+func normalizeGoToken(tok token.Token, lit string) string {
+	switch {
+	case tok == token.IDENT:
+		return identPlaceholder
+	case tok.IsLiteral():
+		return literalPlaceholder
+	case lit != "":
+		return lit
+	default:
+		return tok.String()
+	}
+}
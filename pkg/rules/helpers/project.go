@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"errors"
+	"go/types"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// ForEachTypedFile calls visit for every analyzed Go file of the project that
+// is not a test, with the type information of its package. A package loaded
+// without type information fails the walk; rule names it in the error.
+func ForEachTypedFile(ctx *core.GoProjectContext, rule string, visit func(*core.FileContext, *types.Info)) error {
+	if ctx == nil {
+		return errors.New(rule + ": nil Go project context")
+	}
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.Package == nil || pkg.Package.TypesInfo == nil {
+			return errors.New(rule + ": package has no typed syntax")
+		}
+		for _, fileCtx := range pkg.Files {
+			if fileCtx.GoAST == nil || fileCtx.IsTestFile() {
+				continue
+			}
+			visit(fileCtx, pkg.Package.TypesInfo)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package patterns
+
+import (
+	"fmt"
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewChannelClosedByReceiverRule())
+}
+
+// ChannelClosedByReceiverRule detects a channel closed by a function that
+// only receives from it while another goroutine sends on it:
+//
+//	results := make(chan int)
+//	for _, u := range urls {
+//		go func() { results <- fetch(u) }()
+//	}
+//	first := <-results
+//	close(results)        // the other senders panic: send on closed channel
+//
+// Closing tells the receivers that no more values come; only the sender knows
+// that. A receiver that closes turns every send still in flight into a panic
+// that takes down the process.
+//
+// The channel is followed from its make into the closures and functions it
+// is handed to. Not flagged: functions that both send and receive, channels
+// whose only sends are in the closing function's own goroutine, closes after
+// the function waited for the senders — sync.WaitGroup or errgroup Wait, or a
+// receive from a done channel — and channels stored where they cannot be
+// followed:
+//
+//	for _, u := range urls {
+//		wg.Add(1)
+//		go func() { defer wg.Done(); results <- fetch(u) }()
+//	}
+//	wg.Wait()
+//	close(results) // every sender has finished
+
+type ChannelClosedByReceiverRule struct {
+	*rules.BaseRule
+}
+
+// NewChannelClosedByReceiverRule creates the rule
+func NewChannelClosedByReceiverRule() *ChannelClosedByReceiverRule {
+	return &ChannelClosedByReceiverRule{
+		BaseRule: rules.NewBaseRule(
+			"channel-closed-by-receiver",
+			"patterns",
+			"Detects channels closed by their receiver while another goroutine still sends, a send-on-closed-channel panic",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op because this rule requires shared package SSA.
+func (r *ChannelClosedByReceiverRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *ChannelClosedByReceiverRule) RequiresSSA() bool { return true }
+
+// AnalyzeGoProject compares, for every channel the project makes, the
+// functions that close it with the functions that send on it.
+func (r *ChannelClosedByReceiverRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	project, err := newSSAProject(ctx, "channel closed by receiver")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fn := range project.functions {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				if made, ok := instr.(*ssa.MakeChan); ok {
+					violations = append(violations, r.analyzeChannel(project, made)...)
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// analyzeChannel reports the closes of a channel by a function that receives
+// from it and never sends while a goroutine elsewhere does.
+func (r *ChannelClosedByReceiverRule) analyzeChannel(project *ssaProject, made *ssa.MakeChan) []*core.Violation {
+	ops, complete := chanOps(made)
+	if !complete {
+		return nil
+	}
+	sends := make(map[*ssa.Function]bool)
+	receives := make(map[*ssa.Function]bool)
+	var closes []chanOp
+	for _, op := range ops {
+		switch op.kind {
+		case chanSend:
+			sends[op.instr.Parent()] = true
+		case chanRecv:
+			receives[op.instr.Parent()] = true
+		case chanClose:
+			closes = append(closes, op)
+		}
+	}
+
+	var violations []*core.Violation
+	for _, closing := range closes {
+		fn := closing.instr.Parent()
+		if sends[fn] || !receives[fn] || waitsBefore(ops, closing) {
+			continue
+		}
+		sender := concurrentSender(ops, closing)
+		if sender == nil {
+			continue
+		}
+		v := project.violation(r.CreateViolation, closing.instr.Pos(),
+			fmt.Sprintf("channel closed by its receiver while the goroutine sending at line %d may still send: a send on a closed channel panics",
+				project.line(sender.instr.Pos())))
+		if v == nil {
+			continue
+		}
+		v.WithSuggestion("Let the sender close the channel when it is done, or signal the senders to stop through a separate done channel or context")
+		v.WithContext("send_line", project.line(sender.instr.Pos()))
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// concurrentSender returns a send running in another goroutine than a close.
+func concurrentSender(ops []chanOp, closing chanOp) *chanOp {
+	for i, op := range ops {
+		if op.kind == chanSend && op.spawn != closing.spawn {
+			return &ops[i]
+		}
+	}
+	return nil
+}
+
+// senderWaits are the calls that return once the goroutines they track are
+// done.
+var senderWaits = map[string]bool{
+	"(*sync.WaitGroup).Wait":                   true,
+	"(*golang.org/x/sync/errgroup.Group).Wait": true,
+}
+
+// waitsBefore reports whether the closing function waits for its senders on
+// every path to the close: a WaitGroup or errgroup Wait, or a receive from
+// another channel, that dominates it.
+func waitsBefore(ops []chanOp, closing chanOp) bool {
+	onChannel := make(map[ssa.Instruction]bool, len(ops))
+	for _, op := range ops {
+		onChannel[op.instr] = true
+	}
+	for _, block := range closing.instr.Parent().Blocks {
+		for _, instr := range block.Instrs {
+			if !isSenderWait(instr, onChannel) || !instrDominates(instr, closing.instr) {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func isSenderWait(instr ssa.Instruction, onChannel map[ssa.Instruction]bool) bool {
+	switch instr := instr.(type) {
+	case *ssa.UnOp:
+		return instr.Op == token.ARROW && !onChannel[instr]
+	case *ssa.Call:
+		callee := instr.Call.StaticCallee()
+		return callee != nil && senderWaits[callee.String()]
+	}
+	return false
+}
+
+// instrDominates reports whether a runs before b on every path to b.
+func instrDominates(a, b ssa.Instruction) bool {
+	if a.Block() != b.Block() {
+		return a.Block().Dominates(b.Block())
+	}
+	for _, instr := range a.Block().Instrs {
+		switch instr {
+		case a:
+			return true
+		case b:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeReceiverCloses(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.ProjectWithSSA(t, map[string]string{"fetch/fetch.go": source})
+	violations, err := NewChannelClosedByReceiverRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestChannelClosedByReceiverRule_Metadata(t *testing.T) {
+	rule := NewChannelClosedByReceiverRule()
+	assert.Equal(t, "channel-closed-by-receiver", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+}
+
+func TestChannelClosedByReceiverRule_Detection(t *testing.T) {
+	violations := analyzeReceiverCloses(t, `package fetch
+
+func First(urls []string) int {
+	results := make(chan int)
+	for _, u := range urls {
+		go func() { results <- fetch(u) }()
+	}
+	defer close(results)
+	return <-results
+}
+
+func Consume(jobs chan int) {
+	for job := range jobs {
+		if job < 0 {
+			close(jobs)
+			return
+		}
+	}
+}
+
+func Pipeline() {
+	jobs := make(chan int)
+	go produce(jobs)
+	Consume(jobs)
+}
+
+func produce(out chan int) {
+	for i := 0; i < 10; i++ {
+		out <- i
+	}
+}
+
+func fetch(u string) int { return len(u) }
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, 8, violations[0].Line)
+	assert.Equal(t, 6, violations[0].Context["send_line"])
+	assert.Equal(t, 15, violations[1].Line)
+	assert.Contains(t, violations[1].Message, "line 29")
+}
+
+func TestChannelClosedByReceiverRule_AcceptsSenderClose(t *testing.T) {
+	violations := analyzeReceiverCloses(t, `package fetch
+
+func Generate(n int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 0; i < n; i++ {
+			out <- i
+		}
+	}()
+	return out
+}
+
+func Sum() int {
+	values := make(chan int)
+	go func() {
+		defer close(values)
+		for i := 0; i < 3; i++ {
+			values <- i
+		}
+	}()
+	total := 0
+	for v := range values {
+		total += v
+	}
+	return total
+}
+
+func Local() int {
+	ch := make(chan int, 1)
+	ch <- 1
+	v := <-ch
+	close(ch)
+	return v
+}
+`)
+
+	assert.Empty(t, violations)
+}
+
+// Fan-in: the close comes after the function waited for every sender.
+func TestChannelClosedByReceiverRule_AcceptsCloseAfterWait(t *testing.T) {
+	violations := analyzeReceiverCloses(t, `package fetch
+
+import "sync"
+
+func FanIn(urls []string) []int {
+	results := make(chan int, len(urls))
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- fetch(u)
+		}()
+	}
+	wg.Wait()
+	close(results)
+	var out []int
+	for v := range results {
+		out = append(out, v)
+	}
+	return out
+}
+
+func UntilDone(urls []string) int {
+	results := make(chan int, len(urls))
+	done := make(chan struct{})
+	go func() {
+		for _, u := range urls {
+			results <- fetch(u)
+		}
+		close(done)
+	}()
+	<-done
+	close(results)
+	total := 0
+	for v := range results {
+		total += v
+	}
+	return total
+}
+
+func fetch(u string) int { return len(u) }
+`)
+
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,147 @@
+package patterns
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewContextCancelNotCalledRule())
+}
+
+// cancelConstructors are the context functions that return a cancel function
+// the caller must call.
+var cancelConstructors = map[string]bool{
+	"context.WithCancel":        true,
+	"context.WithCancelCause":   true,
+	"context.WithTimeout":       true,
+	"context.WithTimeoutCause":  true,
+	"context.WithDeadline":      true,
+	"context.WithDeadlineCause": true,
+}
+
+// ContextCancelNotCalledRule detects the cancel function of
+// context.WithCancel, WithTimeout or WithDeadline left uncalled on some path:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	if err := validate(req); err != nil {
+//		return err                 // cancel never runs
+//	}
+//	defer cancel()
+//
+// Until cancel runs, the derived context stays registered with its parent and
+// a timeout keeps its timer; on a hot path that is a leak which only ends
+// when the parent context does. go vet's lostcancel catches a discarded
+// cancel; this rule also follows the paths from the call to every return.
+//
+// A cancel that is stored, returned, passed to another function or captured
+// by a closure is left to whoever receives it.
+type ContextCancelNotCalledRule struct {
+	*rules.BaseRule
+}
+
+// NewContextCancelNotCalledRule creates the rule
+func NewContextCancelNotCalledRule() *ContextCancelNotCalledRule {
+	return &ContextCancelNotCalledRule{
+		BaseRule: rules.NewBaseRule(
+			"context-cancel-not-called",
+			"patterns",
+			"Detects context.WithCancel/WithTimeout/WithDeadline whose cancel function is not called on every path",
+			core.SeverityMedium,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op because this rule requires shared package SSA.
+func (r *ContextCancelNotCalledRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *ContextCancelNotCalledRule) RequiresSSA() bool { return true }
+
+// AnalyzeGoProject searches, from every call returning a cancel function, for
+// a return no call of the cancel precedes.
+func (r *ContextCancelNotCalledRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	project, err := newSSAProject(ctx, "context cancel not called")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fn := range project.functions {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok || !cancelConstructors[calledFunc(&call.Call)] {
+					continue
+				}
+				if v := r.analyzeCall(project, call); v != nil {
+					violations = append(violations, v)
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// analyzeCall reports a cancel function that is discarded, or not called
+// before some return.
+func (r *ContextCancelNotCalledRule) analyzeCall(project *ssaProject, call *ssa.Call) *core.Violation {
+	name := strings.TrimPrefix(calledFunc(&call.Call), "context.")
+	cancel, discarded := cancelResult(call)
+	if discarded {
+		v := project.violation(r.CreateViolation, call.Pos(),
+			fmt.Sprintf("the cancel function of context.%s is discarded: the context is released only when its parent is", name))
+		if v != nil {
+			v.WithSuggestion("Keep the cancel function and defer it right after the call")
+		}
+		return v
+	}
+	if cancel == nil {
+		return nil
+	}
+	calls := make(map[ssa.Instruction]bool)
+	for _, instr := range referrersOf(cancel) {
+		use, ok := instr.(ssa.CallInstruction)
+		if !ok || use.Common().Value != cancel {
+			// Handed on: whoever receives it is responsible.
+			return nil
+		}
+		calls[instr] = true
+	}
+	ret := returnAvoiding(call, func(instr ssa.Instruction) (bool, []*ssa.BasicBlock) {
+		return calls[instr], nil
+	})
+	if ret == nil {
+		return nil
+	}
+	line := project.line(ret.Pos())
+	v := project.violation(r.CreateViolation, call.Pos(),
+		fmt.Sprintf("the cancel function of context.%s is not called when the function returns at line %d", name, line))
+	if v == nil {
+		return nil
+	}
+	v.WithSuggestion("Defer cancel() right after the call, before any return")
+	v.WithContext("return_line", line)
+	return v
+}
+
+// cancelResult returns the cancel function a call returns; discarded reports
+// a cancel assigned to the blank identifier or never used.
+func cancelResult(call *ssa.Call) (cancel ssa.Value, discarded bool) {
+	referrers := call.Referrers()
+	if referrers == nil {
+		return nil, false
+	}
+	for _, instr := range *referrers {
+		if extract, ok := instr.(*ssa.Extract); ok && extract.Index == 1 {
+			return extract, len(referrersOf(extract)) == 0
+		}
+	}
+	return nil, true
+}
@@ -0,0 +1,104 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeCancels(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.ProjectWithSSA(t, map[string]string{"api/api.go": source})
+	violations, err := NewContextCancelNotCalledRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestContextCancelNotCalledRule_Metadata(t *testing.T) {
+	rule := NewContextCancelNotCalledRule()
+	assert.Equal(t, "context-cancel-not-called", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityMedium, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+}
+
+func TestContextCancelNotCalledRule_Detection(t *testing.T) {
+	violations := analyzeCancels(t, `package api
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func Handle(ctx context.Context, valid bool) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	if !valid {
+		return errors.New("invalid")
+	}
+	defer cancel()
+	return call(ctx)
+}
+
+func Detached(ctx context.Context) error {
+	ctx, _ = context.WithCancel(ctx)
+	return call(ctx)
+}
+
+func call(ctx context.Context) error { return ctx.Err() }
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, 10, violations[0].Line)
+	assert.Equal(t, 12, violations[0].Context["return_line"])
+	assert.Contains(t, violations[0].Message, "context.WithTimeout")
+	assert.Equal(t, 19, violations[1].Line)
+	assert.Contains(t, violations[1].Message, "discarded")
+}
+
+func TestContextCancelNotCalledRule_AcceptsCancelledContexts(t *testing.T) {
+	violations := analyzeCancels(t, `package api
+
+import (
+	"context"
+	"time"
+)
+
+func Deferred(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	return call(ctx)
+}
+
+func EveryPath(ctx context.Context, fast bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	if fast {
+		cancel()
+		return nil
+	}
+	err := call(ctx)
+	cancel()
+	return err
+}
+
+func Returned(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(ctx, time.Now().Add(time.Second))
+}
+
+func Handed(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+		_ = call(ctx)
+	}()
+}
+
+func call(ctx context.Context) error { return ctx.Err() }
+`)
+
+	assert.Empty(t, violations)
+}
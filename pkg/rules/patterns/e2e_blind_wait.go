@@ -157,9 +157,13 @@ func (r *E2EBlindWaitRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation
 					"Wait for what the step produces: a locator (waitFor/toBeVisible), the URL (waitForURL), or a value via expect with a timeout"))
 			}
 			if r.blindTimeout.MatchString(line) {
-				violations = append(violations, r.report(ctx, i+1,
+				v := r.report(ctx, i+1,
 					"Browser test pauses for a fixed time — the wait passes or fails by machine speed, not by behaviour",
-					"Replace the pause with a wait for the expected state: locator.waitFor, page.waitForURL, or expect(...).toX({ timeout })"))
+					"Replace the pause with a wait for the expected state: locator.waitFor, page.waitForURL, or expect(...).toX({ timeout })")
+				// Only the fixed pause has a mechanical replacement; the fixer
+				// must not touch the networkidle findings on the same line.
+				v.WithContext("wait", "timeout")
+				violations = append(violations, v)
 			}
 			if r.tryStart.MatchString(trimmed) {
 				tryDepth = depth + 1
@@ -0,0 +1,93 @@
+package patterns
+
+import (
+	"errors"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// typedFunction is a function body of a typed package with the type
+// information that resolves its identifiers. Function literals come as
+// functions of their own, as well as inside the body that encloses them.
+type typedFunction struct {
+	file *core.FileContext
+	info *types.Info
+	body *ast.BlockStmt
+}
+
+// typedFunctions returns the bodies of the functions and function literals of
+// the project's non-test files.
+func typedFunctions(ctx *core.GoProjectContext, rule string) ([]typedFunction, error) {
+	if ctx == nil {
+		return nil, errors.New(rule + ": nil Go project context")
+	}
+	var functions []typedFunction
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.Package == nil || pkg.Package.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Package.Syntax {
+			fileCtx, err := ctx.FileForPosition(file.Pos())
+			if err != nil || fileCtx == nil || fileCtx.IsTestFile() {
+				continue
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				var body *ast.BlockStmt
+				switch fn := n.(type) {
+				case *ast.FuncDecl:
+					body = fn.Body
+				case *ast.FuncLit:
+					body = fn.Body
+				}
+				if body != nil {
+					functions = append(functions, typedFunction{file: fileCtx, info: pkg.Package.TypesInfo, body: body})
+				}
+				return true
+			})
+		}
+	}
+	return functions, nil
+}
+
+// errorVar returns the local error variable an expression names, or nil.
+func (f typedFunction) errorVar(expr ast.Expr) *types.Var {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil
+	}
+	v, ok := f.info.ObjectOf(ident).(*types.Var)
+	if !ok || v.IsField() || v.Parent() == nil || v.Parent() == v.Pkg().Scope() || !isErrorType(v.Type()) {
+		return nil
+	}
+	return v
+}
+
+// uses reports whether node refers to the variable.
+func (f typedFunction) uses(node ast.Node, v *types.Var) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && f.info.ObjectOf(ident) == v {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// callee returns the full name of the function or method a call calls —
+// "os.Create", "(*os.File).Close" — or "" for dynamic calls.
+func (f typedFunction) callee(call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(f.info, call).(*types.Func)
+	if !ok {
+		return ""
+	}
+	return fn.FullName()
+}
+
+func (f typedFunction) line(node ast.Node) int {
+	return f.file.PositionFor(node).Line
+}
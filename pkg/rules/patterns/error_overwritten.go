@@ -0,0 +1,303 @@
+package patterns
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewErrorOverwrittenRule())
+}
+
+// ErrorOverwrittenRule detects an error assigned to a variable and assigned
+// again before anything reads it:
+//
+//	user, err := repo.Find(ctx, id)
+//	perms, err := repo.Permissions(ctx, id) // the Find error is lost
+//	if err != nil {
+//		return err
+//	}
+//
+// The first failure is never seen: the code goes on with a zero user as if
+// Find had succeeded. The function body is followed path by path with the
+// shared flow walker, and an assignment is reported only when it is unread on
+// every path to the overwrite. Assigning nil is a reset, not an overwrite.
+//
+// Variables declared outside the body (parameters, named results), captured by
+// a closure or whose address is taken are left alone: something else may read
+// them between the two assignments.
+type ErrorOverwrittenRule struct {
+	*rules.BaseRule
+}
+
+// NewErrorOverwrittenRule creates the rule
+func NewErrorOverwrittenRule() *ErrorOverwrittenRule {
+	return &ErrorOverwrittenRule{
+		BaseRule: rules.NewBaseRule(
+			"error-overwritten",
+			"patterns",
+			"Detects an error assigned to a variable and overwritten by a later assignment before it is read",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// RequiresSSA reports that typed packages are enough — no SSA program needed.
+func (r *ErrorOverwrittenRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile is unused: the rule works on the typed project.
+func (r *ErrorOverwrittenRule) AnalyzeFile(_ *core.FileContext) []*core.Violation { return nil }
+
+// AnalyzeGoProject walks every function body for error assignments that are
+// overwritten unread.
+func (r *ErrorOverwrittenRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	functions, err := typedFunctions(ctx, "error overwritten")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fn := range functions {
+		analyzer := &errorOverwriteAnalyzer{
+			rule:     r,
+			fn:       fn,
+			excluded: escapingVars(fn),
+			reported: make(map[ast.Node]bool),
+		}
+		walker := &flowWalker[errorWrites, struct{}]{rule: analyzer}
+		walker.walk(fn.body, errorWrites{}, struct{}{})
+		violations = append(violations, analyzer.violations...)
+	}
+	return violations, nil
+}
+
+// errorWrites maps each error variable to its assignment no path has read
+// yet. nil is the dead state.
+type errorWrites map[*types.Var]ast.Node
+
+type errorOverwriteAnalyzer struct {
+	rule       *ErrorOverwrittenRule
+	fn         typedFunction
+	excluded   map[*types.Var]bool
+	reported   map[ast.Node]bool
+	violations []*core.Violation
+}
+
+// escapingVars returns the variables a function literal in the body captures
+// and those whose address is taken.
+func escapingVars(fn typedFunction) map[*types.Var]bool {
+	escaping := make(map[*types.Var]bool)
+	mark := func(node ast.Node) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok {
+				if v, ok := fn.info.ObjectOf(ident).(*types.Var); ok && (v.Pos() < node.Pos() || v.Pos() >= node.End()) {
+					escaping[v] = true
+				}
+			}
+			return true
+		})
+	}
+	ast.Inspect(fn.body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			mark(n)
+			return false
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				mark(n.X)
+			}
+		}
+		return true
+	})
+	return escaping
+}
+
+// tracked returns the error variable an assignment target names when the
+// rule follows it.
+func (a *errorOverwriteAnalyzer) tracked(expr ast.Expr) *types.Var {
+	v := a.fn.errorVar(expr)
+	if v == nil || a.excluded[v] || v.Pos() < a.fn.body.Pos() || v.Pos() >= a.fn.body.End() {
+		return nil
+	}
+	return v
+}
+
+// read clears the pending assignments of every variable node refers to.
+func (a *errorOverwriteAnalyzer) read(node ast.Node, state errorWrites) {
+	if node == nil || len(state) == 0 {
+		return
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if v, ok := a.fn.info.ObjectOf(ident).(*types.Var); ok {
+				delete(state, v)
+			}
+		}
+		return true
+	})
+}
+
+// write records an assignment to v, reporting the pending one it overwrites.
+func (a *errorOverwriteAnalyzer) write(v *types.Var, target ast.Node, state errorWrites) {
+	if previous, ok := state[v]; ok && !a.reported[previous] {
+		a.reported[previous] = true
+		a.report(v, previous, target)
+	}
+	state[v] = target
+}
+
+func (a *errorOverwriteAnalyzer) report(v *types.Var, previous, target ast.Node) {
+	pos := a.fn.file.PositionFor(previous)
+	overwritten := a.fn.line(target)
+	violation := a.rule.CreateViolation(a.fn.file.RelPath, pos.Line,
+		fmt.Sprintf("the error assigned to %s is overwritten at line %d before it is read", v.Name(), overwritten))
+	violation.WithCode(strings.TrimSpace(a.fn.file.GetLine(pos.Line)))
+	violation.WithSuggestion("Check the error before the next assignment, or use a separate variable for each call")
+	violation.WithContext("overwritten_line", overwritten)
+	a.violations = append(a.violations, violation)
+}
+
+func (a *errorOverwriteAnalyzer) assign(stmt *ast.AssignStmt, state errorWrites) {
+	for _, rhs := range stmt.Rhs {
+		a.read(rhs, state)
+	}
+	for _, lhs := range stmt.Lhs {
+		if _, ok := ast.Unparen(lhs).(*ast.Ident); !ok {
+			a.read(lhs, state)
+		}
+	}
+	for i, lhs := range stmt.Lhs {
+		v := a.tracked(lhs)
+		if v == nil {
+			continue
+		}
+		if len(stmt.Rhs) == len(stmt.Lhs) && isNilIdent(stmt.Rhs[i]) {
+			delete(state, v)
+			continue
+		}
+		a.write(v, lhs, state)
+	}
+}
+
+func (a *errorOverwriteAnalyzer) declare(stmt *ast.DeclStmt, state errorWrites) {
+	decl, ok := stmt.Decl.(*ast.GenDecl)
+	if !ok || decl.Tok != token.VAR {
+		return
+	}
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, value := range valueSpec.Values {
+			a.read(value, state)
+		}
+		for i, name := range valueSpec.Names {
+			v := a.tracked(name)
+			if v == nil {
+				continue
+			}
+			if len(valueSpec.Values) != len(valueSpec.Names) && len(valueSpec.Values) != 1 ||
+				len(valueSpec.Values) == len(valueSpec.Names) && isNilIdent(valueSpec.Values[i]) {
+				delete(state, v)
+				continue
+			}
+			a.write(v, name, state)
+		}
+	}
+}
+
+func (a *errorOverwriteAnalyzer) cloneState(state errorWrites) errorWrites { return maps.Clone(state) }
+
+// joinStates keeps the assignments still unread on both paths.
+func (a *errorOverwriteAnalyzer) joinStates(left, right errorWrites) errorWrites {
+	joined := errorWrites{}
+	for v, node := range left {
+		if right[v] == node {
+			joined[v] = node
+		}
+	}
+	return joined
+}
+
+func (a *errorOverwriteAnalyzer) liveState(state errorWrites) bool { return state != nil }
+
+func (a *errorOverwriteAnalyzer) deadState() errorWrites { return nil }
+
+func (a *errorOverwriteAnalyzer) enterScope(
+	_ flowScopeKind,
+	_ ast.Node,
+	parent struct{},
+	state errorWrites,
+) (struct{}, errorWrites) {
+	return parent, state
+}
+
+func (a *errorOverwriteAnalyzer) leaveScope(flowScopeKind, struct{}, *flowEdges[errorWrites]) {}
+
+func (a *errorOverwriteAnalyzer) simpleStmt(stmt ast.Stmt, state errorWrites, _ struct{}) (errorWrites, bool) {
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		a.assign(stmt, state)
+	case *ast.DeclStmt:
+		a.declare(stmt, state)
+	case *ast.ReturnStmt:
+		return nil, true
+	default:
+		a.read(stmt, state)
+		if isPanicStatement(stmt) {
+			return nil, true
+		}
+	}
+	return state, false
+}
+
+func (a *errorOverwriteAnalyzer) ifCondition(stmt *ast.IfStmt, state errorWrites, _ struct{}) (errorWrites, errorWrites) {
+	a.read(stmt.Cond, state)
+	return state, maps.Clone(state)
+}
+
+func (a *errorOverwriteAnalyzer) flowExpr(expr ast.Expr, state errorWrites, _ struct{}) errorWrites {
+	a.read(expr, state)
+	return state
+}
+
+// rangeVars forgets error variables the range statement assigns: each
+// iteration hands them a new value the body is expected to look at.
+func (a *errorOverwriteAnalyzer) rangeVars(stmt *ast.RangeStmt, state errorWrites, _ struct{}) errorWrites {
+	for _, expr := range []ast.Expr{stmt.Key, stmt.Value} {
+		if v := a.tracked(expr); v != nil {
+			delete(state, v)
+		}
+	}
+	return state
+}
+
+func (a *errorOverwriteAnalyzer) typeSwitchGuard(stmt ast.Stmt, state errorWrites, _ struct{}) errorWrites {
+	a.read(stmt, state)
+	return state
+}
+
+func (a *errorOverwriteAnalyzer) caseClause(
+	_ ast.Stmt,
+	clause *ast.CaseClause,
+	state errorWrites,
+	parent struct{},
+) (errorWrites, struct{}) {
+	for _, expr := range clause.List {
+		a.read(expr, state)
+	}
+	return state, parent
+}
+
+func (a *errorOverwriteAnalyzer) commClause(_ *ast.CommClause, state errorWrites, parent struct{}) (errorWrites, struct{}) {
+	return state, parent
+}
+
+func (a *errorOverwriteAnalyzer) normalize(*flowEdges[errorWrites]) {}
@@ -0,0 +1,119 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeErrorOverwrites(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.Project(t, map[string]string{"api/api.go": source})
+	violations, err := NewErrorOverwrittenRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestErrorOverwrittenRule_Metadata(t *testing.T) {
+	rule := NewErrorOverwrittenRule()
+	assert.Equal(t, "error-overwritten", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.False(t, rule.RequiresSSA())
+}
+
+func TestErrorOverwrittenRule_Detection(t *testing.T) {
+	violations := analyzeErrorOverwrites(t, `package api
+
+func find(id int) (string, error)   { return "", nil }
+func perms(id int) ([]string, error) { return nil, nil }
+func save(name string) error         { return nil }
+
+func Load(id int) (string, []string, error) {
+	name, err := find(id)
+	list, err := perms(id)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, list, nil
+}
+
+func Branches(id int, fast bool) error {
+	_, err := find(id)
+	if fast {
+		err = save("fast")
+	} else {
+		err = save("slow")
+	}
+	return err
+}
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, 8, violations[0].Line)
+	assert.Equal(t, "the error assigned to err is overwritten at line 9 before it is read", violations[0].Message)
+	assert.Equal(t, 9, violations[0].Context["overwritten_line"])
+	assert.Equal(t, 17, violations[1].Line)
+	assert.Equal(t, 19, violations[1].Context["overwritten_line"])
+}
+
+func TestErrorOverwrittenRule_AcceptsReadErrors(t *testing.T) {
+	violations := analyzeErrorOverwrites(t, `package api
+
+import "log"
+
+func find(id int) (string, error) { return "", nil }
+func save(name string) error       { return nil }
+
+func Checked(id int) error {
+	name, err := find(id)
+	if err != nil {
+		return err
+	}
+	err = save(name)
+	return err
+}
+
+func OnePath(id int, verbose bool) error {
+	_, err := find(id)
+	if verbose {
+		log.Print(err)
+	}
+	err = save("x")
+	return err
+}
+
+func Reset(id int) error {
+	_, err := find(id)
+	err = nil
+	err = save("x")
+	return err
+}
+
+func Wrapped(id int) error {
+	_, err := find(id)
+	err = wrap(err)
+	return err
+}
+
+func Deferred(id int) {
+	_, err := find(id)
+	defer func() { log.Print(err) }()
+	err = save("x")
+}
+
+func Named(id int) (err error) {
+	_, err = find(id)
+	err = save("x")
+	return
+}
+
+func wrap(err error) error { return err }
+`)
+
+	assert.Empty(t, violations)
+}
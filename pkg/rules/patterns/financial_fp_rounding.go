@@ -26,8 +26,14 @@ func init() {
 //   - `Math.floor(value * 100 + 1e-9) / 100` — explicit epsilon
 //   - `value.toFixed(2)` when half-up rounding is acceptable
 //   - In Go: use `decimal.Decimal` arithmetic, never float64
+//
+// With the money_helper setting (and money_helper_import, the module it is
+// imported from) `glint fix` routes JS/TS findings through that helper,
+// called as helper(value, 'floor' | 'ceil' | 'trunc').
 type FinancialFPRoundingRule struct {
 	*rules.BaseRule
+	moneyHelper       string
+	moneyHelperImport string
 	// JS/TS: Math.floor|ceil|trunc(<expr> * 100) / 100 — without epsilon.
 	jsFloorBy100 *regexp.Regexp
 	// JS/TS: Math.floor|ceil|trunc(<expr> * <pct>) / 100 — pct is var/literal
@@ -57,6 +63,16 @@ func NewFinancialFPRoundingRule() *FinancialFPRoundingRule {
 	}
 }
 
+// Configure reads the optional money_helper and money_helper_import settings.
+func (r *FinancialFPRoundingRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	r.moneyHelper = r.GetStringSetting("money_helper", "")
+	r.moneyHelperImport = r.GetStringSetting("money_helper_import", "")
+	return nil
+}
+
 // AnalyzeFile checks for floating-point rounding of money
 func (r *FinancialFPRoundingRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
 	if r.shouldSkip(ctx) {
@@ -82,8 +98,8 @@ func (r *FinancialFPRoundingRule) AnalyzeFile(ctx *core.FileContext) []*core.Vio
 		if isTSJS {
 			if m := r.jsFloorBy100.FindStringSubmatch(line); m != nil {
 				if r.moneyContext.MatchString(m[1]) {
-					violations = append(violations, r.violation(ctx, i+1, line,
-						"Math.floor(money * 100)/100 — IEEE-754 shimmer drops cents; use Math.round(v*100)/100 or v.toFixed(2)"))
+					violations = append(violations, r.withHelper(r.violation(ctx, i+1, line,
+						"Math.floor(money * 100)/100 — IEEE-754 shimmer drops cents; use Math.round(v*100)/100 or v.toFixed(2)")))
 					continue
 				}
 			}
@@ -93,8 +109,8 @@ func (r *FinancialFPRoundingRule) AnalyzeFile(ctx *core.FileContext) []*core.Vio
 					continue
 				}
 				if r.moneyContext.MatchString(m[1]) {
-					violations = append(violations, r.violation(ctx, i+1, line,
-						"Math.floor(money * pct)/100 — FP shimmer can drop a cent; add epsilon or use toFixed"))
+					violations = append(violations, r.withHelper(r.violation(ctx, i+1, line,
+						"Math.floor(money * pct)/100 — FP shimmer can drop a cent; add epsilon or use toFixed")))
 				}
 			}
 		}
@@ -133,3 +149,13 @@ func (r *FinancialFPRoundingRule) violation(ctx *core.FileContext, lineNum int,
 	v.WithContext("pattern", "financial-fp-rounding")
 	return v
 }
+
+// withHelper records the configured money helper, which the fixer routes the
+// rounding through; without one there is nothing to fix with.
+func (r *FinancialFPRoundingRule) withHelper(v *core.Violation) *core.Violation {
+	if r.moneyHelper != "" {
+		v.WithContext("money_helper", r.moneyHelper)
+		v.WithContext("money_helper_import", r.moneyHelperImport)
+	}
+	return v
+}
@@ -90,3 +90,24 @@ func TestFinancialFPRoundingRule(t *testing.T) {
 		})
 	}
 }
+
+// The fixer routes findings through the helper the rule was configured with;
+// the Go findings have no JS helper to go through.
+func TestFinancialFPRoundingRecordsConfiguredHelper(t *testing.T) {
+	rule := NewFinancialFPRoundingRule()
+	if err := rule.Configure(map[string]any{"money_helper": "roundMoney", "money_helper_import": "@/lib/money"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := rule.AnalyzeFile(core.NewFileContext("frontend/src/Withdrawal.tsx", ".",
+		[]byte(`const v = Math.floor(balance * 100) / 100`), nil))
+	if len(ts) != 1 || ts[0].Context["money_helper"] != "roundMoney" || ts[0].Context["money_helper_import"] != "@/lib/money" {
+		t.Fatalf("the TS finding must carry the configured helper, got %+v", ts)
+	}
+
+	goFindings := rule.AnalyzeFile(core.NewFileContext("backend/balance.go", ".",
+		[]byte(`cents := math.Floor(balance * 100) / 100`), nil))
+	if len(goFindings) != 1 || goFindings[0].Context["money_helper"] != nil {
+		t.Fatalf("the Go finding must not carry a JS helper, got %+v", goFindings)
+	}
+}
@@ -387,10 +387,11 @@ func (w *flowWalker[S, C]) selectStmt(stmt *ast.SelectStmt, state S, parent C) f
 // Compile-time assertions; they also tell the unused linter that the flowRule
 // methods are reached through the generic walker.
 var (
-	_ flowRule[bool, struct{}]                                  = (*httpBodyReachRule)(nil)
 	_ flowRule[[]idempotencyPath, *idempotencyScope]            = (*idempotencyFunctionAnalyzer)(nil)
 	_ flowRule[[]checkpointFlow, *checkpointLexicalScope]       = (*checkpointFlowAnalyzer)(nil)
 	_ flowRule[[]statusHistoryPath, *statusHistoryLexicalScope] = (*statusHistoryFlowAnalyzer)(nil)
 	_ flowRule[[]providerFlowState, struct{}]                   = (*providerFlowAnalyzer)(nil)
 	_ flowRule[*responseState, struct{}]                        = (*unboundedResponseAnalyzer)(nil)
+	_ flowRule[errorWrites, struct{}]                           = (*errorOverwriteAnalyzer)(nil)
+	_ flowRule[resourceState, struct{}]                         = (*resourceLifecycle)(nil)
 )
@@ -0,0 +1,22 @@
+package patterns
+
+import "github.com/aiseeq/glint/pkg/core"
+
+// atTSNode places a violation on a node of a TypeScript syntax tree: its
+// column, and its end line when the node spans several lines.
+func atTSNode(v *core.Violation, node *core.TSNode) *core.Violation {
+	v.WithColumn(node.Start.Column)
+	if node.End.Line > node.Start.Line {
+		v.WithEndLine(node.End.Line)
+	}
+	return v
+}
+
+// tsCallee returns the dotted path a call's callee spells, "" for computed
+// callees.
+func tsCallee(call *core.TSNode) string {
+	if call.Kind != core.TSCall && call.Kind != core.TSNew {
+		return ""
+	}
+	return call.Child(0).Path()
+}
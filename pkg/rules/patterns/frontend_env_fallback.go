@@ -1,7 +1,7 @@
 package patterns
 
 import (
-	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/aiseeq/glint/pkg/core"
@@ -16,8 +16,6 @@ func init() {
 // degrade at build/runtime instead of failing explicitly.
 type FrontendEnvFallbackRule struct {
 	*rules.BaseRule
-	bracketNextPublicEnv *regexp.Regexp
-	requiredSupabaseEnv  *regexp.Regexp
 }
 
 // NewFrontendEnvFallbackRule creates the rule
@@ -29,11 +27,22 @@ func NewFrontendEnvFallbackRule() *FrontendEnvFallbackRule {
 			"Detects placeholder and fallback public environment configuration in frontend code",
 			core.SeverityCritical,
 		),
-		bracketNextPublicEnv: regexp.MustCompile(`process\.env\[['"]NEXT_PUBLIC_[A-Z0-9_]+['"]\]`),
-		requiredSupabaseEnv:  regexp.MustCompile(`process\.env(?:\[['"]NEXT_PUBLIC_SUPABASE_(?:URL|ANON_KEY)['"]\]|\.NEXT_PUBLIC_SUPABASE_(?:URL|ANON_KEY))\s*(?:\|\||\?\?)`),
 	}
 }
 
+// envFinding kinds, in the order they win when several share a line.
+const (
+	envPlaceholder = iota
+	envBracketAccess
+	envFallback
+)
+
+type envFinding struct {
+	kind   int
+	node   *core.TSNode
+	envVar string
+}
+
 // AnalyzeFile checks for placeholder and fallback public environment configuration
 func (r *FrontendEnvFallbackRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
 	if !ctx.IsTypeScriptFile() && !ctx.IsJavaScriptFile() {
@@ -43,30 +52,71 @@ func (r *FrontendEnvFallbackRule) AnalyzeFile(ctx *core.FileContext) []*core.Vio
 		return nil
 	}
 
-	var violations []*core.Violation
-	for i, line := range ctx.Lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
-			continue
+	// One finding per line: the fixer finds the fallback by line.
+	byLine := make(map[int]envFinding)
+	var lines []int
+	ctx.TypeScriptAST().Program.Walk(func(n *core.TSNode, _ []*core.TSNode) bool {
+		finding, ok := classifyEnvNode(n)
+		if !ok {
+			return true
+		}
+		line := n.Start.Line
+		if existing, seen := byLine[line]; !seen {
+			lines = append(lines, line)
+		} else if existing.kind <= finding.kind {
+			return true
 		}
+		byLine[line] = finding
+		return true
+	})
+	slices.Sort(lines)
+
+	var violations []*core.Violation
+	for _, line := range lines {
+		violations = append(violations, r.violation(ctx, byLine[line]))
+	}
+	return violations
+}
 
-		switch {
-		case strings.Contains(line, "placeholder.supabase.co") || strings.Contains(line, "placeholder-key"):
-			violations = append(violations, r.violation(ctx, i+1, line,
-				"Supabase placeholder configuration detected in frontend code",
-				"Remove placeholder credentials. Required public config must fail explicitly when missing."))
-		case r.bracketNextPublicEnv.MatchString(line):
-			violations = append(violations, r.violation(ctx, i+1, line,
-				"Bracket access for NEXT_PUBLIC env prevents reliable Next.js build-time inlining",
-				"Use dot access like process.env.NEXT_PUBLIC_SUPABASE_URL, then validate required values explicitly."))
-		case r.requiredSupabaseEnv.MatchString(line):
-			violations = append(violations, r.violation(ctx, i+1, line,
-				"Required Supabase public env uses a fallback operator",
-				"Remove ||/?? fallback and throw an explicit error when Supabase public config is missing."))
+// classifyEnvNode reports a placeholder literal, bracket access to a
+// NEXT_PUBLIC variable, or a fallback operator after a required Supabase
+// variable.
+func classifyEnvNode(n *core.TSNode) (envFinding, bool) {
+	switch n.Kind {
+	case core.TSLiteral, core.TSTemplateLiteral:
+		if strings.Contains(n.Text, "placeholder.supabase.co") || strings.Contains(n.Text, "placeholder-key") {
+			return envFinding{kind: envPlaceholder, node: n}, true
+		}
+	case core.TSIndex:
+		if strings.HasPrefix(bracketEnvName(n), "NEXT_PUBLIC_") {
+			return envFinding{kind: envBracketAccess, node: n}, true
+		}
+	case core.TSBinary:
+		if n.Text != "||" && n.Text != "??" {
+			break
+		}
+		left := n.Child(0).Unwrap()
+		name := bracketEnvName(left)
+		if object, property, ok := cutLast(left.Path()); ok && object == "process.env" {
+			name = property
+		}
+		if name == "NEXT_PUBLIC_SUPABASE_URL" || name == "NEXT_PUBLIC_SUPABASE_ANON_KEY" {
+			return envFinding{kind: envFallback, node: n, envVar: name}, true
 		}
 	}
+	return envFinding{}, false
+}
 
-	return violations
+// bracketEnvName returns NAME for process.env['NAME'], "" for anything else.
+func bracketEnvName(n *core.TSNode) string {
+	if n == nil || n.Kind != core.TSIndex || n.Child(0).Path() != "process.env" {
+		return ""
+	}
+	key := n.Child(1)
+	if key == nil || key.Kind != core.TSLiteral || len(key.Text) < 2 || (key.Text[0] != '\'' && key.Text[0] != '"') {
+		return ""
+	}
+	return key.Text[1 : len(key.Text)-1]
 }
 
 func (r *FrontendEnvFallbackRule) shouldSkip(ctx *core.FileContext) bool {
@@ -80,11 +130,30 @@ func (r *FrontendEnvFallbackRule) shouldSkip(ctx *core.FileContext) bool {
 		strings.Contains(path, "/dist/")
 }
 
-func (r *FrontendEnvFallbackRule) violation(ctx *core.FileContext, lineNum int, line string, msg string, suggestion string) *core.Violation {
-	v := r.CreateViolation(ctx.RelPath, lineNum, msg)
-	v.WithCode(strings.TrimSpace(line))
+func (r *FrontendEnvFallbackRule) violation(ctx *core.FileContext, finding envFinding) *core.Violation {
+	var msg, suggestion string
+	switch finding.kind {
+	case envPlaceholder:
+		msg = "Supabase placeholder configuration detected in frontend code"
+		suggestion = "Remove placeholder credentials. Required public config must fail explicitly when missing."
+	case envBracketAccess:
+		msg = "Bracket access for NEXT_PUBLIC env prevents reliable Next.js build-time inlining"
+		suggestion = "Use dot access like process.env.NEXT_PUBLIC_SUPABASE_URL, then validate required values explicitly."
+	default:
+		msg = "Required Supabase public env uses a fallback operator"
+		suggestion = "Remove ||/?? fallback and throw an explicit error when Supabase public config is missing."
+	}
+
+	line := finding.node.Start.Line
+	v := r.CreateViolation(ctx.RelPath, line, msg)
+	atTSNode(v, finding.node)
+	v.WithCode(strings.TrimSpace(ctx.GetLine(line)))
 	v.WithSuggestion(suggestion)
 	v.WithContext("pattern", "frontend-env-fallback")
 	v.WithContext("language", "typescript")
+	if finding.envVar != "" {
+		// The variable the fixer makes required.
+		v.WithContext("env_var", finding.envVar)
+	}
 	return v
 }
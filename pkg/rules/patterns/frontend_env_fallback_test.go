@@ -70,3 +70,41 @@ if (!supabaseUrl || !supabaseAnonKey) {
 		})
 	}
 }
+
+// The fixer makes the variable the finding names required.
+func TestFrontendEnvFallbackRecordsFallbackVariable(t *testing.T) {
+	rule := NewFrontendEnvFallbackRule()
+	code := `const url = process.env.NEXT_PUBLIC_SUPABASE_URL || 'http://localhost'`
+
+	violations := rule.AnalyzeFile(core.NewFileContext("frontend/src/lib/supabase.ts", ".", []byte(code), nil))
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	if got := violations[0].Context["env_var"]; got != "NEXT_PUBLIC_SUPABASE_URL" {
+		t.Errorf("the finding must name the variable, got %v", got)
+	}
+}
+
+func TestFrontendEnvFallbackRuleReportsExpression(t *testing.T) {
+	code := `// process.env.NEXT_PUBLIC_SUPABASE_URL || 'placeholder-key' in a comment
+export const config = {
+  url: process.env.NEXT_PUBLIC_SUPABASE_URL ??
+    'https://placeholder.supabase.co',
+  key: (process.env.NEXT_PUBLIC_SUPABASE_ANON_KEY as string) || '',
+}`
+	violations := NewFrontendEnvFallbackRule().AnalyzeFile(core.NewFileContext("frontend/src/lib/supabase.ts", ".", []byte(code), nil))
+	if len(violations) != 3 {
+		t.Fatalf("got %d violations, want 3", len(violations))
+	}
+
+	fallback := violations[0]
+	if fallback.Line != 3 || fallback.Column != 8 || fallback.EndLine != 4 {
+		t.Errorf("fallback at %d:%d-%d, want 3:8-4", fallback.Line, fallback.Column, fallback.EndLine)
+	}
+	if placeholder := violations[1]; placeholder.Line != 4 || placeholder.Column != 5 || placeholder.Context["env_var"] != nil {
+		t.Errorf("placeholder at %d:%d with %v", placeholder.Line, placeholder.Column, placeholder.Context)
+	}
+	if got := violations[2].Context["env_var"]; got != "NEXT_PUBLIC_SUPABASE_ANON_KEY" {
+		t.Errorf("a parenthesized assertion must still name the variable, got %v", got)
+	}
+}
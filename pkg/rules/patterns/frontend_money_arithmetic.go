@@ -2,6 +2,7 @@ package patterns
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/aiseeq/glint/pkg/core"
@@ -29,11 +30,7 @@ const defaultMoneyFields = "amount|balance|fee|fees|price|profit|yield|deposit|w
 // non-money numerics, tests.
 type FrontendMoneyArithmeticRule struct {
 	*rules.BaseRule
-	parseCall    *regexp.Regexp
-	moneyField   *regexp.Regexp
-	reduceRawSum *regexp.Regexp
-	accumulate   *regexp.Regexp
-	reduceMarker string
+	moneyField *regexp.Regexp
 }
 
 // NewFrontendMoneyArithmeticRule creates the rule
@@ -45,9 +42,6 @@ func NewFrontendMoneyArithmeticRule() *FrontendMoneyArithmeticRule {
 			"Detects client-side arithmetic over money values (must come from backend)",
 			core.SeverityHigh,
 		),
-		parseCall:    regexp.MustCompile(`\b(?:parseFloat|Number)\s*\(`),
-		accumulate:   regexp.MustCompile(`\b([\w$]+)\s*[+\-]=\s*(.+)$`),
-		reduceMarker: ".reduce(",
 	}
 	r.setMoneyFields(defaultMoneyFields)
 	return r
@@ -63,11 +57,10 @@ func (r *FrontendMoneyArithmeticRule) Configure(settings map[string]any) error {
 }
 
 func (r *FrontendMoneyArithmeticRule) setMoneyFields(fields string) {
-	r.moneyField = regexp.MustCompile(`(?i)\b\w*(?:` + fields + `)\w*\b`)
-	r.reduceRawSum = regexp.MustCompile(`[+]\s*[\w$]+\.\w*(?i:` + fields + `)\w*\b`)
+	r.moneyField = regexp.MustCompile(`(?i)(?:` + fields + `)`)
 }
 
-// AnalyzeFile checks TS/JS lines for money arithmetic
+// AnalyzeFile checks TS/JS expressions for money arithmetic
 func (r *FrontendMoneyArithmeticRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
 	if !ctx.IsTypeScriptFile() && !ctx.IsJavaScriptFile() {
 		return nil
@@ -76,185 +69,138 @@ func (r *FrontendMoneyArithmeticRule) AnalyzeFile(ctx *core.FileContext) []*core
 		return nil
 	}
 
-	var violations []*core.Violation
-
-	for i, rawLine := range ctx.Lines {
-		line := stripTrailingComment(rawLine)
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "*") {
-			continue
+	// One finding per line: an accumulation of a parsed amount is both.
+	byLine := make(map[int]*core.TSNode)
+	var lines []int
+	ctx.TypeScriptAST().Program.Walk(func(n *core.TSNode, ancestors []*core.TSNode) bool {
+		found := r.arithmeticOverParsedMoney(n, ancestors)
+		if found == nil && (r.isRawMoneyReduce(n) || r.isMoneyAccumulation(n)) {
+			found = n
 		}
-		if r.isSameFieldComparator(line) {
-			continue
+		if found != nil {
+			if _, seen := byLine[found.Start.Line]; !seen {
+				byLine[found.Start.Line] = found
+				lines = append(lines, found.Start.Line)
+			}
 		}
+		return true
+	})
+	slices.Sort(lines)
 
-		if r.lineHasParsedMoneyArithmetic(line) ||
-			(strings.Contains(line, r.reduceMarker) && r.reduceRawSum.MatchString(line)) ||
-			r.isMoneyAccumulation(line) {
-			v := r.CreateViolation(ctx.RelPath, i+1,
-				"Client-side arithmetic over a money value — financial aggregates must be computed on the backend")
-			v.WithCode(trimmed)
-			v.WithSuggestion("Return the computed value from the backend API (canonical calculation) and only format it here")
-			violations = append(violations, v)
-		}
+	var violations []*core.Violation
+	for _, line := range lines {
+		v := r.CreateViolation(ctx.RelPath, line,
+			"Client-side arithmetic over a money value — financial aggregates must be computed on the backend")
+		atTSNode(v, byLine[line])
+		v.WithCode(strings.TrimSpace(ctx.GetLine(line)))
+		v.WithSuggestion("Return the computed value from the backend API (canonical calculation) and only format it here")
+		violations = append(violations, v)
 	}
-
 	return violations
 }
 
-// isMoneyAccumulation matches "moneyVar += <expr with money ident>" —
-// aggregation through intermediate variables (investedAmount += invAmount).
-func (r *FrontendMoneyArithmeticRule) isMoneyAccumulation(line string) bool {
-	m := r.accumulate.FindStringSubmatch(line)
-	if m == nil {
-		return false
+// arithmeticOverParsedMoney returns the arithmetic expression a
+// parseFloat/Number call over a money-named field is an operand of, or nil.
+// Formatting (formatAmount(parseFloat(x))), comparisons and a unary minus
+// are not arithmetic.
+func (r *FrontendMoneyArithmeticRule) arithmeticOverParsedMoney(call *core.TSNode, ancestors []*core.TSNode) *core.TSNode {
+	if r.parsedMoneyField(call) == "" {
+		return nil
 	}
-	return r.moneyField.MatchString(m[1]) && r.moneyField.MatchString(m[2])
-}
-
-// isSameFieldComparator reports whether the line subtracts the SAME money
-// field of two different receivers — the sort-comparator / trend-delta idiom
-// (presentational, not aggregation):
-//
-//	(parseFloat(String(a.amount)) - parseFloat(String(b.amount))) * dir
-func (r *FrontendMoneyArithmeticRule) isSameFieldComparator(line string) bool {
-	locs := r.parseCall.FindAllStringIndex(line, -1)
-	if len(locs) != 2 {
-		return false
+	operand := call
+	i := len(ancestors) - 1
+	for i >= 0 && ancestors[i].Kind == core.TSParen {
+		operand = ancestors[i]
+		i--
+	}
+	if i < 0 {
+		return nil
 	}
-	var fields []string
-	for _, loc := range locs {
-		end := matchingParenEnd(line, loc[1])
-		if end < 0 {
-			return false
+	switch parent := ancestors[i]; parent.Kind {
+	case core.TSBinary:
+		if isArithmeticOperator(parent.Text) && !r.isSameFieldComparator(parent) {
+			return parent
 		}
-		words := r.moneyField.FindAllString(line[loc[1]:end], -1)
-		if len(words) == 0 {
-			return false
+	case core.TSAssign:
+		if parent.Child(1) == operand && isArithmeticOperator(strings.TrimSuffix(parent.Text, "=")) {
+			return parent
 		}
-		fields = append(fields, strings.ToLower(words[len(words)-1]))
 	}
-	between := line[locs[0][1]:locs[1][0]]
-	return fields[0] == fields[1] && strings.Contains(between, "-")
+	return nil
 }
 
-// stripTrailingComment removes a trailing // or /* comment, ignoring comment
-// markers inside string literals.
-func stripTrailingComment(line string) string {
-	quote := byte(0)
-	for i := 0; i < len(line)-1; i++ {
-		c := line[i]
-		if quote != 0 {
-			switch c {
-			case '\\':
-				i++
-			case quote:
-				quote = 0
-			}
-			continue
-		}
-		switch c {
-		case '\'', '"', '`':
-			quote = c
-		case '/':
-			if line[i+1] == '/' || line[i+1] == '*' {
-				return line[:i]
-			}
-		}
-	}
-	return line
+func isArithmeticOperator(op string) bool {
+	return op == "+" || op == "-" || op == "*" || op == "/"
 }
 
-// lineHasParsedMoneyArithmetic reports whether the line applies an arithmetic
-// operator to parseFloat/Number over a money-named field.
-func (r *FrontendMoneyArithmeticRule) lineHasParsedMoneyArithmetic(line string) bool {
-	for _, loc := range r.parseCall.FindAllStringIndex(line, -1) {
-		argStart := loc[1] // position right after '('
-		argEnd := matchingParenEnd(line, argStart)
-		if argEnd < 0 {
-			continue
-		}
-		if !r.moneyField.MatchString(line[argStart:argEnd]) {
-			continue
-		}
-		if hasArithmeticBefore(line[:loc[0]]) || hasArithmeticAfter(line[argEnd+1:]) {
+// parsedMoneyField returns the last money-named field a parseFloat or Number
+// call reads, "" for any other expression.
+func (r *FrontendMoneyArithmeticRule) parsedMoneyField(n *core.TSNode) string {
+	n = n.Unwrap()
+	if n == nil || n.Kind != core.TSCall {
+		return ""
+	}
+	if callee := tsCallee(n); callee != "Number" && n.Child(0).Name() != "parseFloat" {
+		return ""
+	}
+	field := ""
+	for _, arg := range n.Children[1:] {
+		arg.Walk(func(node *core.TSNode, _ []*core.TSNode) bool {
+			if node.Kind == core.TSIdentifier && r.moneyField.MatchString(node.Text) {
+				field = strings.ToLower(node.Text)
+			}
 			return true
-		}
+		})
 	}
-	return false
+	return field
 }
 
-// matchingParenEnd returns the index of the ')' closing the call whose
-// argument starts at start, or -1. Nested parens and quoted strings are
-// handled.
-func matchingParenEnd(line string, start int) int {
-	depth := 1
-	quote := byte(0)
-	for i := start; i < len(line); i++ {
-		c := line[i]
-		if quote != 0 {
-			switch c {
-			case '\\':
-				i++
-			case quote:
-				quote = 0
-			}
-			continue
-		}
-		switch c {
-		case '\'', '"', '`':
-			quote = c
-		case '(':
-			depth++
-		case ')':
-			depth--
-			if depth == 0 {
-				return i
-			}
-		}
+// isSameFieldComparator reports whether a subtraction takes the SAME money
+// field of two different receivers — the sort-comparator / trend-delta idiom
+// (presentational, not aggregation):
+//
+//	(parseFloat(String(a.amount)) - parseFloat(String(b.amount))) * dir
+func (r *FrontendMoneyArithmeticRule) isSameFieldComparator(binary *core.TSNode) bool {
+	if binary.Text != "-" {
+		return false
 	}
-	return -1
+	left := r.parsedMoneyField(binary.Child(0))
+	return left != "" && left == r.parsedMoneyField(binary.Child(1))
 }
 
-// hasArithmeticBefore reports whether the text ends with an arithmetic
-// operator ("sum + ", "total += ") rather than a plain assignment or arrow.
-func hasArithmeticBefore(prefix string) bool {
-	trimmed := strings.TrimRight(prefix, " \t")
-	if trimmed == "" {
+// isRawMoneyReduce matches a reduce callback adding a money field without
+// parsing it: rows.reduce((acc, r) => acc + r.balance, 0).
+func (r *FrontendMoneyArithmeticRule) isRawMoneyReduce(call *core.TSNode) bool {
+	if call.Kind != core.TSCall {
 		return false
 	}
-	last := trimmed[len(trimmed)-1]
-	if strings.IndexByte("+-*/", last) >= 0 {
-		// "=>" arrow already excluded (ends with '>'); unary minus after '('
-		// or '=' ("= -parseFloat") is sign, not aggregation.
-		if last == '-' && len(trimmed) >= 2 {
-			beforeMinus := trimmed[len(trimmed)-2]
-			if strings.IndexByte("(=,", beforeMinus) >= 0 {
-				return false
-			}
-		}
-		return true
-	}
-	// Compound assignment: "+=", "-=", "*=", "/=".
-	if last == '=' && len(trimmed) >= 2 && strings.IndexByte("+-*/", trimmed[len(trimmed)-2]) >= 0 {
-		return true
+	if callee := call.Child(0); callee == nil || callee.Kind != core.TSMember || callee.Child(1).Text != "reduce" {
+		return false
 	}
-	return false
+	found := false
+	call.Child(1).Walk(func(n *core.TSNode, _ []*core.TSNode) bool {
+		if n.Kind == core.TSBinary && n.Text == "+" {
+			right := n.Child(1).Unwrap()
+			found = right != nil && right.Kind == core.TSMember && r.moneyField.MatchString(right.Child(1).Text)
+		}
+		return !found
+	})
+	return found
 }
 
-// hasArithmeticAfter reports whether the text starts with an arithmetic
-// operator, excluding comment starts ("//", "/*").
-func hasArithmeticAfter(suffix string) bool {
-	trimmed := strings.TrimLeft(suffix, " \t")
-	if trimmed == "" {
+// isMoneyAccumulation matches "moneyVar += <expr with money ident>" —
+// aggregation through intermediate variables (investedAmount += invAmount).
+func (r *FrontendMoneyArithmeticRule) isMoneyAccumulation(n *core.TSNode) bool {
+	if n.Kind != core.TSAssign || (n.Text != "+=" && n.Text != "-=") {
 		return false
 	}
-	first := trimmed[0]
-	if strings.IndexByte("+-*", first) >= 0 {
-		return true
-	}
-	if first == '/' && len(trimmed) >= 2 && trimmed[1] != '/' && trimmed[1] != '*' {
-		return true
+	if !r.moneyField.MatchString(n.Child(0).Name()) {
+		return false
 	}
-	return false
+	found := false
+	n.Child(1).Walk(func(node *core.TSNode, _ []*core.TSNode) bool {
+		found = found || (node.Kind == core.TSIdentifier && r.moneyField.MatchString(node.Text))
+		return !found
+	})
+	return found
 }
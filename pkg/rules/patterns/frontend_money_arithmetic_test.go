@@ -151,3 +151,24 @@ retries += attempt
 		})
 	}
 }
+
+func TestFrontendMoneyArithmeticRuleReportsExpression(t *testing.T) {
+	code := `const label = '// ' + formatAmount(parseFloat(a.amount))
+const total = items
+  .filter(Boolean)
+  .reduce((sum, item) => sum +
+    item.price, 0)
+totalBalance += Number(row.balance)
+`
+	ctx := core.NewFileContext("frontend/src/summary.ts", ".", []byte(code), nil)
+	violations := NewFrontendMoneyArithmeticRule().AnalyzeFile(ctx)
+	if len(violations) != 2 {
+		t.Fatalf("got %d violations, want 2: %+v", len(violations), violations)
+	}
+	if v := violations[0]; v.Line != 2 || v.Column != 15 || v.EndLine != 5 {
+		t.Errorf("reduce at %d:%d-%d, want 2:15-5", v.Line, v.Column, v.EndLine)
+	}
+	if v := violations[1]; v.Line != 6 || v.Column != 1 || v.EndLine != 0 {
+		t.Errorf("accumulation at %d:%d-%d, want 6:1", v.Line, v.Column, v.EndLine)
+	}
+}
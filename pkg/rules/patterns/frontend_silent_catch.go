@@ -2,6 +2,7 @@ package patterns
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/aiseeq/glint/pkg/core"
@@ -17,9 +18,7 @@ func init() {
 // that can do so.
 type FrontendSilentCatchRule struct {
 	*rules.BaseRule
-	catchStart       *regexp.Regexp
-	loggerCall       *regexp.Regexp
-	userFeedbackCall *regexp.Regexp
+	feedbackSetter *regexp.Regexp
 }
 
 // NewFrontendSilentCatchRule creates the rule
@@ -31,9 +30,7 @@ func NewFrontendSilentCatchRule() *FrontendSilentCatchRule {
 			"Detects frontend catch blocks that log errors without user-visible handling",
 			core.SeverityHigh,
 		),
-		catchStart:       regexp.MustCompile(`\bcatch\b(?:\s*\([^)]*\))?\s*\{`),
-		loggerCall:       regexp.MustCompile(`\b(?:console|logger)\.error\s*\(`),
-		userFeedbackCall: regexp.MustCompile(`\b(?:set[A-Za-z0-9_]*(?:Error|Message|Notice|Alert|Toast|Status)|toast\.|showToast\s*\(|alert\s*\(|throw\b|Promise\.reject\s*\()`),
+		feedbackSetter: regexp.MustCompile(`^set[A-Za-z0-9_]*(?:Error|Message|Notice|Alert|Toast|Status)`),
 	}
 }
 
@@ -45,22 +42,15 @@ func (r *FrontendSilentCatchRule) AnalyzeFile(ctx *core.FileContext) []*core.Vio
 	if r.shouldSkip(ctx) {
 		return nil
 	}
+	file := ctx.TypeScriptAST()
 
 	var violations []*core.Violation
-	for i := 0; i < len(ctx.Lines); i++ {
-		line := ctx.Lines[i]
-		if !r.catchStart.MatchString(line) {
-			continue
+	file.Program.Walk(func(n *core.TSNode, _ []*core.TSNode) bool {
+		if n.Kind == core.TSCatch && r.isSilentCatch(n.Child(1)) {
+			violations = append(violations, r.violation(ctx, n))
 		}
-
-		block, end := collectBraceBlock(ctx.Lines, i)
-		if end > i {
-			i = end
-		}
-		if r.isSilentCatch(block) {
-			violations = append(violations, r.violation(ctx, i+1, line))
-		}
-	}
+		return true
+	})
 
 	return violations
 }
@@ -92,8 +82,57 @@ func collectBraceBlock(lines []string, start int) (string, int) {
 	return builder.String(), start
 }
 
-func (r *FrontendSilentCatchRule) isSilentCatch(block string) bool {
-	return r.loggerCall.MatchString(block) && !r.userFeedbackCall.MatchString(block)
+// isSilentCatch reports whether a catch body logs the error and does nothing
+// the user or a caller would notice.
+func (r *FrontendSilentCatchRule) isSilentCatch(body *core.TSNode) bool {
+	logs, handles := false, false
+	body.Walk(func(n *core.TSNode, _ []*core.TSNode) bool {
+		switch n.Kind {
+		case core.TSThrow:
+			handles = true
+		case core.TSCall:
+			callee := tsCallee(n)
+			logs = logs || isErrorLoggerCall(callee)
+			handles = handles || r.isFeedbackCall(callee)
+		}
+		return !handles
+	})
+	return logs && !handles
+}
+
+// isErrorLoggerCall matches console.error and logger.error, the logger
+// reached through any object: this.logger.error.
+func isErrorLoggerCall(callee string) bool {
+	object, method, ok := cutLast(callee)
+	if !ok || method != "error" {
+		return false
+	}
+	_, receiver, _ := cutLast(object)
+	return receiver == "console" || receiver == "logger"
+}
+
+// isFeedbackCall matches what shows the error or hands it on: an error state
+// setter, a toast, an alert or a rejected promise.
+func (r *FrontendSilentCatchRule) isFeedbackCall(callee string) bool {
+	if callee == "" {
+		return false
+	}
+	_, name, _ := cutLast(callee)
+	if r.feedbackSetter.MatchString(name) || name == "showToast" || name == "alert" ||
+		callee == "Promise.reject" {
+		return true
+	}
+	return slices.Contains(strings.Split(callee, "."), "toast")
+}
+
+// cutLast splits a dotted path at its last dot; without one the whole path is
+// the last segment.
+func cutLast(path string) (string, string, bool) {
+	i := strings.LastIndexByte(path, '.')
+	if i < 0 {
+		return "", path, false
+	}
+	return path[:i], path[i+1:], true
 }
 
 func (r *FrontendSilentCatchRule) shouldSkip(ctx *core.FileContext) bool {
@@ -120,9 +159,10 @@ func skipFrontendPath(ctx *core.FileContext) bool {
 		strings.HasSuffix(path, "jest.setup.js")
 }
 
-func (r *FrontendSilentCatchRule) violation(ctx *core.FileContext, lineNum int, line string) *core.Violation {
-	v := r.CreateViolation(ctx.RelPath, lineNum, "Frontend catch block logs an error without user-visible handling")
-	v.WithCode(strings.TrimSpace(line))
+func (r *FrontendSilentCatchRule) violation(ctx *core.FileContext, catch *core.TSNode) *core.Violation {
+	v := r.CreateViolation(ctx.RelPath, catch.Start.Line, "Frontend catch block logs an error without user-visible handling")
+	atTSNode(v, catch)
+	v.WithCode(strings.TrimSpace(ctx.GetLine(catch.Start.Line)))
 	v.WithSuggestion("Show the error via component state/toast/alert, or rethrow it to a caller that does so.")
 	v.WithContext("pattern", "frontend-silent-catch")
 	v.WithContext("language", "typescript")
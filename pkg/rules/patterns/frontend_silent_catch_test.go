@@ -112,3 +112,32 @@ func TestFrontendSilentCatchRule(t *testing.T) {
 		})
 	}
 }
+
+func TestFrontendSilentCatchRuleReportsCatchClause(t *testing.T) {
+	code := `async function save() {
+  try {
+    await api.save()
+  } catch (err) {
+    // a comment mentioning throw and toast.show() is not handling
+    this.logger.error('Save failed', err)
+  }
+  try {
+    await api.load()
+  } catch {
+    console.error('load failed')
+    toast.error('Не удалось загрузить')
+  }
+}`
+	ctx := core.NewFileContext("frontend/app/src/save.ts", ".", []byte(code), nil)
+	violations := NewFrontendSilentCatchRule().AnalyzeFile(ctx)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	v := violations[0]
+	if v.Line != 4 || v.Column != 5 || v.EndLine != 7 {
+		t.Errorf("got %d:%d-%d, want 4:5-7", v.Line, v.Column, v.EndLine)
+	}
+	if v.Code != "} catch (err) {" {
+		t.Errorf("got code %q", v.Code)
+	}
+}
@@ -1,7 +1,14 @@
 package patterns
 
 import (
+	"errors"
+	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+	"sort"
+	"strings"
 
 	"github.com/aiseeq/glint/pkg/core"
 	"github.com/aiseeq/glint/pkg/rules"
@@ -11,7 +18,20 @@ func init() {
 	rules.Register(NewGoModernRule())
 }
 
-// GoModernRule detects patterns that could use modern Go features
+// GoModernRule detects code that a newer Go release expresses directly:
+//
+//	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })  // slices.Sort(ids)
+//	for _, v := range xs { if v == x { return true } }; return false  // return slices.Contains(xs, x)
+//	if limit < n { n = limit }                                       // n = min(n, limit)
+//	for k := range m { keys = append(keys, k) }                      // slices.Collect(maps.Keys(m))
+//	for i := 0; i < n; i++ {                                         // for i := range n {
+//
+// Only rewrites that are provably equivalent are reported: an ordered element
+// type for the sort, integer or string operands for min/max (floats differ on
+// NaN), an unchanged bound for range-over-int. Every finding carries the exact
+// replacement and the Go version it needs, so the go-modern fixer can apply it
+// without type information, and nothing is suggested that the module's go
+// directive does not allow yet.
 type GoModernRule struct {
 	*rules.BaseRule
 }
@@ -28,61 +48,817 @@ func NewGoModernRule() *GoModernRule {
 	}
 }
 
-// AnalyzeFile checks for outdated patterns
-func (r *GoModernRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
-	if !ctx.IsGoFile() || ctx.GoAST == nil {
-		return nil
+// AnalyzeFile is a no-op because the rule needs type information to prove a
+// rewrite equivalent.
+func (r *GoModernRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that typed syntax is enough for this rule.
+func (r *GoModernRule) RequiresSSA() bool { return false }
+
+// modernization is one rewrite the rule proved equivalent: the source range it
+// replaces, the replacement text and what the replacement needs.
+type modernization struct {
+	pattern     string
+	minGo       string
+	start, end  token.Pos
+	replacement string
+	imports     []string
+	message     string
+}
+
+// goModernFile carries what every check of one file needs.
+type goModernFile struct {
+	ctx       *core.FileContext
+	fset      *token.FileSet
+	info      *types.Info
+	pkg       *types.Package
+	goVersion string
+}
+
+// AnalyzeGoProject inspects every file of the loaded packages.
+func (r *GoModernRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("go modern: nil Go project context")
+	}
+	if ctx.FileSet == nil {
+		return nil, errors.New("go modern: project has no file set for source positions")
 	}
 
 	var violations []*core.Violation
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.Package == nil || pkg.Package.TypesInfo == nil {
+			return nil, errors.New("go modern: package has no typed syntax")
+		}
+		goVersion := ""
+		if pkg.Package.Module != nil {
+			goVersion = pkg.Package.Module.GoVersion
+		}
+		for _, fileCtx := range pkg.Files {
+			if fileCtx.GoAST == nil {
+				continue
+			}
+			file := &goModernFile{
+				ctx:       fileCtx,
+				fset:      ctx.FileSet,
+				info:      pkg.Package.TypesInfo,
+				pkg:       pkg.Package.Types,
+				goVersion: goVersion,
+			}
+			violations = append(violations, r.analyzeFile(file)...)
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, nil
+}
+
+func (r *GoModernRule) analyzeFile(file *goModernFile) []*core.Violation {
+	var found []modernization
 
-	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
+	ast.Inspect(file.ctx.GoAST, func(n ast.Node) bool {
 		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if m, ok := file.deprecatedReflectHeader(node); ok {
+				found = append(found, m)
+			}
 		case *ast.CallExpr:
-			violations = append(violations, r.checkCallExpr(ctx, node)...)
-
+			if m, ok := file.sortCall(node); ok {
+				found = append(found, m)
+			}
+			if m, ok := file.mathMinMax(node); ok {
+				found = append(found, m)
+			}
+		case *ast.IfStmt:
+			if m, ok := file.conditionalMinMax(node); ok {
+				found = append(found, m)
+			}
 		case *ast.ForStmt:
-			violations = append(violations, r.checkForStmt()...)
-
+			if m, ok := file.rangeOverInt(node); ok {
+				found = append(found, m)
+			}
+		case *ast.BlockStmt:
+			found = append(found, file.blockModernizations(node)...)
 		}
-
 		return true
 	})
 
+	var violations []*core.Violation
+	for _, m := range found {
+		if file.goVersion != "" && version.Compare("go"+file.goVersion, "go"+m.minGo) < 0 {
+			continue // the module cannot use the feature yet
+		}
+		violations = append(violations, r.report(file, m))
+	}
 	return violations
 }
 
-// checkCallExpr checks for function calls that have modern alternatives
-func (r *GoModernRule) checkCallExpr(ctx *core.FileContext, call *ast.CallExpr) []*core.Violation {
-	var violations []*core.Violation
+func (r *GoModernRule) report(file *goModernFile, m modernization) *core.Violation {
+	start := file.fset.Position(m.start)
+	end := file.fset.Position(m.end)
+	v := r.CreateViolation(file.ctx.RelPath, start.Line, m.message)
+	v.WithColumn(start.Column)
+	if end.Line > start.Line {
+		v.WithEndLine(end.Line)
+	}
+	v.WithCode(strings.TrimSpace(file.ctx.GetLine(start.Line)))
+	v.WithContext("pattern", m.pattern)
+	v.WithContext("min_go", m.minGo)
+	if m.replacement == "" {
+		v.WithSuggestion("Use unsafe.Slice and unsafe.String (Go 1.20+)")
+		return v
+	}
+	v.WithSuggestion("Replace with " + firstLine(m.replacement) + " (Go " + m.minGo + "+)")
+	v.WithContext("replacement", m.replacement)
+	v.WithContext("end_line", end.Line)
+	v.WithContext("end_column", end.Column)
+	if len(m.imports) > 0 {
+		v.WithContext("imports", m.imports)
+	}
+	return v
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
+// deprecatedReflectHeader reports uses of reflect.SliceHeader/StringHeader.
+// There is no mechanical replacement, so the finding carries none.
+func (file *goModernFile) deprecatedReflectHeader(sel *ast.SelectorExpr) (modernization, bool) {
+	obj, ok := file.info.Uses[sel.Sel].(*types.TypeName)
+	if !ok || obj.Pkg() == nil || obj.Pkg().Path() != "reflect" {
+		return modernization{}, false
+	}
+	if obj.Name() != "SliceHeader" && obj.Name() != "StringHeader" {
+		return modernization{}, false
+	}
+	return modernization{
+		pattern: "deprecated-reflect",
+		minGo:   "1.20",
+		start:   sel.Pos(),
+		end:     sel.End(),
+		message: "reflect." + obj.Name() + " is deprecated, use unsafe.Slice/unsafe.String instead",
+	}, true
+}
+
+// sortCall recognizes sort.Ints/sort.Strings and an ascending sort.Slice over
+// an ordered element type, all of which are slices.Sort. Equal elements of an
+// integer or string type cannot be told apart, so stability does not matter.
+func (file *goModernFile) sortCall(call *ast.CallExpr) (modernization, bool) {
+	fn, ok := file.packageFunc(call.Fun, "sort")
+	if !ok {
+		return modernization{}, false
+	}
+	var collection ast.Expr
+	switch fn {
+	case "Ints", "Strings":
+		if len(call.Args) != 1 {
+			return modernization{}, false
+		}
+		collection = call.Args[0]
+	case "Slice", "SliceStable":
+		if len(call.Args) != 2 || !file.isAscendingLess(call.Args[1], call.Args[0]) {
+			return modernization{}, false
+		}
+		collection = call.Args[0]
+	default:
+		return modernization{}, false
+	}
+	slice, ok := file.underlyingOf(collection).(*types.Slice)
+	if !ok || !isIntegerOrString(slice.Elem()) || !file.refersToPackage(call.Pos(), "slices") {
+		return modernization{}, false
+	}
+	replacement := "slices.Sort(" + file.source(collection) + ")"
+	return modernization{
+		pattern:     "slices-sort",
+		minGo:       "1.21",
+		start:       call.Pos(),
+		end:         call.End(),
+		replacement: replacement,
+		imports:     []string{"slices"},
+		message:     "sort." + fn + " over an ordered element type is " + replacement,
+	}, true
+}
+
+// isAscendingLess reports whether less is `func(i, j int) bool { return s[i] < s[j] }`
+// over the collection being sorted.
+func (file *goModernFile) isAscendingLess(less, collection ast.Expr) bool {
+	lit, ok := less.(*ast.FuncLit)
+	if !ok || len(lit.Body.List) != 1 || lit.Type.Params == nil {
+		return false
+	}
+	var params []*ast.Ident
+	for _, field := range lit.Type.Params.List {
+		params = append(params, field.Names...)
+	}
+	if len(params) != 2 {
+		return false
+	}
+	ret, ok := lit.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+	cmp, ok := ret.Results[0].(*ast.BinaryExpr)
+	if !ok || cmp.Op != token.LSS {
+		return false
+	}
+	return file.isIndexOf(cmp.X, collection, params[0]) && file.isIndexOf(cmp.Y, collection, params[1])
+}
+
+// isIndexOf reports whether expr is collection[index].
+func (file *goModernFile) isIndexOf(expr, collection ast.Expr, index *ast.Ident) bool {
+	indexExpr, ok := expr.(*ast.IndexExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := indexExpr.Index.(*ast.Ident)
+	if !ok || file.info.ObjectOf(ident) != file.info.ObjectOf(index) {
+		return false
+	}
+	return file.sameOperand(indexExpr.X, collection)
+}
+
+// mathMinMax recognizes math.Min/math.Max, which the built-ins replace with
+// identical results, NaN and signed zeros included. Two untyped constants are
+// left alone: max(1, 2) is an untyped integer, not a float64.
+func (file *goModernFile) mathMinMax(call *ast.CallExpr) (modernization, bool) {
+	fn, ok := file.packageFunc(call.Fun, "math")
+	if !ok || (fn != "Min" && fn != "Max") || len(call.Args) != 2 {
+		return modernization{}, false
+	}
+	if file.isConstant(call.Args[0]) && file.isConstant(call.Args[1]) {
+		return modernization{}, false
+	}
+	builtin := strings.ToLower(fn)
+	if !file.isBuiltin(call.Pos(), builtin) {
+		return modernization{}, false
+	}
+	replacement := fmt.Sprintf("%s(%s, %s)", builtin, file.source(call.Args[0]), file.source(call.Args[1]))
+	return modernization{
+		pattern:     "builtin-min-max",
+		minGo:       "1.21",
+		start:       call.Pos(),
+		end:         call.End(),
+		replacement: replacement,
+		message:     "math." + fn + " is the built-in " + builtin,
+	}, true
+}
+
+// conditionalMinMax recognizes `if b < a { a = b }` and its mirror images over
+// integer or string operands without side effects. The one-line replacement
+// would drop a comment written inside the statement, so one with a comment is
+// left alone.
+func (file *goModernFile) conditionalMinMax(stmt *ast.IfStmt) (modernization, bool) {
+	if stmt.Init != nil || stmt.Else != nil || len(stmt.Body.List) != 1 {
+		return modernization{}, false
+	}
+	assign, ok := stmt.Body.List[0].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return modernization{}, false
+	}
+	cond, ok := stmt.Cond.(*ast.BinaryExpr)
+	if !ok {
+		return modernization{}, false
+	}
+	target, value := assign.Lhs[0], assign.Rhs[0]
+	if !file.isPlainOperand(target) || !file.isPlainOperand(value) {
+		return modernization{}, false
+	}
+	if !isIntegerOrString(file.info.TypeOf(target)) || !types.Identical(file.info.TypeOf(target), file.info.TypeOf(value)) {
+		return modernization{}, false
+	}
+
+	// `value < target` and `target > value` both lower target to value.
+	var builtin string
+	switch {
+	case file.sameOperand(cond.X, value) && file.sameOperand(cond.Y, target):
+		builtin = map[token.Token]string{token.LSS: "min", token.GTR: "max"}[cond.Op]
+	case file.sameOperand(cond.X, target) && file.sameOperand(cond.Y, value):
+		builtin = map[token.Token]string{token.GTR: "min", token.LSS: "max"}[cond.Op]
+	}
+	if builtin == "" || !file.isBuiltin(stmt.Pos(), builtin) || file.hasComment(stmt) {
+		return modernization{}, false
+	}
+	replacement := fmt.Sprintf("%s = %s(%s, %s)", file.source(target), builtin, file.source(target), file.source(value))
+	return modernization{
+		pattern:     "builtin-min-max",
+		minGo:       "1.21",
+		start:       stmt.Pos(),
+		end:         stmt.End(),
+		replacement: replacement,
+		message:     "Conditional assignment is the built-in " + builtin + ": " + replacement,
+	}, true
+}
+
+// rangeOverInt recognizes `for i := 0; i < n; i++` whose body changes neither
+// i nor n: range evaluates n once and gives every iteration its own copy of i,
+// so the two loops are only the same when nothing writes either of them.
+func (file *goModernFile) rangeOverInt(loop *ast.ForStmt) (modernization, bool) {
+	counter, ok := zeroInitializedCounter(loop.Init)
+	if !ok {
+		return modernization{}, false
+	}
+	cond, ok := loop.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.LSS || !file.isIdent(cond.X, counter) {
+		return modernization{}, false
+	}
+	post, ok := loop.Post.(*ast.IncDecStmt)
+	if !ok || post.Tok != token.INC || !file.isIdent(post.X, counter) {
+		return modernization{}, false
+	}
+	bound := cond.Y
+	if !types.Identical(file.info.TypeOf(bound), types.Typ[types.Int]) || !file.isStableBound(bound, loop) {
+		return modernization{}, false
+	}
+	if file.writes(loop.Body, file.info.ObjectOf(counter)) {
+		return modernization{}, false
+	}
+
+	header := "for range " + file.source(bound) + " {"
+	if file.mentions(loop.Body, file.info.ObjectOf(counter)) {
+		header = "for " + counter.Name + " := range " + file.source(bound) + " {"
+	}
+	return modernization{
+		pattern:     "range-over-int",
+		minGo:       "1.22",
+		start:       loop.Pos(),
+		end:         loop.Body.Lbrace + 1,
+		replacement: header,
+		message:     "Counting loop is range over an integer: " + header,
+	}, true
+}
+
+func zeroInitializedCounter(init ast.Stmt) (*ast.Ident, bool) {
+	assign, ok := init.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil, false
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	lit, ok := assign.Rhs[0].(*ast.BasicLit)
+	return ident, ok && lit.Kind == token.INT && lit.Value == "0"
+}
+
+// isStableBound reports whether the loop bound keeps its value while the loop
+// runs: a constant, or a local variable (or len of one) that the loop body never
+// writes and that is never addressed or written from a closure.
+func (file *goModernFile) isStableBound(bound ast.Expr, loop *ast.ForStmt) bool {
+	if file.isConstant(bound) {
+		return true
+	}
+	if call, ok := bound.(*ast.CallExpr); ok && len(call.Args) == 1 && file.isBuiltinCall(call, "len") {
+		bound = call.Args[0]
+	}
+	ident, ok := bound.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	variable, ok := file.info.ObjectOf(ident).(*types.Var)
+	if !ok || variable.Parent() == nil || variable.Parent() == file.pkg.Scope() {
+		return false
+	}
+	if file.writes(loop.Body, variable) {
+		return false
+	}
+	scope := file.enclosingFunc(loop)
+	return scope != nil && !file.escapes(scope, variable)
+}
+
+// blockModernizations looks at pairs of consecutive statements: the rewrites
+// for linear search and key collection replace two statements with one.
+func (file *goModernFile) blockModernizations(block *ast.BlockStmt) []modernization {
+	var found []modernization
+	for i := 0; i+1 < len(block.List); i++ {
+		if m, ok := file.linearSearch(block, i); ok {
+			found = append(found, m)
+		}
+		if m, ok := file.collectKeys(block.List[i], block.List[i+1]); ok {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// linearSearch recognizes
+//
+//	for _, v := range xs { if v == x { return true } }
+//	return false
+//
+// inside a larger function whose one result is a plain bool: slices.Contains
+// returns bool, which a named bool result type does not accept. A function
+// that is nothing but this loop is a reimplemented-stdlib finding, which
+// suggests deleting the helper instead.
+func (file *goModernFile) linearSearch(block *ast.BlockStmt, i int) (modernization, bool) {
+	loop, ok := block.List[i].(*ast.RangeStmt)
+	if !ok || !isReturnOf(block.List[i+1], "false") {
+		return modernization{}, false
+	}
+	if i == 0 && len(block.List) == 2 && file.isFuncBody(block) {
+		return modernization{}, false
+	}
+	if !file.returnsBool(block) {
+		return modernization{}, false
+	}
+	if loop.Value == nil || loop.Tok != token.DEFINE || len(loop.Body.List) != 1 {
+		return modernization{}, false
+	}
+	if key, ok := loop.Key.(*ast.Ident); !ok || key.Name != "_" {
+		return modernization{}, false
+	}
+	element, ok := loop.Value.(*ast.Ident)
+	if !ok {
+		return modernization{}, false
+	}
+	check, ok := loop.Body.List[0].(*ast.IfStmt)
+	if !ok || check.Init != nil || check.Else != nil || len(check.Body.List) != 1 || !isReturnOf(check.Body.List[0], "true") {
+		return modernization{}, false
+	}
+	cond, ok := check.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.EQL {
+		return modernization{}, false
+	}
+	target := cond.Y
+	if !file.isIdent(cond.X, element) {
+		if !file.isIdent(cond.Y, element) {
+			return modernization{}, false
+		}
+		target = cond.X
+	}
+	if !file.isPlainOperand(target) || file.mentions(target, file.info.ObjectOf(element)) {
+		return modernization{}, false
+	}
+	slice, ok := file.underlyingOf(loop.X).(*types.Slice)
+	if !ok || !types.Comparable(slice.Elem()) || !types.Identical(slice.Elem(), file.info.TypeOf(target)) || !file.refersToPackage(loop.Pos(), "slices") {
+		return modernization{}, false
+	}
+	replacement := fmt.Sprintf("return slices.Contains(%s, %s)", file.source(loop.X), file.source(target))
+	return modernization{
+		pattern:     "slices-contains",
+		minGo:       "1.21",
+		start:       loop.Pos(),
+		end:         block.List[i+1].End(),
+		replacement: replacement,
+		imports:     []string{"slices"},
+		message:     "Linear search loop is " + replacement,
+	}, true
+}
+
+func isReturnOf(stmt ast.Stmt, value string) bool {
+	ret, ok := stmt.(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+	ident, ok := ret.Results[0].(*ast.Ident)
+	return ok && ident.Name == value
+}
 
-	// Check for sort.Slice with manual less function that could use slices.SortFunc
-	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-		if ident, ok := sel.X.(*ast.Ident); ok {
-			funcName := ident.Name + "." + sel.Sel.Name
-
-			switch funcName {
-			case "reflect.SliceHeader", "reflect.StringHeader":
-				// These are deprecated in Go 1.20+
-				pos := ctx.PositionFor(call)
-				v := r.CreateViolation(ctx.RelPath, pos.Line,
-					funcName+" is deprecated, use unsafe.Slice/unsafe.String instead")
-				v.WithCode(ctx.GetLine(pos.Line))
-				v.WithSuggestion("Use unsafe.Slice and unsafe.String (Go 1.20+)")
-				v.WithContext("pattern", "deprecated-reflect")
-				violations = append(violations, v)
+// collectKeys recognizes an empty slice followed by a loop appending every key
+// of a map to it. A nil slice becomes slices.Collect, which also returns nil for
+// an empty map; a made slice keeps its make, so an empty map still yields an
+// empty, non-nil slice.
+func (file *goModernFile) collectKeys(decl, next ast.Stmt) (modernization, bool) {
+	loop, ok := next.(*ast.RangeStmt)
+	if !ok || loop.Value != nil || loop.Tok != token.DEFINE || len(loop.Body.List) != 1 {
+		return modernization{}, false
+	}
+	key, ok := loop.Key.(*ast.Ident)
+	if !ok || key.Name == "_" {
+		return modernization{}, false
+	}
+	mapType, ok := file.underlyingOf(loop.X).(*types.Map)
+	if !ok || !file.isPlainOperand(loop.X) {
+		return modernization{}, false
+	}
+	keys, initial, ok := file.emptySliceDecl(decl)
+	if !ok {
+		return modernization{}, false
+	}
+	if !file.appendsTo(loop.Body.List[0], keys, key) {
+		return modernization{}, false
+	}
+	slice, ok := file.underlyingOf(keys).(*types.Slice)
+	if !ok || !types.Identical(slice.Elem(), mapType.Key()) {
+		return modernization{}, false
+	}
+	if !file.refersToPackage(loop.Pos(), "slices") || !file.refersToPackage(loop.Pos(), "maps") {
+		return modernization{}, false
+	}
+
+	seq := "maps.Keys(" + file.source(loop.X) + ")"
+	var replacement string
+	if initial == nil {
+		// slices.Collect returns []K: the declared type must be exactly that.
+		if !types.Identical(file.info.TypeOf(keys), types.NewSlice(mapType.Key())) {
+			return modernization{}, false
+		}
+		replacement = fmt.Sprintf("%s := slices.Collect(%s)", keys.Name, seq)
+	} else {
+		replacement = fmt.Sprintf("%s := slices.AppendSeq(%s, %s)", keys.Name, file.source(initial), seq)
+	}
+	return modernization{
+		pattern:     "maps-keys",
+		minGo:       "1.23",
+		start:       decl.Pos(),
+		end:         loop.End(),
+		replacement: replacement,
+		imports:     []string{"maps", "slices"},
+		message:     "Collecting map keys by hand is " + replacement,
+	}, true
+}
+
+// emptySliceDecl matches `var keys []K` (initial == nil) and
+// `keys := make([]K, 0[, n])`.
+func (file *goModernFile) emptySliceDecl(stmt ast.Stmt) (*ast.Ident, ast.Expr, bool) {
+	switch decl := stmt.(type) {
+	case *ast.DeclStmt:
+		gen, ok := decl.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+			return nil, nil, false
+		}
+		spec, ok := gen.Specs[0].(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || len(spec.Values) != 0 {
+			return nil, nil, false
+		}
+		return spec.Names[0], nil, true
+	case *ast.AssignStmt:
+		if decl.Tok != token.DEFINE || len(decl.Lhs) != 1 || len(decl.Rhs) != 1 {
+			return nil, nil, false
+		}
+		ident, ok := decl.Lhs[0].(*ast.Ident)
+		call, isCall := decl.Rhs[0].(*ast.CallExpr)
+		if !ok || !isCall || !file.isBuiltinCall(call, "make") || len(call.Args) < 2 {
+			return nil, nil, false
+		}
+		if length, ok := call.Args[1].(*ast.BasicLit); !ok || length.Value != "0" {
+			return nil, nil, false
+		}
+		return ident, call, true
+	}
+	return nil, nil, false
+}
+
+// appendsTo reports whether stmt is `keys = append(keys, key)`.
+func (file *goModernFile) appendsTo(stmt ast.Stmt, keys, key *ast.Ident) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	if !file.isIdent(assign.Lhs[0], keys) {
+		return false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || !file.isBuiltinCall(call, "append") || len(call.Args) != 2 || call.Ellipsis.IsValid() {
+		return false
+	}
+	return file.isIdent(call.Args[0], keys) && file.isIdent(call.Args[1], key)
+}
+
+// packageFunc returns the name of the function when fun is pkg.Func of the
+// given standard-library package.
+func (file *goModernFile) packageFunc(fun ast.Expr, pkgPath string) (string, bool) {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	fn, ok := file.info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return "", false
+	}
+	return fn.Name(), true
+}
+
+// isBuiltin reports whether name resolves to the predeclared function at pos,
+// so that a package-level min or max is never shadowed by the rewrite.
+func (file *goModernFile) isBuiltin(pos token.Pos, name string) bool {
+	scope := file.pkg.Scope().Innermost(pos)
+	if scope == nil {
+		return false
+	}
+	_, obj := scope.LookupParent(name, pos)
+	_, ok := obj.(*types.Builtin)
+	return ok
+}
+
+func (file *goModernFile) isBuiltinCall(call *ast.CallExpr, name string) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || ident.Name != name {
+		return false
+	}
+	_, ok = file.info.Uses[ident].(*types.Builtin)
+	return ok
+}
+
+// refersToPackage reports whether the package name is free at pos or already
+// names the standard-library package, so the rewrite cannot bind to a local
+// variable that happens to be called slices or maps.
+func (file *goModernFile) refersToPackage(pos token.Pos, name string) bool {
+	scope := file.pkg.Scope().Innermost(pos)
+	if scope == nil {
+		return false
+	}
+	_, obj := scope.LookupParent(name, pos)
+	if obj == nil {
+		return true
+	}
+	pkgName, ok := obj.(*types.PkgName)
+	return ok && pkgName.Imported().Path() == name
+}
+
+// isPlainOperand accepts identifiers and field selections: expressions that
+// can be evaluated once more or once less without a side effect.
+func (file *goModernFile) isPlainOperand(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		_, isVar := file.info.ObjectOf(e).(*types.Var)
+		return isVar || file.isConstant(e)
+	case *ast.SelectorExpr:
+		if _, ok := file.info.Selections[e]; ok {
+			return file.info.Selections[e].Kind() == types.FieldVal && file.isPlainOperand(e.X)
+		}
+		return file.isConstant(e)
+	case *ast.BasicLit:
+		return true
+	case *ast.ParenExpr:
+		return file.isPlainOperand(e.X)
+	}
+	return false
+}
+
+// sameOperand reports whether two plain operands denote the same value.
+func (file *goModernFile) sameOperand(a, b ast.Expr) bool {
+	if !file.isPlainOperand(a) || !file.isPlainOperand(b) {
+		return false
+	}
+	switch x := a.(type) {
+	case *ast.Ident:
+		y, ok := b.(*ast.Ident)
+		return ok && file.info.ObjectOf(x) == file.info.ObjectOf(y)
+	case *ast.SelectorExpr:
+		y, ok := b.(*ast.SelectorExpr)
+		return ok && file.info.ObjectOf(x.Sel) == file.info.ObjectOf(y.Sel) && file.sameOperand(x.X, y.X)
+	case *ast.ParenExpr:
+		return file.sameOperand(x.X, b)
+	}
+	return file.source(a) == file.source(b)
+}
+
+func (file *goModernFile) isIdent(expr ast.Expr, ident *ast.Ident) bool {
+	other, ok := expr.(*ast.Ident)
+	return ok && file.info.ObjectOf(other) != nil && file.info.ObjectOf(other) == file.info.ObjectOf(ident)
+}
+
+func (file *goModernFile) isConstant(expr ast.Expr) bool {
+	tv, ok := file.info.Types[expr]
+	return ok && tv.Value != nil
+}
+
+// mentions reports whether node refers to obj anywhere.
+func (file *goModernFile) mentions(node ast.Node, obj types.Object) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && file.info.Uses[ident] == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// writes reports whether node assigns obj, increments it or takes its address.
+func (file *goModernFile) writes(node ast.Node, obj types.Object) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				if ident, ok := ast.Unparen(lhs).(*ast.Ident); ok && file.info.ObjectOf(ident) == obj {
+					found = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if ident, ok := ast.Unparen(stmt.X).(*ast.Ident); ok && file.info.ObjectOf(ident) == obj {
+				found = true
+			}
+		case *ast.UnaryExpr:
+			if ident, ok := ast.Unparen(stmt.X).(*ast.Ident); ok && stmt.Op == token.AND && file.info.ObjectOf(ident) == obj {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// escapes reports whether a closure in fn writes the variable or fn takes its
+// address anywhere: then a call inside the loop could change it.
+func (file *goModernFile) escapes(fn ast.Node, variable *types.Var) bool {
+	found := false
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			if file.writes(node.Body, variable) {
+				found = true
 			}
+		case *ast.UnaryExpr:
+			if ident, ok := ast.Unparen(node.X).(*ast.Ident); ok && node.Op == token.AND && file.info.ObjectOf(ident) == variable {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
 
+// enclosingFunc returns the declaration or literal whose body contains node.
+func (file *goModernFile) enclosingFunc(node ast.Node) ast.Node {
+	var enclosing ast.Node
+	ast.Inspect(file.ctx.GoAST, func(n ast.Node) bool {
+		if n == nil || n.Pos() > node.Pos() || n.End() < node.End() {
+			return false
 		}
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			enclosing = n
+		}
+		return true
+	})
+	return enclosing
+}
+
+// returnsBool reports whether the function around node has a single result of
+// type bool itself, not a type defined over it.
+func (file *goModernFile) returnsBool(node ast.Node) bool {
+	var signature *ast.FuncType
+	switch fn := file.enclosingFunc(node).(type) {
+	case *ast.FuncDecl:
+		signature = fn.Type
+	case *ast.FuncLit:
+		signature = fn.Type
+	default:
+		return false
+	}
+	results := signature.Results
+	if results == nil || results.NumFields() != 1 {
+		return false
 	}
+	return types.Identical(file.info.TypeOf(results.List[0].Type), types.Typ[types.Bool])
+}
 
-	return violations
+// hasComment reports whether a comment is written inside node.
+func (file *goModernFile) hasComment(node ast.Node) bool {
+	for _, group := range file.ctx.GoAST.Comments {
+		if group.Pos() >= node.Pos() && group.End() <= node.End() {
+			return true
+		}
+	}
+	return false
 }
 
-// checkForStmt checks for loop patterns that could be modernized
-func (r *GoModernRule) checkForStmt() []*core.Violation {
-	// ForStmt is a regular for loop, not a range loop
-	// Range loops are handled separately as ast.RangeStmt
-	// For now, we don't have specific patterns to check in regular for loops
-	return nil
+// isFuncBody reports whether block is the body of a function.
+func (file *goModernFile) isFuncBody(block *ast.BlockStmt) bool {
+	switch fn := file.enclosingFunc(block).(type) {
+	case *ast.FuncDecl:
+		return fn.Body == block
+	case *ast.FuncLit:
+		return fn.Body == block
+	}
+	return false
+}
+
+// underlyingOf returns the underlying type of expr, nil when it is untyped.
+func (file *goModernFile) underlyingOf(expr ast.Expr) types.Type {
+	t := file.info.TypeOf(expr)
+	if t == nil {
+		return nil
+	}
+	return t.Underlying()
+}
+
+// source returns the source text of a node as written.
+func (file *goModernFile) source(node ast.Node) string {
+	start := file.fset.Position(node.Pos()).Offset
+	end := file.fset.Position(node.End()).Offset
+	if start < 0 || end > len(file.ctx.Content) || start > end {
+		return ""
+	}
+	return string(file.ctx.Content[start:end])
+}
+
+// isIntegerOrString reports whether values of t are totally ordered by <, so
+// sorting or taking the minimum gives one answer whatever the algorithm.
+func isIntegerOrString(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsInteger|types.IsString) != 0
 }
@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
 )
 
 func TestGoModernRule(t *testing.T) {
@@ -15,7 +17,7 @@ func TestGoModernRule(t *testing.T) {
 		wantViolations int
 	}{
 		{
-			name: "sort.Slice - suggest slices.SortFunc",
+			name: "ascending sort.Slice over ints is slices.Sort",
 			code: `package main
 
 import "sort"
@@ -26,10 +28,10 @@ func main() {
 		return items[i] < items[j]
 	})
 }`,
-			wantViolations: 0,
+			wantViolations: 1,
 		},
 		{
-			name: "sort.SliceStable - suggest slices.SortStableFunc",
+			name: "ascending sort.SliceStable over ints is slices.Sort",
 			code: `package main
 
 import "sort"
@@ -40,10 +42,10 @@ func main() {
 		return items[i] < items[j]
 	})
 }`,
-			wantViolations: 0,
+			wantViolations: 1,
 		},
 		{
-			name: "sort.Search - suggest slices.BinarySearch",
+			name: "sort.Search - no equivalent rewrite",
 			code: `package main
 
 import "sort"
@@ -58,7 +60,7 @@ func main() {
 			wantViolations: 0,
 		},
 		{
-			name: "math.Max - suggest built-in max",
+			name: "math.Max is the built-in max",
 			code: `package main
 
 import "math"
@@ -68,10 +70,10 @@ func main() {
 	m := math.Max(a, b)
 	_ = m
 }`,
-			wantViolations: 0,
+			wantViolations: 1,
 		},
 		{
-			name: "math.Min - suggest built-in min",
+			name: "math.Min is the built-in min",
 			code: `package main
 
 import "math"
@@ -81,7 +83,7 @@ func main() {
 	m := math.Min(a, b)
 	_ = m
 }`,
-			wantViolations: 0,
+			wantViolations: 1,
 		},
 		{
 			name: "slices.Sort - already modern, no violation",
@@ -125,7 +127,7 @@ func main() {
 	m := math.Max(a, b)
 	_ = m
 }`,
-			wantViolations: 0,
+			wantViolations: 2,
 		},
 		{
 			name: "method call Walk - not flagged (can't change library APIs)",
@@ -141,7 +143,7 @@ func main() {
 		return true
 	})
 }`,
-			wantViolations: 0, // Method calls skipped - can't change library APIs
+			wantViolations: 0,
 		},
 		{
 			name: "method call ForEach - not flagged (can't change library APIs)",
@@ -157,16 +159,19 @@ func main() {
 		println(s)
 	})
 }`,
-			wantViolations: 0, // Method calls skipped - can't change library APIs
+			wantViolations: 0,
 		},
 		{
-			name: "filepath.Walk - package level call should be flagged",
+			name: "filepath.Walk - callback APIs are left alone",
 			code: `package main
 
-import "path/filepath"
+import (
+	"os"
+	"path/filepath"
+)
 
 func main() {
-	filepath.Walk("/tmp", func(path string, info any, err error) error {
+	filepath.Walk("/tmp", func(path string, info os.FileInfo, err error) error {
 		return nil
 	})
 }`,
@@ -205,17 +210,7 @@ func main() {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rule := NewGoModernRule()
-
-			parser := core.NewParser()
-			ctx := core.NewFileContext("/src/main.go", "/src", []byte(tt.code), core.DefaultConfig())
-			fset, astFile, err := parser.ParseGoFile("/src/main.go", []byte(tt.code))
-			if err != nil {
-				t.Fatalf("Parse error: %v", err)
-			}
-			ctx.SetGoAST(fset, astFile)
-
-			violations := rule.AnalyzeFile(ctx)
+			violations := analyzeGoModern(t, "", tt.code)
 
 			assert.Len(t, violations, tt.wantViolations, "Code:\n%s", tt.code)
 		})
@@ -229,3 +224,288 @@ func TestGoModernRuleMetadata(t *testing.T) {
 	assert.Equal(t, "patterns", rule.Category())
 	assert.Equal(t, core.SeverityLow, rule.DefaultSeverity())
 }
+
+func analyzeGoModern(t *testing.T, goMod, source string) []*core.Violation {
+	t.Helper()
+	files := map[string]string{"modern.go": source}
+	if goMod != "" {
+		files["go.mod"] = goMod
+	}
+	violations, err := NewGoModernRule().AnalyzeGoProject(rulestest.Project(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func TestGoModernSortSliceOverOrderedElements(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+import "sort"
+
+func Sorted(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+`)
+
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, 6, v.Line)
+	assert.Equal(t, 2, v.Column)
+	assert.Equal(t, 8, v.EndLine)
+	assert.Equal(t, "slices-sort", v.Context["pattern"])
+	assert.Equal(t, "slices.Sort(ids)", v.Context["replacement"])
+	assert.Equal(t, []string{"slices"}, v.Context["imports"])
+}
+
+// Descending order, a float element type or a less function over another
+// slice are not slices.Sort.
+func TestGoModernLeavesNonEquivalentSorts(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+import "sort"
+
+func Descending(ids []int) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+}
+
+func Floats(xs []float64) {
+	sort.Slice(xs, func(i, j int) bool { return xs[i] < xs[j] })
+}
+
+func ByOther(ids, weights []int) {
+	sort.Slice(ids, func(i, j int) bool { return weights[i] < weights[j] })
+}
+`)
+
+	assert.Empty(t, violations)
+}
+
+func TestGoModernMathMinMax(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+import "math"
+
+func Clamp(x, limit float64) float64 {
+	return math.Min(x, limit)
+}
+
+func Constant() float64 {
+	return math.Max(1, 2)
+}
+`)
+
+	require.Len(t, violations, 1, "two untyped constants would change the result type")
+	assert.Equal(t, "min(x, limit)", violations[0].Context["replacement"])
+}
+
+func TestGoModernConditionalMinOverIntegers(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+func Limit(n, limit int) int {
+	if limit < n {
+		n = limit
+	}
+	return n
+}
+
+func Ratio(x, limit float64) float64 {
+	if limit < x {
+		x = limit
+	}
+	return x
+}
+`)
+
+	require.Len(t, violations, 1, "floats differ from min on NaN")
+	assert.Equal(t, "n = min(n, limit)", violations[0].Context["replacement"])
+	assert.Equal(t, 6, violations[0].Context["end_line"])
+}
+
+// The replacement is one line; a comment inside the statement would be lost.
+func TestGoModernConditionalMinKeepsComments(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+func Limit(n, limit int) int {
+	if limit < n {
+		// the quota caps every batch
+		n = limit
+	}
+	return n
+}
+`)
+
+	assert.Empty(t, violations)
+}
+
+func TestGoModernBuiltinShadowedByPackageFunction(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+func min(a, b int) int { return a + b }
+
+func Limit(n, limit int) int {
+	if limit < n {
+		n = limit
+	}
+	return min(n, 0)
+}
+`)
+
+	assert.Empty(t, violations)
+}
+
+func TestGoModernRangeOverInt(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+func Sum(xs []int) int {
+	total := 0
+	for i := 0; i < len(xs); i++ {
+		total += xs[i]
+	}
+	for i := 0; i < 3; i++ {
+		total++
+	}
+	return total
+}
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, "for i := range len(xs) {", violations[0].Context["replacement"])
+	assert.Equal(t, "for range 3 {", violations[1].Context["replacement"])
+	assert.Equal(t, "1.22", violations[0].Context["min_go"])
+}
+
+// A body that moves the counter or the bound makes the loops differ.
+func TestGoModernRangeOverIntNeedsStableCounterAndBound(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+func Skip(xs []int) {
+	for i := 0; i < len(xs); i++ {
+		i++
+	}
+	for i := 0; i < len(xs); i++ {
+		xs = append(xs, i)
+	}
+	n := 3
+	grow := func() { n++ }
+	for i := 0; i < n; i++ {
+		grow()
+	}
+	var big int64 = 4
+	for i := 0; i < int(big); i++ {
+	}
+}
+`)
+
+	assert.Empty(t, violations)
+}
+
+func TestGoModernLinearSearch(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+func Allowed(roles []string, role string) bool {
+	if role == "" {
+		return false
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func Contains(xs []int, x int) bool {
+	for _, v := range xs {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+`)
+
+	require.Len(t, violations, 1, "a function that is only the loop is reimplemented-stdlib territory")
+	assert.Equal(t, "return slices.Contains(roles, role)", violations[0].Context["replacement"])
+	assert.Equal(t, 7, violations[0].Line)
+	assert.Equal(t, 12, violations[0].EndLine)
+}
+
+// slices.Contains returns bool, which a named bool result does not accept
+// without a conversion.
+func TestGoModernLinearSearchNeedsPlainBoolResult(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+type Found bool
+
+func Has(roles []string, role string) Found {
+	if role == "" {
+		return false
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+`)
+
+	assert.Empty(t, violations)
+}
+
+func TestGoModernMapKeys(t *testing.T) {
+	violations := analyzeGoModern(t, "", `package modern
+
+func Names(m map[string]int) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
+
+func IDs(m map[int]bool) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, "names := slices.Collect(maps.Keys(m))", violations[0].Context["replacement"])
+	assert.Equal(t, "ids := slices.AppendSeq(make([]int, 0, len(m)), maps.Keys(m))", violations[1].Context["replacement"],
+		"a made slice stays non-nil for an empty map")
+	assert.Equal(t, []string{"maps", "slices"}, violations[0].Context["imports"])
+}
+
+// The go directive bounds what is suggested: range-over-int needs 1.22 and
+// maps.Keys needs 1.23.
+func TestGoModernRespectsModuleGoVersion(t *testing.T) {
+	violations := analyzeGoModern(t, "module example.com/old\n\ngo 1.21\n", `package modern
+
+func Names(m map[string]int) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	for i := 0; i < 3; i++ {
+		names = append(names, "")
+	}
+	return names
+}
+
+func Limit(n, limit int) int {
+	if limit < n {
+		n = limit
+	}
+	return n
+}
+`)
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, "builtin-min-max", violations[0].Context["pattern"])
+}
@@ -0,0 +1,220 @@
+package patterns
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewGoroutineIgnoresContextRule())
+}
+
+// GoroutineIgnoresContextRule detects goroutines that are handed a
+// context.Context and loop without ever looking at it:
+//
+//	go func() {
+//		for {
+//			job := <-jobs
+//			process(job)      // ctx cancelled? the loop never asks
+//		}
+//	}()
+//
+// The context is there to stop the goroutine; a loop that blocks on channels
+// or sleeps without selecting on ctx.Done() keeps running after the request or
+// the service that started it is gone.
+//
+// A loop observes the context when it waits on ctx.Done(), calls ctx.Err(),
+// or passes ctx to a call. Not flagged: loops that never block (they end on
+// their own), loops driven by a receive with ok or by range over a channel
+// (they end when the sender closes it — timer channels excepted, nobody
+// closes them), and goroutines that are not given a context.
+type GoroutineIgnoresContextRule struct {
+	*rules.BaseRule
+}
+
+// NewGoroutineIgnoresContextRule creates the rule
+func NewGoroutineIgnoresContextRule() *GoroutineIgnoresContextRule {
+	return &GoroutineIgnoresContextRule{
+		BaseRule: rules.NewBaseRule(
+			"goroutine-ignores-context",
+			"patterns",
+			"Detects goroutines that capture a context.Context but loop without checking ctx.Done()",
+			core.SeverityMedium,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op because this rule requires shared package SSA.
+func (r *GoroutineIgnoresContextRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *GoroutineIgnoresContextRule) RequiresSSA() bool { return true }
+
+// AnalyzeGoProject inspects the blocking loops of every goroutine that is
+// given a context.
+func (r *GoroutineIgnoresContextRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	project, err := newSSAProject(ctx, "goroutine ignores context")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, g := range project.goroutines() {
+		if !capturesContext(g.body) {
+			continue
+		}
+		for _, loop := range loopBlocks(g.body) {
+			blocking := blockingOp(loop)
+			if blocking == nil || closableLoop(loop) || observesContext(loop) {
+				continue
+			}
+			v := project.violation(r.CreateViolation, g.stmt.Pos(),
+				fmt.Sprintf("goroutine loops without checking its context: the loop blocking at line %d keeps running after ctx is cancelled",
+					project.line(blocking.Pos())))
+			if v == nil {
+				break
+			}
+			v.WithSuggestion("Select on ctx.Done() next to the blocking operation and return when it fires")
+			v.WithContext("loop_line", project.line(blocking.Pos()))
+			violations = append(violations, v)
+			break
+		}
+	}
+	return violations, nil
+}
+
+// capturesContext reports whether a goroutine body is given a context: as a
+// parameter or a captured variable.
+func capturesContext(fn *ssa.Function) bool {
+	for _, param := range fn.Params {
+		if isContextArg(param.Type()) {
+			return true
+		}
+	}
+	for _, free := range fn.FreeVars {
+		t := free.Type()
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if isContextArg(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockingOp returns the first operation of a loop that can wait: a channel
+// send or receive, a blocking select, or time.Sleep.
+func blockingOp(loop []*ssa.BasicBlock) ssa.Instruction {
+	for _, block := range loop {
+		for _, instr := range block.Instrs {
+			switch instr := instr.(type) {
+			case *ssa.Send:
+				return instr
+			case *ssa.UnOp:
+				if instr.Op == token.ARROW {
+					return instr
+				}
+			case *ssa.Select:
+				if instr.Blocking {
+					return instr
+				}
+			case *ssa.Call:
+				if calledFunc(&instr.Call) == "time.Sleep" {
+					return instr
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// closableLoop reports whether a loop receives with ok, as range over a
+// channel does, from a channel its sender can close.
+func closableLoop(loop []*ssa.BasicBlock) bool {
+	for _, block := range loop {
+		for _, instr := range block.Instrs {
+			if recv, ok := instr.(*ssa.UnOp); ok && recv.Op == token.ARROW && recv.CommaOk && !timerChannel(recv.X) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timerChannel reports whether a channel comes from time.Tick, time.After or
+// the C field of a time.Ticker or time.Timer, none of which is ever closed.
+func timerChannel(ch ssa.Value) bool {
+	switch ch := ch.(type) {
+	case *ssa.Call:
+		name := calledFunc(&ch.Call)
+		return name == "time.Tick" || name == "time.After"
+	case *ssa.UnOp:
+		field, ok := ch.X.(*ssa.FieldAddr)
+		if !ok || ch.Op != token.MUL {
+			return false
+		}
+		ptr, ok := field.X.Type().Underlying().(*types.Pointer)
+		if !ok {
+			return false
+		}
+		named, ok := ptr.Elem().(*types.Named)
+		return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" &&
+			(named.Obj().Name() == "Ticker" || named.Obj().Name() == "Timer")
+	}
+	return false
+}
+
+// observesContext reports whether a loop calls Done or Err on a context,
+// waits on a channel a Done call returned, or passes a context to a call. Any
+// context counts, the one the goroutine captured or one derived from it.
+func observesContext(loop []*ssa.BasicBlock) bool {
+	for _, block := range loop {
+		for _, instr := range block.Instrs {
+			switch instr := instr.(type) {
+			case *ssa.Select:
+				for _, state := range instr.States {
+					if isDoneCall(state.Chan) {
+						return true
+					}
+				}
+			case *ssa.UnOp:
+				if instr.Op == token.ARROW && isDoneCall(instr.X) {
+					return true
+				}
+			case ssa.CallInstruction:
+				if isDoneCall(instr.Value()) || checksContext(instr.Common()) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isDoneCall reports whether a value is the result of ctx.Done().
+func isDoneCall(value ssa.Value) bool {
+	call, ok := value.(*ssa.Call)
+	return ok && call.Call.IsInvoke() && call.Call.Method.Name() == "Done" && isContextArg(call.Call.Value.Type())
+}
+
+// checksContext reports whether a call asks a context for its error or
+// passes a context on.
+func checksContext(common *ssa.CallCommon) bool {
+	if common.IsInvoke() && isContextArg(common.Value.Type()) && common.Method.Name() == "Err" {
+		return true
+	}
+	for _, arg := range common.Args {
+		if isContextArg(arg.Type()) {
+			return true
+		}
+	}
+	return false
+}
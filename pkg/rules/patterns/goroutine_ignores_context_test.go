@@ -0,0 +1,128 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeContextLoops(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.ProjectWithSSA(t, map[string]string{"worker/worker.go": source})
+	violations, err := NewGoroutineIgnoresContextRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestGoroutineIgnoresContextRule_Metadata(t *testing.T) {
+	rule := NewGoroutineIgnoresContextRule()
+	assert.Equal(t, "goroutine-ignores-context", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityMedium, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+}
+
+func TestGoroutineIgnoresContextRule_Detection(t *testing.T) {
+	violations := analyzeContextLoops(t, `package worker
+
+import (
+	"context"
+	"time"
+)
+
+func Start(ctx context.Context, jobs chan int) {
+	go func() {
+		started(ctx)
+		for {
+			job := <-jobs
+			process(job)
+		}
+	}()
+	go poll(ctx)
+}
+
+func started(ctx context.Context) {}
+
+func process(job int) {}
+
+func poll(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	for range ticker.C {
+		refresh()
+	}
+}
+
+func refresh() {}
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, 9, violations[0].Line)
+	assert.Equal(t, 12, violations[0].Context["loop_line"])
+	assert.Contains(t, violations[0].Message, "line 12")
+	assert.Equal(t, 16, violations[1].Line)
+}
+
+func TestGoroutineIgnoresContextRule_AcceptsObservedContext(t *testing.T) {
+	violations := analyzeContextLoops(t, `package worker
+
+import (
+	"context"
+	"time"
+)
+
+func Start(ctx context.Context, jobs chan int) {
+	go func() {
+		for {
+			select {
+			case job := <-jobs:
+				process(job)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			handle(ctx, <-jobs)
+		}
+	}()
+	go func() {
+		done := ctx.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+	go func() {
+		for ctx.Err() == nil {
+			time.Sleep(time.Second)
+		}
+	}()
+	go func() {
+		for job := range jobs {
+			process(job)
+		}
+		handle(ctx, 0)
+	}()
+	go func() {
+		for i := 0; i < 3; i++ {
+			process(i)
+		}
+		handle(ctx, 0)
+	}()
+}
+
+func handle(ctx context.Context, job int) {}
+
+func process(job int) {}
+`)
+
+	assert.Empty(t, violations)
+}
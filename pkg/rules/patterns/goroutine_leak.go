@@ -0,0 +1,199 @@
+package patterns
+
+import (
+	"fmt"
+	"go/constant"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewGoroutineLeakRule())
+}
+
+// GoroutineLeakRule detects goroutines that block forever sending on an
+// unbuffered channel because the function that made the channel can return
+// without receiving from it:
+//
+//	ch := make(chan result)
+//	go func() { ch <- fetch() }()
+//	select {
+//	case r := <-ch:
+//		return r, nil
+//	case <-ctx.Done():
+//		return result{}, ctx.Err()   // nobody receives: the goroutine leaks
+//	}
+//
+// An unbuffered send completes only when someone receives. Once the receiver
+// has returned, the goroutine and everything it references stay in memory for
+// the life of the process — one per timed-out request.
+//
+// Not flagged: buffered channels, receives in a loop (taken to receive once
+// per started goroutine), sends that are a case of a select with another way
+// out, channels also received from outside the function that made them, and
+// channels that go where they cannot be followed (fields, maps, interfaces).
+type GoroutineLeakRule struct {
+	*rules.BaseRule
+}
+
+// NewGoroutineLeakRule creates the rule
+func NewGoroutineLeakRule() *GoroutineLeakRule {
+	return &GoroutineLeakRule{
+		BaseRule: rules.NewBaseRule(
+			"goroutine-leak",
+			"patterns",
+			"Detects goroutines blocked forever sending on an unbuffered channel whose receiver can return without receiving",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op because this rule requires shared package SSA.
+func (r *GoroutineLeakRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *GoroutineLeakRule) RequiresSSA() bool { return true }
+
+// AnalyzeGoProject follows every unbuffered channel into the goroutines that
+// send on it and searches the maker for a return that skips the receive.
+func (r *GoroutineLeakRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	project, err := newSSAProject(ctx, "goroutine leak")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fn := range project.functions {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				made, ok := instr.(*ssa.MakeChan)
+				if !ok || !unbuffered(made) {
+					continue
+				}
+				violations = append(violations, r.analyzeChannel(project, made)...)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// analyzeChannel reports the go statements whose goroutine can be left
+// sending on a channel.
+func (r *GoroutineLeakRule) analyzeChannel(project *ssaProject, made *ssa.MakeChan) []*core.Violation {
+	ops, complete := chanOps(made)
+	if !complete {
+		return nil
+	}
+	maker := made.Parent()
+	receives := make(map[ssa.Instruction]bool)
+	var blocked []chanOp
+	for _, op := range ops {
+		switch {
+		case op.kind == chanRecv && op.instr.Parent() != maker:
+			return nil
+		case op.kind == chanRecv:
+			receives[op.instr] = true
+		case op.kind == chanSend && op.spawn != nil && !op.selective:
+			blocked = append(blocked, op)
+		}
+	}
+	if len(receives) == 0 {
+		return nil
+	}
+	// A loop that receives, such as one receive per started goroutine, is
+	// trusted to receive enough: the exit of its counting loop is no leak.
+	receiving := make(map[*ssa.BasicBlock]bool)
+	for _, loop := range loopBlocks(maker) {
+		if loopReceives(loop, receives) {
+			for _, block := range loop {
+				receiving[block] = true
+			}
+		}
+	}
+
+	var violations []*core.Violation
+	reported := make(map[*ssa.Go]bool)
+	for _, op := range blocked {
+		if reported[op.spawn] || op.spawn.Parent() != maker {
+			continue
+		}
+		reported[op.spawn] = true
+		ret := returnAvoiding(op.spawn, func(instr ssa.Instruction) (bool, []*ssa.BasicBlock) {
+			if receiving[instr.Block()] {
+				return true, nil
+			}
+			if !receives[instr] {
+				return false, nil
+			}
+			sel, ok := instr.(*ssa.Select)
+			if !ok {
+				return true, nil
+			}
+			return true, otherCases(sel, made)
+		})
+		if ret == nil {
+			continue
+		}
+		message := fmt.Sprintf("goroutine blocks forever sending on an unbuffered channel when %s returns at line %d without receiving",
+			maker.Name(), project.line(ret.Pos()))
+		v := project.violation(r.CreateViolation, op.spawn.Pos(), message)
+		if v == nil {
+			continue
+		}
+		v.WithSuggestion("Give the channel a buffer of one (make(chan T, 1)) so the send completes, or select on ctx.Done() next to the send")
+		v.WithContext("send_line", project.line(op.instr.Pos()))
+		v.WithContext("return_line", project.line(ret.Pos()))
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// otherCases returns where a select receiving from the channel continues
+// when it takes another case or its default.
+func otherCases(sel *ssa.Select, made *ssa.MakeChan) []*ssa.BasicBlock {
+	cases, fallback := selectCases(sel)
+	blocks := []*ssa.BasicBlock{fallback}
+	for i, state := range sel.States {
+		if !receivesFrom(state.Chan, made) {
+			blocks = append(blocks, cases[i])
+		}
+	}
+	return blocks
+}
+
+// receivesFrom reports whether a select state reads the channel: through the
+// value itself, or a load of the variable holding it.
+func receivesFrom(value ssa.Value, made *ssa.MakeChan) bool {
+	if value == made {
+		return true
+	}
+	cell := stripLoads(value)
+	for _, instr := range referrersOf(made) {
+		if store, ok := instr.(*ssa.Store); ok && store.Addr == cell {
+			return true
+		}
+	}
+	return false
+}
+
+// loopReceives reports whether a loop receives from the channel outside a
+// select.
+func loopReceives(loop []*ssa.BasicBlock, receives map[ssa.Instruction]bool) bool {
+	for _, block := range loop {
+		for _, instr := range block.Instrs {
+			if _, ok := instr.(*ssa.Select); !ok && receives[instr] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func unbuffered(made *ssa.MakeChan) bool {
+	size, ok := made.Size.(*ssa.Const)
+	return ok && size.Value != nil && size.Value.Kind() == constant.Int && size.Int64() == 0
+}
@@ -0,0 +1,151 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeGoroutineLeaks(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.ProjectWithSSA(t, map[string]string{"fetch/fetch.go": source})
+	violations, err := NewGoroutineLeakRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestGoroutineLeakRule_Metadata(t *testing.T) {
+	rule := NewGoroutineLeakRule()
+	assert.Equal(t, "goroutine-leak", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+	assert.Empty(t, rule.AnalyzeFile(&core.FileContext{}))
+}
+
+// The timeout case returns and nobody is left to receive what the worker sends.
+func TestGoroutineLeakRule_SelectTimeout(t *testing.T) {
+	violations := analyzeGoroutineLeaks(t, `package fetch
+
+import (
+	"context"
+	"time"
+)
+
+func Fetch(ctx context.Context) (int, error) {
+	ch := make(chan int)
+	go func() {
+		ch <- compute()
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-time.After(time.Second):
+		return 0, context.DeadlineExceeded
+	}
+}
+
+func compute() int { return 1 }
+`)
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, 10, violations[0].Line)
+	assert.Contains(t, violations[0].Message, "line 17")
+	assert.Equal(t, 11, violations[0].Context["send_line"])
+}
+
+// An error return between the go statement and the receive leaks the same way.
+func TestGoroutineLeakRule_EarlyReturnBeforeReceive(t *testing.T) {
+	violations := analyzeGoroutineLeaks(t, `package fetch
+
+func Fetch(check func() error) (int, error) {
+	ch := make(chan int)
+	go worker(ch)
+	if err := check(); err != nil {
+		return 0, err
+	}
+	return <-ch, nil
+}
+
+func worker(out chan int) {
+	out <- 1
+}
+`)
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, 5, violations[0].Line)
+	assert.Equal(t, 13, violations[0].Context["send_line"])
+}
+
+func TestGoroutineLeakRule_AcceptsSafeChannels(t *testing.T) {
+	violations := analyzeGoroutineLeaks(t, `package fetch
+
+import (
+	"context"
+	"time"
+)
+
+func Buffered() (int, error) {
+	ch := make(chan int, 1)
+	go func() { ch <- 1 }()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-time.After(time.Second):
+		return 0, context.DeadlineExceeded
+	}
+}
+
+func SenderSelects(ctx context.Context) (int, error) {
+	ch := make(chan int)
+	go func() {
+		select {
+		case ch <- 1:
+		case <-ctx.Done():
+		}
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func AlwaysReceives() int {
+	ch := make(chan int)
+	go func() { ch <- 1 }()
+	return <-ch
+}
+
+func FanIn(urls []string) int {
+	ch := make(chan int)
+	for range urls {
+		go func() { ch <- 1 }()
+	}
+	total := 0
+	for range urls {
+		total += <-ch
+	}
+	return total
+}
+
+func TimerInLoop(ctx context.Context) int {
+	ch := make(chan int)
+	go func() { ch <- 1 }()
+	for {
+		select {
+		case v := <-ch:
+			return v
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+`)
+
+	assert.Empty(t, violations)
+}
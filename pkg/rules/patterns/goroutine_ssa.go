@@ -0,0 +1,528 @@
+package patterns
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// ssaProject is the SSA of the project's own functions — closures included,
+// synthetic wrappers and files the config excludes left out — for the
+// goroutine and channel rules.
+type ssaProject struct {
+	ctx       *core.GoProjectContext
+	files     map[string]*core.FileContext
+	functions []*ssa.Function
+}
+
+// newSSAProject collects the project's functions in source order.
+func newSSAProject(ctx *core.GoProjectContext, rule string) (*ssaProject, error) {
+	if ctx == nil {
+		return nil, errors.New(rule + ": nil Go project context")
+	}
+	if ctx.Program == nil {
+		return nil, errors.New(rule + ": Go project has no SSA program")
+	}
+	if ctx.FileSet == nil {
+		return nil, errors.New(rule + ": project has no file set for source positions")
+	}
+	project := &ssaProject{ctx: ctx, files: make(map[string]*core.FileContext)}
+	packages := make(map[*ssa.Package]bool)
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.SSA == nil {
+			continue
+		}
+		packages[pkg.SSA] = true
+		for _, file := range pkg.Files {
+			project.files[filepath.Clean(file.Path)] = file
+		}
+	}
+	for fn := range ssautil.AllFunctions(ctx.Program) {
+		if fn.Synthetic != "" || len(fn.Blocks) == 0 || !packages[fn.Pkg] || project.file(fn.Pos()) == nil {
+			continue
+		}
+		project.functions = append(project.functions, fn)
+	}
+	sort.Slice(project.functions, func(i, j int) bool {
+		return project.functions[i].Pos() < project.functions[j].Pos()
+	})
+	return project, nil
+}
+
+// file returns the file context of a position, or nil outside the analyzed
+// files.
+func (p *ssaProject) file(pos token.Pos) *core.FileContext {
+	if !pos.IsValid() {
+		return nil
+	}
+	return p.files[filepath.Clean(p.ctx.FileSet.PositionFor(pos, false).Filename)]
+}
+
+// line returns the line of a position.
+func (p *ssaProject) line(pos token.Pos) int {
+	return p.ctx.FileSet.PositionFor(pos, false).Line
+}
+
+// violation creates a violation at pos, or returns nil when pos is outside
+// the analyzed files.
+func (p *ssaProject) violation(create func(file string, line int, message string) *core.Violation, pos token.Pos, message string) *core.Violation {
+	fileCtx := p.file(pos)
+	if fileCtx == nil {
+		return nil
+	}
+	position := p.ctx.FileSet.PositionFor(pos, false)
+	v := create(fileCtx.RelPath, position.Line, message)
+	v.WithColumn(position.Column)
+	v.WithCode(strings.TrimSpace(fileCtx.GetLine(position.Line)))
+	return v
+}
+
+// pathStep is one step of the path a finding reports: where it happens and
+// what happens there.
+type pathStep struct {
+	pos  token.Pos
+	text string
+}
+
+// describe renders a path as "bank.go:12 Transfer locks Ledger.mu →
+// bank.go:14 Transfer calls debit → …".
+func (p *ssaProject) describe(steps []pathStep) string {
+	parts := make([]string, len(steps))
+	for i, step := range steps {
+		position := p.ctx.FileSet.PositionFor(step.pos, false)
+		file := filepath.Base(position.Filename)
+		if fileCtx := p.file(step.pos); fileCtx != nil {
+			file = fileCtx.RelPath
+		}
+		parts[i] = fmt.Sprintf("%s:%d %s", file, position.Line, step.text)
+	}
+	return strings.Join(parts, " → ")
+}
+
+// goroutines returns the go statements of the project with the function each
+// one runs: a closure or a function of the project, never a dynamic call.
+func (p *ssaProject) goroutines() []goroutine {
+	var result []goroutine
+	for _, fn := range p.functions {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				stmt, ok := instr.(*ssa.Go)
+				if !ok {
+					continue
+				}
+				if body := goroutineBody(stmt); body != nil && len(body.Blocks) > 0 {
+					result = append(result, goroutine{stmt: stmt, body: body})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// goroutine is a go statement and the function it starts.
+type goroutine struct {
+	stmt *ssa.Go
+	body *ssa.Function
+}
+
+func goroutineBody(stmt *ssa.Go) *ssa.Function {
+	switch callee := stmt.Call.Value.(type) {
+	case *ssa.MakeClosure:
+		fn, _ := callee.Fn.(*ssa.Function)
+		return fn
+	case *ssa.Function:
+		return callee
+	}
+	return nil
+}
+
+// chanOpKind is what an operation does with a channel.
+type chanOpKind int
+
+const (
+	chanSend chanOpKind = iota
+	chanRecv
+	chanClose
+)
+
+// chanOp is one operation on a channel.
+type chanOp struct {
+	kind  chanOpKind
+	instr ssa.Instruction
+	// selective is set for a case of a select that has other cases or a
+	// default, so the operation may never happen.
+	selective bool
+	// spawn is the go statement of the maker that started the goroutine the
+	// operation runs in, nil when it runs in the maker's goroutine.
+	spawn *ssa.Go
+}
+
+// chanValue is a value that holds the channel, or with cell set, points to
+// a variable that holds it.
+type chanValue struct {
+	value ssa.Value
+	cell  bool
+	spawn *ssa.Go
+}
+
+// chanOps follows a channel from the make that creates it into the
+// variables, closures and project functions it is handed to, and returns
+// every send, receive and close. Channels stored in fields, maps or
+// interfaces are followed no further; complete reports whether that never
+// happened.
+func chanOps(made *ssa.MakeChan) (ops []chanOp, complete bool) {
+	complete = true
+	seen := make(map[chanValue]bool)
+	work := []chanValue{{value: made}}
+	push := func(v chanValue) {
+		if !seen[v] {
+			seen[v] = true
+			work = append(work, v)
+		}
+	}
+	for len(work) > 0 {
+		current := work[len(work)-1]
+		work = work[:len(work)-1]
+		referrers := current.value.Referrers()
+		if referrers == nil {
+			continue
+		}
+		for _, instr := range *referrers {
+			if current.cell {
+				complete = followCell(current, instr, push) && complete
+				continue
+			}
+			op, ok := chanOperation(current, instr)
+			if ok {
+				ops = append(ops, op)
+				continue
+			}
+			complete = followChan(current, instr, push) && complete
+		}
+	}
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].instr.Pos() < ops[j].instr.Pos() })
+	return ops, complete
+}
+
+// chanOperation returns the operation instr performs on the channel, if any.
+func chanOperation(current chanValue, instr ssa.Instruction) (chanOp, bool) {
+	op := chanOp{instr: instr, spawn: current.spawn}
+	switch instr := instr.(type) {
+	case *ssa.Send:
+		if instr.Chan != current.value {
+			return op, false
+		}
+		op.kind = chanSend
+	case *ssa.UnOp:
+		if instr.Op != token.ARROW {
+			return op, false
+		}
+		op.kind = chanRecv
+	case *ssa.Select:
+		op.selective = !instr.Blocking || len(instr.States) > 1
+		for _, state := range instr.States {
+			if state.Chan == current.value {
+				op.kind = chanRecv
+				if state.Dir == types.SendOnly {
+					op.kind = chanSend
+				}
+			}
+		}
+	case ssa.CallInstruction:
+		builtin, ok := instr.Common().Value.(*ssa.Builtin)
+		if !ok || builtin.Name() != "close" {
+			return op, false
+		}
+		op.kind = chanClose
+	default:
+		return op, false
+	}
+	return op, true
+}
+
+// followChan pushes the values instr copies the channel into; it returns
+// false when the channel goes somewhere it cannot be followed.
+func followChan(current chanValue, instr ssa.Instruction, push func(chanValue)) bool {
+	switch instr := instr.(type) {
+	case *ssa.Store:
+		if instr.Val != current.value {
+			return true
+		}
+		if _, ok := instr.Addr.(*ssa.Alloc); ok {
+			push(chanValue{value: instr.Addr, cell: true, spawn: current.spawn})
+			return true
+		}
+		return false
+	case *ssa.ChangeType:
+		push(chanValue{value: instr, spawn: current.spawn})
+		return true
+	case *ssa.Phi:
+		push(chanValue{value: instr, spawn: current.spawn})
+		return true
+	case *ssa.MakeClosure:
+		followBindings(current, instr, push)
+		return true
+	case ssa.CallInstruction:
+		return followArgs(current, instr, push)
+	case *ssa.If, *ssa.BinOp, *ssa.DebugRef:
+		return true
+	}
+	return false
+}
+
+// followCell follows the loads of a variable holding the channel and the
+// closures capturing it.
+func followCell(current chanValue, instr ssa.Instruction, push func(chanValue)) bool {
+	switch instr := instr.(type) {
+	case *ssa.UnOp:
+		if instr.Op == token.MUL {
+			push(chanValue{value: instr, spawn: current.spawn})
+		}
+		return true
+	case *ssa.MakeClosure:
+		followBindings(current, instr, push)
+		return true
+	case *ssa.Store:
+		// Assigning the variable another channel is a different channel.
+		return instr.Addr == current.value
+	case *ssa.DebugRef:
+		return true
+	}
+	return false
+}
+
+// followBindings pushes the free variables of a closure bound to the current
+// value; the closure runs in a goroutine when a go statement starts it.
+func followBindings(current chanValue, closure *ssa.MakeClosure, push func(chanValue)) {
+	fn, ok := closure.Fn.(*ssa.Function)
+	if !ok {
+		return
+	}
+	spawn := current.spawn
+	if stmt := startedBy(closure); stmt != nil && spawn == nil {
+		spawn = stmt
+	}
+	for i, binding := range closure.Bindings {
+		if binding == current.value && i < len(fn.FreeVars) {
+			push(chanValue{value: fn.FreeVars[i], cell: current.cell, spawn: spawn})
+		}
+	}
+}
+
+// followArgs pushes the parameters of a project function receiving the
+// channel as an argument.
+func followArgs(current chanValue, call ssa.CallInstruction, push func(chanValue)) bool {
+	common := call.Common()
+	fn := common.StaticCallee()
+	if fn == nil || len(fn.Blocks) == 0 || common.IsInvoke() {
+		return false
+	}
+	spawn := current.spawn
+	if stmt, ok := call.(*ssa.Go); ok && spawn == nil {
+		spawn = stmt
+	}
+	for i, arg := range common.Args {
+		if arg == current.value && i < len(fn.Params) {
+			push(chanValue{value: fn.Params[i], spawn: spawn})
+		}
+	}
+	return true
+}
+
+// startedBy returns the go statement that runs a closure, if any.
+func startedBy(closure *ssa.MakeClosure) *ssa.Go {
+	referrers := closure.Referrers()
+	if referrers == nil {
+		return nil
+	}
+	for _, instr := range *referrers {
+		if stmt, ok := instr.(*ssa.Go); ok && stmt.Call.Value == closure {
+			return stmt
+		}
+	}
+	return nil
+}
+
+// selectCases returns the block each case of a select continues in, in the
+// order of its states, and the block of the default case; blocks the select
+// never reaches are nil.
+func selectCases(sel *ssa.Select) ([]*ssa.BasicBlock, *ssa.BasicBlock) {
+	cases := make([]*ssa.BasicBlock, len(sel.States))
+	var index ssa.Value
+	for _, instr := range referrersOf(sel) {
+		if extract, ok := instr.(*ssa.Extract); ok && extract.Index == 0 {
+			index = extract
+		}
+	}
+	if index == nil {
+		return cases, nil
+	}
+	var last *ssa.If
+	for _, instr := range referrersOf(index) {
+		compare, ok := instr.(*ssa.BinOp)
+		if !ok || compare.Op != token.EQL {
+			continue
+		}
+		constant, ok := compare.Y.(*ssa.Const)
+		if !ok {
+			continue
+		}
+		state := int(constant.Int64())
+		for _, use := range referrersOf(compare) {
+			if branch, ok := use.(*ssa.If); ok && state >= 0 && state < len(cases) {
+				cases[state] = branch.Block().Succs[0]
+				if state == len(cases)-1 {
+					last = branch
+				}
+			}
+		}
+	}
+	if sel.Blocking || last == nil {
+		return cases, nil
+	}
+	return cases, last.Block().Succs[1]
+}
+
+func referrersOf(value ssa.Value) []ssa.Instruction {
+	if referrers := value.Referrers(); referrers != nil {
+		return *referrers
+	}
+	return nil
+}
+
+// loopBlocks returns the blocks of fn that lie on a cycle, grouped by loop:
+// each group is a strongly connected component of the control flow graph.
+func loopBlocks(fn *ssa.Function) [][]*ssa.BasicBlock {
+	index := make(map[*ssa.BasicBlock]int)
+	low := make(map[*ssa.BasicBlock]int)
+	onStack := make(map[*ssa.BasicBlock]bool)
+	var stack []*ssa.BasicBlock
+	var loops [][]*ssa.BasicBlock
+	var visit func(block *ssa.BasicBlock)
+	visit = func(block *ssa.BasicBlock) {
+		index[block] = len(index)
+		low[block] = index[block]
+		stack = append(stack, block)
+		onStack[block] = true
+		for _, succ := range block.Succs {
+			if _, seen := index[succ]; !seen {
+				visit(succ)
+				low[block] = min(low[block], low[succ])
+			} else if onStack[succ] {
+				low[block] = min(low[block], index[succ])
+			}
+		}
+		if low[block] != index[block] {
+			return
+		}
+		var component []*ssa.BasicBlock
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == block {
+				break
+			}
+		}
+		if len(component) > 1 || slices.Contains(block.Succs, block) {
+			sort.Slice(component, func(i, j int) bool { return component[i].Index < component[j].Index })
+			loops = append(loops, component)
+		}
+	}
+	for _, block := range fn.Blocks {
+		if _, seen := index[block]; !seen {
+			visit(block)
+		}
+	}
+	sort.Slice(loops, func(i, j int) bool { return loops[i][0].Index < loops[j][0].Index })
+	return loops
+}
+
+// returnAvoiding searches the paths from just after start to a return of its
+// function and returns the first return reached without passing a block
+// covered reports; panics end a path. A select on a covered path may hand
+// back the blocks it continues in when it does not cover every case.
+func returnAvoiding(start ssa.Instruction, covers func(instr ssa.Instruction) (covered bool, continues []*ssa.BasicBlock)) *ssa.Return {
+	type position struct {
+		block *ssa.BasicBlock
+		from  int
+	}
+	startBlock := start.Block()
+	from := 0
+	for i, instr := range startBlock.Instrs {
+		if instr == start {
+			from = i + 1
+		}
+	}
+	seen := make(map[*ssa.BasicBlock]bool)
+	work := []position{{startBlock, from}}
+	for len(work) > 0 {
+		current := work[len(work)-1]
+		work = work[:len(work)-1]
+		stopped := false
+		for _, instr := range current.block.Instrs[current.from:] {
+			if ret, ok := instr.(*ssa.Return); ok {
+				return ret
+			}
+			covered, continues := covers(instr)
+			if !covered {
+				continue
+			}
+			for _, block := range continues {
+				if block != nil && !seen[block] {
+					seen[block] = true
+					work = append(work, position{block, 0})
+				}
+			}
+			stopped = true
+			break
+		}
+		if stopped {
+			continue
+		}
+		for _, succ := range current.block.Succs {
+			if !seen[succ] {
+				seen[succ] = true
+				work = append(work, position{succ, 0})
+			}
+		}
+	}
+	return nil
+}
+
+// calledFunc returns the full name of the function a call calls statically,
+// "context.WithCancel" or "(*sync.WaitGroup).Add", or "".
+func calledFunc(call *ssa.CallCommon) string {
+	if call.IsInvoke() {
+		return ""
+	}
+	if fn := call.StaticCallee(); fn != nil && fn.Object() != nil {
+		if obj, ok := fn.Object().(*types.Func); ok {
+			return obj.FullName()
+		}
+	}
+	return ""
+}
+
+// stripLoads returns the variable a value is loaded from, through any number
+// of loads: the free variable wg for *wg.
+func stripLoads(value ssa.Value) ssa.Value {
+	for {
+		load, ok := value.(*ssa.UnOp)
+		if !ok || load.Op != token.MUL {
+			return value
+		}
+		value = load.X
+	}
+}
@@ -36,97 +36,29 @@ func (r *HTTPBodyCloseRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation
 		return nil
 	}
 	httpAliases := httpImportAliases(ctx)
+	// resp, покидающий функцию — return resp или аргумент вызова, который сам его
+	// закрывает или не виден отсюда, — передан по ответственности (семантика bodyclose).
+	kinds := []*resourceKind{{
+		name:            "HTTP response body",
+		acquires:        func(call *ast.CallExpr) bool { return isHTTPResponseCall(call, httpAliases) },
+		releases:        []string{"Body.Close"},
+		returnTransfers: true,
+	}}
+	handOff := sameFileHandOff(ctx.GoAST)
 	var violations []*core.Violation
 	for _, declaration := range ctx.GoAST.Decls {
 		function, ok := declaration.(*ast.FuncDecl)
 		if !ok || function.Body == nil {
 			continue
 		}
-		r.checkFunction(ctx, function.Body, httpAliases, &violations)
-	}
-	return violations
-}
-
-func (r *HTTPBodyCloseRule) checkFunction(
-	ctx *core.FileContext,
-	body *ast.BlockStmt,
-	httpAliases map[string]struct{},
-	violations *[]*core.Violation,
-) {
-	// Track response variable names
-	responseVars := make(map[string]int) // varName -> line
-
-	walkReachableHTTPBodyStatements(body.List, func(n ast.Node) {
-		ast.Inspect(n, func(n ast.Node) bool {
-			// Skip nested function literals
-			if _, ok := n.(*ast.FuncLit); ok {
-				return false
-			}
-
-			assign, ok := n.(*ast.AssignStmt)
-			if !ok {
-				return true
-			}
-
-			// Check for http response assignments
-			// resp, err := http.Get(...) or resp, err := client.Do(...)
-			if len(assign.Lhs) >= 1 && len(assign.Rhs) == 1 {
-				if isHTTPResponseCall(assign.Rhs[0], httpAliases) {
-					if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
-						responseVars[ident.Name] = r.getLineFromNode(ctx, assign)
-					}
-				}
-			}
-
-			return true
-		})
-	})
-
-	if len(responseVars) == 0 {
-		return
-	}
-
-	// Check for defer resp.Body.Close() or resp.Body.Close()
-	// Note: we DO need to check inside FuncLit because defer func() { resp.Body.Close() }() is common
-	closedVars := make(map[string]bool)
-	// resp, покидающий функцию — return resp или аргумент любого вызова, — передан
-	// по ответственности: закрывать его будет получатель (семантика bodyclose).
-	escapedVars := make(map[string]bool)
-
-	walkReachableHTTPBodyStatements(body.List, func(n ast.Node) {
-		ast.Inspect(n, func(n ast.Node) bool {
-			switch node := n.(type) {
-			case *ast.CallExpr:
-				if varName := r.getBodyCloseVar(node); varName != "" {
-					closedVars[varName] = true
-				}
-				for _, arg := range node.Args {
-					if ident, ok := ast.Unparen(arg).(*ast.Ident); ok {
-						escapedVars[ident.Name] = true
-					}
-				}
-			case *ast.ReturnStmt:
-				for _, result := range node.Results {
-					if ident, ok := ast.Unparen(result).(*ast.Ident); ok {
-						escapedVars[ident.Name] = true
-					}
-				}
-			}
-			return true
-		})
-	})
-
-	// Report unclosed responses
-	for varName, line := range responseVars {
-		if !closedVars[varName] && !escapedVars[varName] {
-			v := r.CreateViolation(ctx.RelPath, line, "HTTP response body not closed - resource leak")
-			v.WithCode(ctx.GetLine(line))
-			v.WithSuggestion("Add defer " + varName + ".Body.Close() after nil check")
+		for _, leak := range analyzeResourceLifecycles(function.Body, kinds, handOff) {
+			v := leak.violation(r.CreateViolation, ctx, "HTTP response body not closed - resource leak",
+				"Add defer "+leak.resource.variable+".Body.Close() after nil check")
 			v.WithContext("pattern", "http_body_leak")
-			v.WithContext("variable", varName)
-			*violations = append(*violations, v)
+			violations = append(violations, v)
 		}
 	}
+	return violations
 }
 
 type httpClientScope struct {
@@ -393,72 +325,6 @@ func (c *httpClientTypeCollector) collectExpressionIdentifiers(node ast.Node, sc
 	})
 }
 
-// httpBodyReachRule adapts the shared flow walker into a plain reachability
-// walk: the state is a single "reachable" flag and every visited node is
-// handed to the visit callback.
-type httpBodyReachRule struct {
-	visit func(ast.Node)
-}
-
-func walkReachableHTTPBodyStatements(statements []ast.Stmt, visit func(ast.Node)) {
-	walker := &flowWalker[bool, struct{}]{rule: &httpBodyReachRule{visit: visit}}
-	walker.stmtList(statements, true, struct{}{})
-}
-
-func (r *httpBodyReachRule) cloneState(state bool) bool { return state }
-
-func (r *httpBodyReachRule) joinStates(bool, bool) bool { return true }
-
-func (r *httpBodyReachRule) liveState(state bool) bool { return state }
-
-func (r *httpBodyReachRule) deadState() bool { return false }
-
-func (r *httpBodyReachRule) enterScope(_ flowScopeKind, _ ast.Node, parent struct{}, state bool) (struct{}, bool) {
-	return parent, state
-}
-
-func (r *httpBodyReachRule) leaveScope(flowScopeKind, struct{}, *flowEdges[bool]) {}
-
-func (r *httpBodyReachRule) simpleStmt(stmt ast.Stmt, state bool, _ struct{}) (bool, bool) {
-	r.visit(stmt)
-	if _, isReturn := stmt.(*ast.ReturnStmt); isReturn {
-		return false, true
-	}
-	return state, isPanicStatement(stmt)
-}
-
-func (r *httpBodyReachRule) ifCondition(stmt *ast.IfStmt, state bool, _ struct{}) (bool, bool) {
-	r.visit(stmt.Cond)
-	return state, state
-}
-
-func (r *httpBodyReachRule) flowExpr(expr ast.Expr, state bool, _ struct{}) bool {
-	r.visit(expr)
-	return state
-}
-
-func (r *httpBodyReachRule) rangeVars(_ *ast.RangeStmt, state bool, _ struct{}) bool {
-	return state
-}
-
-func (r *httpBodyReachRule) typeSwitchGuard(stmt ast.Stmt, state bool, scope struct{}) bool {
-	next, _ := r.simpleStmt(stmt, state, scope)
-	return next
-}
-
-func (r *httpBodyReachRule) caseClause(_ ast.Stmt, clause *ast.CaseClause, state bool, parent struct{}) (bool, struct{}) {
-	for _, expression := range clause.List {
-		r.visit(expression)
-	}
-	return state, parent
-}
-
-func (r *httpBodyReachRule) commClause(_ *ast.CommClause, state bool, parent struct{}) (bool, struct{}) {
-	return state, parent
-}
-
-func (r *httpBodyReachRule) normalize(*flowEdges[bool]) {}
-
 func httpImportAliases(ctx *core.FileContext) map[string]struct{} {
 	aliases := make(map[string]struct{})
 	hasNetHTTP := false
@@ -600,28 +466,3 @@ func isHTTPTypeSyntax(expr ast.Expr, typeName string, httpAliases map[string]str
 		return false
 	}
 }
-
-func (r *HTTPBodyCloseRule) getBodyCloseVar(call *ast.CallExpr) string {
-	// Looking for resp.Body.Close()
-	sel, ok := call.Fun.(*ast.SelectorExpr)
-	if !ok || sel.Sel.Name != "Close" {
-		return ""
-	}
-
-	// Check for .Body
-	bodySel, ok := sel.X.(*ast.SelectorExpr)
-	if !ok || bodySel.Sel.Name != "Body" {
-		return ""
-	}
-
-	// Get the variable name
-	if ident, ok := bodySel.X.(*ast.Ident); ok {
-		return ident.Name
-	}
-
-	return ""
-}
-
-func (r *HTTPBodyCloseRule) getLineFromNode(ctx *core.FileContext, node ast.Node) int {
-	return ctx.LineFor(node)
-}
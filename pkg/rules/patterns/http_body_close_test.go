@@ -91,6 +91,26 @@ func example() {
 `,
 			expectMatch: false,
 		},
+		{
+			name: "early return skips the close",
+			code: `package main
+
+import "net/http"
+
+func example() int {
+	resp, err := http.Get("http://example.com")
+	if err != nil {
+		return 0
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode
+	}
+	resp.Body.Close()
+	return http.StatusOK
+}
+`,
+			expectMatch: true,
+		},
 		{
 			name: "client.Do without close",
 			code: `package main
@@ -0,0 +1,337 @@
+package patterns
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// ssaLockMethods maps the SSA names of the lockMethods and unlockMethods of
+// sync.Mutex and sync.RWMutex to whether they acquire the lock.
+var ssaLockMethods = func() map[string]bool {
+	methods := make(map[string]bool)
+	for _, receiver := range []string{"(*sync.Mutex).", "(*sync.RWMutex)."} {
+		for name := range lockMethodNames {
+			methods[receiver+name] = true
+		}
+		for name := range unlockMethods {
+			methods[receiver+name] = false
+		}
+	}
+	return methods
+}()
+
+// blockingCalls are the calls that wait on the network, the database or the
+// clock.
+var blockingCalls = func() map[string]bool {
+	calls := map[string]bool{
+		"time.Sleep":                     true,
+		"net/http.Get":                   true,
+		"net/http.Head":                  true,
+		"net/http.Post":                  true,
+		"net/http.PostForm":              true,
+		"(*net/http.Client).Do":          true,
+		"(*net/http.Client).Get":         true,
+		"(*net/http.Client).Head":        true,
+		"(*net/http.Client).Post":        true,
+		"(*net/http.Client).PostForm":    true,
+		"(*database/sql.DB).Begin":       true,
+		"(*database/sql.DB).BeginTx":     true,
+		"(*database/sql.DB).Ping":        true,
+		"(*database/sql.DB).PingContext": true,
+		"(*database/sql.Tx).Commit":      true,
+	}
+	for _, receiver := range []string{"DB", "Tx", "Conn", "Stmt"} {
+		for _, method := range []string{"Exec", "ExecContext", "Query", "QueryContext", "QueryRow", "QueryRowContext"} {
+			calls["(*database/sql."+receiver+")."+method] = true
+		}
+	}
+	return calls
+}()
+
+// heldLock is a lock held at some point and where it was taken.
+type heldLock struct {
+	key string
+	pos token.Pos
+}
+
+// lockEvent is a lock acquisition, a call into the project or a blocking
+// operation, with the locks held just before it.
+type lockEvent struct {
+	instr ssa.Instruction
+	held  []heldLock
+	// lock is the lock an acquisition takes; callee the project function a
+	// call calls; blocking what a blocking operation waits on.
+	lock     string
+	callee   *ssa.Function
+	blocking string
+}
+
+// lockGraph records, for the project's functions, which locks they hold at
+// which calls, and follows the static calls to the locks and blocking
+// operations their callees reach.
+type lockGraph struct {
+	project  *ssaProject
+	events   map[*ssa.Function][]lockEvent
+	acquired map[*ssa.Function]map[string][]pathStep
+	blocks   map[*ssa.Function][]pathStep
+	visiting map[*ssa.Function]bool
+}
+
+func newLockGraph(project *ssaProject) *lockGraph {
+	return &lockGraph{
+		project:  project,
+		events:   make(map[*ssa.Function][]lockEvent),
+		acquired: make(map[*ssa.Function]map[string][]pathStep),
+		blocks:   make(map[*ssa.Function][]pathStep),
+		visiting: make(map[*ssa.Function]bool),
+	}
+}
+
+// lockKey names the mutex a Lock or Unlock call acts on by the field or
+// package variable holding it — "example.com/bank.Account.mu" — or returns ""
+// for mutexes without a stable name, such as locals and parameters.
+func lockKey(receiver ssa.Value) string {
+	switch v := stripLoads(receiver).(type) {
+	case *ssa.FieldAddr:
+		ptr, ok := v.X.Type().Underlying().(*types.Pointer)
+		if !ok {
+			return ""
+		}
+		named, ok := ptr.Elem().(*types.Named)
+		if !ok {
+			return ""
+		}
+		st, ok := named.Underlying().(*types.Struct)
+		if !ok || v.Field >= st.NumFields() || named.Obj().Pkg() == nil {
+			return ""
+		}
+		return named.Obj().Pkg().Path() + "." + named.Obj().Name() + "." + st.Field(v.Field).Name()
+	case *ssa.Global:
+		if v.Pkg == nil {
+			return ""
+		}
+		return v.Pkg.Pkg.Path() + "." + v.Name()
+	}
+	return ""
+}
+
+// lockName is the short form of a lock key: "Account.mu" or "bank.mu".
+func lockName(key string) string {
+	parts := strings.Split(key[strings.LastIndexByte(key, '/')+1:], ".")
+	return strings.Join(parts[max(0, len(parts)-2):], ".")
+}
+
+// lockEventsOf computes the locks surely held at each event of fn: a lock
+// counts as held after its Lock when no path has released it since. Deferred
+// unlocks release nothing before the function returns.
+func (g *lockGraph) lockEventsOf(fn *ssa.Function) []lockEvent {
+	if events, ok := g.events[fn]; ok {
+		return events
+	}
+	out := make(map[*ssa.BasicBlock]map[string]token.Pos)
+	for changed := true; changed; {
+		changed = false
+		for _, block := range fn.DomPreorder() {
+			held := g.transfer(block, blockEntry(block, out), nil)
+			if previous, ok := out[block]; !ok || !sameLocks(previous, held) {
+				out[block] = held
+				changed = true
+			}
+		}
+	}
+	var events []lockEvent
+	for _, block := range fn.DomPreorder() {
+		g.transfer(block, blockEntry(block, out), func(event lockEvent) {
+			events = append(events, event)
+		})
+	}
+	g.events[fn] = events
+	return events
+}
+
+// blockEntry intersects the locks held at the end of the predecessors
+// computed so far.
+func blockEntry(block *ssa.BasicBlock, out map[*ssa.BasicBlock]map[string]token.Pos) map[string]token.Pos {
+	var entry map[string]token.Pos
+	for _, pred := range block.Preds {
+		held, ok := out[pred]
+		if !ok {
+			continue
+		}
+		if entry == nil {
+			entry = make(map[string]token.Pos, len(held))
+			for key, pos := range held {
+				entry[key] = pos
+			}
+			continue
+		}
+		for key := range entry {
+			if _, ok := held[key]; !ok {
+				delete(entry, key)
+			}
+		}
+	}
+	if entry == nil {
+		entry = make(map[string]token.Pos)
+	}
+	return entry
+}
+
+func sameLocks(a, b map[string]token.Pos) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// transfer applies the locks and unlocks of a block to held, reporting the
+// events it passes to emit when emit is not nil.
+func (g *lockGraph) transfer(block *ssa.BasicBlock, held map[string]token.Pos, emit func(lockEvent)) map[string]token.Pos {
+	for _, instr := range block.Instrs {
+		event := lockEvent{instr: instr}
+		switch instr := instr.(type) {
+		case *ssa.Call:
+			name := calledFunc(&instr.Call)
+			acquire, isLock := ssaLockMethods[name]
+			switch {
+			case isLock && len(instr.Call.Args) == 1:
+				key := lockKey(instr.Call.Args[0])
+				if key == "" {
+					continue
+				}
+				if !acquire {
+					delete(held, key)
+					continue
+				}
+				event.lock = key
+			case blockingCalls[name]:
+				event.blocking = shortCallName(name)
+			default:
+				callee := instr.Call.StaticCallee()
+				if callee == nil || len(callee.Blocks) == 0 || g.project.file(callee.Pos()) == nil {
+					continue
+				}
+				event.callee = callee
+			}
+		case *ssa.Send:
+			event.blocking = "a channel send"
+		case *ssa.Select:
+			if !instr.Blocking || !selectSends(instr) {
+				continue
+			}
+			event.blocking = "a channel send"
+		default:
+			continue
+		}
+		if emit != nil {
+			event.held = sortedLocks(held)
+			emit(event)
+		}
+		if event.lock != "" {
+			if _, ok := held[event.lock]; !ok {
+				held[event.lock] = instr.Pos()
+			}
+		}
+	}
+	return held
+}
+
+// shortCallName drops the receiver syntax and the import path but its last
+// element: "(*net/http.Client).Do" becomes "http.Client.Do".
+func shortCallName(name string) string {
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+	return name[strings.LastIndexByte(name, '/')+1:]
+}
+
+func selectSends(sel *ssa.Select) bool {
+	for _, state := range sel.States {
+		if state.Dir == types.SendOnly {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedLocks(held map[string]token.Pos) []heldLock {
+	locks := make([]heldLock, 0, len(held))
+	for key, pos := range held {
+		locks = append(locks, heldLock{key: key, pos: pos})
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].key < locks[j].key })
+	return locks
+}
+
+// acquiredBy returns the locks fn takes, itself or through the functions it
+// calls, each with the chain of steps leading to the acquisition.
+func (g *lockGraph) acquiredBy(fn *ssa.Function) map[string][]pathStep {
+	if locks, ok := g.acquired[fn]; ok || g.visiting[fn] {
+		return locks
+	}
+	g.visiting[fn] = true
+	locks := make(map[string][]pathStep)
+	for _, event := range g.lockEventsOf(fn) {
+		switch {
+		case event.lock != "":
+			if _, ok := locks[event.lock]; !ok {
+				locks[event.lock] = []pathStep{g.acquireStep(fn, event.instr.Pos(), event.lock)}
+			}
+		case event.callee != nil:
+			for key, chain := range g.acquiredBy(event.callee) {
+				if _, ok := locks[key]; !ok {
+					locks[key] = append([]pathStep{g.callStep(fn, event)}, chain...)
+				}
+			}
+		}
+	}
+	delete(g.visiting, fn)
+	g.acquired[fn] = locks
+	return locks
+}
+
+// blockingChain returns the steps from fn to the first blocking operation it
+// performs itself or through the functions it calls, or nil.
+func (g *lockGraph) blockingChain(fn *ssa.Function) []pathStep {
+	if chain, ok := g.blocks[fn]; ok || g.visiting[fn] {
+		return chain
+	}
+	g.visiting[fn] = true
+	var chain []pathStep
+	for _, event := range g.lockEventsOf(fn) {
+		if event.blocking != "" {
+			chain = []pathStep{g.waitStep(fn, event)}
+			break
+		}
+		if event.callee == nil {
+			continue
+		}
+		if inner := g.blockingChain(event.callee); inner != nil {
+			chain = append([]pathStep{g.callStep(fn, event)}, inner...)
+			break
+		}
+	}
+	delete(g.visiting, fn)
+	g.blocks[fn] = chain
+	return chain
+}
+
+func (g *lockGraph) acquireStep(fn *ssa.Function, pos token.Pos, key string) pathStep {
+	return pathStep{pos: pos, text: fmt.Sprintf("%s locks %s", fn.Name(), lockName(key))}
+}
+
+func (g *lockGraph) waitStep(fn *ssa.Function, event lockEvent) pathStep {
+	return pathStep{pos: event.instr.Pos(), text: fmt.Sprintf("%s waits on %s", fn.Name(), event.blocking)}
+}
+
+func (g *lockGraph) callStep(fn *ssa.Function, event lockEvent) pathStep {
+	return pathStep{pos: event.instr.Pos(), text: fmt.Sprintf("%s calls %s", fn.Name(), event.callee.Name())}
+}
@@ -0,0 +1,135 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeBlockingSections(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.ProjectWithSSA(t, map[string]string{"client/client.go": source})
+	violations, err := NewLockHeldAcrossBlockingCallRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestLockHeldAcrossBlockingCallRule_Metadata(t *testing.T) {
+	rule := NewLockHeldAcrossBlockingCallRule()
+	assert.Equal(t, "lock-held-across-blocking-call", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityMedium, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+}
+
+func TestLockHeldAcrossBlockingCallRule_Detection(t *testing.T) {
+	violations := analyzeBlockingSections(t, `package client
+
+import (
+	"database/sql"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Client struct {
+	mu      sync.Mutex
+	http    *http.Client
+	db      *sql.DB
+	events  chan string
+	retries int
+}
+
+func (c *Client) Fetch(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.http.Do(req)
+}
+
+func (c *Client) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.store()
+}
+
+func (c *Client) store() error {
+	_, err := c.db.Exec("UPDATE state SET retries = $1", c.retries)
+	return err
+}
+
+func (c *Client) Notify(event string) {
+	c.mu.Lock()
+	c.events <- event
+	c.mu.Unlock()
+}
+
+func (c *Client) Backoff() {
+	c.mu.Lock()
+	c.retries++
+	time.Sleep(time.Second)
+	c.mu.Unlock()
+}
+`)
+
+	require.Len(t, violations, 4)
+	assert.Equal(t, 21, violations[0].Line)
+	assert.Contains(t, violations[0].Message, "http.Client.Do")
+	assert.Equal(t, 19, violations[0].Context["lock_line"])
+	assert.Equal(t, 27, violations[1].Line)
+	assert.Contains(t, violations[1].Message, "Save calls store → client/client.go:31 store waits on sql.DB.Exec")
+	assert.Equal(t, 37, violations[2].Line)
+	assert.Contains(t, violations[2].Message, "a channel send")
+	assert.Equal(t, 44, violations[3].Line)
+	assert.Contains(t, violations[3].Message, "time.Sleep")
+}
+
+func TestLockHeldAcrossBlockingCallRule_AcceptsShortSections(t *testing.T) {
+	violations := analyzeBlockingSections(t, `package client
+
+import (
+	"net/http"
+	"sync"
+)
+
+type Client struct {
+	mu     sync.Mutex
+	http   *http.Client
+	token  string
+	events chan string
+}
+
+func (c *Client) Fetch(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+	req.Header.Set("Authorization", token)
+	return c.http.Do(req)
+}
+
+func (c *Client) TryNotify(event string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case c.events <- event:
+	default:
+	}
+}
+
+func (c *Client) Branches(fast bool) {
+	c.mu.Lock()
+	if fast {
+		c.mu.Unlock()
+		c.events <- "fast"
+		return
+	}
+	c.mu.Unlock()
+	c.events <- "slow"
+}
+`)
+
+	assert.Empty(t, violations)
+}
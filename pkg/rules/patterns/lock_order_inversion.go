@@ -0,0 +1,124 @@
+package patterns
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewLockOrderInversionRule())
+}
+
+// LockOrderInversionRule detects two mutexes taken in opposite orders on
+// different call paths:
+//
+//	func (l *Ledger) Transfer(a *Account) { l.mu.Lock(); a.Debit() }   // Ledger.mu → Account.mu
+//	func (a *Account) Close(l *Ledger)     { a.mu.Lock(); l.Forget(a) } // Account.mu → Ledger.mu
+//
+// When the two paths run at the same time, each goroutine holds the lock the
+// other waits for and both wait forever. Neither the race detector nor a test
+// that runs the paths one at a time notices; production deadlocks.
+//
+// Mutexes are told apart by the field or package variable that holds them
+// (Account.mu, not which account). Acquisitions are followed through static
+// calls of the project, so the lock a helper takes counts where the helper is
+// called. The finding names both chains. Not flagged: mutexes in locals and
+// parameters, locks taken through interface calls or in a started goroutine,
+// and two instances of the same field (one lock as far as the graph knows).
+type LockOrderInversionRule struct {
+	*rules.BaseRule
+}
+
+// NewLockOrderInversionRule creates the rule
+func NewLockOrderInversionRule() *LockOrderInversionRule {
+	return &LockOrderInversionRule{
+		BaseRule: rules.NewBaseRule(
+			"lock-order-inversion",
+			"patterns",
+			"Detects two mutexes acquired in opposite orders on different call paths (deadlock)",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op because this rule requires shared package SSA.
+func (r *LockOrderInversionRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *LockOrderInversionRule) RequiresSSA() bool { return true }
+
+// lockOrder is the first chain found that takes to after from.
+type lockOrder struct {
+	from, to string
+	chain    []pathStep
+}
+
+// AnalyzeGoProject builds the lock graph of the project and reports each pair
+// of locks with edges both ways.
+func (r *LockOrderInversionRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	project, err := newSSAProject(ctx, "lock order inversion")
+	if err != nil {
+		return nil, err
+	}
+	graph := newLockGraph(project)
+	orders := make(map[[2]string]lockOrder)
+	var found []lockOrder
+	record := func(order lockOrder) {
+		edge := [2]string{order.from, order.to}
+		if _, ok := orders[edge]; !ok {
+			orders[edge] = order
+			found = append(found, order)
+		}
+	}
+	for _, fn := range project.functions {
+		for _, event := range graph.lockEventsOf(fn) {
+			for _, held := range event.held {
+				outer := graph.acquireStep(fn, held.pos, held.key)
+				if event.lock != "" && event.lock != held.key {
+					record(lockOrder{from: held.key, to: event.lock,
+						chain: []pathStep{outer, graph.acquireStep(fn, event.instr.Pos(), event.lock)}})
+				}
+				if event.callee == nil {
+					continue
+				}
+				acquired := graph.acquiredBy(event.callee)
+				keys := make([]string, 0, len(acquired))
+				for key := range acquired {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					if key != held.key {
+						chain := append([]pathStep{outer, graph.callStep(fn, event)}, acquired[key]...)
+						record(lockOrder{from: held.key, to: key, chain: chain})
+					}
+				}
+			}
+		}
+	}
+
+	var violations []*core.Violation
+	for _, order := range found {
+		reverse, ok := orders[[2]string{order.to, order.from}]
+		if !ok || order.from > order.to {
+			continue
+		}
+		message := fmt.Sprintf("lock order inversion: %s then %s (%s), but %s then %s (%s) — two goroutines on these paths deadlock",
+			lockName(order.from), lockName(order.to), project.describe(order.chain),
+			lockName(order.to), lockName(order.from), project.describe(reverse.chain))
+		v := project.violation(r.CreateViolation, order.chain[0].pos, message)
+		if v == nil {
+			continue
+		}
+		v.WithSuggestion("Acquire the two mutexes in one global order everywhere, or release the first before calling into code that takes the second")
+		v.WithContext("order", project.describe(order.chain))
+		v.WithContext("reverse_order", project.describe(reverse.chain))
+		violations = append(violations, v)
+	}
+	return violations, nil
+}
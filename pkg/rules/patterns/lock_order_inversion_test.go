@@ -0,0 +1,126 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeLockOrder(t *testing.T, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := NewLockOrderInversionRule().AnalyzeGoProject(rulestest.ProjectWithSSA(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func TestLockOrderInversionRule_Metadata(t *testing.T) {
+	rule := NewLockOrderInversionRule()
+	assert.Equal(t, "lock-order-inversion", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+}
+
+// The second lock is taken two calls deep on one side: only a call graph sees
+// the inversion.
+func TestLockOrderInversionRule_AcrossCalls(t *testing.T) {
+	violations := analyzeLockOrder(t, map[string]string{
+		"bank/ledger.go": `package bank
+
+import "sync"
+
+type Ledger struct {
+	mu      sync.Mutex
+	entries map[*Account]int
+}
+
+func (l *Ledger) Transfer(a *Account, amount int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[a] += amount
+	a.Debit(amount)
+}
+
+func (l *Ledger) Forget(a *Account) {
+	l.mu.Lock()
+	delete(l.entries, a)
+	l.mu.Unlock()
+}
+`,
+		"bank/account.go": `package bank
+
+import "sync"
+
+type Account struct {
+	mu      sync.Mutex
+	balance int
+}
+
+func (a *Account) Debit(amount int) {
+	a.mu.Lock()
+	a.balance -= amount
+	a.mu.Unlock()
+}
+
+func (a *Account) Close(l *Ledger) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.balance = 0
+	forget(l, a)
+}
+
+func forget(l *Ledger, a *Account) {
+	l.Forget(a)
+}
+`,
+	})
+
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "bank/account.go", v.File)
+	assert.Equal(t, 17, v.Line)
+	assert.Equal(t, "bank/account.go:17 Close locks Account.mu → bank/account.go:20 Close calls forget → "+
+		"bank/account.go:24 forget calls Forget → bank/ledger.go:18 Forget locks Ledger.mu", v.Context["order"])
+	assert.Equal(t, "bank/ledger.go:11 Transfer locks Ledger.mu → bank/ledger.go:14 Transfer calls Debit → "+
+		"bank/account.go:11 Debit locks Account.mu", v.Context["reverse_order"])
+	assert.Contains(t, v.Message, "Account.mu then Ledger.mu")
+}
+
+func TestLockOrderInversionRule_AcceptsConsistentOrder(t *testing.T) {
+	violations := analyzeLockOrder(t, map[string]string{
+		"cache/cache.go": `package cache
+
+import "sync"
+
+var registryMu sync.Mutex
+
+type Cache struct {
+	mu    sync.RWMutex
+	items map[string]int
+}
+
+func (c *Cache) Put(key string, value int) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	c.mu.Lock()
+	c.items[key] = value
+	c.mu.Unlock()
+}
+
+func (c *Cache) Get(key string) int {
+	c.mu.RLock()
+	value := c.items[key]
+	c.mu.RUnlock()
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return value
+}
+`,
+	})
+
+	assert.Empty(t, violations)
+}
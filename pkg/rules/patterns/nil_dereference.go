@@ -0,0 +1,509 @@
+package patterns
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewNilDereferenceRule())
+}
+
+// noReturnCalls end the path they are on: nothing after them runs.
+var noReturnCalls = map[string]bool{
+	"os.Exit":                   true,
+	"log.Fatal":                 true,
+	"log.Fatalf":                true,
+	"log.Fatalln":               true,
+	"log.Panic":                 true,
+	"log.Panicf":                true,
+	"log.Panicln":               true,
+	"(*log.Logger).Fatal":       true,
+	"(*log.Logger).Fatalf":      true,
+	"(*log.Logger).Fatalln":     true,
+	"(*log.Logger).Panic":       true,
+	"(*log.Logger).Panicf":      true,
+	"(*log.Logger).Panicln":     true,
+	"(*testing.common).Fatal":   true,
+	"(*testing.common).Fatalf":  true,
+	"(*testing.common).FailNow": true,
+	"(*testing.common).Skip":    true,
+	"(*testing.common).Skipf":   true,
+	"(*testing.common).SkipNow": true,
+}
+
+// NilDereferenceRule detects pointers dereferenced on a path where they are
+// nil, following each function's control flow over SSA:
+//
+//	user, err := repo.Find(id)
+//	log.Printf("loaded %s", user.Name)    // nil when Find failed
+//	if err != nil { return err }
+//
+//	var target *APIError
+//	errors.As(err, &target)
+//	return target.Code                    // nil when errors.As returned false
+//
+//	if cfg == nil { log.Print("no config") }
+//	timeout := cfg.Timeout                // the nil path falls through
+//
+// Four sources of nil are tracked: the pointer result of a call returning an
+// error, dereferenced where that error has not been found nil; a pointer read
+// from a map without the ok form, by a key the function was given, used
+// without a nil check; an errors.As target read outside the branch where
+// errors.As returned true; and a struct pointer compared with nil whose nil
+// branch reaches a dereference. A nil check of the pointer itself, or of the
+// error, on the only way into a block clears it. Each source is reported once,
+// at its first such dereference, with the path from the source of nil in
+// nil_path.
+//
+// Not flagged: results whose error is never compared with nil (ignoring the
+// error is another finding), map keys the function found itself (ranging over
+// the map or a list kept alongside it), pointers that go through variables
+// captured by closures, and paths that end in os.Exit, log.Fatal or panic
+// first.
+type NilDereferenceRule struct {
+	*rules.BaseRule
+}
+
+// NewNilDereferenceRule creates the rule
+func NewNilDereferenceRule() *NilDereferenceRule {
+	return &NilDereferenceRule{
+		BaseRule: rules.NewBaseRule(
+			"nil-dereference",
+			"patterns",
+			"Detects pointers dereferenced on a path where they are nil: before the error check, after a map miss, errors.As or a nil comparison",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op because this rule requires shared package SSA.
+func (r *NilDereferenceRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *NilDereferenceRule) RequiresSSA() bool { return true }
+
+// nilSource is a pointer that is nil on some path, and what clears it.
+type nilSource struct {
+	value ssa.Value
+	// origin is where nil comes from; reach, when set, the block the nil
+	// paths start in, for pointers that are not nil everywhere after origin.
+	origin pathStep
+	reach  *ssa.BasicBlock
+	// guards are blocks where the pointer is known not to be nil when they
+	// are entered.
+	guards []*ssa.BasicBlock
+	// describe explains a dereference of the pointer.
+	describe func(expr string) string
+}
+
+// AnalyzeGoProject collects the sources of nil of every function and reports
+// each at the first dereference no guard covers.
+func (r *NilDereferenceRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	project, err := newSSAProject(ctx, "nil dereference")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fn := range project.functions {
+		reported := make(map[ssa.Value]bool)
+		for _, source := range nilSources(project, fn) {
+			if reported[source.value] {
+				continue
+			}
+			site := source.firstUnguarded()
+			if site == nil {
+				continue
+			}
+			reported[source.value] = true
+			expr := derefExpr(fn, site.Pos())
+			v := project.violation(r.CreateViolation, site.Pos(), source.describe(expr))
+			if v == nil {
+				continue
+			}
+			v.WithSuggestion("Check the pointer, or the error that comes with it, before dereferencing it")
+			v.WithContext("nil_path", project.describe([]pathStep{source.origin, {pos: site.Pos(), text: expr + " dereferenced"}}))
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// firstUnguarded returns the first dereference of the pointer, in source
+// order, that a nil path reaches and no guard covers, or nil.
+func (s nilSource) firstUnguarded() ssa.Instruction {
+	reachable := reachableFrom(s.reach)
+	var first ssa.Instruction
+	for _, site := range derefSites(s.value) {
+		if !site.Pos().IsValid() || guarded(site, s.guards) || s.reach != nil && !reachable[site.Block()] {
+			continue
+		}
+		if first == nil || site.Pos() < first.Pos() {
+			first = site
+		}
+	}
+	return first
+}
+
+// nilSources returns the pointers of fn that are nil on some path.
+func nilSources(project *ssaProject, fn *ssa.Function) []nilSource {
+	var sources []nilSource
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			switch instr := instr.(type) {
+			case *ssa.Call:
+				if source, ok := erroredResult(project, instr); ok {
+					sources = append(sources, source)
+				}
+				if source, ok := errorsAsTargets(instr); ok {
+					sources = append(sources, source...)
+				}
+			case *ssa.Lookup:
+				if source, ok := mapMiss(instr); ok {
+					sources = append(sources, source)
+				}
+			case *ssa.If:
+				if source, ok := comparedWithNil(instr); ok {
+					sources = append(sources, source)
+				}
+			}
+		}
+	}
+	return sources
+}
+
+// erroredResult returns the pointer result of a call that also returns an
+// error, cleared where the error is known nil.
+func erroredResult(project *ssaProject, call *ssa.Call) (nilSource, bool) {
+	results := call.Call.Signature().Results()
+	if results.Len() < 2 || !isPointer(results.At(0).Type()) || !isErrorType(results.At(results.Len()-1).Type()) {
+		return nilSource{}, false
+	}
+	var value, errValue ssa.Value
+	for _, instr := range referrersOf(call) {
+		if extract, ok := instr.(*ssa.Extract); ok {
+			switch extract.Index {
+			case 0:
+				value = extract
+			case results.Len() - 1:
+				errValue = extract
+			}
+		}
+	}
+	if value == nil || errValue == nil {
+		return nilSource{}, false
+	}
+	errNil, _, checks := nilChecks(errValue)
+	if len(checks) == 0 {
+		return nilSource{}, false
+	}
+	_, nonNil, _ := nilChecks(value)
+	name := "the call"
+	if callee := calledFunc(&call.Call); callee != "" {
+		name = shortCallName(callee)
+	}
+	checked := project.line(checks[0].Pos())
+	return nilSource{
+		value:  value,
+		origin: pathStep{pos: call.Pos(), text: name + " returns nil with its error"},
+		guards: append(errNil, nonNil...),
+		describe: func(expr string) string {
+			return fmt.Sprintf("%s is dereferenced where the error of %s (checked at line %d) may not be nil: it is nil when the call fails", expr, name, checked)
+		},
+	}, true
+}
+
+// mapMiss returns a pointer read from a map without the ok form, with a key
+// the function received: keys the function found itself, ranging over the
+// map or a list kept alongside it, are taken to be present.
+func mapMiss(lookup *ssa.Lookup) (nilSource, bool) {
+	if lookup.CommaOk || !isPointer(lookup.Type()) {
+		return nilSource{}, false
+	}
+	if _, ok := stripConversions(lookup.Index).(*ssa.Parameter); !ok {
+		return nilSource{}, false
+	}
+	if _, ok := lookup.X.Type().Underlying().(*types.Map); !ok {
+		return nilSource{}, false
+	}
+	_, nonNil, _ := nilChecks(lookup)
+	return nilSource{
+		value:  lookup,
+		origin: pathStep{pos: lookup.Pos(), text: "map lookup yields nil for a missing key"},
+		guards: nonNil,
+		describe: func(expr string) string {
+			return fmt.Sprintf("%s comes from a map lookup without ok and is dereferenced unchecked: a missing key yields nil", expr)
+		},
+	}, true
+}
+
+// errorsAsTargets returns the reads of an errors.As target variable, cleared
+// in the branch where errors.As returned true.
+func errorsAsTargets(call *ssa.Call) ([]nilSource, bool) {
+	if calledFunc(&call.Call) != "errors.As" || len(call.Call.Args) != 2 {
+		return nil, false
+	}
+	boxed, ok := call.Call.Args[1].(*ssa.MakeInterface)
+	if !ok {
+		return nil, false
+	}
+	target, ok := boxed.X.(*ssa.Alloc)
+	if !ok || !onlyNilStores(target) {
+		return nil, false
+	}
+	var matched []*ssa.BasicBlock
+	for _, instr := range referrersOf(call) {
+		if branch, ok := instr.(*ssa.If); ok {
+			matched = append(matched, branch.Block().Succs[0])
+		}
+	}
+	var sources []nilSource
+	for _, instr := range referrersOf(target) {
+		load, ok := instr.(*ssa.UnOp)
+		if !ok || load.Op != token.MUL {
+			continue
+		}
+		_, nonNil, _ := nilChecks(load)
+		sources = append(sources, nilSource{
+			value:  load,
+			origin: pathStep{pos: call.Pos(), text: "errors.As leaves " + target.Comment + " nil when it returns false"},
+			reach:  call.Block(),
+			guards: append(append([]*ssa.BasicBlock(nil), matched...), nonNil...),
+			describe: func(expr string) string {
+				return fmt.Sprintf("%s is dereferenced where errors.As may have returned false and left it nil", expr)
+			},
+		})
+	}
+	return sources, len(sources) > 0
+}
+
+// comparedWithNil returns a struct pointer compared with nil, with the nil
+// paths starting in the branch where the comparison found it nil.
+func comparedWithNil(branch *ssa.If) (nilSource, bool) {
+	compare, ok := branch.Cond.(*ssa.BinOp)
+	if !ok {
+		return nilSource{}, false
+	}
+	value, ok := nilOperand(compare)
+	if !ok || !isStructPointer(value.Type()) {
+		return nilSource{}, false
+	}
+	nilSide := branch.Block().Succs[0]
+	if compare.Op == token.NEQ {
+		nilSide = branch.Block().Succs[1]
+	}
+	_, nonNil, _ := nilChecks(value)
+	return nilSource{
+		value:  value,
+		origin: pathStep{pos: compare.Pos(), text: "compared with nil; the nil branch continues"},
+		reach:  nilSide,
+		guards: nonNil,
+		describe: func(expr string) string {
+			return fmt.Sprintf("%s is dereferenced on a path where it is nil: the nil branch of its comparison reaches here", expr)
+		},
+	}, true
+}
+
+// nilChecks returns, for the comparisons of value with nil that branch, the
+// blocks entered only when value is nil and only when it is not, and the
+// comparisons themselves.
+func nilChecks(value ssa.Value) (isNil, notNil []*ssa.BasicBlock, checks []*ssa.BinOp) {
+	for _, instr := range referrersOf(value) {
+		compare, ok := instr.(*ssa.BinOp)
+		if !ok {
+			continue
+		}
+		if operand, ok := nilOperand(compare); !ok || operand != value {
+			continue
+		}
+		for _, use := range referrersOf(compare) {
+			branch, ok := use.(*ssa.If)
+			if !ok {
+				continue
+			}
+			then, otherwise := branch.Block().Succs[0], branch.Block().Succs[1]
+			if compare.Op == token.NEQ {
+				then, otherwise = otherwise, then
+			}
+			isNil = appendEntered(isNil, then)
+			notNil = appendEntered(notNil, otherwise)
+			checks = append(checks, compare)
+		}
+	}
+	return isNil, notNil, checks
+}
+
+// appendEntered appends a branch target that has no other way in: only then
+// does the comparison hold throughout the blocks it dominates.
+func appendEntered(blocks []*ssa.BasicBlock, block *ssa.BasicBlock) []*ssa.BasicBlock {
+	if len(block.Preds) != 1 {
+		return blocks
+	}
+	return append(blocks, block)
+}
+
+// nilOperand returns the operand an == or != comparison compares with a nil
+// constant.
+func nilOperand(compare *ssa.BinOp) (ssa.Value, bool) {
+	if compare.Op != token.EQL && compare.Op != token.NEQ {
+		return nil, false
+	}
+	if constant, ok := compare.Y.(*ssa.Const); ok && constant.IsNil() {
+		return compare.X, true
+	}
+	if constant, ok := compare.X.(*ssa.Const); ok && constant.IsNil() {
+		return compare.Y, true
+	}
+	return nil, false
+}
+
+// onlyNilStores reports whether a variable is never assigned anything but
+// nil, so that it is nil until errors.As fills it.
+func onlyNilStores(target *ssa.Alloc) bool {
+	for _, instr := range referrersOf(target) {
+		store, ok := instr.(*ssa.Store)
+		if !ok || store.Addr != target {
+			continue
+		}
+		if constant, ok := store.Val.(*ssa.Const); !ok || !constant.IsNil() {
+			return false
+		}
+	}
+	return true
+}
+
+// derefSites returns the instructions that dereference a pointer: field
+// access, an explicit *p, indexing a pointer to an array, and storing
+// through it.
+func derefSites(value ssa.Value) []ssa.Instruction {
+	var sites []ssa.Instruction
+	for _, instr := range referrersOf(value) {
+		switch instr := instr.(type) {
+		case *ssa.FieldAddr:
+			sites = append(sites, instr)
+		case *ssa.IndexAddr:
+			if isPointer(value.Type()) {
+				sites = append(sites, instr)
+			}
+		case *ssa.UnOp:
+			if instr.Op == token.MUL {
+				sites = append(sites, instr)
+			}
+		case *ssa.Store:
+			if instr.Addr == value {
+				sites = append(sites, instr)
+			}
+		}
+	}
+	return sites
+}
+
+// guarded reports whether a guard dominates the site.
+func guarded(site ssa.Instruction, guards []*ssa.BasicBlock) bool {
+	for _, guard := range guards {
+		if guard.Dominates(site.Block()) {
+			return true
+		}
+	}
+	return false
+}
+
+// reachableFrom returns the blocks reachable from start, start included,
+// without passing a call that never returns; nil for a nil start.
+func reachableFrom(start *ssa.BasicBlock) map[*ssa.BasicBlock]bool {
+	if start == nil {
+		return nil
+	}
+	reachable := map[*ssa.BasicBlock]bool{start: true}
+	work := []*ssa.BasicBlock{start}
+	for len(work) > 0 {
+		block := work[len(work)-1]
+		work = work[:len(work)-1]
+		if endsPath(block) {
+			continue
+		}
+		for _, succ := range block.Succs {
+			if !reachable[succ] {
+				reachable[succ] = true
+				work = append(work, succ)
+			}
+		}
+	}
+	return reachable
+}
+
+// endsPath reports whether a block calls something that never returns.
+func endsPath(block *ssa.BasicBlock) bool {
+	for _, instr := range block.Instrs {
+		if call, ok := instr.(*ssa.Call); ok && noReturnCalls[calledFunc(&call.Call)] {
+			return true
+		}
+	}
+	return false
+}
+
+// derefExpr returns the source of the pointer dereferenced at pos — "user"
+// for user.Name, "*p" gives "p" — or "the pointer" when the syntax is not
+// found.
+func derefExpr(fn *ssa.Function, pos token.Pos) string {
+	expr := "the pointer"
+	syntax := fn.Syntax()
+	if syntax == nil {
+		return expr
+	}
+	ast.Inspect(syntax, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if n.Sel.Pos() == pos {
+				expr = types.ExprString(n.X)
+			}
+		case *ast.StarExpr:
+			if n.Star == pos {
+				expr = types.ExprString(n.X)
+			}
+		case *ast.IndexExpr:
+			if n.Lbrack == pos {
+				expr = types.ExprString(n.X)
+			}
+		}
+		return true
+	})
+	return expr
+}
+
+// stripConversions returns the value a chain of conversions starts from.
+func stripConversions(value ssa.Value) ssa.Value {
+	for {
+		switch v := value.(type) {
+		case *ssa.ChangeType:
+			value = v.X
+		case *ssa.Convert:
+			value = v.X
+		default:
+			return value
+		}
+	}
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Pointer)
+	return ok
+}
+
+func isStructPointer(t types.Type) bool {
+	ptr, ok := t.Underlying().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	_, ok = ptr.Elem().Underlying().(*types.Struct)
+	return ok
+}
@@ -0,0 +1,181 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeNilDereferences(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.ProjectWithSSA(t, map[string]string{"api/api.go": source})
+	violations, err := NewNilDereferenceRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestNilDereferenceRule_Metadata(t *testing.T) {
+	rule := NewNilDereferenceRule()
+	assert.Equal(t, "nil-dereference", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+}
+
+func TestNilDereferenceRule_Detection(t *testing.T) {
+	violations := analyzeNilDereferences(t, `package api
+
+import (
+	"errors"
+	"log"
+)
+
+type User struct{ Name string }
+
+type APIError struct{ Code int }
+
+func (e *APIError) Error() string { return "api" }
+
+func find(id int) (*User, error) { return nil, errors.New("missing") }
+
+func BeforeCheck(id int) (string, error) {
+	user, err := find(id)
+	log.Printf("loaded %s", user.Name)
+	if err != nil {
+		return "", err
+	}
+	return user.Name, nil
+}
+
+func Cached(cache map[int]*User, id int) string {
+	return cache[id].Name
+}
+
+func Code(err error) int {
+	var target *APIError
+	errors.As(err, &target)
+	return target.Code
+}
+
+func Greet(user *User) string {
+	if user == nil {
+		log.Print("no user")
+	}
+	return "hello " + user.Name
+}
+`)
+
+	require.Len(t, violations, 4)
+	assert.Equal(t, 18, violations[0].Line)
+	assert.Contains(t, violations[0].Message, "user is dereferenced where the error of api.find (checked at line 19)")
+	assert.Equal(t, "api/api.go:17 api.find returns nil with its error → api/api.go:18 user dereferenced", violations[0].Context["nil_path"])
+	assert.Equal(t, 26, violations[1].Line)
+	assert.Contains(t, violations[1].Message, "map lookup without ok")
+	assert.Equal(t, 32, violations[2].Line)
+	assert.Contains(t, violations[2].Message, "errors.As may have returned false")
+	assert.Equal(t, 39, violations[3].Line)
+	assert.Contains(t, violations[3].Message, "user is dereferenced on a path where it is nil")
+}
+
+func TestNilDereferenceRule_AcceptsCheckedPointers(t *testing.T) {
+	violations := analyzeNilDereferences(t, `package api
+
+import (
+	"errors"
+	"log"
+	"os"
+)
+
+type User struct{ Name string }
+
+type APIError struct{ Code int }
+
+func (e *APIError) Error() string { return "api" }
+
+func find(id int) (*User, error) { return nil, errors.New("missing") }
+
+func AfterCheck(id int) (string, error) {
+	user, err := find(id)
+	if err != nil {
+		return "", err
+	}
+	return user.Name, nil
+}
+
+func EitherCheck(id int) string {
+	user, err := find(id)
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.Name
+}
+
+func Unchecked(id int) string {
+	user, _ := find(id)
+	return user.Name
+}
+
+func Cached(cache map[int]*User, id int) string {
+	if user, ok := cache[id]; ok {
+		return user.Name
+	}
+	if user := cache[id]; user != nil {
+		return user.Name
+	}
+	return ""
+}
+
+func Names(cache map[int]*User) []string {
+	var names []string
+	for id := range cache {
+		names = append(names, cache[id].Name)
+	}
+	return names
+}
+
+func Code(err error) int {
+	var target *APIError
+	if errors.As(err, &target) {
+		return target.Code
+	}
+	return 0
+}
+
+func Greet(user *User) string {
+	if user == nil {
+		log.Fatal("no user")
+	}
+	return "hello " + user.Name
+}
+
+func Exit(user *User) string {
+	if user == nil {
+		os.Exit(1)
+	}
+	return user.Name
+}
+
+func Default(user *User) string {
+	if user == nil {
+		user = &User{Name: "guest"}
+	}
+	return user.Name
+}
+
+func Walk(users []*User) int {
+	n := 0
+	for _, user := range users {
+		if user != nil && user.Name != "" {
+			n++
+		}
+	}
+	return n
+}
+`)
+
+	assert.Empty(t, violations)
+}
@@ -1,7 +1,6 @@
 package patterns
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/aiseeq/glint/pkg/core"
@@ -17,9 +16,6 @@ func init() {
 // is not an object.
 type NullableObjectCallRule struct {
 	*rules.BaseRule
-	objectCollectionCall *regexp.Regexp
-	hasOwnPropertyCall   *regexp.Regexp
-	objectHasOwnCall     *regexp.Regexp
 }
 
 // NewNullableObjectCallRule creates the rule
@@ -31,12 +27,18 @@ func NewNullableObjectCallRule() *NullableObjectCallRule {
 			"Detects Object.* calls on possibly nullable nested values",
 			core.SeverityHigh,
 		),
-		objectCollectionCall: regexp.MustCompile(`Object\.(?:keys|values|entries)\s*\(([^)]*)\)`),
-		hasOwnPropertyCall:   regexp.MustCompile(`Object\.prototype\.hasOwnProperty\.call\s*\(([^,)]*)`),
-		objectHasOwnCall:     regexp.MustCompile(`Object\.hasOwn\s*\(([^,)]*)`),
 	}
 }
 
+// objectTargetCalls are the calls whose first argument must be an object.
+var objectTargetCalls = map[string]bool{
+	"Object.keys":                          true,
+	"Object.values":                        true,
+	"Object.entries":                       true,
+	"Object.hasOwn":                        true,
+	"Object.prototype.hasOwnProperty.call": true,
+}
+
 // AnalyzeFile checks for Object.* calls on possibly nullable values
 func (r *NullableObjectCallRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
 	if !ctx.IsTypeScriptFile() && !ctx.IsJavaScriptFile() {
@@ -45,79 +47,65 @@ func (r *NullableObjectCallRule) AnalyzeFile(ctx *core.FileContext) []*core.Viol
 	if r.shouldSkip(ctx) {
 		return nil
 	}
+	file := ctx.TypeScriptAST()
 
 	var violations []*core.Violation
-	for i, line := range ctx.Lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
-			continue
+	file.Program.Walk(func(n *core.TSNode, ancestors []*core.TSNode) bool {
+		if n.Kind != core.TSCall || !objectTargetCalls[tsCallee(n)] {
+			return true
 		}
-
-		if arg, ok := r.unsafeObjectCollectionArg(line); ok {
-			violations = append(violations, r.violation(ctx, i+1, line, arg))
-			continue
-		}
-
-		if arg, ok := r.unsafeHasOwnArg(line); ok {
-			violations = append(violations, r.violation(ctx, i+1, line, arg))
+		arg := n.Child(1)
+		if isNestedAccess(arg) && !isGuarded(file, n, arg, ancestors) {
+			violations = append(violations, r.violation(ctx, n, file.Text(arg)))
 		}
-	}
+		return true
+	})
 
 	return violations
 }
 
-func (r *NullableObjectCallRule) unsafeObjectCollectionArg(line string) (string, bool) {
-	matches := r.objectCollectionCall.FindAllStringSubmatch(line, -1)
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
-		}
-		arg := strings.TrimSpace(match[1])
-		if isUnsafeNullableObjectArg(line, arg) {
-			return arg, true
-		}
-	}
-	return "", false
+// isNestedAccess reports a property or element access — a value read out of
+// another object, which an API response may leave null. A fallback such as
+// entry.details ?? {} is no access.
+func isNestedAccess(arg *core.TSNode) bool {
+	arg = arg.Unwrap()
+	return arg != nil && (arg.Kind == core.TSMember || arg.Kind == core.TSIndex)
 }
 
-func (r *NullableObjectCallRule) unsafeHasOwnArg(line string) (string, bool) {
-	for _, pattern := range []*regexp.Regexp{r.hasOwnPropertyCall, r.objectHasOwnCall} {
-		matches := pattern.FindAllStringSubmatch(line, -1)
-		for _, match := range matches {
-			if len(match) < 2 {
-				continue
-			}
-			arg := strings.TrimSpace(match[1])
-			if isUnsafeNullableObjectArg(line, arg) {
-				return arg, true
-			}
+// isGuarded reports whether the call only runs once the argument has been
+// tested: the right operand of arg && ..., or the branch of a condition on it.
+func isGuarded(file *core.TSFile, call, arg *core.TSNode, ancestors []*core.TSNode) bool {
+	inner := call
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		parent := ancestors[i]
+		var test *core.TSNode
+		switch {
+		case parent.Kind == core.TSBinary && parent.Text == "&&" && parent.Child(1) == inner:
+			test = parent.Child(0)
+		case parent.Kind == core.TSConditional && parent.Child(1) == inner:
+			test = parent.Child(0)
+		case parent.Kind == core.TSIf && parent.Child(1) == inner:
+			test = parent.Child(0)
 		}
+		if test != nil && mentionsExpr(file, test, arg) {
+			return true
+		}
+		inner = parent
 	}
-	return "", false
-}
-
-func isUnsafeNullableObjectArg(line string, arg string) bool {
-	if arg == "" || hasObjectFallback(arg) || hasSameLineObjectGuard(line, arg) {
-		return false
-	}
-	if strings.HasPrefix(arg, "{") || strings.HasPrefix(arg, "[") || strings.HasPrefix(arg, "new ") {
-		return false
-	}
-
-	return strings.Contains(arg, ".") || strings.Contains(arg, "[")
-}
-
-func hasObjectFallback(arg string) bool {
-	return strings.Contains(arg, "?? {}") || strings.Contains(arg, "|| {}") || strings.Contains(arg, "? {}")
+	return false
 }
 
-func hasSameLineObjectGuard(line string, arg string) bool {
-	idx := strings.Index(line, "Object.")
-	if idx <= 0 {
-		return false
-	}
-	prefix := line[:idx]
-	return strings.Contains(prefix, arg+" &&") || strings.Contains(prefix, "typeof "+arg+" === 'object'") || strings.Contains(prefix, "typeof "+arg+" === \"object\"")
+// mentionsExpr reports whether within spells the same access as expr.
+func mentionsExpr(file *core.TSFile, within, expr *core.TSNode) bool {
+	path, text := expr.Path(), file.Text(expr.Unwrap())
+	found := false
+	within.Walk(func(n *core.TSNode, _ []*core.TSNode) bool {
+		if (path != "" && n.Path() == path) || file.Text(n) == text {
+			found = true
+		}
+		return !found
+	})
+	return found
 }
 
 func (r *NullableObjectCallRule) shouldSkip(ctx *core.FileContext) bool {
@@ -134,9 +122,10 @@ func (r *NullableObjectCallRule) shouldSkip(ctx *core.FileContext) bool {
 		strings.Contains(path, ".generated")
 }
 
-func (r *NullableObjectCallRule) violation(ctx *core.FileContext, lineNum int, line string, arg string) *core.Violation {
-	v := r.CreateViolation(ctx.RelPath, lineNum, "Object.* call uses a nested value that may be null or undefined")
-	v.WithCode(strings.TrimSpace(line))
+func (r *NullableObjectCallRule) violation(ctx *core.FileContext, call *core.TSNode, arg string) *core.Violation {
+	v := r.CreateViolation(ctx.RelPath, call.Start.Line, "Object.* call uses a nested value that may be null or undefined")
+	atTSNode(v, call)
+	v.WithCode(strings.TrimSpace(ctx.GetLine(call.Start.Line)))
 	v.WithSuggestion("Normalize " + arg + " to a verified object before calling Object.keys/values/entries or hasOwnProperty.")
 	v.WithContext("pattern", "nullable-object-call")
 	v.WithContext("language", "typescript")
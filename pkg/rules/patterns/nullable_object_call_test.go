@@ -72,3 +72,24 @@ func TestNullableObjectCallRule(t *testing.T) {
 		})
 	}
 }
+
+func TestNullableObjectCallRuleReportsCall(t *testing.T) {
+	code := `const counts = data.stats ? Object.keys(data.stats).length : 0
+if (typeof entry.meta === 'object') {
+  render(Object.values(entry.meta))
+}
+const rows = Object.entries(
+  response.data['items'] as Record<string, Item>,
+)`
+	violations := NewNullableObjectCallRule().AnalyzeFile(core.NewFileContext("frontend/src/lib/api.ts", ".", []byte(code), nil))
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	v := violations[0]
+	if v.Line != 5 || v.Column != 14 || v.EndLine != 7 {
+		t.Errorf("got %d:%d-%d, want 5:14-7", v.Line, v.Column, v.EndLine)
+	}
+	if got := v.Context["argument"]; got != "response.data['items'] as Record<string, Item>" {
+		t.Errorf("got argument %q", got)
+	}
+}
@@ -3,18 +3,29 @@ package patterns
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/aiseeq/glint/pkg/core"
 	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/rules/helpers"
 )
 
 func init() {
 	rules.Register(NewOrphanedInterfaceRule())
 }
 
-// OrphanedInterfaceRule detects interfaces with no implementations or usages
-// These are "dead code" interfaces that can be safely removed
+// OrphanedInterfaceRule detects interfaces that no project code refers to and
+// no project type implements. These are "dead code" interfaces that can be
+// safely removed.
+//
+// Both questions are answered through the type information of every loaded
+// package: a reference is any use of the interface's name outside its own
+// declaration — parameter, field, conversion, assertion, embedding, constraint
+// — and an implementation is any named type of the project whose method set
+// satisfies it per types.Implements, wherever the two are declared.
 type OrphanedInterfaceRule struct {
 	*rules.BaseRule
 }
@@ -25,382 +36,200 @@ func NewOrphanedInterfaceRule() *OrphanedInterfaceRule {
 		BaseRule: rules.NewBaseRule(
 			"orphaned-interface",
 			"patterns",
-			"Detects interfaces with no implementations or usages in the same file",
+			"Detects interfaces that nothing in the project implements or refers to",
 			core.SeverityMedium,
 		),
 	}
 }
 
-// interfaceInfo holds information about a declared interface
-type interfaceInfo struct {
-	name    string
-	pos     token.Position
-	methods []string // method names for implementation matching
-}
+// RequiresSSA reports that typed syntax is enough for this rule.
+func (r *OrphanedInterfaceRule) RequiresSSA() bool { return false }
 
-// AnalyzeFile checks for orphaned interfaces
-func (r *OrphanedInterfaceRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
-	if r.shouldSkipFile(ctx) {
-		return nil
-	}
+// AnalyzeFile is a no-op: implementations and users may live in any package.
+func (r *OrphanedInterfaceRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
 
-	if !ctx.HasGoAST() {
-		return nil
-	}
+// declaredInterface is an interface type declared in an analyzed file.
+type declaredInterface struct {
+	obj        *types.TypeName
+	iface      *types.Interface
+	fileCtx    *core.FileContext
+	line       int
+	start, end token.Pos
+}
 
-	// Phase 1: Collect all interfaces
-	interfaces := r.collectInterfaces(ctx)
-	if len(interfaces) == 0 {
-		return nil
+// AnalyzeGoProject reports the interfaces with neither a reference nor an
+// implementation.
+func (r *OrphanedInterfaceRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	var declared []*declaredInterface
+	err := helpers.ForEachTypedFile(ctx, "orphaned interface", func(fileCtx *core.FileContext, info *types.Info) {
+		if !isVendoredOrGeneratedPath(fileCtx.RelPath) {
+			declared = append(declared, collectInterfaces(fileCtx, info)...)
+		}
+	})
+	if err != nil {
+		return nil, err
 	}
+	referenced := referencedInterfaces(ctx, declared)
+	testNames := namesInTestFiles(ctx.Files)
 
-	// Phase 2: Find implementations (types with matching methods)
-	implementedBy := r.findImplementations(ctx, interfaces)
-
-	// Phase 3: Find usages (params, returns, fields, type assertions)
-	usedIn := r.findUsages(ctx, interfaces)
-
-	// Phase 4: Report orphaned interfaces
 	var violations []*core.Violation
-	for _, iface := range interfaces {
-		hasImpl := len(implementedBy[iface.name]) > 0
-		hasUsage := usedIn[iface.name]
-
-		if !hasImpl && !hasUsage {
-			// Check for nolint or "Used by:" comment
-			if r.hasExemptComment(ctx, iface.pos.Line) {
-				continue
-			}
-
-			v := r.CreateViolation(ctx.RelPath, iface.pos.Line,
-				"Interface '"+iface.name+"' has no implementations or usages in this file - potentially orphaned")
-			v.Suggestion = "Remove unused interface or add implementations/usages. " +
-				"If interface is implemented in other files, consider adding a usage comment."
-			violations = append(violations, v)
+	for _, decl := range declared {
+		if referenced[decl.obj] || implementedInProject(ctx, decl) {
+			continue
 		}
+		violations = append(violations, r.report(decl, testNames[filepath.Dir(decl.fileCtx.Path)][decl.obj.Name()]))
 	}
 
-	return violations
-}
-
-// shouldSkipFile checks if file should be excluded
-func (r *OrphanedInterfaceRule) shouldSkipFile(ctx *core.FileContext) bool {
-	path := ctx.RelPath
-
-	// Skip test files - they may have mock interfaces
-	if ctx.IsTestFile() {
-		return true
-	}
-
-	if isVendoredOrGeneratedPath(path) {
-		return true
-	}
-
-	// Skip interface definition directories (contracts, interfaces packages)
-	// These are meant to be implemented elsewhere
-	if strings.Contains(path, "contracts/") || strings.Contains(path, "interfaces/") {
-		return true
-	}
-
-	// Skip files specifically named for interface definitions
-	baseName := ctx.BaseName()
-	if strings.HasSuffix(baseName, "_interface.go") ||
-		strings.HasSuffix(baseName, "_interfaces.go") ||
-		baseName == "interfaces.go" ||
-		baseName == "interface.go" {
-		return true
-	}
-
-	return false
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, nil
 }
 
-// hasExemptComment checks if interface has nolint or "Used by:" documentation
-func (r *OrphanedInterfaceRule) hasExemptComment(ctx *core.FileContext, line int) bool {
-	// Check 5 lines above interface declaration for comments
-	for i := max(1, line-5); i <= line; i++ {
-		lineContent := ctx.GetLine(i)
-		// Check for nolint comment
-		if strings.Contains(lineContent, "nolint") {
-			return true
-		}
-		// Check for "Used by:" documentation pattern
-		if strings.Contains(lineContent, "Used by:") || strings.Contains(lineContent, "USED BY:") {
-			return true
-		}
+func (r *OrphanedInterfaceRule) report(decl *declaredInterface, mentionedByTests bool) *core.Violation {
+	name := decl.obj.Name()
+	message := "Interface '" + name + "' has no implementations or usages in the project - orphaned"
+	if mentionedByTests {
+		message = "Interface '" + name + "' has no implementations or usages outside tests - orphaned in production code"
 	}
-	return false
+	v := r.CreateViolation(decl.fileCtx.RelPath, decl.line, message)
+	v.WithCode(strings.TrimSpace(decl.fileCtx.GetLine(decl.line)))
+	v.WithSuggestion("Remove the interface, or accept it where its implementations are used")
+	v.WithContext("interface", name)
+	v.WithContext("package", decl.obj.Pkg().Path())
+	return v
 }
 
-// collectInterfaces finds all interface type declarations
-func (r *OrphanedInterfaceRule) collectInterfaces(ctx *core.FileContext) []*interfaceInfo {
-	var interfaces []*interfaceInfo
-
-	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
-		genDecl, ok := n.(*ast.GenDecl)
+// collectInterfaces returns the package-level interface types one file
+// declares.
+func collectInterfaces(fileCtx *core.FileContext, info *types.Info) []*declaredInterface {
+	var declared []*declaredInterface
+	for _, decl := range fileCtx.GoAST.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
 		if !ok || genDecl.Tok != token.TYPE {
-			return true
+			continue
 		}
-
 		for _, spec := range genDecl.Specs {
 			typeSpec, ok := spec.(*ast.TypeSpec)
-			if !ok {
+			if !ok || typeSpec.Assign.IsValid() {
 				continue
 			}
-
-			ifaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if _, ok := typeSpec.Type.(*ast.InterfaceType); !ok {
+				continue
+			}
+			obj, ok := info.Defs[typeSpec.Name].(*types.TypeName)
 			if !ok {
 				continue
 			}
-
-			// Skip interfaces with common DI/abstraction suffixes
-			// These are typically defined in one file and used elsewhere
-			name := typeSpec.Name.Name
-			if r.isDIInterface(name) {
+			iface, ok := obj.Type().Underlying().(*types.Interface)
+			if !ok {
 				continue
 			}
-
-			iface := &interfaceInfo{
-				name:    name,
-				pos:     ctx.PositionFor(typeSpec),
-				methods: r.extractMethodNames(ifaceType),
+			declared = append(declared, &declaredInterface{
+				obj:     obj,
+				iface:   iface,
+				fileCtx: fileCtx,
+				line:    fileCtx.PositionFor(typeSpec.Name).Line,
+				start:   typeSpec.Pos(),
+				end:     typeSpec.End(),
+			})
+		}
+	}
+	return declared
+}
+
+// referencedInterfaces returns the interfaces named anywhere in the project
+// outside their own declaration.
+func referencedInterfaces(ctx *core.GoProjectContext, declared []*declaredInterface) map[*types.TypeName]bool {
+	byObj := make(map[*types.TypeName]*declaredInterface, len(declared))
+	for _, decl := range declared {
+		byObj[decl.obj] = decl
+	}
+	referenced := make(map[*types.TypeName]bool)
+	for _, pkg := range ctx.Packages {
+		for ident, obj := range pkg.Package.TypesInfo.Uses {
+			typeName, ok := obj.(*types.TypeName)
+			if !ok {
+				continue
 			}
-			interfaces = append(interfaces, iface)
-		}
-
-		return true
-	})
-
-	return interfaces
-}
-
-// isDIInterface checks if interface name suggests it's a DI/abstraction interface
-// These are typically defined in type files and implemented/used elsewhere
-func (r *OrphanedInterfaceRule) isDIInterface(name string) bool {
-	diSuffixes := []string{
-		"Interface",
-		"Service",
-		"Repository",
-		"Factory",
-		"Provider",
-		"Manager",
-		"Handler",
-		"Adapter",
-		"Client",
-		"Store",
-		"Cache",
-		"Reader",
-		"Writer",
-		"Validator",
-		"Formatter",
-		"Parser",
-		"Builder",
-		"Registrar",
-		"Registry",
-		"Checker",
-		"Constraint",
-		"Rule",
-	}
-
-	for _, suffix := range diSuffixes {
-		if strings.HasSuffix(name, suffix) {
-			return true
-		}
-	}
-	return false
-}
-
-// extractMethodNames gets method names from interface
-func (r *OrphanedInterfaceRule) extractMethodNames(ifaceType *ast.InterfaceType) []string {
-	var methods []string
-
-	if ifaceType.Methods == nil {
-		return methods
-	}
-
-	for _, method := range ifaceType.Methods.List {
-		// Named method
-		for _, name := range method.Names {
-			methods = append(methods, name.Name)
-		}
-		// Embedded interface - we skip these for simplicity
-	}
-
-	return methods
-}
-
-// findImplementations checks if any struct types implement the interfaces
-func (r *OrphanedInterfaceRule) findImplementations(ctx *core.FileContext, interfaces []*interfaceInfo) map[string][]string {
-	// Map: interface name -> list of implementing type names
-	implementations := make(map[string][]string)
-
-	// Collect all type declarations and their methods
-	typeMethods := make(map[string]map[string]bool) // type name -> method names
-
-	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
-		// Find method declarations
-		funcDecl, ok := n.(*ast.FuncDecl)
-		if !ok || funcDecl.Recv == nil {
-			return true
-		}
-
-		// Get receiver type name
-		recvType := receiverTypeName(funcDecl.Recv)
-		if recvType == "" {
-			return true
-		}
-
-		if typeMethods[recvType] == nil {
-			typeMethods[recvType] = make(map[string]bool)
-		}
-		typeMethods[recvType][funcDecl.Name.Name] = true
-
-		return true
-	})
-
-	// Check which types implement which interfaces
-	for _, iface := range interfaces {
-		if len(iface.methods) == 0 {
-			// Empty interface - everything implements it, skip
-			continue
-		}
-
-		for typeName, methods := range typeMethods {
-			if r.implementsInterface(iface.methods, methods) {
-				implementations[iface.name] = append(implementations[iface.name], typeName)
+			decl := byObj[typeName]
+			if decl == nil || (ident.Pos() >= decl.start && ident.Pos() < decl.end) {
+				continue
 			}
+			referenced[typeName] = true
 		}
 	}
-
-	return implementations
+	return referenced
 }
 
-// implementsInterface checks if a type implements an interface
-func (r *OrphanedInterfaceRule) implementsInterface(ifaceMethods []string, typeMethods map[string]bool) bool {
-	for _, method := range ifaceMethods {
-		if !typeMethods[method] {
-			return false
-		}
+// implementedInProject reports whether a named type of the project satisfies
+// the interface. Interfaces without methods, with type sets or with type
+// parameters count only through references: everything or nothing would
+// implement them.
+func implementedInProject(ctx *core.GoProjectContext, decl *declaredInterface) bool {
+	if decl.iface.NumMethods() == 0 || !decl.iface.IsMethodSet() {
+		return false
 	}
-	return true
-}
-
-// findUsages checks if interfaces are used anywhere in the file
-func (r *OrphanedInterfaceRule) findUsages(ctx *core.FileContext, interfaces []*interfaceInfo) map[string]bool {
-	usages := make(map[string]bool)
-	interfaceNames := make(map[string]bool)
-	for _, iface := range interfaces {
-		interfaceNames[iface.name] = true
+	if named, ok := decl.obj.Type().(*types.Named); !ok || named.TypeParams().Len() > 0 {
+		return false
 	}
-
-	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
-		switch node := n.(type) {
-		// Function parameters, return types and generic constraints
-		case *ast.FuncType:
-			r.checkFieldList(node.TypeParams, interfaceNames, usages)
-			r.checkFieldList(node.Params, interfaceNames, usages)
-			r.checkFieldList(node.Results, interfaceNames, usages)
-
-		// Struct fields
-		case *ast.StructType:
-			r.checkFieldList(node.Fields, interfaceNames, usages)
-
-		// Embedding into another interface: type B interface { A }.
-		// Named methods carry a FuncType and are ignored by checkExpr, so only
-		// embedded interface identifiers register as usages here.
-		case *ast.InterfaceType:
-			r.checkFieldList(node.Methods, interfaceNames, usages)
-
-		// Type assertions: x.(InterfaceName)
-		case *ast.TypeAssertExpr:
-			if ident, ok := node.Type.(*ast.Ident); ok {
-				if interfaceNames[ident.Name] {
-					usages[ident.Name] = true
-				}
+	for _, pkg := range ctx.Packages {
+		scope := pkg.Package.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() || types.IsInterface(typeName.Type()) {
+				continue
 			}
-
-		// Type switch cases
-		case *ast.TypeSwitchStmt:
-			r.checkTypeSwitchCases(node, interfaceNames, usages)
-
-		// Variable declarations
-		case *ast.ValueSpec:
-			if node.Type != nil {
-				if ident, ok := node.Type.(*ast.Ident); ok {
-					if interfaceNames[ident.Name] {
-						usages[ident.Name] = true
-					}
-				}
+			if implementsInterface(typeName.Type(), decl.iface) {
+				return true
 			}
-
-		// Composite literals (map[InterfaceName]...)
-		case *ast.MapType:
-			r.checkExpr(node.Key, interfaceNames, usages)
-			r.checkExpr(node.Value, interfaceNames, usages)
-
-		// Array/slice types
-		case *ast.ArrayType:
-			r.checkExpr(node.Elt, interfaceNames, usages)
 		}
-
-		return true
-	})
-
-	return usages
-}
-
-// checkFieldList checks field list for interface usages
-func (r *OrphanedInterfaceRule) checkFieldList(fields *ast.FieldList, names map[string]bool, usages map[string]bool) {
-	if fields == nil {
-		return
-	}
-
-	for _, field := range fields.List {
-		r.checkExpr(field.Type, names, usages)
 	}
+	return false
 }
 
-// checkExpr checks an expression for interface identifier
-func (r *OrphanedInterfaceRule) checkExpr(expr ast.Expr, names map[string]bool, usages map[string]bool) {
-	if expr == nil {
-		return
+// implementsInterface checks the pointer method set, which includes the value
+// one. types.Implements is unspecified for uninstantiated generic types; their
+// methods are matched by name instead.
+func implementsInterface(t types.Type, iface *types.Interface) bool {
+	ptr := types.NewPointer(t)
+	if named, ok := t.(*types.Named); !ok || named.TypeParams().Len() == 0 {
+		return types.Implements(ptr, iface)
 	}
-
-	switch e := expr.(type) {
-	case *ast.Ident:
-		if names[e.Name] {
-			usages[e.Name] = true
-		}
-	case *ast.StarExpr:
-		r.checkExpr(e.X, names, usages)
-	case *ast.ArrayType:
-		r.checkExpr(e.Elt, names, usages)
-	case *ast.MapType:
-		r.checkExpr(e.Key, names, usages)
-		r.checkExpr(e.Value, names, usages)
-	case *ast.ChanType:
-		r.checkExpr(e.Value, names, usages)
-	case *ast.SelectorExpr:
-		// package.Type - check the selector (Type) part
-		if names[e.Sel.Name] {
-			usages[e.Sel.Name] = true
+	methods := types.NewMethodSet(ptr)
+	for i := range iface.NumMethods() {
+		wanted := iface.Method(i)
+		if methods.Lookup(wanted.Pkg(), wanted.Name()) == nil {
+			return false
 		}
 	}
+	return true
 }
 
-// checkTypeSwitchCases checks type switch for interface usages
-func (r *OrphanedInterfaceRule) checkTypeSwitchCases(ts *ast.TypeSwitchStmt, names map[string]bool, usages map[string]bool) {
-	if ts.Body == nil {
-		return
-	}
-
-	for _, stmt := range ts.Body.List {
-		caseClause, ok := stmt.(*ast.CaseClause)
-		if !ok {
+// namesInTestFiles collects the identifiers of the project's _test.go files by
+// package directory. Test files are outside the typed load; a mention there
+// only changes how a finding is worded.
+func namesInTestFiles(files []*core.FileContext) map[string]map[string]bool {
+	names := make(map[string]map[string]bool)
+	for _, fileCtx := range files {
+		if fileCtx == nil || !fileCtx.IsTestFile() || fileCtx.GoAST == nil {
 			continue
 		}
-
-		for _, expr := range caseClause.List {
-			r.checkExpr(expr, names, usages)
+		dir := filepath.Dir(fileCtx.Path)
+		if names[dir] == nil {
+			names[dir] = make(map[string]bool)
 		}
+		ast.Inspect(fileCtx.GoAST, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok {
+				names[dir][ident.Name] = true
+			}
+			return true
+		})
 	}
+	return names
 }
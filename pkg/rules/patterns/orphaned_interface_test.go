@@ -3,28 +3,77 @@ package patterns
 import (
 	"testing"
 
-	"github.com/aiseeq/glint/pkg/core"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
 )
 
-func TestOrphanedInterfaceSkipsRuleCapabilityInterface(t *testing.T) {
-	ctx, err := core.NewFileContextChecked(
-		"rule.go",
-		".",
-		[]byte("package rules\n\ntype ProjectRule interface { AnalyzeProject() error }\n"),
-		core.DefaultConfig(),
-	)
+func analyzeOrphanedInterfaces(t *testing.T, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := NewOrphanedInterfaceRule().AnalyzeGoProject(rulestest.Project(t, files))
 	require.NoError(t, err)
-	fset, file, err := core.NewParser().ParseGoFile(ctx.Path, ctx.Content)
-	require.NoError(t, err)
-	ctx.SetGoAST(fset, file)
+	return violations
+}
 
-	require.Empty(t, NewOrphanedInterfaceRule().AnalyzeFile(ctx))
+func TestOrphanedInterfaceReportsUnusedInterface(t *testing.T) {
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"rules/rule.go": "package rules\n\ntype ProjectRule interface { AnalyzeProject() error }\n",
+	})
+
+	require.Len(t, violations, 1, "a name suffix no longer excuses an interface nothing uses")
+	assert.Equal(t, "rules/rule.go", violations[0].File)
+	assert.Equal(t, 3, violations[0].Line)
+	assert.Equal(t, "ProjectRule", violations[0].Context["interface"])
+}
+
+// An interface implemented in another package is not orphaned, though no
+// file of its own package mentions the implementation.
+func TestOrphanedInterfaceImplementedInAnotherPackage(t *testing.T) {
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"port/port.go": "package port\n\ntype Notifier interface{ Notify(msg string) error }\n",
+		"adapter/mail.go": `package adapter
+
+type Mailer struct{ sent []string }
+
+func (m *Mailer) Notify(msg string) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+`,
+	})
+	assert.Empty(t, violations)
+}
+
+// Matching method names without matching signatures is no implementation.
+func TestOrphanedInterfaceSignatureMismatchIsNoImplementation(t *testing.T) {
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"port/port.go": "package port\n\ntype Notifier interface{ Notify(msg string) error }\n",
+		"adapter/log.go": `package adapter
+
+type Logger struct{}
+
+func (Logger) Notify(code int) {}
+`,
+	})
+	require.Len(t, violations, 1)
+	assert.Equal(t, "Notifier", violations[0].Context["interface"])
+}
+
+// A parameter in another file of the package is a reference.
+func TestOrphanedInterfaceReferenceInSiblingFile(t *testing.T) {
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"app/types.go":   "package app\n\ntype clock interface{ Now() int64 }\n",
+		"app/service.go": "package app\n\nfunc Stamp(c clock) int64 { return c.Now() }\n",
+	})
+	assert.Empty(t, violations)
 }
 
 // Интерфейс, используемый только как generic-констрейнт, — не сирота.
 func TestOrphanedInterfaceGenericConstraintIsUsage(t *testing.T) {
-	src := `package sample
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"sample/sum.go": `package sample
 
 type Number interface{ Value() int }
 
@@ -35,19 +84,15 @@ func Sum[T Number](items []T) int {
 	}
 	return total
 }
-`
-	ctx, err := core.NewFileContextChecked("sum.go", ".", []byte(src), core.DefaultConfig())
-	require.NoError(t, err)
-	fset, file, err := core.NewParser().ParseGoFile(ctx.Path, ctx.Content)
-	require.NoError(t, err)
-	ctx.SetGoAST(fset, file)
-
-	require.Empty(t, NewOrphanedInterfaceRule().AnalyzeFile(ctx))
+`,
+	})
+	assert.Empty(t, violations)
 }
 
 // Метод generic-типа (Buffer[T]) — реализация: интерфейс не сирота.
 func TestOrphanedInterfaceGenericReceiverImplements(t *testing.T) {
-	src := `package sample
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"sample/buffer.go": `package sample
 
 type Flusher interface{ FlushIt() error }
 
@@ -57,19 +102,15 @@ func (b *Buffer[T]) FlushIt() error {
 	b.items = nil
 	return nil
 }
-`
-	ctx, err := core.NewFileContextChecked("buffer.go", ".", []byte(src), core.DefaultConfig())
-	require.NoError(t, err)
-	fset, file, err := core.NewParser().ParseGoFile(ctx.Path, ctx.Content)
-	require.NoError(t, err)
-	ctx.SetGoAST(fset, file)
-
-	require.Empty(t, NewOrphanedInterfaceRule().AnalyzeFile(ctx))
+`,
+	})
+	assert.Empty(t, violations)
 }
 
 // Интерфейс, встроенный в другой интерфейс (type B interface { A }), используется.
 func TestOrphanedInterfaceEmbeddingIsUsage(t *testing.T) {
-	src := `package sample
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"sample/resource.go": `package sample
 
 type Closable interface{ CloseIt() error }
 
@@ -79,12 +120,33 @@ type Resource interface {
 }
 
 func handle(res Resource) { _ = res }
-`
-	ctx, err := core.NewFileContextChecked("resource.go", ".", []byte(src), core.DefaultConfig())
-	require.NoError(t, err)
-	fset, file, err := core.NewParser().ParseGoFile(ctx.Path, ctx.Content)
-	require.NoError(t, err)
-	ctx.SetGoAST(fset, file)
+`,
+	})
+	assert.Empty(t, violations)
+}
+
+// A self-reference inside the declaration does not count.
+func TestOrphanedInterfaceSelfReferenceIsNoUsage(t *testing.T) {
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"tree/node.go": "package tree\n\ntype Node interface{ Children() []Node }\n",
+	})
+	require.Len(t, violations, 1)
+}
+
+func TestOrphanedInterfaceMentionedOnlyByTests(t *testing.T) {
+	violations := analyzeOrphanedInterfaces(t, map[string]string{
+		"cache/cache.go": "package cache\n\ntype Evicter interface{ Evict(key string) }\n",
+		"cache/cache_test.go": `package cache
+
+import "testing"
+
+type fakeEvicter struct{}
+
+func (fakeEvicter) Evict(string) {}
 
-	require.Empty(t, NewOrphanedInterfaceRule().AnalyzeFile(ctx))
+func TestEvict(t *testing.T) { var _ Evicter = fakeEvicter{} }
+`,
+	})
+	require.Len(t, violations, 1)
+	assert.Contains(t, violations[0].Message, "outside tests")
 }
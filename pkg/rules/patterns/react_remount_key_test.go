@@ -121,3 +121,23 @@ func TestReactRemountKeyRuleSkipsNonFrontendFiles(t *testing.T) {
 	testCtx := core.NewFileContext("/src/app/page.test.tsx", "/src", []byte(code), core.DefaultConfig())
 	assert.Empty(t, rule.AnalyzeFile(testCtx))
 }
+
+func TestReactRemountKeyRuleReportsKeyAttribute(t *testing.T) {
+	code := `export const Rows = ({ rows }: Props) => (
+  <ul>
+    {rows.map((row, i) => <li key={row.label.trim() + i}>
+      <Field value={row.label} onChangeText={setLabel} />
+    </li>)}
+    <li key={other.label}>{other.label}</li>
+  </ul>
+)`
+	ctx := core.NewFileContext("/src/app/rows.tsx", "/src", []byte(code), core.DefaultConfig())
+	violations := NewReactRemountKeyRule().AnalyzeFile(ctx)
+	if assert.Len(t, violations, 1) {
+		v := violations[0]
+		assert.Equal(t, 3, v.Line)
+		assert.Equal(t, 31, v.Column)
+		assert.Equal(t, 0, v.EndLine)
+		assert.Equal(t, "row.label", v.Context["key_path"])
+	}
+}
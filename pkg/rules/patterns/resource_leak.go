@@ -0,0 +1,327 @@
+package patterns
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewResourceLeakRule())
+}
+
+// resourceSpec describes a resource type for resource-leak: how a value is
+// acquired, which methods release it and whether returning it hands the
+// release to the caller.
+type resourceSpec struct {
+	name string
+	// constructors are full function names: "os.Open",
+	// "(*database/sql.DB).BeginTx".
+	constructors map[string]bool
+	// typeName matches any call whose first result has this type:
+	// "*time.Ticker".
+	typeName        string
+	releases        []string
+	returnTransfers bool
+}
+
+// defaultResources are the resources the standard library hands out that
+// must be released on every path.
+var defaultResources = []resourceSpec{
+	{
+		name:            "file",
+		constructors:    map[string]bool{"os.Open": true, "os.Create": true, "os.OpenFile": true, "os.CreateTemp": true},
+		releases:        []string{"Close"},
+		returnTransfers: true,
+	},
+	{
+		name:            "ticker",
+		constructors:    map[string]bool{"time.NewTicker": true},
+		releases:        []string{"Stop"},
+		returnTransfers: true,
+	},
+	{
+		name: "transaction",
+		constructors: map[string]bool{
+			"(*database/sql.DB).Begin":     true,
+			"(*database/sql.DB).BeginTx":   true,
+			"(*database/sql.Conn).BeginTx": true,
+		},
+		releases:        []string{"Commit", "Rollback"},
+		returnTransfers: true,
+	},
+}
+
+// ResourceLeakRule detects a resource that some path out of the function
+// neither releases, returns nor hands over:
+//
+//	f, err := os.Open(path)
+//	if err != nil {
+//		return err
+//	}
+//	if !valid(header) {
+//		return errInvalid // f is never closed
+//	}
+//	defer f.Close()
+//
+// Files, tickers and transactions are built in. Project types with the same
+// contract — a consumer that must be closed, a lease that must be released —
+// are declared in the configuration:
+//
+//	resource-leak:
+//	  settings:
+//	    resources:
+//	      - name: kafka consumer
+//	        constructors: ["github.com/acme/kafka.NewConsumer"]
+//	        release: [Close]
+//	      - name: object body
+//	        type: "*github.com/acme/storage.Object"
+//	        release: [Body.Close]
+//	        return_transfers: false
+//
+// An entry named like a built-in replaces it. Passing the resource to a
+// function of the project is a hand-over when that function releases its
+// parameter; interface methods and function values are trusted, functions of
+// other modules are not.
+type ResourceLeakRule struct {
+	*rules.BaseRule
+	resources []resourceSpec
+}
+
+// NewResourceLeakRule creates the rule
+func NewResourceLeakRule() *ResourceLeakRule {
+	return &ResourceLeakRule{
+		BaseRule: rules.NewBaseRule(
+			"resource-leak",
+			"patterns",
+			"Detects files, tickers, transactions and configured resources not released on every path out of the function",
+			core.SeverityHigh,
+		),
+		resources: defaultResources,
+	}
+}
+
+// RequiresSSA reports that typed packages are enough — no SSA program needed.
+func (r *ResourceLeakRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile is unused: the rule works on the typed project.
+func (r *ResourceLeakRule) AnalyzeFile(_ *core.FileContext) []*core.Violation { return nil }
+
+// Configure reads the resources setting on top of the base rule settings.
+func (r *ResourceLeakRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return fmt.Errorf("configure resource-leak: %w", err)
+	}
+	raw, ok := settings["resources"]
+	if !ok {
+		return nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("configure resource-leak: resources must be a list, got %T", raw)
+	}
+	resources := append([]resourceSpec(nil), defaultResources...)
+	for i, item := range list {
+		spec, err := parseResourceSpec(item)
+		if err != nil {
+			return fmt.Errorf("configure resource-leak: resources item %d: %w", i, err)
+		}
+		replaced := false
+		for j := range resources {
+			if resources[j].name == spec.name {
+				resources[j], replaced = spec, true
+			}
+		}
+		if !replaced {
+			resources = append(resources, spec)
+		}
+	}
+	r.resources = resources
+	return nil
+}
+
+func parseResourceSpec(item any) (resourceSpec, error) {
+	fields, ok := item.(map[string]any)
+	if !ok {
+		return resourceSpec{}, fmt.Errorf("must be a map, got %T", item)
+	}
+	spec := resourceSpec{returnTransfers: true}
+	name, ok := fields["name"].(string)
+	if !ok || strings.TrimSpace(name) == "" {
+		return resourceSpec{}, errors.New("name must be a non-empty string")
+	}
+	spec.name = name
+	if raw, ok := fields["constructors"]; ok {
+		constructors, err := settingStrings("constructors", raw)
+		if err != nil {
+			return resourceSpec{}, err
+		}
+		spec.constructors = make(map[string]bool, len(constructors))
+		for _, constructor := range constructors {
+			spec.constructors[constructor] = true
+		}
+	}
+	if raw, ok := fields["type"]; ok {
+		typeName, ok := raw.(string)
+		if !ok || strings.TrimSpace(typeName) == "" {
+			return resourceSpec{}, errors.New("type must be a non-empty string")
+		}
+		spec.typeName = typeName
+	}
+	if len(spec.constructors) == 0 && spec.typeName == "" {
+		return resourceSpec{}, fmt.Errorf("%s needs constructors or a type", name)
+	}
+	releases, err := settingStrings("release", fields["release"])
+	if err != nil {
+		return resourceSpec{}, err
+	}
+	if len(releases) == 0 {
+		return resourceSpec{}, fmt.Errorf("%s needs at least one release method", name)
+	}
+	spec.releases = releases
+	if raw, ok := fields["return_transfers"]; ok {
+		transfers, ok := raw.(bool)
+		if !ok {
+			return resourceSpec{}, fmt.Errorf("return_transfers must be a bool, got %T", raw)
+		}
+		spec.returnTransfers = transfers
+	}
+	return spec, nil
+}
+
+// settingStrings reads a list of non-empty strings.
+func settingStrings(key string, raw any) ([]string, error) {
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list, got %T", key, raw)
+	}
+	values := make([]string, 0, len(list))
+	for i, item := range list {
+		value, ok := item.(string)
+		if !ok || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("%s item %d must be a non-empty string", key, i)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// AnalyzeGoProject follows the configured resources through every function
+// body of the project.
+func (r *ResourceLeakRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	functions, err := typedFunctions(ctx, "resource leak")
+	if err != nil {
+		return nil, err
+	}
+	decls := projectFuncDecls(ctx)
+	var violations []*core.Violation
+	for _, fn := range functions {
+		kinds := r.kinds(fn)
+		for _, leak := range analyzeResourceLifecycles(fn.body, kinds, typedHandOff(fn, decls)) {
+			violations = append(violations, r.report(fn, leak))
+		}
+	}
+	return violations, nil
+}
+
+// kinds binds the resource specs to the type information of one function.
+func (r *ResourceLeakRule) kinds(fn typedFunction) []*resourceKind {
+	kinds := make([]*resourceKind, 0, len(r.resources))
+	for _, spec := range r.resources {
+		kinds = append(kinds, &resourceKind{
+			name: spec.name,
+			acquires: func(call *ast.CallExpr) bool {
+				if spec.constructors[fn.callee(call)] {
+					return true
+				}
+				return spec.typeName != "" && firstResultType(fn.info, call) == spec.typeName
+			},
+			releases:        spec.releases,
+			returnTransfers: spec.returnTransfers,
+		})
+	}
+	return kinds
+}
+
+// firstResultType returns the type of a call's first result as written by
+// types.TypeString, or "".
+func firstResultType(info *types.Info, call *ast.CallExpr) string {
+	t := info.TypeOf(call)
+	if tuple, ok := t.(*types.Tuple); ok {
+		if tuple.Len() == 0 {
+			return ""
+		}
+		t = tuple.At(0).Type()
+	}
+	if t == nil {
+		return ""
+	}
+	return types.TypeString(t, nil)
+}
+
+// projectFuncDecls maps the functions and methods of the project to their
+// declarations.
+func projectFuncDecls(ctx *core.GoProjectContext) map[*types.Func]*ast.FuncDecl {
+	decls := make(map[*types.Func]*ast.FuncDecl)
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.Package == nil || pkg.Package.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Package.Syntax {
+			for _, d := range file.Decls {
+				decl, ok := d.(*ast.FuncDecl)
+				if !ok || decl.Body == nil {
+					continue
+				}
+				if fn, ok := pkg.Package.TypesInfo.Defs[decl.Name].(*types.Func); ok {
+					decls[fn] = decl
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// typedHandOff trusts interface methods and function values with a resource,
+// checks the functions of the project for a release of their parameter and
+// keeps the resource with the caller for functions of other modules, which
+// only borrow it: json.NewDecoder(f) does not close f.
+func typedHandOff(fn typedFunction, decls map[*types.Func]*ast.FuncDecl) resourceHandOff {
+	return func(call *ast.CallExpr, index int, kind *resourceKind) bool {
+		callee, ok := typeutil.Callee(fn.info, call).(*types.Func)
+		if !ok {
+			return true
+		}
+		if sig, ok := callee.Type().(*types.Signature); ok && sig.Recv() != nil && types.IsInterface(sig.Recv().Type()) {
+			return true
+		}
+		decl, ok := decls[callee.Origin()]
+		if !ok {
+			return false
+		}
+		return releasesParam(decl, index, kind)
+	}
+}
+
+func (r *ResourceLeakRule) report(fn typedFunction, leak resourceLeak) *core.Violation {
+	held := leak.resource
+	exit := fn.file.LineForPos(leak.exit)
+	message := fmt.Sprintf("the %s in %s is not released on the path that leaves the function at line %d",
+		held.kind.name, held.variable, exit)
+	if leak.reassigned {
+		message = fmt.Sprintf("the %s in %s is still held when %s is assigned again at line %d",
+			held.kind.name, held.variable, held.variable, exit)
+	}
+	v := leak.violation(r.CreateViolation, fn.file, message,
+		fmt.Sprintf("Call %s on every path (defer it right after the error check), return %s or hand it to a function that releases it",
+			strings.Join(held.kind.releases, " or "), held.variable))
+	v.WithContext("pattern", "resource_leak")
+	return v
+}
@@ -0,0 +1,321 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeResourceLeaks(t *testing.T, rule *ResourceLeakRule, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := rule.AnalyzeGoProject(rulestest.Project(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func TestResourceLeakRule_Metadata(t *testing.T) {
+	rule := NewResourceLeakRule()
+	assert.Equal(t, "resource-leak", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.False(t, rule.RequiresSSA())
+	assert.Empty(t, rule.AnalyzeFile(&core.FileContext{}))
+
+	registered, ok := rules.Get("resource-leak")
+	require.True(t, ok)
+	assert.IsType(t, rule, registered)
+}
+
+func TestResourceLeakRule_Detection(t *testing.T) {
+	violations := analyzeResourceLeaks(t, NewResourceLeakRule(), map[string]string{"store/store.go": `package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+func Header(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return nil, err
+	}
+	f.Close()
+	return header, nil
+}
+
+func Poll(ctx context.Context, check func() bool) {
+	ticker := time.NewTicker(time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if check() {
+				return
+			}
+		}
+	}
+}
+
+func Transfer(ctx context.Context, db *sql.DB, amount int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 {
+		return errors.New("nothing to transfer")
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - $1", amount); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func Rotate(first, second string) error {
+	f, err := os.Create(first)
+	if err != nil {
+		return err
+	}
+	f, err = os.Create(second)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+`})
+
+	require.Len(t, violations, 4)
+	assert.Equal(t, 13, violations[0].Line)
+	assert.Equal(t, "the file in f is not released on the path that leaves the function at line 19", violations[0].Message)
+	assert.Equal(t, "f", violations[0].Context["variable"])
+	assert.Equal(t, 19, violations[0].Context["exit_line"])
+	assert.Equal(t, 26, violations[1].Line)
+	assert.Contains(t, violations[1].Message, "the ticker in ticker")
+	assert.Contains(t, violations[1].Suggestion, "Stop")
+	assert.Equal(t, 40, violations[2].Line)
+	assert.Equal(t, "the transaction in tx is not released on the path that leaves the function at line 45", violations[2].Message)
+	assert.Equal(t, 55, violations[3].Line)
+	assert.Equal(t, "the file in f is still held when f is assigned again at line 59", violations[3].Message)
+}
+
+func TestResourceLeakRule_AcceptsReleasedResources(t *testing.T) {
+	violations := analyzeResourceLeaks(t, NewResourceLeakRule(), map[string]string{"store/store.go": `package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+func Load(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
+}
+
+func Open(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func Count(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	return countLines(f)
+}
+
+func countLines(f *os.File) (int, error) {
+	defer f.Close()
+	return 0, nil
+}
+
+func Tick(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	<-ctx.Done()
+}
+
+func Transfer(ctx context.Context, db *sql.DB) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if _, err = tx.ExecContext(ctx, "DELETE FROM sessions"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+`})
+
+	assert.Empty(t, violations)
+}
+
+// A function of another module only borrows the resource: handing it over
+// does not release it.
+func TestResourceLeakRule_ExternalCallDoesNotTakeOver(t *testing.T) {
+	violations := analyzeResourceLeaks(t, NewResourceLeakRule(), map[string]string{"store/store.go": `package store
+
+import (
+	"encoding/json"
+	"os"
+)
+
+func Load(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(f).Decode(v)
+}
+`})
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, 9, violations[0].Line)
+}
+
+func TestResourceLeakRule_ConfiguredResource(t *testing.T) {
+	files := map[string]string{
+		"queue/queue.go": `package queue
+
+type Consumer struct{}
+
+func NewConsumer(topic string) (*Consumer, error) { return &Consumer{}, nil }
+
+func (c *Consumer) Poll() ([]byte, error) { return nil, nil }
+func (c *Consumer) Close() error           { return nil }
+`,
+		"worker/worker.go": `package worker
+
+import "example.com/rulestest/queue"
+
+func Drain(topic string) error {
+	consumer, err := queue.NewConsumer(topic)
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := consumer.Poll()
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			return consumer.Close()
+		}
+	}
+}
+
+func Connect(topic string) (*queue.Consumer, error) {
+	return queue.NewConsumer(topic)
+}
+`,
+	}
+	assert.Empty(t, analyzeResourceLeaks(t, NewResourceLeakRule(), files))
+
+	rule := NewResourceLeakRule()
+	require.NoError(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{
+			"name":         "consumer",
+			"constructors": []any{"example.com/rulestest/queue.NewConsumer"},
+			"release":      []any{"Close"},
+		},
+	}}))
+	violations := analyzeResourceLeaks(t, rule, files)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "worker/worker.go", violations[0].File)
+	assert.Equal(t, "the consumer in consumer is not released on the path that leaves the function at line 13", violations[0].Message)
+
+	byType := NewResourceLeakRule()
+	require.NoError(t, byType.Configure(map[string]any{"resources": []any{
+		map[string]any{
+			"name":             "consumer",
+			"type":             "*example.com/rulestest/queue.Consumer",
+			"release":          []any{"Close"},
+			"return_transfers": false,
+		},
+	}}))
+	violations = analyzeResourceLeaks(t, byType, files)
+	require.Len(t, violations, 1)
+	assert.Equal(t, 6, violations[0].Line)
+}
+
+// An entry named like a built-in replaces it: here files may no longer leave
+// the function that opened them.
+func TestResourceLeakRule_ConfigureReplacesBuiltIn(t *testing.T) {
+	files := map[string]string{"store/store.go": `package store
+
+import "os"
+
+func Open(path string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+`}
+	assert.Empty(t, analyzeResourceLeaks(t, NewResourceLeakRule(), files))
+
+	rule := NewResourceLeakRule()
+	require.NoError(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{"name": "file", "constructors": []any{"os.Open"}, "release": []any{"Close"}, "return_transfers": false},
+	}}))
+	violations := analyzeResourceLeaks(t, rule, files)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "the file in f is not released on the path that leaves the function at line 10", violations[0].Message)
+	assert.Len(t, rule.resources, len(defaultResources))
+	assert.True(t, NewResourceLeakRule().resources[0].returnTransfers, "the built-in list is left alone")
+}
+
+func TestResourceLeakRule_ConfigureRejectsBadSettings(t *testing.T) {
+	rule := NewResourceLeakRule()
+
+	require.Error(t, rule.Configure(map[string]any{"resources": "file"}))
+	require.Error(t, rule.Configure(map[string]any{"resources": []any{"file"}}))
+	require.Error(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{"constructors": []any{"os.Open"}, "release": []any{"Close"}},
+	}}))
+	require.Error(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{"name": "lease", "release": []any{"Release"}},
+	}}))
+	require.Error(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{"name": "lease", "type": "*lease.Lease"},
+	}}))
+	require.Error(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{"name": "lease", "type": "*lease.Lease", "release": []any{" "}},
+	}}))
+	require.Error(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{"name": "lease", "type": "*lease.Lease", "release": []any{"Release"}, "return_transfers": "yes"},
+	}}))
+	require.NoError(t, rule.Configure(map[string]any{"resources": []any{
+		map[string]any{"name": "lease", "type": "*lease.Lease", "release": []any{"Release"}},
+	}}))
+}
@@ -0,0 +1,468 @@
+package patterns
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// resourceKind is one kind of resource the lifecycle analysis follows: the
+// calls that acquire it, the calls that release it and whether returning it
+// hands the release to the caller.
+type resourceKind struct {
+	name     string
+	acquires func(call *ast.CallExpr) bool
+	// releases are method paths relative to the variable holding the
+	// resource: "Close", "Stop", "Body.Close".
+	releases        []string
+	returnTransfers bool
+}
+
+// resourceHandOff reports whether passing a resource as the index-th argument
+// of a call hands its release over to the callee.
+type resourceHandOff func(call *ast.CallExpr, index int, kind *resourceKind) bool
+
+// heldResource is a resource acquired on the current path and neither
+// released nor handed over yet.
+type heldResource struct {
+	kind     *resourceKind
+	variable string
+	// errVar is the error returned with the resource: where it is known not
+	// to be nil, there is no resource to release.
+	errVar   string
+	acquired *ast.AssignStmt
+}
+
+// resourceLeak is a resource still held where a path leaves the function, or
+// where its variable is assigned again.
+type resourceLeak struct {
+	resource   *heldResource
+	exit       token.Pos
+	reassigned bool
+}
+
+// resourceState holds the resources of the current path by their
+// acquisition. nil is the dead state.
+type resourceState map[*ast.AssignStmt]*heldResource
+
+// resourceLifecycle follows resources through one function body with the
+// shared flow walker. A resource must be released, returned (when its kind
+// allows) or handed to a callee on every path that leaves the function; a
+// return or a panic with the resource still held is a leak. Paths where the
+// error returned with the resource is not nil, or the resource itself is nil,
+// hold nothing. A function literal that refers to the resource takes it over,
+// which covers `defer func() { _ = rows.Close() }()`.
+type resourceLifecycle struct {
+	kinds    []*resourceKind
+	handOff  resourceHandOff
+	leaks    []resourceLeak
+	reported map[*ast.AssignStmt]bool
+}
+
+// analyzeResourceLifecycles returns the leaks of body in acquisition order.
+func analyzeResourceLifecycles(body *ast.BlockStmt, kinds []*resourceKind, handOff resourceHandOff) []resourceLeak {
+	if body == nil || len(kinds) == 0 {
+		return nil
+	}
+	l := &resourceLifecycle{kinds: kinds, handOff: handOff, reported: make(map[*ast.AssignStmt]bool)}
+	walker := &flowWalker[resourceState, struct{}]{rule: l}
+	edges := walker.walk(body, resourceState{}, struct{}{})
+	if edges.next != nil {
+		l.leave(edges.next, body.Rbrace, false)
+	}
+	sort.SliceStable(l.leaks, func(i, j int) bool {
+		return l.leaks[i].resource.acquired.Pos() < l.leaks[j].resource.acquired.Pos()
+	})
+	return l.leaks
+}
+
+// violation reports a leak at the line the resource was acquired.
+func (leak resourceLeak) violation(
+	create func(file string, line int, message string) *core.Violation,
+	ctx *core.FileContext,
+	message, suggestion string,
+) *core.Violation {
+	line := ctx.LineFor(leak.resource.acquired)
+	v := create(ctx.RelPath, line, message)
+	v.WithCode(ctx.GetLine(line))
+	v.WithSuggestion(suggestion)
+	v.WithContext("variable", leak.resource.variable)
+	v.WithContext("exit_line", ctx.LineForPos(leak.exit))
+	return v
+}
+
+// leave records the resources still held where a path ends.
+func (l *resourceLifecycle) leave(state resourceState, exit token.Pos, reassigned bool) {
+	for _, held := range state {
+		if l.reported[held.acquired] {
+			continue
+		}
+		l.reported[held.acquired] = true
+		l.leaks = append(l.leaks, resourceLeak{resource: held, exit: exit, reassigned: reassigned})
+	}
+}
+
+// acquisition returns the kind of resource an assignment acquires, or nil.
+func (l *resourceLifecycle) acquisition(assign *ast.AssignStmt) *resourceKind {
+	if len(assign.Rhs) != 1 {
+		return nil
+	}
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	for _, kind := range l.kinds {
+		if kind.acquires(call) {
+			return kind
+		}
+	}
+	return nil
+}
+
+func (l *resourceLifecycle) assign(assign *ast.AssignStmt, state resourceState) {
+	for i, rhs := range assign.Rhs {
+		if len(assign.Rhs) == len(assign.Lhs) && isBlank(assign.Lhs[i]) {
+			if _, ok := ast.Unparen(rhs).(*ast.Ident); ok {
+				continue
+			}
+		}
+		l.scan(rhs, state)
+	}
+	for _, lhs := range assign.Lhs {
+		if _, ok := lhs.(*ast.Ident); !ok {
+			l.scan(lhs, state)
+		}
+	}
+	kind := l.acquisition(assign)
+	for i, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			continue
+		}
+		for key, held := range state {
+			switch {
+			case held.variable == ident.Name:
+				if kind != nil && i == 0 {
+					l.leave(resourceState{key: held}, assign.Pos(), true)
+				}
+				delete(state, key)
+			case held.errVar == ident.Name:
+				forgotten := *held
+				forgotten.errVar = ""
+				state[key] = &forgotten
+			}
+		}
+	}
+	if kind == nil || isBlank(assign.Lhs[0]) {
+		return
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	held := &heldResource{kind: kind, variable: ident.Name, acquired: assign}
+	if last, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident); ok && len(assign.Lhs) > 1 && last.Name != "_" {
+		held.errVar = last.Name
+	}
+	state[assign] = held
+}
+
+func isBlank(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// scan applies the releases, hand-overs and escapes inside node.
+func (l *resourceLifecycle) scan(node ast.Node, state resourceState) {
+	if node == nil || len(state) == 0 {
+		return
+	}
+	var stack []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		var parent ast.Node
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			l.capture(n, state)
+			return false
+		case *ast.CallExpr:
+			l.release(n, state)
+		case *ast.Ident:
+			l.use(n, parent, state)
+		}
+		stack = append(stack, n)
+		return true
+	})
+}
+
+// capture hands the resources a function literal refers to over to it.
+func (l *resourceLifecycle) capture(lit *ast.FuncLit, state resourceState) {
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			l.drop(ident.Name, state, func(*heldResource) bool { return true })
+		}
+		return true
+	})
+}
+
+// release drops the resource a call releases.
+func (l *resourceLifecycle) release(call *ast.CallExpr, state resourceState) {
+	root, path := releasePath(call)
+	if root == "" {
+		return
+	}
+	l.drop(root, state, func(held *heldResource) bool { return slices.Contains(held.kind.releases, path) })
+}
+
+// releasePath splits a method call on a variable into the variable and the
+// path to the method: rows.Close() gives "rows" and "Close", resp.Body.Close()
+// gives "resp" and "Body.Close".
+func releasePath(call *ast.CallExpr) (root, path string) {
+	var parts []string
+	expr := ast.Expr(call.Fun)
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.SelectorExpr:
+			parts = append(parts, e.Sel.Name)
+			expr = e.X
+			continue
+		case *ast.Ident:
+			if len(parts) == 0 {
+				return "", ""
+			}
+			slices.Reverse(parts)
+			return e.Name, strings.Join(parts, ".")
+		}
+		return "", ""
+	}
+}
+
+// use classifies an appearance of a resource variable: calling its methods,
+// reading its fields and comparing it with nil keep it held; returning it or
+// passing it to a call may hand it over; anything else — storing, sending,
+// aliasing — lets it escape the analysis.
+func (l *resourceLifecycle) use(ident *ast.Ident, parent ast.Node, state resourceState) {
+	switch p := parent.(type) {
+	case *ast.SelectorExpr, *ast.KeyValueExpr:
+		return
+	case *ast.BinaryExpr:
+		if isNilIdent(p.X) || isNilIdent(p.Y) {
+			return
+		}
+	case *ast.CallExpr:
+		index := slices.IndexFunc(p.Args, func(arg ast.Expr) bool { return arg == ident })
+		if index < 0 {
+			return
+		}
+		l.drop(ident.Name, state, func(held *heldResource) bool { return l.handOff(p, index, held.kind) })
+		return
+	case *ast.ReturnStmt:
+		l.drop(ident.Name, state, func(held *heldResource) bool { return held.kind.returnTransfers })
+		return
+	}
+	l.drop(ident.Name, state, func(*heldResource) bool { return true })
+}
+
+// drop removes the resources held in variable that match.
+func (l *resourceLifecycle) drop(variable string, state resourceState, match func(*heldResource) bool) {
+	for key, held := range state {
+		if held.variable == variable && match(held) {
+			delete(state, key)
+		}
+	}
+}
+
+// sameFileHandOff trusts callees it cannot see with the resource, and checks
+// the plain functions declared in file for a release of their parameter: a
+// helper that never releases it leaves the leak with the caller.
+func sameFileHandOff(file *ast.File) resourceHandOff {
+	return func(call *ast.CallExpr, index int, kind *resourceKind) bool {
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		decl := findFuncDecl(file, ident.Name)
+		if decl == nil || decl.Body == nil {
+			return true
+		}
+		return releasesParam(decl, index, kind)
+	}
+}
+
+// releasesParam reports whether a function releases its index-th parameter;
+// unnamed parameters are trusted.
+func releasesParam(decl *ast.FuncDecl, index int, kind *resourceKind) bool {
+	name := paramNameAt(decl.Type, index)
+	if name == "" {
+		return true
+	}
+	releases := false
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			root, path := releasePath(call)
+			releases = releases || root == name && slices.Contains(kind.releases, path)
+		}
+		return !releases
+	})
+	return releases
+}
+
+// findFuncDecl returns the package-level function named name, or nil.
+func findFuncDecl(file *ast.File, name string) *ast.FuncDecl {
+	if file == nil {
+		return nil
+	}
+	for _, d := range file.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// paramNameAt returns the name of the index-th parameter, counting grouped
+// names (a, b T) separately; "" when the parameter is unnamed or absent.
+func paramNameAt(ft *ast.FuncType, index int) string {
+	if ft == nil || ft.Params == nil {
+		return ""
+	}
+	i := 0
+	for _, field := range ft.Params.List {
+		if len(field.Names) == 0 {
+			if i == index {
+				return ""
+			}
+			i++
+			continue
+		}
+		for _, n := range field.Names {
+			if i == index {
+				return n.Name
+			}
+			i++
+		}
+	}
+	return ""
+}
+
+func (l *resourceLifecycle) cloneState(state resourceState) resourceState { return maps.Clone(state) }
+
+// joinStates keeps the resources held on either path: a leak on one path is
+// a leak.
+func (l *resourceLifecycle) joinStates(left, right resourceState) resourceState {
+	joined := maps.Clone(left)
+	for key, held := range right {
+		if _, ok := joined[key]; !ok {
+			joined[key] = held
+		}
+	}
+	return joined
+}
+
+func (l *resourceLifecycle) liveState(state resourceState) bool { return state != nil }
+
+func (l *resourceLifecycle) deadState() resourceState { return nil }
+
+func (l *resourceLifecycle) enterScope(
+	_ flowScopeKind,
+	_ ast.Node,
+	parent struct{},
+	state resourceState,
+) (struct{}, resourceState) {
+	return parent, state
+}
+
+func (l *resourceLifecycle) leaveScope(flowScopeKind, struct{}, *flowEdges[resourceState]) {}
+
+func (l *resourceLifecycle) simpleStmt(stmt ast.Stmt, state resourceState, _ struct{}) (resourceState, bool) {
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		l.assign(stmt, state)
+	case *ast.ReturnStmt:
+		l.scan(stmt, state)
+		l.leave(state, stmt.Pos(), false)
+		return nil, true
+	default:
+		l.scan(stmt, state)
+		if isPanicStatement(stmt) {
+			l.leave(state, stmt.Pos(), false)
+			return nil, true
+		}
+	}
+	return state, false
+}
+
+// ifCondition drops, from the branch where it holds, the resources a nil
+// comparison of their error or of themselves shows were never acquired.
+func (l *resourceLifecycle) ifCondition(stmt *ast.IfStmt, state resourceState, _ struct{}) (resourceState, resourceState) {
+	l.scan(stmt.Cond, state)
+	thenState, elseState := state, maps.Clone(state)
+	operand, equal, ok := nilComparison(stmt.Cond)
+	if !ok {
+		return thenState, elseState
+	}
+	ident, ok := ast.Unparen(operand).(*ast.Ident)
+	if !ok {
+		return thenState, elseState
+	}
+	for key, held := range state {
+		switch ident.Name {
+		case held.errVar:
+			if equal {
+				delete(elseState, key)
+			} else {
+				delete(thenState, key)
+			}
+		case held.variable:
+			if equal {
+				delete(thenState, key)
+			} else {
+				delete(elseState, key)
+			}
+		}
+	}
+	return thenState, elseState
+}
+
+func (l *resourceLifecycle) flowExpr(expr ast.Expr, state resourceState, _ struct{}) resourceState {
+	l.scan(expr, state)
+	return state
+}
+
+func (l *resourceLifecycle) rangeVars(_ *ast.RangeStmt, state resourceState, _ struct{}) resourceState {
+	return state
+}
+
+func (l *resourceLifecycle) typeSwitchGuard(stmt ast.Stmt, state resourceState, _ struct{}) resourceState {
+	l.scan(stmt, state)
+	return state
+}
+
+func (l *resourceLifecycle) caseClause(
+	_ ast.Stmt,
+	clause *ast.CaseClause,
+	state resourceState,
+	parent struct{},
+) (resourceState, struct{}) {
+	for _, expr := range clause.List {
+		l.scan(expr, state)
+	}
+	return state, parent
+}
+
+func (l *resourceLifecycle) commClause(_ *ast.CommClause, state resourceState, parent struct{}) (resourceState, struct{}) {
+	return state, parent
+}
+
+func (l *resourceLifecycle) normalize(*flowEdges[resourceState]) {}
@@ -72,6 +72,25 @@ func example(db *sql.DB) {
 `,
 			expectMatch: false,
 		},
+		{
+			name: "early return skips the close",
+			code: `package main
+
+import "database/sql"
+
+func example(db *sql.DB, limit int) {
+	rows, err := db.Query("SELECT * FROM users")
+	if err != nil {
+		return
+	}
+	if limit == 0 {
+		return
+	}
+	rows.Close()
+}
+`,
+			expectMatch: true,
+		},
 		{
 			name: "queryContext without close",
 			code: `package main
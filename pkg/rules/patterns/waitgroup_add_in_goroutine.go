@@ -0,0 +1,117 @@
+package patterns
+
+import (
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewWaitGroupAddInGoroutineRule())
+}
+
+// WaitGroupAddInGoroutineRule detects sync.WaitGroup.Add called inside the
+// goroutine it counts:
+//
+//	for _, item := range items {
+//		go func() {
+//			wg.Add(1)          // may run after wg.Wait() has returned
+//			defer wg.Done()
+//			process(item)
+//		}()
+//	}
+//	wg.Wait()
+//
+// Nothing orders the Add against the Wait in the starting goroutine: when the
+// scheduler has not run the new goroutine yet, Wait sees a zero counter and
+// returns while the work is still to come. Add belongs before the go
+// statement.
+//
+// Only an Add on a WaitGroup from outside the goroutine whose Done the same
+// goroutine calls is flagged; a goroutine that adds for the goroutines it
+// starts itself is fine.
+type WaitGroupAddInGoroutineRule struct {
+	*rules.BaseRule
+}
+
+// NewWaitGroupAddInGoroutineRule creates the rule
+func NewWaitGroupAddInGoroutineRule() *WaitGroupAddInGoroutineRule {
+	return &WaitGroupAddInGoroutineRule{
+		BaseRule: rules.NewBaseRule(
+			"waitgroup-add-in-goroutine",
+			"patterns",
+			"Detects sync.WaitGroup.Add called inside the goroutine it counts, racing with Wait",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op because this rule requires shared package SSA.
+func (r *WaitGroupAddInGoroutineRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *WaitGroupAddInGoroutineRule) RequiresSSA() bool { return true }
+
+// AnalyzeGoProject reports the Add calls of goroutines that also call Done on
+// the same WaitGroup.
+func (r *WaitGroupAddInGoroutineRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	project, err := newSSAProject(ctx, "waitgroup add in goroutine")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, g := range project.goroutines() {
+		adds, done := waitGroupCalls(g.body)
+		for _, add := range adds {
+			group := stripLoads(add.Call.Args[0])
+			if !done[group] || !outsideValue(group) {
+				continue
+			}
+			v := project.violation(r.CreateViolation, add.Pos(),
+				"WaitGroup.Add inside the goroutine it counts races with Wait: the goroutine may not have started when Wait returns")
+			if v == nil {
+				continue
+			}
+			v.WithSuggestion("Call wg.Add before the go statement, or use wg.Go (Go 1.25)")
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// waitGroupCalls returns the WaitGroup.Add calls of a function and the
+// WaitGroups it calls or defers Done on.
+func waitGroupCalls(fn *ssa.Function) ([]*ssa.Call, map[ssa.Value]bool) {
+	var adds []*ssa.Call
+	done := make(map[ssa.Value]bool)
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok || len(call.Common().Args) == 0 {
+				continue
+			}
+			switch calledFunc(call.Common()) {
+			case "(*sync.WaitGroup).Add":
+				if add, ok := call.(*ssa.Call); ok {
+					adds = append(adds, add)
+				}
+			case "(*sync.WaitGroup).Done":
+				done[stripLoads(call.Common().Args[0])] = true
+			}
+		}
+	}
+	return adds, done
+}
+
+// outsideValue reports whether a value comes from outside the function:
+// a captured variable, a parameter or a package variable.
+func outsideValue(value ssa.Value) bool {
+	switch value.(type) {
+	case *ssa.FreeVar, *ssa.Parameter, *ssa.Global:
+		return true
+	}
+	return false
+}
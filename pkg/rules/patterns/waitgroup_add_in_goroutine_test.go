@@ -0,0 +1,105 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeWaitGroupAdds(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.ProjectWithSSA(t, map[string]string{"batch/batch.go": source})
+	violations, err := NewWaitGroupAddInGoroutineRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestWaitGroupAddInGoroutineRule_Metadata(t *testing.T) {
+	rule := NewWaitGroupAddInGoroutineRule()
+	assert.Equal(t, "waitgroup-add-in-goroutine", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.True(t, rule.RequiresSSA())
+}
+
+func TestWaitGroupAddInGoroutineRule_Detection(t *testing.T) {
+	violations := analyzeWaitGroupAdds(t, `package batch
+
+import "sync"
+
+func Run(items []int) {
+	var wg sync.WaitGroup
+	for _, item := range items {
+		go func() {
+			wg.Add(1)
+			defer wg.Done()
+			process(item)
+		}()
+	}
+	wg.Wait()
+}
+
+func RunShared(wg *sync.WaitGroup, items []int) {
+	for _, item := range items {
+		go work(wg, item)
+	}
+}
+
+func work(wg *sync.WaitGroup, item int) {
+	wg.Add(1)
+	process(item)
+	wg.Done()
+}
+
+func process(item int) {}
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, 9, violations[0].Line)
+	assert.Equal(t, 24, violations[1].Line)
+}
+
+func TestWaitGroupAddInGoroutineRule_AcceptsAddBeforeGo(t *testing.T) {
+	violations := analyzeWaitGroupAdds(t, `package batch
+
+import "sync"
+
+func Run(items []int) {
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			process(item)
+		}()
+	}
+	wg.Wait()
+}
+
+// Fan adds for the goroutines it starts itself, while it is counted.
+func Fan(items []int) {
+	var outer, inner sync.WaitGroup
+	outer.Add(1)
+	go func() {
+		defer outer.Done()
+		for _, item := range items {
+			inner.Add(1)
+			go func() {
+				defer inner.Done()
+				process(item)
+			}()
+		}
+	}()
+	outer.Wait()
+	inner.Wait()
+}
+
+func process(item int) {}
+`)
+
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,212 @@
+package patterns
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewWriteCloseErrorDroppedRule())
+}
+
+// writeOpenFlags are the os.OpenFile flags that open a file for writing.
+var writeOpenFlags = map[string]bool{
+	"O_WRONLY": true,
+	"O_RDWR":   true,
+	"O_APPEND": true,
+	"O_CREATE": true,
+	"O_TRUNC":  true,
+}
+
+// WriteCloseErrorDroppedRule detects `defer f.Close()` on a file opened for
+// writing, with no other Close or Sync whose error is checked:
+//
+//	f, err := os.Create(path)
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()          // the error that reports a failed flush is lost
+//	_, err = f.Write(data)
+//	return err
+//
+// Data written to a file may reach the disk only when it is closed; on NFS,
+// a full disk or a quota, Close is where the failure shows up. Deferring it
+// bare reports success for a file that was never written. For files only
+// read, the Close error carries nothing and the rule stays quiet.
+//
+// A checked `err := f.Close()` or `f.Sync()` elsewhere in the function makes
+// the deferred Close the safety net it is meant to be and is accepted.
+type WriteCloseErrorDroppedRule struct {
+	*rules.BaseRule
+}
+
+// NewWriteCloseErrorDroppedRule creates the rule
+func NewWriteCloseErrorDroppedRule() *WriteCloseErrorDroppedRule {
+	return &WriteCloseErrorDroppedRule{
+		BaseRule: rules.NewBaseRule(
+			"write-close-error-dropped",
+			"patterns",
+			"Detects defer f.Close() on a file opened for writing, dropping the error that reports a failed flush",
+			core.SeverityMedium,
+		),
+	}
+}
+
+// RequiresSSA reports that typed packages are enough — no SSA program needed.
+func (r *WriteCloseErrorDroppedRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile is unused: the rule works on the typed project.
+func (r *WriteCloseErrorDroppedRule) AnalyzeFile(_ *core.FileContext) []*core.Violation { return nil }
+
+// writtenFile is a file variable and the call that opened it for writing.
+type writtenFile struct {
+	open    *ast.CallExpr
+	name    string
+	defers  []*ast.DeferStmt
+	checked bool
+}
+
+// AnalyzeGoProject collects, per function, the files opened for writing and
+// how their Close and Sync results are used.
+func (r *WriteCloseErrorDroppedRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	functions, err := typedFunctions(ctx, "write close error dropped")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fn := range functions {
+		files := r.writtenFiles(fn)
+		if len(files) == 0 {
+			continue
+		}
+		r.closeCalls(fn, files)
+		var found []*core.Violation
+		for _, file := range files {
+			if file.checked {
+				continue
+			}
+			for _, deferStmt := range file.defers {
+				found = append(found, r.report(fn, file, deferStmt))
+			}
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].Line < found[j].Line })
+		violations = append(violations, found...)
+	}
+	return violations, nil
+}
+
+// writtenFiles returns the file variables the function assigns from
+// os.Create, os.CreateTemp or os.OpenFile with a write flag.
+func (r *WriteCloseErrorDroppedRule) writtenFiles(fn typedFunction) map[*types.Var]*writtenFile {
+	files := make(map[*types.Var]*writtenFile)
+	ast.Inspect(fn.body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+			return true
+		}
+		call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+		if !ok || !opensForWriting(fn, call) {
+			return true
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if v, ok := fn.info.ObjectOf(ident).(*types.Var); ok {
+			files[v] = &writtenFile{open: call, name: strings.TrimPrefix(fn.callee(call), "os.")}
+		}
+		return true
+	})
+	return files
+}
+
+func opensForWriting(fn typedFunction, call *ast.CallExpr) bool {
+	switch fn.callee(call) {
+	case "os.Create", "os.CreateTemp":
+		return true
+	case "os.OpenFile":
+		if len(call.Args) < 2 {
+			return false
+		}
+		writes := false
+		ast.Inspect(call.Args[1], func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok && writeOpenFlags[sel.Sel.Name] {
+				writes = true
+			}
+			return !writes
+		})
+		return writes
+	}
+	return false
+}
+
+// closeCalls records the bare deferred Close of each file and marks the files
+// with a Close or Sync whose error is used.
+func (r *WriteCloseErrorDroppedRule) closeCalls(fn typedFunction, files map[*types.Var]*writtenFile) {
+	dropped := make(map[*ast.CallExpr]bool)
+	ast.Inspect(fn.body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok {
+				dropped[call] = true
+			}
+		case *ast.DeferStmt:
+			dropped[n.Call] = true
+			if file := fileCall(fn, n.Call, files, "Close"); file != nil {
+				file.defers = append(file.defers, n)
+			}
+		case *ast.CallExpr:
+			if dropped[n] {
+				return true
+			}
+			for _, method := range []string{"Close", "Sync"} {
+				if file := fileCall(fn, n, files, method); file != nil {
+					file.checked = true
+				}
+			}
+		}
+		return true
+	})
+}
+
+// fileCall returns the written file a call calls the method of, or nil.
+func fileCall(fn typedFunction, call *ast.CallExpr, files map[*types.Var]*writtenFile, method string) *writtenFile {
+	if fn.callee(call) != "(*os.File)."+method {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	ident, ok := ast.Unparen(sel.X).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v, ok := fn.info.ObjectOf(ident).(*types.Var)
+	if !ok {
+		return nil
+	}
+	return files[v]
+}
+
+func (r *WriteCloseErrorDroppedRule) report(fn typedFunction, file *writtenFile, deferStmt *ast.DeferStmt) *core.Violation {
+	pos := fn.file.PositionFor(deferStmt)
+	opened := fn.line(file.open)
+	v := r.CreateViolation(fn.file.RelPath, pos.Line,
+		fmt.Sprintf("deferred Close of a file opened with os.%s (line %d) drops its error: a failed flush or a full disk goes unreported", file.name, opened))
+	v.WithCode(strings.TrimSpace(fn.file.GetLine(pos.Line)))
+	v.WithSuggestion("Check the error of Close (or Sync) before returning success; keep the deferred Close only as a safety net")
+	v.WithContext("opened_line", opened)
+	return v
+}
@@ -0,0 +1,114 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeWriteCloses(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.Project(t, map[string]string{"store/store.go": source})
+	violations, err := NewWriteCloseErrorDroppedRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestWriteCloseErrorDroppedRule_Metadata(t *testing.T) {
+	rule := NewWriteCloseErrorDroppedRule()
+	assert.Equal(t, "write-close-error-dropped", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityMedium, rule.DefaultSeverity())
+	assert.False(t, rule.RequiresSSA())
+}
+
+func TestWriteCloseErrorDroppedRule_Detection(t *testing.T) {
+	violations := analyzeWriteCloses(t, `package store
+
+import "os"
+
+func Save(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
+
+func Append(path string, line string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(line)
+	return err
+}
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, 10, violations[0].Line)
+	assert.Equal(t, "deferred Close of a file opened with os.Create (line 6) drops its error: a failed flush or a full disk goes unreported", violations[0].Message)
+	assert.Equal(t, 6, violations[0].Context["opened_line"])
+	assert.Equal(t, 20, violations[1].Line)
+	assert.Contains(t, violations[1].Message, "os.OpenFile")
+}
+
+func TestWriteCloseErrorDroppedRule_AcceptsCheckedCloses(t *testing.T) {
+	violations := analyzeWriteCloses(t, `package store
+
+import "os"
+
+func Read(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buf := make([]byte, 16)
+	n, err := f.Read(buf)
+	return buf[:n], err
+}
+
+func ReadOnly(path string) error {
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return nil
+}
+
+func Save(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func Synced(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+`)
+
+	assert.Empty(t, violations)
+}
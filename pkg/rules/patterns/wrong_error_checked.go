@@ -0,0 +1,148 @@
+package patterns
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewWrongErrorCheckedRule())
+}
+
+// WrongErrorCheckedRule detects a nil check of one error variable right after
+// a call that returned its error in another:
+//
+//	order, err := s.orders.Get(ctx, id)
+//	if err != nil { ... }
+//	invoice, err2 := s.billing.Invoice(ctx, order)
+//	if err != nil {                  // err2 is never looked at
+//		return err
+//	}
+//
+// The check compiles, reads naturally and always passes, so the failure of the
+// second call goes on as a zero invoice. The rule looks at the if statement
+// directly after the assignment, or at the if whose init is the assignment,
+// and reports it when its condition compares another error variable with nil
+// and never mentions the one just assigned.
+type WrongErrorCheckedRule struct {
+	*rules.BaseRule
+}
+
+// NewWrongErrorCheckedRule creates the rule
+func NewWrongErrorCheckedRule() *WrongErrorCheckedRule {
+	return &WrongErrorCheckedRule{
+		BaseRule: rules.NewBaseRule(
+			"wrong-error-checked",
+			"patterns",
+			"Detects an if err != nil that checks a different error variable than the one the call just before assigned",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// RequiresSSA reports that typed packages are enough — no SSA program needed.
+func (r *WrongErrorCheckedRule) RequiresSSA() bool { return false }
+
+// AnalyzeFile is unused: the rule works on the typed project.
+func (r *WrongErrorCheckedRule) AnalyzeFile(_ *core.FileContext) []*core.Violation { return nil }
+
+// AnalyzeGoProject pairs each error assignment with the if statement that
+// follows it.
+func (r *WrongErrorCheckedRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	functions, err := typedFunctions(ctx, "wrong error checked")
+	if err != nil {
+		return nil, err
+	}
+	var violations []*core.Violation
+	for _, fn := range functions {
+		ast.Inspect(fn.body, func(n ast.Node) bool {
+			var stmts []ast.Stmt
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.BlockStmt:
+				stmts = n.List
+			case *ast.CaseClause:
+				stmts = n.Body
+			case *ast.CommClause:
+				stmts = n.Body
+			}
+			for i, stmt := range stmts {
+				ifStmt, ok := stmt.(*ast.IfStmt)
+				if !ok {
+					continue
+				}
+				assign, ok := ifStmt.Init.(*ast.AssignStmt)
+				if !ok && ifStmt.Init == nil && i > 0 {
+					assign, ok = stmts[i-1].(*ast.AssignStmt)
+				}
+				if !ok {
+					continue
+				}
+				if v := r.check(fn, assign, ifStmt); v != nil {
+					violations = append(violations, v)
+				}
+			}
+			return true
+		})
+	}
+	return violations, nil
+}
+
+// check reports an if statement that compares an error variable other than
+// the one assign gives a call's error to.
+func (r *WrongErrorCheckedRule) check(fn typedFunction, assign *ast.AssignStmt, ifStmt *ast.IfStmt) *core.Violation {
+	if len(assign.Rhs) != 1 {
+		return nil
+	}
+	call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	var assigned *types.Var
+	for _, lhs := range assign.Lhs {
+		if v := fn.errorVar(lhs); v != nil {
+			if assigned != nil {
+				return nil
+			}
+			assigned = v
+		}
+	}
+	if assigned == nil || fn.uses(ifStmt.Cond, assigned) {
+		return nil
+	}
+	checked := checkedErrorVar(fn, ifStmt.Cond)
+	if checked == nil {
+		return nil
+	}
+	pos := fn.file.PositionFor(ifStmt)
+	callName := core.ExtractFullFunctionName(call)
+	v := r.CreateViolation(fn.file.RelPath, pos.Line,
+		fmt.Sprintf("checks %s, but the error of %s just before is in %s, which is never checked here", checked.Name(), callName, assigned.Name()))
+	v.WithCode(strings.TrimSpace(fn.file.GetLine(pos.Line)))
+	v.WithSuggestion(fmt.Sprintf("Check %s, the error %s returned", assigned.Name(), callName))
+	v.WithContext("assigned_line", fn.line(assign))
+	return v
+}
+
+// checkedErrorVar returns the first error variable a condition compares with
+// nil, or nil.
+func checkedErrorVar(fn typedFunction, cond ast.Expr) *types.Var {
+	var checked *types.Var
+	ast.Inspect(cond, func(n ast.Node) bool {
+		expr, ok := n.(ast.Expr)
+		if !ok || checked != nil {
+			return checked == nil
+		}
+		if operand, _, ok := nilComparison(expr); ok {
+			checked = fn.errorVar(operand)
+		}
+		return checked == nil
+	})
+	return checked
+}
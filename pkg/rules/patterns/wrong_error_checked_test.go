@@ -0,0 +1,88 @@
+package patterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeWrongErrorChecks(t *testing.T, source string) []*core.Violation {
+	t.Helper()
+	project := rulestest.Project(t, map[string]string{"api/api.go": source})
+	violations, err := NewWrongErrorCheckedRule().AnalyzeGoProject(project)
+	require.NoError(t, err)
+	return violations
+}
+
+func TestWrongErrorCheckedRule_Metadata(t *testing.T) {
+	rule := NewWrongErrorCheckedRule()
+	assert.Equal(t, "wrong-error-checked", rule.Name())
+	assert.Equal(t, "patterns", rule.Category())
+	assert.Equal(t, core.SeverityHigh, rule.DefaultSeverity())
+	assert.False(t, rule.RequiresSSA())
+}
+
+func TestWrongErrorCheckedRule_Detection(t *testing.T) {
+	violations := analyzeWrongErrorChecks(t, `package api
+
+func get(id int) (string, error)        { return "", nil }
+func invoice(order string) (int, error) { return 0, nil }
+func save(total int) error              { return nil }
+
+func Bill(id int) (int, error) {
+	order, err := get(id)
+	if err != nil {
+		return 0, err
+	}
+	total, err2 := invoice(order)
+	if err != nil {
+		return 0, err2
+	}
+	if err3 := save(total); err != nil {
+		return 0, err3
+	}
+	return total, nil
+}
+`)
+
+	require.Len(t, violations, 2)
+	assert.Equal(t, 13, violations[0].Line)
+	assert.Equal(t, "checks err, but the error of invoice just before is in err2, which is never checked here", violations[0].Message)
+	assert.Equal(t, 12, violations[0].Context["assigned_line"])
+	assert.Equal(t, 16, violations[1].Line)
+	assert.Contains(t, violations[1].Message, "err3")
+}
+
+func TestWrongErrorCheckedRule_AcceptsMatchingChecks(t *testing.T) {
+	violations := analyzeWrongErrorChecks(t, `package api
+
+func get(id int) (string, error)        { return "", nil }
+func invoice(order string) (int, error) { return 0, nil }
+
+func Bill(id int) (int, error) {
+	order, err := get(id)
+	if err != nil {
+		return 0, err
+	}
+	total, err2 := invoice(order)
+	if err2 != nil {
+		return 0, err2
+	}
+	_, err3 := invoice(order)
+	if err != nil || err3 != nil {
+		return 0, err3
+	}
+	total++
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+`)
+
+	assert.Empty(t, violations)
+}
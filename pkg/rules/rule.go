@@ -1,6 +1,9 @@
 package rules
 
 import (
+	"fmt"
+	"path/filepath"
+
 	"github.com/aiseeq/glint/pkg/core"
 )
 
@@ -126,6 +129,31 @@ func (r *BaseRule) GetFloat64Setting(key string, defaultVal float64) float64 {
 	return defaultVal
 }
 
+// GetFilePatterns reads the "files" setting: the base-name globs of the files
+// an ExtraFilesRule reads, replacing defaultVal.
+func (r *BaseRule) GetFilePatterns(defaultVal []string) ([]string, error) {
+	raw, ok := r.settings["files"]
+	if !ok {
+		return defaultVal, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("configure %s: files must be a list, got %T", r.name, raw)
+	}
+	patterns := make([]string, 0, len(list))
+	for _, item := range list {
+		pattern, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("configure %s: files entries must be strings, got %T", r.name, item)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("configure %s: files pattern %q: %w", r.name, pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
 // CreateViolation creates a new violation for this rule
 func (r *BaseRule) CreateViolation(file string, line int, message string) *core.Violation {
 	return core.NewViolation(r.name, r.category, file, line, r.defaultSeverity, message)
@@ -168,6 +196,37 @@ func ResetState(list []Rule) {
 	}
 }
 
+// ExtraFilesRule is an optional interface for rules that also read files
+// outside the analyzable extensions — configuration, dotenv, Dockerfiles. The
+// walker admits the files an enabled rule claims, and only the rules claiming
+// a file are run on it.
+type ExtraFilesRule interface {
+	Rule
+	// ExtraFiles returns the base-name globs of the files, as "*.yaml".
+	ExtraFiles() []string
+}
+
+// ExtraFiles returns the patterns every ExtraFilesRule in the list claims.
+func ExtraFiles(list []Rule) []string {
+	var patterns []string
+	for _, rule := range list {
+		if extra, ok := rule.(ExtraFilesRule); ok {
+			patterns = append(patterns, extra.ExtraFiles()...)
+		}
+	}
+	return patterns
+}
+
+// AnalyzesFile reports whether a rule is run on the file at path: every rule
+// reads the analyzable extensions, an ExtraFilesRule also the files it claims.
+func AnalyzesFile(rule Rule, path string) bool {
+	if core.IsAnalyzablePath(path) {
+		return true
+	}
+	extra, ok := rule.(ExtraFilesRule)
+	return ok && core.MatchesFileName(path, extra.ExtraFiles())
+}
+
 // SuppressionExempt is an optional interface for rules whose findings must
 // not be silenced by inline comments (nolint:<rule> / <rule>: safe). Policy
 // rules that forbid a pattern unconditionally implement it and return true.
@@ -129,3 +129,40 @@ func TestGetRuleInfo(t *testing.T) {
 	assert.Equal(t, "Info description", info.Description)
 	assert.Equal(t, core.SeverityCritical, info.Severity)
 }
+
+type extraFilesStubRule struct {
+	*BaseRule
+}
+
+func (r *extraFilesStubRule) AnalyzeFile(*core.FileContext) []*core.Violation { return nil }
+
+func (r *extraFilesStubRule) ExtraFiles() []string { return []string{"*.yaml"} }
+
+func TestAnalyzesFile(t *testing.T) {
+	plain := &extraFilesStubRule{BaseRule: NewBaseRule("plain", "cat", "desc", core.SeverityLow)}
+	var rule Rule = plain
+	assert.True(t, AnalyzesFile(rule, "/src/main.go"))
+	assert.True(t, AnalyzesFile(rule, "/src/deploy/values.yaml"))
+	assert.False(t, AnalyzesFile(rule, "/src/Dockerfile"))
+	assert.Equal(t, []string{"*.yaml"}, ExtraFiles([]Rule{rule}))
+}
+
+func TestBaseRuleGetFilePatterns(t *testing.T) {
+	rule := NewBaseRule("test", "cat", "desc", core.SeverityMedium)
+	patterns, err := rule.GetFilePatterns([]string{"*.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.yaml"}, patterns)
+
+	require.NoError(t, rule.Configure(map[string]any{"files": []any{"*.toml", ".env.*"}}))
+	patterns, err = rule.GetFilePatterns([]string{"*.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.toml", ".env.*"}, patterns)
+
+	require.NoError(t, rule.Configure(map[string]any{"files": "*.toml"}))
+	_, err = rule.GetFilePatterns(nil)
+	assert.EqualError(t, err, "configure test: files must be a list, got string")
+
+	require.NoError(t, rule.Configure(map[string]any{"files": []any{"[*.toml"}}))
+	_, err = rule.GetFilePatterns(nil)
+	assert.ErrorContains(t, err, `files pattern "[*.toml"`)
+}
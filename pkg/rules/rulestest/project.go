@@ -8,7 +8,6 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -20,7 +19,8 @@ const defaultGoMod = "module example.com/rulestest\n\ngo 1.24\n"
 
 // Module writes the given files into a fresh temporary directory, adding a
 // go.mod when the caller did not provide one, and returns the module root
-// together with the file contexts of its Go files in path order.
+// together with the file contexts of its files but go.mod and go.sum, in path
+// order.
 func Module(t *testing.T, files map[string]string) (string, []*core.FileContext) {
 	t.Helper()
 
@@ -44,7 +44,7 @@ func Module(t *testing.T, files map[string]string) (string, []*core.FileContext)
 		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
 		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
 
-		if !strings.HasSuffix(name, ".go") {
+		if base := filepath.Base(name); base == "go.mod" || base == "go.sum" {
 			continue
 		}
 		ctx, err := core.NewFileContextChecked(path, root, []byte(content), core.DefaultConfig())
@@ -0,0 +1,142 @@
+package security
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path"
+	"regexp"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/taint"
+)
+
+func init() {
+	rules.Register(NewCommandInjectionRule())
+}
+
+// CommandInjectionRule detects data from a request reaching the program or
+// the arguments of a command the service starts, and any shell run with
+// "-c" and a command string that is not a constant: the shell parses
+// whatever the string spells, quotes, semicolons and $(...) included.
+type CommandInjectionRule struct {
+	*taintRule
+}
+
+var commandSinks = taint.MustParseSpecs(
+	"os/exec.Command", "os/exec.CommandContext", "os.StartProcess", "syscall.Exec", "syscall.ForkExec",
+)
+
+var shellQuoters = taint.MustParseSpecs(
+	"github.com/alessio/shellescape.Quote", "github.com/kballard/go-shellquote.Join",
+)
+
+// shells are the programs whose "-c" argument is a script.
+var shells = map[string]bool{"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true, "ash": true}
+
+// shellScriptFlag matches "-c" alone or after other single-letter flags, as
+// in "-ec" or "-lc".
+var shellScriptFlag = regexp.MustCompile(`^-[a-z]*c$`)
+
+// NewCommandInjectionRule creates the rule
+func NewCommandInjectionRule() *CommandInjectionRule {
+	r := newTaintRule(
+		"command-injection",
+		"Detects request data in the commands a service runs and shells run with dynamic scripts",
+		core.SeverityCritical,
+		"CWE-78",
+		requestSources(),
+		commandSinks,
+	)
+	r.defaults.Sanitizers = shellQuoters
+	r.message = func(f taint.Finding) string {
+		return fmt.Sprintf("Command injection: untrusted data from %s reaches the command of %s", f.Source, f.Callee)
+	}
+	r.suggestion = "Run a fixed program with the value as a separate argument, checked against an allowlist; never pass it to a shell"
+	return &CommandInjectionRule{taintRule: r}
+}
+
+// AnalyzeGoProject reports tainted commands, then dynamic shell scripts on
+// lines no tainted command was found on.
+func (r *CommandInjectionRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	violations, err := r.taintRule.AnalyzeGoProject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reported := make(map[string]bool, len(violations))
+	for _, v := range violations {
+		reported[fmt.Sprintf("%s:%d", v.File, v.Line)] = true
+	}
+
+	files := projectFiles(ctx)
+	for _, pkg := range ctx.Packages {
+		info := pkg.Package.TypesInfo
+		for _, file := range pkg.Package.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				script, shell := dynamicShellScript(call, info)
+				if script == nil {
+					return true
+				}
+				v := r.violationAt(ctx, files, script.Pos(),
+					fmt.Sprintf("Shell command built at runtime: %s -c runs whatever the string spells", shell))
+				if v == nil || reported[fmt.Sprintf("%s:%d", v.File, v.Line)] {
+					return true
+				}
+				v.WithContext("shell", shell)
+				violations = append(violations, v)
+				return true
+			})
+		}
+	}
+	return violations, nil
+}
+
+// dynamicShellScript returns the script argument of exec.Command(shell, "-c",
+// script) when script is not a constant, along with the shell's name.
+func dynamicShellScript(call *ast.CallExpr, info *types.Info) (ast.Expr, string) {
+	fn := typeutil.StaticCallee(info, call)
+	if fn == nil || call.Ellipsis.IsValid() {
+		return nil, ""
+	}
+	args := call.Args
+	switch fn.FullName() {
+	case "os/exec.Command":
+	case "os/exec.CommandContext":
+		if len(args) == 0 {
+			return nil, ""
+		}
+		args = args[1:]
+	default:
+		return nil, ""
+	}
+	if len(args) < 3 {
+		return nil, ""
+	}
+	shell, ok := constantString(args[0], info)
+	if !ok || !shells[path.Base(shell)] {
+		return nil, ""
+	}
+	if flag, ok := constantString(args[1], info); !ok || !shellScriptFlag.MatchString(flag) {
+		return nil, ""
+	}
+	if _, ok := constantString(args[2], info); ok {
+		return nil, ""
+	}
+	return args[2], path.Base(shell)
+}
+
+func constantString(expr ast.Expr, info *types.Info) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
@@ -0,0 +1,109 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandInjectionRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantLines []int
+	}{
+		{
+			name: "request parameter as a command argument",
+			code: `package app
+
+import (
+	"net/http"
+	"os/exec"
+)
+
+func Convert(w http.ResponseWriter, r *http.Request) {
+	exec.Command("convert", r.FormValue("src"), "out.png").Run()
+}
+`,
+			wantLines: []int{9},
+		},
+		{
+			name: "request body decoded into the program name",
+			code: `package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+)
+
+type job struct{ Tool string }
+
+func Run(w http.ResponseWriter, r *http.Request) {
+	var j job
+	json.NewDecoder(r.Body).Decode(&j)
+	exec.CommandContext(context.Background(), j.Tool).Run()
+}
+`,
+			wantLines: []int{15},
+		},
+		{
+			name: "shell script from configuration",
+			code: `package app
+
+import "os/exec"
+
+func Hook(script string) error {
+	return exec.Command("/bin/bash", "-ec", "cd /srv && "+script).Run()
+}
+`,
+			wantLines: []int{6},
+		},
+		{
+			name: "constant shell script and fixed commands",
+			code: `package app
+
+import (
+	"net/http"
+	"os/exec"
+	"strconv"
+)
+
+func Status(w http.ResponseWriter, r *http.Request) {
+	exec.Command("sh", "-c", "uptime | cut -d, -f1").Run()
+	n, _ := strconv.Atoi(r.FormValue("lines"))
+	exec.Command("tail", "-n", strconv.Itoa(n), "/var/log/app.log").Run()
+	exec.Command("git", "-c", r.FormValue("cfg"))
+}
+`,
+			wantLines: []int{13},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLines, violationLines(analyzeTaintRule(t, NewCommandInjectionRule(), tt.code)))
+		})
+	}
+}
+
+func TestCommandInjectionRuleReportsShellOnceWhenTainted(t *testing.T) {
+	violations := analyzeTaintRule(t, NewCommandInjectionRule(), `package app
+
+import (
+	"net/http"
+	"os/exec"
+)
+
+func Ping(w http.ResponseWriter, r *http.Request) {
+	exec.Command("sh", "-c", "ping -c1 "+r.URL.Query().Get("host")).Run()
+}
+`)
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "net/http.Request.URL", v.Context["source"])
+	assert.Equal(t, "os/exec.Command", v.Context["sink"])
+	assert.Equal(t, "CWE-78", v.Context["cwe"])
+}
@@ -0,0 +1,160 @@
+package security
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// configFiles are the files besides code where credentials get committed:
+// dotenv, YAML and JSON configuration, Dockerfiles and Terraform.
+var configFiles = []string{
+	".env", ".env.*", "*.env",
+	"*.yaml", "*.yml", "*.json",
+	"Dockerfile", "Dockerfile.*", "*.dockerfile",
+	"*.tf", "*.tfvars",
+}
+
+// structuredConfigFiles are the configFiles configEntries parses.
+var structuredConfigFiles = []string{".env", ".env.*", "*.env", "*.yaml", "*.yml", "*.json"}
+
+// envTemplateSuffixes mark dotenv files meant to be committed: they document
+// the variables with placeholders.
+var envTemplateSuffixes = []string{".example", ".sample", ".template", ".dist"}
+
+// envName matches a variable name in KEY=value.
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// configEntry is a string value of a configuration file with the key path
+// leading to it — "database.password", "services.db.environment.PASSWORD" —
+// and its line.
+type configEntry struct {
+	key   string
+	value string
+	line  int
+}
+
+// errNotStructured is returned for files configEntries does not parse.
+var errNotStructured = errors.New("not a dotenv, YAML or JSON file")
+
+// configEntries parses dotenv, YAML and JSON files into their string values.
+// Other files, and files that do not parse, are for the caller to read line
+// by line.
+func configEntries(ctx *core.FileContext) ([]configEntry, error) {
+	name := strings.ToLower(filepath.Base(ctx.Path))
+	switch {
+	case isEnvFile(ctx.Path):
+		return dotenvEntries(ctx.Lines), nil
+	case strings.HasSuffix(name, ".yaml"), strings.HasSuffix(name, ".yml"):
+		return yamlEntries(ctx.Content)
+	case strings.HasSuffix(name, ".json"):
+		// JSON is YAML, except for tab indentation; outside strings, where
+		// JSON requires them escaped, tabs are only whitespace.
+		return yamlEntries(bytes.ReplaceAll(ctx.Content, []byte("\t"), []byte(" ")))
+	}
+	return nil, errNotStructured
+}
+
+// isEnvFile reports whether a path names a dotenv file: .env, .env.production
+// or app.env.
+func isEnvFile(path string) bool {
+	return core.MatchesFileName(path, []string{".env", ".env.*", "*.env"})
+}
+
+// isEnvTemplate reports whether a dotenv file is a template such as
+// .env.example.
+func isEnvTemplate(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	for _, suffix := range envTemplateSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dotenvEntries reads KEY=value lines, with an optional export, quotes and
+// trailing comment.
+func dotenvEntries(lines []string) []configEntry {
+	var entries []configEntry
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(trimmed, "export "), "=")
+		name = strings.TrimSpace(name)
+		if !ok || !envName.MatchString(name) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		} else if comment := strings.Index(value, " #"); comment >= 0 {
+			value = strings.TrimSpace(value[:comment])
+		}
+		entries = append(entries, configEntry{key: name, value: value, line: i + 1})
+	}
+	return entries
+}
+
+// yamlEntries reads the string scalars of every document.
+func yamlEntries(content []byte) ([]configEntry, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	var entries []configEntry
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse configuration: %w", err)
+		}
+		entries = appendYAMLEntries(entries, "", &doc)
+	}
+}
+
+func appendYAMLEntries(entries []configEntry, key string, node *yaml.Node) []configEntry {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			entries = appendYAMLEntries(entries, key, child)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			entries = appendYAMLEntries(entries, joinKey(key, node.Content[i].Value), node.Content[i+1])
+		}
+	case yaml.ScalarNode:
+		if node.Tag != "!!str" {
+			break
+		}
+		// docker-compose and Kubernetes list variables as KEY=value.
+		if name, value, ok := strings.Cut(node.Value, "="); ok && envName.MatchString(name) {
+			return append(entries, configEntry{key: joinKey(key, name), value: value, line: node.Line})
+		}
+		entries = append(entries, configEntry{key: key, value: node.Value, line: node.Line})
+	}
+	return entries
+}
+
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// lastKeyWords splits the last segment of a key path into lowercase words:
+// "db.POSTGRES_PASSWORD" gives "postgres" and "password".
+func lastKeyWords(key string) []string {
+	return identWords(key[strings.LastIndexByte(key, '.')+1:])
+}
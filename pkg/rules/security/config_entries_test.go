@@ -0,0 +1,61 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+func parseConfigEntries(t *testing.T, name, content string) []configEntry {
+	t.Helper()
+	entries, err := configEntries(core.NewFileContext("/src/"+name, "/src", []byte(content), core.DefaultConfig()))
+	require.NoError(t, err)
+	return entries
+}
+
+func TestConfigEntriesDotenv(t *testing.T) {
+	entries := parseConfigEntries(t, ".env.production", `# database
+DB_HOST=localhost
+export DB_PASSWORD="p4ss word"
+API_TOKEN=abc123 # rotated monthly
+not a variable
+`)
+	assert.Equal(t, []configEntry{
+		{key: "DB_HOST", value: "localhost", line: 2},
+		{key: "DB_PASSWORD", value: "p4ss word", line: 3},
+		{key: "API_TOKEN", value: "abc123", line: 4},
+	}, entries)
+}
+
+func TestConfigEntriesYAML(t *testing.T) {
+	entries := parseConfigEntries(t, "docker-compose.yml", `services:
+  db:
+    image: postgres:16
+    ports: [5432]
+    environment:
+      - POSTGRES_PASSWORD=hunter2
+---
+database:
+  password: hunter3
+`)
+	assert.Equal(t, []configEntry{
+		{key: "services.db.image", value: "postgres:16", line: 3},
+		{key: "services.db.environment.POSTGRES_PASSWORD", value: "hunter2", line: 6},
+		{key: "database.password", value: "hunter3", line: 9},
+	}, entries)
+}
+
+func TestConfigEntriesJSON(t *testing.T) {
+	entries := parseConfigEntries(t, "appsettings.json", "{\n\t\"Auth\": {\n\t\t\"ClientSecret\": \"s3cr3t\",\n\t\t\"Enabled\": true\n\t}\n}\n")
+	assert.Equal(t, []configEntry{{key: "Auth.ClientSecret", value: "s3cr3t", line: 3}}, entries)
+}
+
+func TestConfigEntriesFallsBackOnParseErrors(t *testing.T) {
+	_, err := configEntries(core.NewFileContext("/src/broken.yaml", "/src", []byte("a: [\n"), core.DefaultConfig()))
+	assert.ErrorContains(t, err, "parse configuration")
+	_, err = configEntries(core.NewFileContext("/src/main.tf", "/src", []byte("x = 1\n"), core.DefaultConfig()))
+	assert.ErrorIs(t, err, errNotStructured)
+}
@@ -0,0 +1,217 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewHardcodedIVRule())
+}
+
+// HardcodedIVRule detects initialization vectors and nonces that are the same
+// on every call: a literal, a constant converted to bytes, or a buffer left
+// as allocated — zero — because nothing fills it between its declaration and
+// the cipher. A fixed IV makes CBC leak equal prefixes; a fixed GCM nonce
+// reveals the XOR of two plaintexts and the authentication key.
+type HardcodedIVRule struct {
+	*rules.BaseRule
+}
+
+// ivArgs maps the functions taking an IV or a nonce to its argument.
+var ivArgs = map[string]int{
+	"crypto/cipher.NewCBCEncrypter": 1, "crypto/cipher.NewCBCDecrypter": 1,
+	"crypto/cipher.NewCFBEncrypter": 1, "crypto/cipher.NewCFBDecrypter": 1,
+	"crypto/cipher.NewOFB": 1, "crypto/cipher.NewCTR": 1,
+	"(crypto/cipher.AEAD).Seal": 1, "(crypto/cipher.AEAD).Open": 1,
+}
+
+// NewHardcodedIVRule creates the rule
+func NewHardcodedIVRule() *HardcodedIVRule {
+	return &HardcodedIVRule{
+		BaseRule: rules.NewBaseRule(
+			"hardcoded-iv",
+			"security",
+			"Detects constant or never-filled IVs and nonces passed to ciphers",
+			core.SeverityCritical,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op: the rule needs type information.
+func (r *HardcodedIVRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *HardcodedIVRule) RequiresSSA() bool { return false }
+
+// AnalyzeGoProject reports fixed IVs and nonces.
+func (r *HardcodedIVRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("hardcoded iv: nil Go project context")
+	}
+	inits := variableInits(ctx)
+	var violations []*core.Violation
+	walkGoSources(ctx, []ast.Node{(*ast.CallExpr)(nil)}, func(src goSource, n ast.Node, stack []ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		name := calleeName(src.info, call)
+		i, ok := ivArgs[name]
+		if !ok || i >= len(call.Args) {
+			return
+		}
+		iv := call.Args[i]
+		if !isFixedBytes(src.info, iv, inits, enclosingBody(stack)) {
+			return
+		}
+		v := src.at(r.CreateViolation, iv,
+			fmt.Sprintf("%s gets an IV or nonce that is the same on every call", name))
+		v.WithSuggestion("Fill a fresh IV or nonce from crypto/rand for each message and send it along with the ciphertext")
+		v.WithContext("cwe", "CWE-329")
+		violations = append(violations, v)
+	})
+	return violations, nil
+}
+
+// variableInits maps the project's variables declared one to a value, or
+// with none, to the expression they start out as: nil for the zero value.
+func variableInits(ctx *core.GoProjectContext) map[*types.Var]ast.Expr {
+	inits := make(map[*types.Var]ast.Expr)
+	declare := func(src goSource, ident *ast.Ident, value ast.Expr) {
+		if obj, ok := src.info.Defs[ident].(*types.Var); ok {
+			inits[obj] = value
+		}
+	}
+	nodes := []ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}
+	walkGoSources(ctx, nodes, func(src goSource, n ast.Node, _ []ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE && len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+				if ident, ok := n.Lhs[0].(*ast.Ident); ok {
+					declare(src, ident, n.Rhs[0])
+				}
+			}
+		case *ast.ValueSpec:
+			switch {
+			case len(n.Names) == 1 && len(n.Values) == 1:
+				declare(src, n.Names[0], n.Values[0])
+			case len(n.Values) == 0:
+				for _, name := range n.Names {
+					declare(src, name, nil)
+				}
+			}
+		}
+	})
+	return inits
+}
+
+// enclosingBody returns the body of the outermost function around the top of
+// stack, where the variables closures capture are declared.
+func enclosingBody(stack []ast.Node) *ast.BlockStmt {
+	for _, n := range stack {
+		switch fn := n.(type) {
+		case *ast.FuncLit:
+			return fn.Body
+		case *ast.FuncDecl:
+			return fn.Body
+		}
+	}
+	return nil
+}
+
+// isFixedBytes reports whether an IV is the same on every call: a byte
+// literal, a constant converted to bytes, or a variable declared as one — or
+// declared zero — that the function never touches except to pass it here or
+// take its length. Package-level variables count when declared with a
+// literal.
+func isFixedBytes(info *types.Info, expr ast.Expr, inits map[*types.Var]ast.Expr, body *ast.BlockStmt) bool {
+	expr = ast.Unparen(expr)
+	if slice, ok := expr.(*ast.SliceExpr); ok {
+		expr = ast.Unparen(slice.X)
+	}
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			if tv, ok := info.Types[elt]; !ok || tv.Value == nil {
+				return false
+			}
+		}
+		return true
+	case *ast.CallExpr:
+		if tv, ok := info.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			arg, ok := info.Types[e.Args[0]]
+			return ok && arg.Value != nil
+		}
+		return false
+	case *ast.Ident:
+		obj, ok := info.Uses[e].(*types.Var)
+		if !ok {
+			return false
+		}
+		init, ok := inits[obj]
+		if !ok {
+			return false
+		}
+		if obj.Parent() == obj.Pkg().Scope() {
+			return init != nil && isFixedBytes(info, init, inits, nil)
+		}
+		if body == nil || touchedElsewhere(info, obj, e, body) {
+			return false
+		}
+		return init == nil || isAllocation(info, init) || isFixedBytes(info, init, inits, nil)
+	}
+	return false
+}
+
+// isAllocation reports whether an expression is make([]byte, n).
+func isAllocation(info *types.Info, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	builtin, ok := info.Uses[ident].(*types.Builtin)
+	return ok && builtin.Name() == "make"
+}
+
+// touchedElsewhere reports whether a function uses a variable anywhere but at
+// use and inside len or cap.
+func touchedElsewhere(info *types.Info, obj *types.Var, use *ast.Ident, body *ast.BlockStmt) bool {
+	measured := make(map[*ast.Ident]bool)
+	touched := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if ident, ok := ast.Unparen(n.Fun).(*ast.Ident); ok {
+				if builtin, ok := info.Uses[ident].(*types.Builtin); ok && (builtin.Name() == "len" || builtin.Name() == "cap") {
+					for _, arg := range n.Args {
+						if id, ok := ast.Unparen(arg).(*ast.Ident); ok {
+							measured[id] = true
+						}
+					}
+				}
+			}
+		case *ast.Ident:
+			if n != use && !measured[n] && info.Uses[n] == obj {
+				touched = true
+			}
+		}
+		return !touched
+	})
+	return touched
+}
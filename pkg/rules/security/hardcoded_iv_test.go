@@ -0,0 +1,56 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHardcodedIVRule(t *testing.T) {
+	violations := analyzeTypedRule(t, NewHardcodedIVRule(), map[string]string{"app/app.go": `package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+)
+
+var staticIV = []byte("0123456789abcdef")
+
+func EncryptCBC(block cipher.Block, dst, src []byte) {
+	cipher.NewCBCEncrypter(block, staticIV).CryptBlocks(dst, src)
+}
+
+func EncryptCTR(block cipher.Block, dst, src []byte) {
+	iv := make([]byte, aes.BlockSize)
+	cipher.NewCTR(block, iv[:len(iv)]).XORKeyStream(dst, src)
+}
+
+func Seal(gcm cipher.AEAD, plaintext []byte) []byte {
+	var nonce [12]byte
+	return gcm.Seal(nil, nonce[:], plaintext, nil)
+}
+
+func SealLiteral(gcm cipher.AEAD, plaintext []byte) []byte {
+	return gcm.Seal(nil, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, plaintext, nil)
+}
+
+func SealRandom(gcm cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func Open(gcm cipher.AEAD, data []byte) ([]byte, error) {
+	size := gcm.NonceSize()
+	return gcm.Open(nil, data[:size], data[size:], nil)
+}
+`})
+
+	assert.Equal(t, []int{13, 18, 23, 27}, violationLines(violations))
+	assert.Equal(t, "crypto/cipher.NewCBCEncrypter gets an IV or nonce that is the same on every call", violations[0].Message)
+	assert.Equal(t, "CWE-329", violations[0].Context["cwe"])
+}
@@ -1,6 +1,7 @@
 package security
 
 import (
+	"math"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -13,12 +14,60 @@ func init() {
 	rules.Register(NewHardcodedSecretsRule())
 }
 
-// HardcodedSecretsRule detects hardcoded passwords, API keys, and tokens
+// HardcodedSecretsRule detects hardcoded passwords, API keys, and tokens:
+// provider formats by their prefix, generic credentials by the name they are
+// assigned to, and — when no pattern matches — random-looking literals
+// assigned to a credential-like name, scored by Shannon entropy
+// (entropy_threshold bits per character, at least entropy_min_length long).
+//
+// Besides code it reads the configuration files in files — dotenv, YAML,
+// JSON, Dockerfiles and Terraform by default. Dotenv, YAML and JSON are parsed,
+// so the key a value sits under names it even without quotes or an equals
+// sign, and a committed dotenv file other than a template is reported by
+// itself.
 type HardcodedSecretsRule struct {
 	*rules.BaseRule
-	patterns []*secretPattern
+	patterns         []*secretPattern
+	entropyThreshold float64
+	entropyMinLength int
+	files            []string
 }
 
+const (
+	defaultEntropyThreshold = 3.5
+	defaultEntropyMinLength = 16
+)
+
+// credentialAssignment captures a name — bare, quoted or a JSON key — and the
+// quoted literal assigned to it.
+var credentialAssignment = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_.-]*)["']?\s*(?::=|=>|[:=])\s*["'\x60]([^"'\x60\s]+)["'\x60]`)
+
+// credentialWords are the name words that make an assigned literal a
+// candidate for entropy scoring.
+var credentialWords = map[string]bool{
+	"key": true, "apikey": true, "secret": true, "token": true, "password": true, "passwd": true,
+	"pwd": true, "pass": true, "credential": true, "credentials": true, "cred": true, "creds": true,
+	"auth": true, "private": true, "signing": true, "signature": true,
+}
+
+// credentialKeyWords end a configuration key that names a credential, as
+// DB_PASSWORD or client_secret; keyQualifiers make a trailing "key" one, as
+// api_key or signing_key.
+var (
+	credentialKeyWords = map[string]bool{
+		"password": true, "passwd": true, "pwd": true, "secret": true, "token": true,
+		"apikey": true, "credential": true, "credentials": true,
+	}
+	keyQualifiers = map[string]bool{
+		"api": true, "secret": true, "private": true, "access": true, "signing": true,
+		"encryption": true, "master": true,
+	}
+)
+
+// configReferences mark configuration values that point at a secret kept
+// elsewhere: shell and template substitution, sops, Vault.
+var configReferences = []string{"$", "{{", "<", "%(", "ENC[", "vault:"}
+
 type secretPattern struct {
 	name           string
 	regex          *regexp.Regexp
@@ -54,6 +103,42 @@ func NewHardcodedSecretsRule() *HardcodedSecretsRule {
 				message:        "Stripe live secret key detected",
 				highConfidence: true,
 			},
+			{
+				name:           "stripe_restricted_key",
+				regex:          regexp.MustCompile(`\brk_live_[A-Za-z0-9]{20,}\b`),
+				message:        "Stripe live restricted key detected",
+				highConfidence: true,
+			},
+			{
+				name:           "github_token",
+				regex:          regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`),
+				message:        "GitHub token detected",
+				highConfidence: true,
+			},
+			{
+				name:           "slack_token",
+				regex:          regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+				message:        "Slack token detected",
+				highConfidence: true,
+			},
+			{
+				name:           "slack_webhook",
+				regex:          regexp.MustCompile(`https://hooks\.slack\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]{20,}`),
+				message:        "Slack incoming webhook URL detected",
+				highConfidence: true,
+			},
+			{
+				name:           "gcp_service_account",
+				regex:          regexp.MustCompile(`"private_key_id"\s*:\s*"[a-f0-9]{40}"`),
+				message:        "GCP service account key detected",
+				highConfidence: true,
+			},
+			{
+				name:           "twilio_api_key",
+				regex:          regexp.MustCompile(`\bSK[0-9a-f]{32}\b`),
+				message:        "Twilio API key detected",
+				highConfidence: true,
+			},
 			{
 				name:           "pgpassword",
 				regex:          regexp.MustCompile(`\bPGPASSWORD=\S{20,}`),
@@ -98,45 +183,206 @@ func NewHardcodedSecretsRule() *HardcodedSecretsRule {
 				message: "JWT token detected in source code",
 			},
 		},
+		entropyThreshold: defaultEntropyThreshold,
+		entropyMinLength: defaultEntropyMinLength,
+		files:            configFiles,
+	}
+}
+
+// Configure reads entropy_threshold, entropy_min_length and files.
+func (r *HardcodedSecretsRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	r.entropyThreshold = r.GetFloat64Setting("entropy_threshold", defaultEntropyThreshold)
+	r.entropyMinLength = r.GetIntSetting("entropy_min_length", defaultEntropyMinLength)
+	files, err := r.GetFilePatterns(configFiles)
+	if err != nil {
+		return err
 	}
+	r.files = files
+	return nil
+}
+
+// ExtraFiles returns the configuration files the rule reads besides code.
+func (r *HardcodedSecretsRule) ExtraFiles() []string {
+	return r.files
 }
 
 // AnalyzeFile checks for hardcoded secrets
 func (r *HardcodedSecretsRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
 	var violations []*core.Violation
 
+	if isEnvFile(ctx.Path) && !isEnvTemplate(ctx.Path) && !ctx.IsSuppressed(1, r.Name()) {
+		v := r.CreateViolation(ctx.RelPath, 1, "Environment file committed to the repository")
+		v.WithSuggestion("Remove it from git, rotate what it holds, add it to .gitignore and commit a .env.example with placeholders instead")
+		v.WithContext("pattern", "committed_env_file")
+		violations = append(violations, v)
+	}
+
+	generic := !ctx.IsTestFile() && !isTestConfigPath(ctx.RelPath)
+	// Files that do not parse are read line by line.
+	if entries, err := configEntries(ctx); err == nil {
+		return append(violations, r.analyzeEntries(ctx, entries, generic)...)
+	}
 	for lineNum, line := range ctx.Lines {
 		// Skip comments
 		trimmed := strings.TrimSpace(line)
 		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") {
 			continue
 		}
+		if ctx.IsSuppressed(lineNum+1, r.Name()) {
+			continue
+		}
 
-		for _, pattern := range r.patterns {
-			if (ctx.IsTestFile() || isTestConfigPath(ctx.RelPath)) && !pattern.highConfidence {
-				continue
-			}
-			if !pattern.highConfidence && r.isPlaceholder(line) {
-				continue
-			}
-			match := pattern.regex.FindString(line)
-			if match != "" && !isDynamicSecretMatch(match) {
-				if ctx.IsSuppressed(lineNum+1, r.Name()) {
-					break
-				}
-				v := r.CreateViolation(ctx.RelPath, lineNum+1, pattern.message)
-				v.WithCode(r.maskSecretMatches(line))
-				v.WithSuggestion("Use environment variables or a secrets manager")
-				v.WithContext("pattern", pattern.name)
-				violations = append(violations, v)
-				break // Only report one match per line
-			}
+		pattern, message, ok := r.matchLine(line, generic && !r.isPlaceholder(line))
+		if !ok {
+			continue
 		}
+		v := r.CreateViolation(ctx.RelPath, lineNum+1, message)
+		v.WithCode(r.maskSecretMatches(line))
+		v.WithSuggestion("Use environment variables or a secrets manager")
+		v.WithContext("pattern", pattern)
+		violations = append(violations, v)
 	}
 
 	return violations
 }
 
+// analyzeEntries checks the values of a parsed configuration file, one
+// finding per line.
+func (r *HardcodedSecretsRule) analyzeEntries(ctx *core.FileContext, entries []configEntry, generic bool) []*core.Violation {
+	var violations []*core.Violation
+	reported := make(map[int]bool)
+	for _, entry := range entries {
+		if reported[entry.line] || ctx.IsSuppressed(entry.line, r.Name()) {
+			continue
+		}
+		pattern, message, ok := r.matchEntry(entry, generic)
+		if !ok {
+			continue
+		}
+		reported[entry.line] = true
+		v := r.CreateViolation(ctx.RelPath, entry.line, message)
+		v.WithCode(r.maskSecretMatches(ctx.GetLine(entry.line), entry.value))
+		v.WithSuggestion("Use environment variables or a secrets manager")
+		v.WithContext("pattern", pattern)
+		v.WithContext("key", entry.key)
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// matchEntry checks a configuration value: the provider patterns first, then
+// — outside tests, for literal values — the key it is under.
+func (r *HardcodedSecretsRule) matchEntry(entry configEntry, generic bool) (pattern, message string, ok bool) {
+	literal := entry.value != "" && !r.isPlaceholder(entry.value) && !isConfigReference(entry.value)
+	if pattern, message, ok := r.matchLine(entry.value, generic && literal); ok {
+		return pattern, message, true
+	}
+	if !generic || !literal {
+		return "", "", false
+	}
+	if namesCredential(entry.key) {
+		return "config_credential", "Hardcoded credential in " + entry.key, true
+	}
+	if r.looksRandom(entry.key, entry.value) {
+		return "high_entropy", "High-entropy string assigned to " + entry.key, true
+	}
+	return "", "", false
+}
+
+// namesCredential reports whether a configuration key names a credential by
+// its last word.
+func namesCredential(key string) bool {
+	words := lastKeyWords(key)
+	if len(words) == 0 {
+		return false
+	}
+	last := words[len(words)-1]
+	if credentialKeyWords[last] {
+		return true
+	}
+	return last == "key" && len(words) > 1 && keyQualifiers[words[len(words)-2]]
+}
+
+func isConfigReference(value string) bool {
+	for _, marker := range configReferences {
+		if strings.HasPrefix(value, marker) {
+			return true
+		}
+	}
+	return strings.Contains(value, "${")
+}
+
+// matchLine returns the first pattern a line matches — only one finding is
+// reported per line — falling back to entropy scoring. The keyword patterns
+// and entropy, which guess from names, run only when generic is set.
+func (r *HardcodedSecretsRule) matchLine(line string, generic bool) (pattern, message string, ok bool) {
+	for _, p := range r.patterns {
+		if !p.highConfidence && !generic {
+			continue
+		}
+		if match := p.regex.FindString(line); match != "" && !isDynamicSecretMatch(match) {
+			return p.name, p.message, true
+		}
+	}
+	if !generic {
+		return "", "", false
+	}
+	if found := r.highEntropyAssignments(line); len(found) > 0 {
+		return "high_entropy", "High-entropy string assigned to " + found[0][0], true
+	}
+	return "", "", false
+}
+
+// highEntropyAssignments returns the name and value of each literal on the
+// line that looksRandom.
+func (r *HardcodedSecretsRule) highEntropyAssignments(line string) [][2]string {
+	var found [][2]string
+	for _, m := range credentialAssignment.FindAllStringSubmatch(line, -1) {
+		if r.looksRandom(m[1], m[2]) {
+			found = append(found, [2]string{m[1], m[2]})
+		}
+	}
+	return found
+}
+
+// looksRandom reports whether a value assigned to a credential-like name is
+// random: long enough, mixing letters and digits, and above the entropy
+// threshold. Paths and URLs are left to the provider patterns.
+func (r *HardcodedSecretsRule) looksRandom(name, value string) bool {
+	if len(value) < r.entropyMinLength || strings.Contains(value, "://") || strings.HasPrefix(value, "/") ||
+		!strings.ContainsAny(value, "0123456789") || strings.IndexFunc(value, isASCIILetter) < 0 {
+		return false
+	}
+	if _, ok := namedWord([]string{name}, credentialWords); !ok {
+		return false
+	}
+	return shannonEntropy(value) >= r.entropyThreshold
+}
+
+func isASCIILetter(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// shannonEntropy returns the bits per character of a string's byte
+// distribution.
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(len(s))
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
 func isDynamicSecretMatch(match string) bool {
 	separator := strings.IndexByte(match, '=')
 	if separator < 0 {
@@ -181,7 +427,17 @@ func (r *HardcodedSecretsRule) isPlaceholder(line string) bool {
 	return false
 }
 
-func (r *HardcodedSecretsRule) maskSecretMatches(line string) string {
+// maskSecretMatches redacts every secret the patterns and entropy find on the
+// line, and the last occurrence of each of the known values.
+func (r *HardcodedSecretsRule) maskSecretMatches(line string, values ...string) string {
+	for _, value := range values {
+		if i := strings.LastIndex(line, value); value != "" && i >= 0 {
+			line = line[:i] + "[REDACTED]" + line[i+len(value):]
+		}
+	}
+	for _, assignment := range r.highEntropyAssignments(line) {
+		line = strings.ReplaceAll(line, assignment[1], "[REDACTED]")
+	}
 	for _, pattern := range r.patterns {
 		line = pattern.regex.ReplaceAllString(line, "[REDACTED]")
 	}
@@ -1,6 +1,7 @@
 package security
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -337,3 +338,166 @@ func TestHardcodedSecretsMasksEveryMatchOnReportedLine(t *testing.T) {
 		assert.Equal(t, 3, strings.Count(violations[0].Code, "[REDACTED]"))
 	}
 }
+
+func TestHardcodedSecretsProviderFormats(t *testing.T) {
+	// Assembled at runtime so the fixtures do not trip glint's own scan.
+	alnum := strings.Repeat("aB3dE5gH7j", 10)
+	hex := strings.Repeat("0a1b2c3d4e", 4)
+	tests := []struct {
+		name        string
+		code        string
+		wantPattern string
+	}{
+		{"github classic token", `token := "` + "gh" + "p_" + alnum[:36] + `"`, "github_token"},
+		{"github fine-grained token", `var t = "` + "github" + "_pat_" + alnum[:82] + `"`, "github_token"},
+		{"slack bot token", `SLACK=` + "xo" + "xb-1234567890-" + alnum[:24], "slack_token"},
+		{"slack webhook", `url: ` + "https://hooks.slack.com" + "/services/T0123ABCD/B0123ABCD/" + alnum[:24], "slack_webhook"},
+		{"gcp service account", `  "private_key_id": "` + hex + `",`, "gcp_service_account"},
+		{"stripe restricted key", `key = "` + "rk" + "_live_" + alnum[:24] + `"`, "stripe_restricted_key"},
+		{"twilio api key", `TWILIO_KEY=` + "S" + "K" + hex[:32], "twilio_api_key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.NewFileContext("/src/deploy/settings.go", "/src", []byte(tt.code), core.DefaultConfig())
+			violations := NewHardcodedSecretsRule().AnalyzeFile(ctx)
+			if assert.Len(t, violations, 1) {
+				assert.Equal(t, tt.wantPattern, violations[0].Context["pattern"])
+				assert.Contains(t, violations[0].Code, "[REDACTED]")
+			}
+		})
+	}
+}
+
+func TestHardcodedSecretsEntropy(t *testing.T) {
+	random := strings.Join([]string{"q7Vx2Lp9", "Zr4Tm8Kc", "W1nB6yHs"}, "")
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{"random signing key", `var signingKey = "` + random + `"`, true},
+		{"random value in JSON", `{"clientCredentials": "` + random + `"}`, true},
+		{"random value under a neutral name", `var requestID = "` + random + `"`, false},
+		{"low-entropy value", `var signingKey = "aaaa1111aaaa1111aaaa"`, false},
+		{"words without digits", `var signingKey = "correcthorsebatterystaple"`, false},
+		{"short value", `var signingKey = "q7Vx2Lp9"`, false},
+		{"path", `var keyFile = "/etc/keys/` + random + `.pem"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.NewFileContext("/src/app/config.go", "/src", []byte(tt.code), core.DefaultConfig())
+			violations := NewHardcodedSecretsRule().AnalyzeFile(ctx)
+			if !tt.want {
+				assert.Empty(t, violations)
+				return
+			}
+			if assert.Len(t, violations, 1) {
+				assert.Equal(t, "high_entropy", violations[0].Context["pattern"])
+				assert.NotContains(t, violations[0].Code, random)
+				assert.Contains(t, violations[0].Code, "[REDACTED]")
+			}
+		})
+	}
+
+	t.Run("not in tests", func(t *testing.T) {
+		ctx := core.NewFileContext("/src/app/config_test.go", "/src", []byte(tests[0].code), core.DefaultConfig())
+		assert.Empty(t, NewHardcodedSecretsRule().AnalyzeFile(ctx))
+	})
+
+	t.Run("configured threshold", func(t *testing.T) {
+		rule := NewHardcodedSecretsRule()
+		assert.NoError(t, rule.Configure(map[string]any{"entropy_threshold": 5.0}))
+		ctx := core.NewFileContext("/src/app/config.go", "/src", []byte(tests[0].code), core.DefaultConfig())
+		assert.Empty(t, rule.AnalyzeFile(ctx))
+	})
+}
+
+func TestShannonEntropy(t *testing.T) {
+	assert.InDelta(t, 0, shannonEntropy("aaaa"), 1e-9)
+	assert.InDelta(t, 1, shannonEntropy("abab"), 1e-9)
+	assert.InDelta(t, 4, shannonEntropy("0123456789abcdef"), 1e-9)
+}
+
+func TestHardcodedSecretsConfigFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		code        string
+		wantLines   []int
+		wantPattern []string
+	}{
+		{
+			name: "committed dotenv file and its credentials",
+			path: "/src/.env.production",
+			code: "DB_HOST=db.internal\nDB_PASSWORD=hunter2\nAPI_URL=${BASE_URL}/v1\nSESSION_SECRET=$SESSION_SECRET\n",
+			// The file itself, then the password; references are not literals.
+			wantLines:   []int{1, 2},
+			wantPattern: []string{"committed_env_file", "config_credential"},
+		},
+		{
+			name: "dotenv template",
+			path: "/src/.env.example",
+			code: "DB_PASSWORD=change_me\n",
+		},
+		{
+			name:        "unquoted YAML password",
+			path:        "/src/deploy/values.yaml",
+			code:        "database:\n  user: app\n  password: hunter2\n  password_min_length: 12\n  token_ttl: 1h\n",
+			wantLines:   []int{3},
+			wantPattern: []string{"config_credential"},
+		},
+		{
+			name:        "compose environment list",
+			path:        "/src/docker-compose.yml",
+			code:        "services:\n  db:\n    environment:\n      - POSTGRES_USER=app\n      - POSTGRES_PASSWORD=postgres\n",
+			wantLines:   []int{5},
+			wantPattern: []string{"config_credential"},
+		},
+		{
+			name:        "JSON signing key",
+			path:        "/src/config/appsettings.json",
+			code:        "{\n  \"Jwt\": {\n    \"SigningKey\": \"q7Vx2Lp9Zr4Tm8KcW1nB6yHs\",\n    \"Issuer\": \"app\"\n  }\n}\n",
+			wantLines:   []int{3},
+			wantPattern: []string{"config_credential"},
+		},
+		{
+			name:        "Dockerfile is read line by line",
+			path:        "/src/Dockerfile",
+			code:        "FROM alpine\nENV " + "AWS_KEY=" + strings.Join([]string{"AKIA", "IOSFODNN7", "DOCKER1"}, "") + "\n",
+			wantLines:   []int{2},
+			wantPattern: []string{"aws_key"},
+		},
+		{
+			name: "test fixture YAML",
+			path: "/src/testdata/config.yaml",
+			code: "password: hunter2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := core.NewFileContext(tt.path, "/src", []byte(tt.code), core.DefaultConfig())
+			violations := NewHardcodedSecretsRule().AnalyzeFile(ctx)
+			var lines []int
+			var patterns []string
+			for _, v := range violations {
+				lines = append(lines, v.Line)
+				patterns = append(patterns, fmt.Sprint(v.Context["pattern"]))
+				assert.NotContains(t, v.Code, "hunter2")
+				assert.NotContains(t, v.Code, "q7Vx2Lp9")
+			}
+			assert.Equal(t, tt.wantLines, lines)
+			assert.Equal(t, tt.wantPattern, patterns)
+		})
+	}
+}
+
+func TestHardcodedSecretsConfigFileSettings(t *testing.T) {
+	rule := NewHardcodedSecretsRule()
+	assert.Contains(t, rule.ExtraFiles(), "*.tf")
+	assert.NoError(t, rule.Configure(map[string]any{"files": []any{"*.toml"}}))
+	assert.Equal(t, []string{"*.toml"}, rule.ExtraFiles())
+	assert.Error(t, rule.Configure(map[string]any{"files": []any{1}}))
+}
@@ -0,0 +1,57 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsecureRandomRule(t *testing.T) {
+	violations := analyzeTypedRule(t, NewInsecureRandomRule(), map[string]string{"app/app.go": `package app
+
+import (
+	"math/rand"
+	"time"
+)
+
+const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+func GenerateToken(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rand.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+type Session struct {
+	ID    int64
+	Owner string
+}
+
+func Start(owner string) Session {
+	nonce := rand.Int63()
+	_ = nonce
+	return Session{ID: rand.Int63(), Owner: owner}
+}
+
+func Salt() []byte {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return buf
+}
+
+func Backoff(attempt int) time.Duration {
+	jitter := time.Duration(rand.Intn(100)) * time.Millisecond
+	return time.Duration(attempt)*time.Second + jitter
+}
+
+func Pick(servers []string) string {
+	return servers[rand.Intn(len(servers))]
+}
+`})
+
+	assert.Equal(t, []int{13, 24, 26, 31}, violationLines(violations))
+	assert.Equal(t, "rand.Intn generates a token: math/rand output is predictable", violations[0].Message)
+	assert.Equal(t, "CWE-338", violations[0].Context["cwe"])
+}
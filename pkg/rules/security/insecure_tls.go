@@ -0,0 +1,120 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewInsecureTLSRule())
+}
+
+// InsecureTLSRule detects crypto/tls configurations that turn off certificate
+// verification or accept protocol versions below TLS 1.2, in a composite
+// literal or an assignment to the field. Only constants are reported: a
+// setting read from configuration is a decision someone can review.
+type InsecureTLSRule struct {
+	*rules.BaseRule
+}
+
+// tlsVersionNames name the crypto/tls version constants below TLS 1.2.
+var tlsVersionNames = map[int64]string{0x0300: "SSL 3.0", 0x0301: "TLS 1.0", 0x0302: "TLS 1.1"}
+
+const tls12 = 0x0303
+
+// NewInsecureTLSRule creates the rule
+func NewInsecureTLSRule() *InsecureTLSRule {
+	return &InsecureTLSRule{
+		BaseRule: rules.NewBaseRule(
+			"insecure-tls",
+			"security",
+			"Detects TLS configurations that skip certificate verification or allow versions below TLS 1.2",
+			core.SeverityCritical,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op: the rule needs type information.
+func (r *InsecureTLSRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *InsecureTLSRule) RequiresSSA() bool { return false }
+
+// AnalyzeGoProject reports insecure tls.Config fields set to constants.
+func (r *InsecureTLSRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("insecure tls: nil Go project context")
+	}
+	var violations []*core.Violation
+	report := func(src goSource, field *types.Var, node ast.Node, value ast.Expr) {
+		if v := r.check(src, field, node, value); v != nil {
+			violations = append(violations, v)
+		}
+	}
+	nodes := []ast.Node{(*ast.KeyValueExpr)(nil), (*ast.AssignStmt)(nil)}
+	walkGoSources(ctx, nodes, func(src goSource, n ast.Node, _ []ast.Node) {
+		switch n := n.(type) {
+		case *ast.KeyValueExpr:
+			if key, ok := n.Key.(*ast.Ident); ok {
+				field, _ := src.info.Uses[key].(*types.Var)
+				report(src, field, n, n.Value)
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				return
+			}
+			for i, lhs := range n.Lhs {
+				if sel, ok := lhs.(*ast.SelectorExpr); ok {
+					field, _ := src.info.Uses[sel.Sel].(*types.Var)
+					report(src, field, n, n.Rhs[i])
+				}
+			}
+		}
+	})
+	return violations, nil
+}
+
+// check reports a tls.Config field set to an insecure constant.
+func (r *InsecureTLSRule) check(src goSource, field *types.Var, node ast.Node, value ast.Expr) *core.Violation {
+	if field == nil || !field.IsField() || field.Pkg() == nil || field.Pkg().Path() != "crypto/tls" {
+		return nil
+	}
+	tv, ok := src.info.Types[value]
+	if !ok || tv.Value == nil {
+		return nil
+	}
+	switch field.Name() {
+	case "InsecureSkipVerify":
+		if tv.Value.Kind() != constant.Bool || !constant.BoolVal(tv.Value) {
+			return nil
+		}
+		v := src.at(r.CreateViolation, node,
+			"TLS certificate verification is disabled: anyone on the network path can impersonate the server")
+		v.WithSuggestion("Verify certificates; trust a private CA through RootCAs instead of skipping verification")
+		v.WithContext("cwe", "CWE-295")
+		return v
+	case "MinVersion":
+		version, ok := constant.Int64Val(tv.Value)
+		if !ok || version == 0 || version >= tls12 {
+			return nil
+		}
+		name, ok := tlsVersionNames[version]
+		if !ok {
+			name = fmt.Sprintf("version 0x%04x", version)
+		}
+		v := src.at(r.CreateViolation, node,
+			fmt.Sprintf("TLS MinVersion allows %s, which has known attacks and no modern cipher suites", name))
+		v.WithSuggestion("Set MinVersion to tls.VersionTLS12 or leave it zero for Go's default")
+		v.WithContext("cwe", "CWE-326")
+		return v
+	}
+	return nil
+}
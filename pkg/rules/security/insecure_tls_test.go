@@ -0,0 +1,50 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsecureTLSRule(t *testing.T) {
+	violations := analyzeTypedRule(t, NewInsecureTLSRule(), map[string]string{
+		"app/app.go": `package app
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+func Client() *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+}
+
+func Server(insecure bool) *tls.Config {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS10, InsecureSkipVerify: insecure}
+	cfg.MinVersion = tls.VersionTLS13
+	return cfg
+}
+
+func Legacy(cfg *tls.Config) {
+	cfg.InsecureSkipVerify = true
+	cfg.MinVersion = 0x0302
+}
+`,
+		"app/app_test.go": `package app
+
+import "crypto/tls"
+
+var testConfig = &tls.Config{InsecureSkipVerify: true}
+`,
+	})
+
+	require.Len(t, violations, 4)
+	assert.Equal(t, []int{10, 15, 21, 22}, violationLines(violations))
+	assert.Equal(t, "CWE-295", violations[0].Context["cwe"])
+	assert.Equal(t, "TLS MinVersion allows TLS 1.0, which has known attacks and no modern cipher suites", violations[1].Message)
+	assert.Equal(t, "CWE-326", violations[1].Context["cwe"])
+	assert.Contains(t, violations[3].Message, "TLS 1.1")
+}
@@ -0,0 +1,216 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewJWTAlgorithmRule())
+}
+
+// JWTAlgorithmRule detects JWTs parsed without pinning the signing algorithm.
+// The token names its own algorithm; a key function that hands back the key
+// without looking at token.Method lets an attacker pick "none", or HMAC keyed
+// with the RSA public key everyone has. Parsing is fine when the options or
+// the parser carry WithValidMethods, or the key function reads token.Method
+// or the "alg" header.
+type JWTAlgorithmRule struct {
+	*rules.BaseRule
+}
+
+// jwtPackage matches the import paths of golang-jwt and its predecessors.
+var jwtPackage = regexp.MustCompile(`^github\.com/(?:golang-jwt/jwt|dgrijalva/jwt-go|form3tech-oss/jwt-go)(?:/v\d+)?$`)
+
+// jwtKeyFuncArgs maps the parse functions to their key function argument.
+var jwtKeyFuncArgs = map[string]int{"Parse": 1, "ParseWithClaims": 2}
+
+// NewJWTAlgorithmRule creates the rule
+func NewJWTAlgorithmRule() *JWTAlgorithmRule {
+	return &JWTAlgorithmRule{
+		BaseRule: rules.NewBaseRule(
+			"jwt-algorithm-unchecked",
+			"security",
+			"Detects JWT parsing that accepts whatever signing algorithm the token names",
+			core.SeverityCritical,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op: the rule needs type information.
+func (r *JWTAlgorithmRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *JWTAlgorithmRule) RequiresSSA() bool { return false }
+
+// AnalyzeGoProject reports parse calls whose key function and options leave
+// the algorithm open.
+func (r *JWTAlgorithmRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("jwt algorithm: nil Go project context")
+	}
+	decls := funcDecls(ctx)
+	inits := variableInits(ctx)
+	var violations []*core.Violation
+	walkGoSources(ctx, []ast.Node{(*ast.CallExpr)(nil)}, func(src goSource, n ast.Node, _ []ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		fn, method := jwtParse(src.info, call)
+		if fn == nil {
+			return
+		}
+		keyArg, ok := jwtKeyFuncArgs[fn.Name()]
+		if !ok || keyArg >= len(call.Args) {
+			return
+		}
+		if pinsMethods(src.info, call.Args[keyArg+1:]...) || method && !parserUnpinned(src.info, call, inits) {
+			return
+		}
+		checked, known := keyFuncChecksAlg(src.info, call.Args[keyArg], decls)
+		if checked || !known {
+			return
+		}
+		v := src.at(r.CreateViolation, call,
+			fmt.Sprintf("jwt.%s accepts the algorithm the token names: the key function never checks token.Method", fn.Name()))
+		v.WithSuggestion("Pass jwt.WithValidMethods([]string{...}) or check token.Method in the key function before returning the key")
+		v.WithContext("cwe", "CWE-347")
+		violations = append(violations, v)
+	})
+	return violations, nil
+}
+
+// jwtParse returns the parse function or method a call calls, and whether
+// it is a method of a Parser.
+func jwtParse(info *types.Info, call *ast.CallExpr) (*types.Func, bool) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || !jwtPackage.MatchString(fn.Pkg().Path()) {
+		return nil, false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	return fn, ok && sig.Recv() != nil
+}
+
+// pinsMethods reports whether any of the expressions calls WithValidMethods.
+func pinsMethods(info *types.Info, exprs ...ast.Expr) bool {
+	found := false
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok && strings.HasSuffix(calleeName(info, call), ".WithValidMethods") {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// parserUnpinned reports whether the receiver of a Parser method is built —
+// in place or in the variable's declaration — by NewParser without
+// WithValidMethods, or by a composite literal without ValidMethods. Receivers
+// built elsewhere are given the benefit of the doubt.
+func parserUnpinned(info *types.Info, call *ast.CallExpr, inits map[*types.Var]ast.Expr) bool {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	init := ast.Unparen(sel.X)
+	if ident, ok := init.(*ast.Ident); ok {
+		obj, ok := info.Uses[ident].(*types.Var)
+		if !ok {
+			return false
+		}
+		init = inits[obj]
+	}
+	if unary, ok := init.(*ast.UnaryExpr); ok {
+		init = unary.X
+	}
+	switch init := init.(type) {
+	case *ast.CallExpr:
+		return strings.HasSuffix(calleeName(info, init), ".NewParser") && !pinsMethods(info, init.Args...)
+	case *ast.CompositeLit:
+		for _, elt := range init.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok && exprName(kv.Key) == "ValidMethods" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// keyFuncChecksAlg reports whether a key function, a literal or a declared
+// function, reads the token's Method or its "alg" header; known is false
+// when the function cannot be found.
+func keyFuncChecksAlg(info *types.Info, keyFunc ast.Expr, decls map[*types.Func]funcDecl) (checked, known bool) {
+	var body *ast.BlockStmt
+	if lit, ok := ast.Unparen(keyFunc).(*ast.FuncLit); ok {
+		body = lit.Body
+	} else if fn, ok := funcRefObject(info, keyFunc); ok {
+		decl, ok := decls[fn]
+		if !ok {
+			return false, false
+		}
+		info, body = decl.info, decl.decl.Body
+	} else {
+		return false, false
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if n.Sel.Name == "Method" && isJWTToken(info.TypeOf(n.X)) {
+				checked = true
+			}
+		case *ast.IndexExpr:
+			if tv, ok := info.Types[n.Index]; ok && tv.Value != nil && tv.Value.Kind() == constant.String &&
+				constant.StringVal(tv.Value) == "alg" && exprName(n.X) == "Header" {
+				checked = true
+			}
+		}
+		return !checked
+	})
+	return checked, true
+}
+
+func isJWTToken(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == "Token" && named.Obj().Pkg() != nil && jwtPackage.MatchString(named.Obj().Pkg().Path())
+}
+
+// funcDecl is a declared function with the type information of its package.
+type funcDecl struct {
+	decl *ast.FuncDecl
+	info *types.Info
+}
+
+// funcDecls maps the project's declared functions and methods to their
+// declarations.
+func funcDecls(ctx *core.GoProjectContext) map[*types.Func]funcDecl {
+	decls := make(map[*types.Func]funcDecl)
+	walkGoSources(ctx, []ast.Node{(*ast.FuncDecl)(nil)}, func(src goSource, n ast.Node, _ []ast.Node) {
+		if decl, ok := n.(*ast.FuncDecl); ok && decl.Body != nil {
+			if fn, ok := src.info.Defs[decl.Name].(*types.Func); ok {
+				decls[fn] = funcDecl{decl: decl, info: src.info}
+			}
+		}
+	})
+	return decls
+}
@@ -0,0 +1,101 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jwtModule stubs github.com/golang-jwt/jwt/v5, which the test module cannot
+// download.
+func jwtModule(app string) map[string]string {
+	return map[string]string{
+		"go.mod":     "module example.com/rulestest\n\ngo 1.24\n\nrequire github.com/golang-jwt/jwt/v5 v5.0.0\n\nreplace github.com/golang-jwt/jwt/v5 => ./jwt\n",
+		"jwt/go.mod": "module github.com/golang-jwt/jwt/v5\n\ngo 1.24\n",
+		"jwt/jwt.go": `package jwt
+
+type SigningMethod interface{ Alg() string }
+
+type SigningMethodHMAC struct{}
+
+func (m *SigningMethodHMAC) Alg() string { return "HS256" }
+
+type Token struct {
+	Method SigningMethod
+	Header map[string]any
+}
+
+type Claims interface{}
+
+type MapClaims map[string]any
+
+type Keyfunc func(*Token) (any, error)
+
+type ParserOption func(*Parser)
+
+type Parser struct{ ValidMethods []string }
+
+func NewParser(options ...ParserOption) *Parser { return &Parser{} }
+
+func WithValidMethods(methods []string) ParserOption { return nil }
+
+func (p *Parser) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) { return nil, nil }
+
+func Parse(tokenString string, keyFunc Keyfunc, options ...ParserOption) (*Token, error) {
+	return nil, nil
+}
+
+func ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc, options ...ParserOption) (*Token, error) {
+	return nil, nil
+}
+`,
+		"app/app.go": app,
+	}
+}
+
+func TestJWTAlgorithmRule(t *testing.T) {
+	violations := analyzeTypedRule(t, NewJWTAlgorithmRule(), jwtModule(`package app
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var secret = []byte("k")
+
+func key(*jwt.Token) (any, error) { return secret, nil }
+
+func checkedKey(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, errors.New("unexpected signing method")
+	}
+	return secret, nil
+}
+
+func Verify(raw string) {
+	jwt.Parse(raw, func(*jwt.Token) (any, error) { return secret, nil })
+	jwt.ParseWithClaims(raw, jwt.MapClaims{}, key)
+	jwt.NewParser().Parse(raw, key)
+	p := jwt.NewParser()
+	p.Parse(raw, key)
+
+	jwt.Parse(raw, checkedKey)
+	jwt.Parse(raw, key, jwt.WithValidMethods([]string{"HS256"}))
+	jwt.Parse(raw, func(t *jwt.Token) (any, error) {
+		if t.Header["alg"] != "HS256" {
+			return nil, errors.New("unexpected alg")
+		}
+		return secret, nil
+	})
+	pinned := jwt.NewParser(jwt.WithValidMethods([]string{"HS256"}))
+	pinned.Parse(raw, key)
+	literal := &jwt.Parser{ValidMethods: []string{"HS256"}}
+	literal.Parse(raw, key)
+}
+`))
+
+	assert.Equal(t, []int{21, 22, 23, 25}, violationLines(violations))
+	assert.Equal(t, "jwt.Parse accepts the algorithm the token names: the key function never checks token.Method", violations[0].Message)
+	assert.Equal(t, "CWE-347", violations[0].Context["cwe"])
+}
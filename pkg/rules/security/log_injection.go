@@ -0,0 +1,97 @@
+package security
+
+import (
+	"fmt"
+	"go/constant"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/taint"
+)
+
+func init() {
+	rules.Register(NewLogInjectionRule())
+}
+
+// LogInjectionRule detects data from a request written into a log line as
+// is: a header carrying "\r\n" forges log entries of its own. It covers the
+// log package and the message of log/slog, which slog's default handler
+// writes raw; slog's text and JSON handlers quote attribute values.
+//
+// Replacing characters in the value or quoting it cleans it, and so does a
+// format string that prints every operand with %q or as a number.
+type LogInjectionRule struct {
+	*taintRule
+}
+
+var logSinks = taint.MustParseSpecs(
+	"log.Print*", "log.Fatal*", "log.Panic*",
+	"(*log.Logger).Print*", "(*log.Logger).Fatal*", "(*log.Logger).Panic*", "(*log.Logger).Output#1",
+	"log/slog.Debug#0", "log/slog.Info#0", "log/slog.Warn#0", "log/slog.Error#0",
+	"log/slog.DebugContext#1", "log/slog.InfoContext#1", "log/slog.WarnContext#1", "log/slog.ErrorContext#1",
+	"log/slog.Log#2",
+	"(*log/slog.Logger).Debug#0", "(*log/slog.Logger).Info#0", "(*log/slog.Logger).Warn#0", "(*log/slog.Logger).Error#0",
+	"(*log/slog.Logger).DebugContext#1", "(*log/slog.Logger).InfoContext#1",
+	"(*log/slog.Logger).WarnContext#1", "(*log/slog.Logger).ErrorContext#1",
+	"(*log/slog.Logger).Log#2",
+)
+
+var logSanitizers = taint.MustParseSpecs(
+	"strings.ReplaceAll", "strings.Replace", "(*strings.Replacer).Replace",
+	"strconv.Quote", "strconv.QuoteToASCII", "net/url.QueryEscape", "net/url.PathEscape",
+)
+
+// formatVerb matches a verb of a fmt format string, capturing its letter.
+var formatVerb = regexp.MustCompile(`%[-+# 0]*(?:\[\d+\])?(?:\d+|\*)?(?:\.(?:\d+|\*)?)?(?:\[\d+\])?([a-zA-Z%])`)
+
+// NewLogInjectionRule creates the rule
+func NewLogInjectionRule() *LogInjectionRule {
+	r := newTaintRule(
+		"log-injection",
+		"Detects request data written into log lines unquoted, letting CR/LF forge entries",
+		core.SeverityMedium,
+		"CWE-117",
+		requestSources(),
+		logSinks,
+	)
+	r.defaults.Sanitizers = logSanitizers
+	r.message = func(f taint.Finding) string {
+		return fmt.Sprintf("Log injection: untrusted data from %s is written unquoted by %s", f.Source, f.Callee)
+	}
+	r.suggestion = "Log the value with %q or as a slog attribute, or strip \\r and \\n from it first"
+	r.keep = func(f taint.Finding) bool { return !quotesOperands(f) }
+	return &LogInjectionRule{taintRule: r}
+}
+
+// quotesOperands reports whether a Printf-style sink has a constant format
+// that prints no operand with %s or %v.
+func quotesOperands(f taint.Finding) bool {
+	if !strings.HasSuffix(f.Callee, "f") {
+		return false
+	}
+	call, ok := f.Sink.(ssa.CallInstruction)
+	if !ok {
+		return false
+	}
+	args := call.Common().Args
+	if call.Common().Signature().Recv() != nil {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return false
+	}
+	format, ok := args[0].(*ssa.Const)
+	if !ok || format.Value == nil || format.Value.Kind() != constant.String {
+		return false
+	}
+	for _, verb := range formatVerb.FindAllStringSubmatch(constant.StringVal(format.Value), -1) {
+		if verb[1] == "s" || verb[1] == "v" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogInjectionRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantLines []int
+	}{
+		{
+			name: "header logged with %s",
+			code: `package app
+
+import (
+	"log"
+	"net/http"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	log.Printf("login from %s", r.Header.Get("X-Forwarded-For"))
+}
+`,
+			wantLines: []int{9},
+		},
+		{
+			name: "user agent as a slog message",
+			code: `package app
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+func Handle(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	slog.Info("request from " + r.UserAgent())
+	logger.WarnContext(r.Context(), "bad referer "+r.Referer())
+}
+`,
+			wantLines: []int{9, 10},
+		},
+		{
+			name: "quoted, stripped or attribute values",
+			code: `package app
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	ua := r.UserAgent()
+	log.Printf("login from %q", r.Header.Get("X-Forwarded-For"))
+	log.Println("agent", strings.ReplaceAll(ua, "\n", ""))
+	slog.Info("request", "agent", ua)
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLines, violationLines(analyzeTaintRule(t, NewLogInjectionRule(), tt.code)))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package security
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/taint"
+)
+
+func init() {
+	rules.Register(NewPathTraversalRule())
+}
+
+// PathTraversalRule detects data from a request reaching the path of a file
+// operation, directly or joined onto a base directory: filepath.Join cleans
+// "../../etc/passwd" into a path outside the base, not into one inside it.
+//
+// filepath.IsLocal, filepath.Rel and strings.HasPrefix are trusted on the
+// code that runs only once they passed: the branch on IsLocal or HasPrefix
+// being true, or on the error of Rel being nil. A prefix must end in a
+// separator, or "/srv/data-evil" passes the test for "/srv/data".
+// filepath.Base keeps only the last element.
+type PathTraversalRule struct {
+	*taintRule
+}
+
+var fileSinks = taint.MustParseSpecs(
+	"os.Open", "os.OpenFile#0", "os.Create", "os.ReadFile", "os.WriteFile#0", "os.ReadDir",
+	"os.Remove", "os.RemoveAll", "os.Mkdir#0", "os.MkdirAll#0", "os.Rename", "os.Truncate#0",
+	"os.Chmod#0", "os.Chown#0", "os.Symlink", "os.Link",
+	"io/ioutil.ReadFile", "io/ioutil.WriteFile#0", "io/ioutil.ReadDir",
+	"net/http.ServeFile#2",
+)
+
+var pathGuards = taint.MustParseSpecs("path/filepath.Rel#1", "path/filepath.IsLocal", "strings.HasPrefix#0")
+
+// NewPathTraversalRule creates the rule
+func NewPathTraversalRule() *PathTraversalRule {
+	r := newTaintRule(
+		"path-traversal",
+		"Detects request data in file paths without a check that the path stays inside its base directory",
+		core.SeverityHigh,
+		"CWE-22",
+		requestSources(),
+		fileSinks,
+	)
+	r.defaults.Sanitizers = taint.MustParseSpecs("path/filepath.Base", "path.Base")
+	r.defaults.Guards = pathGuards
+	r.defaults.TrustGuard = prefixEndsInSeparator
+	r.message = func(f taint.Finding) string {
+		return fmt.Sprintf("Path traversal: untrusted data from %s reaches the path of %s", f.Source, f.Callee)
+	}
+	r.suggestion = "Open files through os.Root, or reject paths for which filepath.IsLocal is false before joining them onto the base directory"
+	return &PathTraversalRule{taintRule: r}
+}
+
+// prefixEndsInSeparator trusts a strings.HasPrefix guard only when the prefix
+// visibly ends in a separator: a constant such as base+string(filepath.Separator)
+// or a concatenation whose last operand is one.
+func prefixEndsInSeparator(name string, args []ssa.Value) bool {
+	if name != "strings.HasPrefix" || len(args) != 2 {
+		return true
+	}
+	prefix := args[1]
+	for {
+		switch v := prefix.(type) {
+		case *ssa.Const:
+			if v.Value == nil || v.Value.Kind() != constant.String {
+				return false
+			}
+			text := constant.StringVal(v.Value)
+			return strings.HasSuffix(text, "/") || strings.HasSuffix(text, `\`)
+		case *ssa.BinOp:
+			if v.Op != token.ADD {
+				return false
+			}
+			prefix = v.Y
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathTraversalRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantLines []int
+	}{
+		{
+			name: "request path joined onto the base directory",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func Download(w http.ResponseWriter, r *http.Request) {
+	data, _ := os.ReadFile(filepath.Join("/srv/files", r.URL.Query().Get("name")))
+	w.Write(data)
+}
+`,
+			wantLines: []int{10},
+		},
+		{
+			name: "path value served through a helper",
+			code: `package app
+
+import "net/http"
+
+func serve(w http.ResponseWriter, r *http.Request, name string) {
+	http.ServeFile(w, r, "/srv/files/"+name)
+}
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, r.PathValue("file"))
+}
+`,
+			wantLines: []int{6},
+		},
+		{
+			name: "joined path checked against the base",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const base = "/srv/files"
+
+func Download(w http.ResponseWriter, r *http.Request) {
+	path := filepath.Join(base, r.FormValue("name"))
+	if !strings.HasPrefix(path, base+string(filepath.Separator)) {
+		http.Error(w, "bad path", http.StatusBadRequest)
+		return
+	}
+	os.Open(path)
+}
+`,
+		},
+		{
+			name: "name checked as local and base name only",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if !filepath.IsLocal(name) {
+		return
+	}
+	os.Open(filepath.Join("/srv", name))
+	os.Remove(filepath.Join("/tmp", filepath.Base(r.FormValue("upload"))))
+}
+`,
+		},
+		{
+			name: "substring test of the name decides nothing",
+			code: `package app
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if strings.Contains(name, ".txt") {
+		log.Printf("text file %s", name)
+	}
+	os.Open(filepath.Join("/srv", name))
+}
+`,
+			wantLines: []int{16},
+		},
+		{
+			name: "prefix test of the raw name decides nothing",
+			code: `package app
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if strings.HasPrefix(name, "reports/") {
+		log.Printf("report %s", name)
+	}
+	os.Open(filepath.Join("/srv", name))
+}
+`,
+			wantLines: []int{16},
+		},
+		{
+			name: "prefix test of the raw name guarding the open",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if !strings.HasPrefix(name, "reports/") {
+		http.Error(w, "bad path", http.StatusBadRequest)
+		return
+	}
+	os.Open(filepath.Join("/srv", name))
+}
+`,
+		},
+		{
+			name: "relative path computed with its error ignored",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	rel, _ := filepath.Rel("/srv", name)
+	w.Header().Set("X-Path", rel)
+	os.Open(name)
+}
+`,
+			wantLines: []int{13},
+		},
+		{
+			name: "relative path with its error checked",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if _, err := filepath.Rel("/srv", name); err != nil {
+		http.Error(w, "bad path", http.StatusBadRequest)
+		return
+	}
+	os.Open(name)
+}
+`,
+		},
+		{
+			name: "prefix test of the joined path whose result decides nothing",
+			code: `package app
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const base = "/srv/files/"
+
+func Download(w http.ResponseWriter, r *http.Request) {
+	path := filepath.Join(base, r.FormValue("name"))
+	inside := strings.HasPrefix(path, base)
+	log.Printf("inside base: %v", inside)
+	os.Open(path)
+}
+`,
+			wantLines: []int{17},
+		},
+		{
+			name: "open on the failed branch of the prefix test",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const base = "/srv/files/"
+
+func Download(w http.ResponseWriter, r *http.Request) {
+	path := filepath.Join(base, r.FormValue("name"))
+	if !strings.HasPrefix(path, base) {
+		os.Open(path)
+	}
+}
+`,
+			wantLines: []int{15},
+		},
+		{
+			name: "prefix without a trailing separator",
+			code: `package app
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const base = "/srv/data"
+
+func Download(w http.ResponseWriter, r *http.Request) {
+	path := filepath.Join(base, r.FormValue("name"))
+	if !strings.HasPrefix(path, base) {
+		http.Error(w, "bad path", http.StatusBadRequest)
+		return
+	}
+	os.Open(path)
+}
+`,
+			wantLines: []int{18},
+		},
+		{
+			name: "command-line paths are the user's own",
+			code: `package app
+
+import "os"
+
+func Main() {
+	os.ReadFile(os.Args[1])
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLines, violationLines(analyzeTaintRule(t, NewPathTraversalRule(), tt.code)))
+		})
+	}
+}
@@ -14,10 +14,13 @@ func init() {
 
 // SensitiveQueryParameterRule detects credentials and action tokens passed in
 // URLs, where they can leak through logs, browser history, caches, and Referer.
+// Besides code it reads the dotenv, YAML and JSON files in files, where the
+// URLs configured for webhooks and callbacks are checked value by value.
 type SensitiveQueryParameterRule struct {
 	*rules.BaseRule
 	queryGetter *regexp.Regexp
 	urlLiteral  *regexp.Regexp
+	files       []string
 }
 
 // NewSensitiveQueryParameterRule creates the rule.
@@ -32,17 +35,42 @@ func NewSensitiveQueryParameterRule() *SensitiveQueryParameterRule {
 		),
 		queryGetter: regexp.MustCompile(`(?i)(?:query\(\)|searchparams)\s*\.\s*(?:get)\(\s*["']` + sensitiveName + `["']\s*\)`),
 		urlLiteral:  regexp.MustCompile(`(?i)[?&]` + sensitiveName + `=`),
+		files:       structuredConfigFiles,
 	}
 }
 
-// AnalyzeFile checks Go, JavaScript, and TypeScript source files.
+// Configure reads files.
+func (r *SensitiveQueryParameterRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	files, err := r.GetFilePatterns(structuredConfigFiles)
+	if err != nil {
+		return err
+	}
+	r.files = files
+	return nil
+}
+
+// ExtraFiles returns the configuration files the rule reads besides code.
+func (r *SensitiveQueryParameterRule) ExtraFiles() []string {
+	return r.files
+}
+
+// AnalyzeFile checks Go, JavaScript, and TypeScript source files, and the
+// configured configuration files.
 func (r *SensitiveQueryParameterRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
-	if !ctx.IsGoFile() && !ctx.IsTypeScriptFile() && !ctx.IsJavaScriptFile() {
+	config := core.MatchesFileName(ctx.Path, r.files)
+	if !ctx.IsGoFile() && !ctx.IsTypeScriptFile() && !ctx.IsJavaScriptFile() && !config {
 		return nil
 	}
 	if ctx.IsTestFile() {
 		return nil
 	}
+	// Files that do not parse are read line by line.
+	if entries, err := configEntries(ctx); err == nil && config {
+		return r.analyzeEntries(ctx, entries)
+	}
 
 	var violations []*core.Violation
 	for i, line := range ctx.Lines {
@@ -65,13 +93,32 @@ func (r *SensitiveQueryParameterRule) AnalyzeFile(ctx *core.FileContext) []*core
 			continue
 		}
 
-		v := r.CreateViolation(ctx.RelPath, lineNum,
-			"Sensitive value exposed through URL query parameter")
-		v.WithCode("sensitive query parameter usage")
-		v.WithSuggestion("Use an Authorization header, secure cookie, POST body, or URL fragment when the server does not need the value")
-		v.WithContext("pattern", pattern)
-		v.WithContext("cwe", "CWE-598")
+		violations = append(violations, r.violation(ctx, lineNum, pattern))
+	}
+	return violations
+}
+
+// analyzeEntries checks the URLs among the values of a configuration file.
+func (r *SensitiveQueryParameterRule) analyzeEntries(ctx *core.FileContext, entries []configEntry) []*core.Violation {
+	var violations []*core.Violation
+	for _, entry := range entries {
+		if !r.urlLiteral.MatchString(entry.value) || ctx.IsSuppressed(entry.line, r.Name()) {
+			continue
+		}
+		v := r.violation(ctx, entry.line, "config-url")
+		v.WithContext("key", entry.key)
 		violations = append(violations, v)
 	}
 	return violations
 }
+
+// violation reports a sensitive query parameter. The code is not quoted: the
+// line holds the value.
+func (r *SensitiveQueryParameterRule) violation(ctx *core.FileContext, line int, pattern string) *core.Violation {
+	v := r.CreateViolation(ctx.RelPath, line, "Sensitive value exposed through URL query parameter")
+	v.WithCode("sensitive query parameter usage")
+	v.WithSuggestion("Use an Authorization header, secure cookie, POST body, or URL fragment when the server does not need the value")
+	v.WithContext("pattern", pattern)
+	v.WithContext("cwe", "CWE-598")
+	return v
+}
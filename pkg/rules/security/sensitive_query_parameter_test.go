@@ -78,3 +78,21 @@ func token(r *http.Request) string {
 	ctx := core.NewFileContext("/src/auth.go", "/src", []byte(code), core.DefaultConfig())
 	assert.Empty(t, rule.AnalyzeFile(ctx))
 }
+
+func TestSensitiveQueryParameterConfigFiles(t *testing.T) {
+	rule := NewSensitiveQueryParameterRule()
+	code := "webhooks:\n  deploy: https://ci.example.com/hook?token=abc123\n  status: https://status.example.com/?page=1\n"
+	ctx := core.NewFileContext("/src/deploy/config.yaml", "/src", []byte(code), core.DefaultConfig())
+
+	violations := rule.AnalyzeFile(ctx)
+
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, 2, violations[0].Line)
+		assert.Equal(t, "config-url", violations[0].Context["pattern"])
+		assert.Equal(t, "webhooks.deploy", violations[0].Context["key"])
+		assert.NotContains(t, violations[0].Code, "abc123")
+	}
+
+	ctx = core.NewFileContext("/src/Dockerfile", "/src", []byte("ENV HOOK=https://x/?token=abc123\n"), core.DefaultConfig())
+	assert.Empty(t, rule.AnalyzeFile(ctx), "files outside the configured list are not read")
+}
@@ -1,198 +1,75 @@
 package security
 
 import (
-	"go/ast"
-	"go/token"
-	"strings"
+	"fmt"
 
 	"github.com/aiseeq/glint/pkg/core"
 	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/taint"
 )
 
 func init() {
 	rules.Register(NewSQLInjectionRule())
 }
 
-// SQLInjectionRule detects potential SQL injection vulnerabilities
+// SQLInjectionRule detects untrusted data reaching the query string of a
+// database call: request fields, command-line arguments, the environment or
+// decoded JSON, followed through variables, helpers, strings.Builder and
+// fmt.Sprintf to database/sql, sqlx, pgx or gorm. A query assembled from
+// constants is not reported, nor is data parsed into a number or passed
+// through a quoting function.
+//
+// The sources, sanitizers and sinks settings add taint.Spec patterns to the
+// defaults — "(*example.com/db.Store).Raw#0" makes the first argument of a
+// project's own query method a sink.
 type SQLInjectionRule struct {
-	*rules.BaseRule
+	*taintRule
 }
 
-// NewSQLInjectionRule creates the rule
-func NewSQLInjectionRule() *SQLInjectionRule {
-	return &SQLInjectionRule{
-		BaseRule: rules.NewBaseRule(
-			"sql-injection",
-			"security",
-			"Detects potential SQL injection through string concatenation in queries",
-			core.SeverityCritical,
-		),
-	}
-}
-
-// AnalyzeFile checks for SQL injection patterns
-func (r *SQLInjectionRule) AnalyzeFile(ctx *core.FileContext) []*core.Violation {
-	if !ctx.IsGoFile() || ctx.GoAST == nil {
-		return nil
-	}
-
-	// Skip test files and test infrastructure (DDL commands don't support parameters)
-	if ctx.IsTestFile() || strings.Contains(ctx.RelPath, "/testing/") {
-		return nil
-	}
-
-	var violations []*core.Violation
-
-	ast.Inspect(ctx.GoAST, func(n ast.Node) bool {
-		// Look for function calls to database methods
-		call, ok := n.(*ast.CallExpr)
-		if !ok {
-			return true
-		}
-
-		// Check if it's a database query method
-		if !r.isDBQueryMethod(call) {
-			return true
-		}
-
-		// Check if first string argument uses concatenation or Sprintf
-		if len(call.Args) == 0 {
-			return true
-		}
-
-		arg := call.Args[0]
-
-		// Check for string concatenation
-		if binary, ok := arg.(*ast.BinaryExpr); ok {
-			if r.isSQLConcatenation(binary) {
-				pos := ctx.PositionFor(call)
-				v := r.CreateViolation(ctx.RelPath, pos.Line,
-					"Potential SQL injection: query built with string concatenation")
-				v.WithCode(ctx.GetLine(pos.Line))
-				v.WithSuggestion("Use parameterized queries with $1, $2 or ? placeholders")
-				v.WithContext("pattern", "concatenation")
-				violations = append(violations, v)
-			}
-		}
-
-		// Check for fmt.Sprintf
-		if r.isFmtSprintfCall(arg) {
-			pos := ctx.PositionFor(call)
-			v := r.CreateViolation(ctx.RelPath, pos.Line,
-				"Potential SQL injection: query built with fmt.Sprintf")
-			v.WithCode(ctx.GetLine(pos.Line))
-			v.WithSuggestion("Use parameterized queries instead of string formatting")
-			v.WithContext("pattern", "sprintf")
-			violations = append(violations, v)
-		}
-
-		return true
-	})
-
-	return violations
-}
-
-func (r *SQLInjectionRule) isDBQueryMethod(call *ast.CallExpr) bool {
-	// Handle selector expressions like db.Query, tx.Exec
-	sel, ok := call.Fun.(*ast.SelectorExpr)
-	if !ok {
-		return false
-	}
-
-	methodName := sel.Sel.Name
-	dbMethods := []string{
-		"Query", "QueryRow", "QueryContext", "QueryRowContext",
-		"Exec", "ExecContext",
-		"Prepare", "PrepareContext",
-		"Get", "Select", "NamedExec", "NamedQuery",
-		"Queryx", "QueryRowx", "MustExec",
-	}
-
-	for _, m := range dbMethods {
-		if methodName == m {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (r *SQLInjectionRule) isSQLConcatenation(binary *ast.BinaryExpr) bool {
-	// Check if this is string concatenation (+)
-	if binary.Op.String() != "+" {
-		return false
-	}
-
-	// Check if either side looks like a SQL query
-	return r.looksLikeSQL(binary.X) || r.looksLikeSQL(binary.Y)
-}
-
-func (r *SQLInjectionRule) looksLikeSQL(expr ast.Expr) bool {
-	// Descend into chained concatenation: "SELECT ..." + id + " LIMIT 1"
-	// parses as (("SELECT ..." + id) + " LIMIT 1"), so the SQL literal may be
-	// nested arbitrarily deep on either side.
-	if binary, ok := expr.(*ast.BinaryExpr); ok {
-		return binary.Op == token.ADD && (r.looksLikeSQL(binary.X) || r.looksLikeSQL(binary.Y))
-	}
-
-	lit, ok := expr.(*ast.BasicLit)
-	if !ok {
-		return false
-	}
-
-	value := strings.ToUpper(lit.Value)
-	sqlKeywords := []string{
-		"SELECT", "INSERT", "UPDATE", "DELETE", "FROM", "WHERE",
-		"JOIN", "ORDER BY", "GROUP BY", "HAVING", "UNION",
-	}
-
-	for _, keyword := range sqlKeywords {
-		if strings.Contains(value, keyword) {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (r *SQLInjectionRule) isFmtSprintfCall(expr ast.Expr) bool {
-	call, ok := expr.(*ast.CallExpr)
-	if !ok {
-		return false
-	}
-
-	sel, ok := call.Fun.(*ast.SelectorExpr)
-	if !ok {
-		return false
-	}
-
-	ident, ok := sel.X.(*ast.Ident)
-	if !ok {
-		return false
-	}
-
-	// Check for fmt.Sprintf
-	if ident.Name == "fmt" && sel.Sel.Name == "Sprintf" {
-		// Check if first argument looks like SQL
-		if len(call.Args) > 0 {
-			if lit, ok := call.Args[0].(*ast.BasicLit); ok {
-				return r.looksLikeSQLFromLit(lit)
-			}
-		}
-	}
-
-	return false
-}
-
-func (r *SQLInjectionRule) looksLikeSQLFromLit(lit *ast.BasicLit) bool {
-	value := strings.ToUpper(lit.Value)
-	sqlKeywords := []string{"SELECT", "INSERT", "UPDATE", "DELETE", "FROM", "WHERE"}
+// sqlQuerySinks are the query arguments of the common database packages;
+// the "*" after "(" covers pointer receivers and interfaces alike.
+var sqlQuerySinks = taint.MustParseSpecs(
+	"(*database/sql.*).Query#0", "(*database/sql.*).QueryRow#0", "(*database/sql.*).Exec#0", "(*database/sql.*).Prepare#0",
+	"(*database/sql.*).QueryContext#1", "(*database/sql.*).QueryRowContext#1",
+	"(*database/sql.*).ExecContext#1", "(*database/sql.*).PrepareContext#1",
+
+	"(*github.com/jmoiron/sqlx.*).Query#0", "(*github.com/jmoiron/sqlx.*).QueryRow#0",
+	"(*github.com/jmoiron/sqlx.*).Exec#0", "(*github.com/jmoiron/sqlx.*).Prepare#0",
+	"(*github.com/jmoiron/sqlx.*).Queryx#0", "(*github.com/jmoiron/sqlx.*).QueryRowx#0",
+	"(*github.com/jmoiron/sqlx.*).MustExec#0", "(*github.com/jmoiron/sqlx.*).NamedExec#0",
+	"(*github.com/jmoiron/sqlx.*).NamedQuery#0", "(*github.com/jmoiron/sqlx.*).Preparex#0",
+	"(*github.com/jmoiron/sqlx.*).Get#1", "(*github.com/jmoiron/sqlx.*).Select#1",
+	"(*github.com/jmoiron/sqlx.*).QueryContext#1", "(*github.com/jmoiron/sqlx.*).QueryRowContext#1",
+	"(*github.com/jmoiron/sqlx.*).ExecContext#1", "(*github.com/jmoiron/sqlx.*).QueryxContext#1",
+	"(*github.com/jmoiron/sqlx.*).QueryRowxContext#1", "(*github.com/jmoiron/sqlx.*).MustExecContext#1",
+	"(*github.com/jmoiron/sqlx.*).NamedExecContext#1", "(*github.com/jmoiron/sqlx.*).GetContext#2",
+	"(*github.com/jmoiron/sqlx.*).SelectContext#2",
+
+	"(*github.com/jackc/pgx/v*).Query#1", "(*github.com/jackc/pgx/v*).QueryRow#1", "(*github.com/jackc/pgx/v*).Exec#1",
+
+	"(*gorm.io/gorm.DB).Raw#0", "(*gorm.io/gorm.DB).Exec#0",
+)
 
-	for _, keyword := range sqlKeywords {
-		if strings.Contains(value, keyword) {
-			return true
-		}
-	}
+// sqlSanitizers quote their argument for use in a query.
+var sqlSanitizers = taint.MustParseSpecs(
+	"github.com/lib/pq.QuoteIdentifier", "github.com/lib/pq.QuoteLiteral",
+	"(github.com/jackc/pgx/v*.Identifier).Sanitize",
+)
 
-	return false
+// NewSQLInjectionRule creates the rule
+func NewSQLInjectionRule() *SQLInjectionRule {
+	r := newTaintRule(
+		"sql-injection",
+		"Detects untrusted data reaching the query string of a database call",
+		core.SeverityCritical,
+		"CWE-89",
+		untrustedSources(),
+		sqlQuerySinks,
+	)
+	r.defaults.Sanitizers = sqlSanitizers
+	r.message = func(f taint.Finding) string {
+		return fmt.Sprintf("Potential SQL injection: untrusted data from %s reaches the query of %s", f.Source, f.Callee)
+	}
+	r.suggestion = "Pass the value as a query parameter ($1, ?) instead of building it into the query string"
+	return &SQLInjectionRule{taintRule: r}
 }
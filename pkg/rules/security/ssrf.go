@@ -0,0 +1,94 @@
+package security
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/taint"
+)
+
+func init() {
+	rules.Register(NewSSRFRule())
+}
+
+// SSRFRule detects data from a request choosing where the service sends a
+// request of its own, letting a client reach internal hosts and cloud
+// metadata endpoints through it. A URL whose scheme and host are constants —
+// "https://api.example.com/users/" + id — is not reported: the client picks
+// the path on a host the service chose.
+type SSRFRule struct {
+	*taintRule
+}
+
+var outboundSinks = taint.MustParseSpecs(
+	"net/http.Get", "net/http.Head", "net/http.Post#0", "net/http.PostForm#0",
+	"net/http.NewRequest#1", "net/http.NewRequestWithContext#2",
+	"(*net/http.Client).Get", "(*net/http.Client).Head", "(*net/http.Client).Post#0", "(*net/http.Client).PostForm#0",
+	"net.Dial#1", "net.DialTimeout#1", "(*net.Dialer).Dial#1", "(*net.Dialer).DialContext#2",
+)
+
+// fixedHostPrefix matches the start of a URL that spells its host in full.
+var fixedHostPrefix = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#%]+[/?#]`)
+
+// NewSSRFRule creates the rule
+func NewSSRFRule() *SSRFRule {
+	r := newTaintRule(
+		"ssrf",
+		"Detects request data choosing the host of an outgoing request",
+		core.SeverityHigh,
+		"CWE-918",
+		requestSources(),
+		outboundSinks,
+	)
+	r.message = func(f taint.Finding) string {
+		return fmt.Sprintf("Server-side request forgery: untrusted data from %s chooses the destination of %s", f.Source, f.Callee)
+	}
+	r.suggestion = "Keep the scheme and host fixed, or resolve the client's choice through an allowlist of known hosts"
+	r.keep = func(f taint.Finding) bool {
+		call, ok := f.Sink.(ssa.CallInstruction)
+		if !ok {
+			return true
+		}
+		for _, arg := range call.Common().Args {
+			if hasFixedHost(arg) {
+				return false
+			}
+		}
+		return true
+	}
+	return &SSRFRule{taintRule: r}
+}
+
+// hasFixedHost reports whether a URL is built by appending to a constant that
+// spells scheme and host, or by fmt.Sprintf with a format that starts so.
+func hasFixedHost(v ssa.Value) bool {
+	switch v := v.(type) {
+	case *ssa.BinOp:
+		return v.Op == token.ADD && hasFixedHost(v.X)
+	case *ssa.Const:
+		return v.Value != nil && v.Value.Kind() == constant.String &&
+			fixedHostPrefix.MatchString(constant.StringVal(v.Value))
+	case *ssa.Call:
+		fn, ok := v.Call.Value.(*ssa.Function)
+		if !ok || fn.String() != "fmt.Sprintf" || len(v.Call.Args) == 0 {
+			return false
+		}
+		format, ok := v.Call.Args[0].(*ssa.Const)
+		if !ok || format.Value == nil || format.Value.Kind() != constant.String {
+			return false
+		}
+		text := constant.StringVal(format.Value)
+		if i := strings.IndexByte(text, '%'); i >= 0 {
+			text = text[:i]
+		}
+		return fixedHostPrefix.MatchString(text)
+	}
+	return false
+}
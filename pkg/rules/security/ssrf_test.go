@@ -0,0 +1,70 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSRFRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantLines []int
+	}{
+		{
+			name: "webhook URL from the request",
+			code: `package app
+
+import "net/http"
+
+func Notify(w http.ResponseWriter, r *http.Request) {
+	http.Get(r.FormValue("callback"))
+}
+`,
+			wantLines: []int{6},
+		},
+		{
+			name: "host from a decoded body in a new request",
+			code: `package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type probe struct{ Host string }
+
+func Probe(client *http.Client, w http.ResponseWriter, r *http.Request) {
+	var p probe
+	json.NewDecoder(r.Body).Decode(&p)
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/health", p.Host), nil)
+	client.Do(req)
+}
+`,
+			wantLines: []int{14},
+		},
+		{
+			name: "request data in the path of a fixed host",
+			code: `package app
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func User(client *http.Client, w http.ResponseWriter, r *http.Request) {
+	client.Get("https://api.example.com/users/" + r.PathValue("id"))
+	http.Get(fmt.Sprintf("https://api.example.com/orders/%s", r.FormValue("order")))
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLines, violationLines(analyzeTaintRule(t, NewSSRFRule(), tt.code)))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package security
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/taint"
+)
+
+// taintRule is what the rules following untrusted data into a sink share:
+// the sources, sanitizers and sinks settings, which add taint.Spec patterns
+// to the rule's defaults, and the reporting of each finding at its sink with
+// the path leading to it.
+type taintRule struct {
+	*rules.BaseRule
+	cwe        string
+	defaults   taint.Config
+	sources    []taint.Spec
+	sanitizers []taint.Spec
+	sinks      []taint.Spec
+
+	// message describes a finding; keep, when set, drops the findings a
+	// rule knows to be harmless.
+	message    func(taint.Finding) string
+	suggestion string
+	keep       func(taint.Finding) bool
+}
+
+// newTaintRule creates a rule following data from sources to sinks; numbers
+// and booleans parsed from untrusted data count as clean. Rules add their
+// default sanitizers and guards to defaults.
+func newTaintRule(name, description string, severity core.Severity, cwe string, sources, sinks []taint.Spec) *taintRule {
+	return &taintRule{
+		BaseRule: rules.NewBaseRule(name, "security", description, severity),
+		cwe:      cwe,
+		defaults: taint.Config{Sources: sources, Sinks: sinks, ScalarsAreClean: true},
+	}
+}
+
+// requestSources are the sources of data a client sends.
+func requestSources() []taint.Spec {
+	return concatSpecs(taint.HTTPRequestSources, taint.JSONSources)
+}
+
+// untrustedSources adds the command line and the environment to what a client
+// sends.
+func untrustedSources() []taint.Spec {
+	return concatSpecs(taint.HTTPRequestSources, taint.ProcessSources, taint.JSONSources)
+}
+
+func concatSpecs(lists ...[]taint.Spec) []taint.Spec {
+	var specs []taint.Spec
+	for _, list := range lists {
+		specs = append(specs, list...)
+	}
+	return specs
+}
+
+// Configure reads the extra sources, sanitizers and sinks.
+func (r *taintRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	lists := []struct {
+		key    string
+		target *[]taint.Spec
+	}{{"sources", &r.sources}, {"sanitizers", &r.sanitizers}, {"sinks", &r.sinks}}
+	for _, list := range lists {
+		raw, ok := settings[list.key]
+		if !ok {
+			continue
+		}
+		specs, err := parseSpecList(r.Name(), list.key, raw)
+		if err != nil {
+			return err
+		}
+		*list.target = specs
+	}
+	return nil
+}
+
+// parseSpecList parses a setting listing taint.Spec patterns.
+func parseSpecList(rule, key string, raw any) ([]taint.Spec, error) {
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("configure %s: %s must be a list, got %T", rule, key, raw)
+	}
+	specs := make([]taint.Spec, 0, len(list))
+	for i, item := range list {
+		text, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("configure %s: %s item %d must be a string, got %T", rule, key, i, item)
+		}
+		spec, err := taint.ParseSpec(text)
+		if err != nil {
+			return nil, fmt.Errorf("configure %s: %s item %d: %w", rule, key, i, err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (r *taintRule) config() taint.Config {
+	cfg := r.defaults
+	cfg.Sources = concatSpecs(r.defaults.Sources, r.sources)
+	cfg.Sanitizers = concatSpecs(r.defaults.Sanitizers, r.sanitizers)
+	cfg.Sinks = concatSpecs(r.defaults.Sinks, r.sinks)
+	return cfg
+}
+
+// RequiresSSA reports that AnalyzeGoProject requires built SSA and its program.
+func (r *taintRule) RequiresSSA() bool { return true }
+
+// AnalyzeFile is a no-op: data flows across functions and files.
+func (r *taintRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// AnalyzeGoProject reports each source reaching a sink, at the sink.
+func (r *taintRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	findings, err := taint.AnalyzeProject(ctx, r.config())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.Name(), err)
+	}
+	files := projectFiles(ctx)
+	var violations []*core.Violation
+	for _, finding := range findings {
+		if r.keep != nil && !r.keep(finding) {
+			continue
+		}
+		v := r.violationAt(ctx, files, finding.Sink.Pos(), r.message(finding))
+		if v == nil {
+			continue
+		}
+		v.WithContext("source", finding.Source)
+		v.WithContext("sink", finding.Callee)
+		v.WithContext("taint_path", finding.Trace(ctx.FileSet, ctx.ProjectRoot))
+		violations = append(violations, v)
+	}
+	return violations, nil
+}
+
+// projectFiles indexes the analyzed Go files by path.
+func projectFiles(ctx *core.GoProjectContext) map[string]*core.FileContext {
+	files := make(map[string]*core.FileContext)
+	for _, pkg := range ctx.Packages {
+		for _, fileCtx := range pkg.Files {
+			files[filepath.Clean(fileCtx.Path)] = fileCtx
+		}
+	}
+	return files
+}
+
+// violationAt creates a violation at pos, or returns nil when pos is in a file
+// the config excludes from analysis or in test infrastructure, which feeds the
+// code it drives crafted input on purpose.
+func (r *taintRule) violationAt(ctx *core.GoProjectContext, files map[string]*core.FileContext, pos token.Pos, message string) *core.Violation {
+	position := ctx.FileSet.Position(pos)
+	fileCtx := files[filepath.Clean(position.Filename)]
+	if fileCtx == nil || strings.Contains(fileCtx.RelPath, "/testing/") {
+		return nil
+	}
+	v := r.CreateViolation(fileCtx.RelPath, position.Line, message)
+	v.WithColumn(position.Column)
+	v.WithCode(strings.TrimSpace(fileCtx.GetLine(position.Line)))
+	v.WithSuggestion(r.suggestion)
+	v.WithContext("cwe", r.cwe)
+	return v
+}
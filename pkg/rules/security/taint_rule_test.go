@@ -0,0 +1,36 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeTaintRule(t *testing.T, rule rules.GoProjectRule, source string) []*core.Violation {
+	t.Helper()
+	violations, err := rule.AnalyzeGoProject(rulestest.ProjectWithSSA(t, map[string]string{"app/app.go": source}))
+	require.NoError(t, err)
+	return violations
+}
+
+func violationLines(violations []*core.Violation) []int {
+	var lines []int
+	for _, v := range violations {
+		lines = append(lines, v.Line)
+	}
+	return lines
+}
+
+func TestTaintRuleConfigureErrors(t *testing.T) {
+	rule := NewPathTraversalRule()
+	assert.EqualError(t, rule.Configure(map[string]any{"sinks": "db.Raw"}),
+		"configure path-traversal: sinks must be a list, got string")
+	assert.EqualError(t, rule.Configure(map[string]any{"sources": []any{1}}),
+		"configure path-traversal: sources item 0 must be a string, got int")
+	assert.Error(t, rule.Configure(map[string]any{"sanitizers": []any{"clean#x"}}))
+}
@@ -0,0 +1,82 @@
+package security
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/taint"
+)
+
+func init() {
+	rules.Register(NewTemplateInjectionRule())
+}
+
+// TemplateInjectionRule detects data from a request reaching HTML without
+// escaping: converted to html/template.HTML and its siblings, which tell the
+// template engine the value is already safe; rendered by text/template,
+// which escapes nothing, into an HTTP response; or parsed as the text of a
+// template, which runs whatever actions it spells.
+type TemplateInjectionRule struct {
+	*taintRule
+}
+
+var templateSinks = taint.MustParseSpecs(
+	"html/template.HTML", "html/template.HTMLAttr", "html/template.JS", "html/template.JSStr",
+	"html/template.CSS", "html/template.URL", "html/template.Srcset",
+	"(*text/template.Template).Execute#1", "(*text/template.Template).ExecuteTemplate#2",
+	"(*text/template.Template).Parse", "(*html/template.Template).Parse",
+)
+
+// NewTemplateInjectionRule creates the rule
+func NewTemplateInjectionRule() *TemplateInjectionRule {
+	r := newTaintRule(
+		"template-injection",
+		"Detects request data rendered into HTML unescaped or parsed as template text",
+		core.SeverityHigh,
+		"CWE-79",
+		requestSources(),
+		templateSinks,
+	)
+	r.message = func(f taint.Finding) string {
+		switch {
+		case strings.HasSuffix(f.Callee, ".Parse"):
+			return fmt.Sprintf("Template injection: untrusted data from %s becomes template text in %s", f.Source, f.Callee)
+		case strings.HasPrefix(f.Callee, "html/template."):
+			return fmt.Sprintf("Cross-site scripting: untrusted data from %s is marked safe by conversion to %s", f.Source, f.Callee)
+		}
+		return fmt.Sprintf("Cross-site scripting: untrusted data from %s is rendered unescaped by %s", f.Source, f.Callee)
+	}
+	r.suggestion = "Render the value through html/template as plain data, which escapes it for the context it lands in"
+	r.keep = rendersToResponse
+	return &TemplateInjectionRule{taintRule: r}
+}
+
+// rendersToResponse keeps text/template executions only when they write to
+// an http.ResponseWriter: text/template rendering a config file or source
+// code is what it is for.
+func rendersToResponse(f taint.Finding) bool {
+	if !strings.HasPrefix(f.Callee, "(*text/template.Template).Execute") {
+		return true
+	}
+	call, ok := f.Sink.(ssa.CallInstruction)
+	if !ok {
+		return true
+	}
+	args := call.Common().Args
+	if len(args) < 2 {
+		return false
+	}
+	writer := args[1]
+	if conv, ok := writer.(*ssa.MakeInterface); ok {
+		writer = conv.X
+	}
+	if conv, ok := writer.(*ssa.ChangeInterface); ok {
+		writer = conv.X
+	}
+	return types.TypeString(writer.Type(), nil) == "net/http.ResponseWriter"
+}
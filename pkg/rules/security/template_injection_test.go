@@ -0,0 +1,114 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateInjectionRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		wantLines []int
+	}{
+		{
+			name: "request data marked as safe HTML",
+			code: `package app
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var page = template.Must(template.New("page").Parse("<p>{{.}}</p>"))
+
+func Show(w http.ResponseWriter, r *http.Request) {
+	page.Execute(w, template.HTML(r.FormValue("bio")))
+}
+`,
+			wantLines: []int{11},
+		},
+		{
+			name: "text/template rendering request data into the response",
+			code: `package app
+
+import (
+	"net/http"
+	"text/template"
+)
+
+var page = template.Must(template.New("page").Parse("<p>Hello {{.}}</p>"))
+
+func Hello(w http.ResponseWriter, r *http.Request) {
+	page.Execute(w, r.URL.Query().Get("name"))
+}
+`,
+			wantLines: []int{11},
+		},
+		{
+			name: "request data parsed as template text",
+			code: `package app
+
+import (
+	"html/template"
+	"net/http"
+)
+
+func Preview(w http.ResponseWriter, r *http.Request) {
+	tmpl, _ := template.New("preview").Parse(r.FormValue("body"))
+	tmpl.Execute(w, nil)
+}
+`,
+			wantLines: []int{9},
+		},
+		{
+			name: "html/template escaping and text/template into a file",
+			code: `package app
+
+import (
+	htmltemplate "html/template"
+	"net/http"
+	"os"
+	"text/template"
+)
+
+var page = htmltemplate.Must(htmltemplate.New("page").Parse("<p>{{.}}</p>"))
+
+var unit = template.Must(template.New("unit").Parse("ExecStart={{.}}\n"))
+
+func Save(w http.ResponseWriter, r *http.Request) {
+	page.Execute(w, r.FormValue("bio"))
+	f, _ := os.Create("/etc/app.service")
+	unit.Execute(f, r.FormValue("cmd"))
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLines, violationLines(analyzeTaintRule(t, NewTemplateInjectionRule(), tt.code)))
+		})
+	}
+}
+
+func TestTemplateInjectionRuleDescribesConversion(t *testing.T) {
+	violations := analyzeTaintRule(t, NewTemplateInjectionRule(), `package app
+
+import (
+	"html/template"
+	"net/http"
+)
+
+func Bio(r *http.Request) template.HTML {
+	return template.HTML(r.FormValue("bio"))
+}
+`)
+	require.Len(t, violations, 1)
+	v := violations[0]
+	assert.Equal(t, "html/template.HTML", v.Context["sink"])
+	assert.Equal(t, "CWE-79", v.Context["cwe"])
+	assert.Contains(t, v.Message, "marked safe by conversion to html/template.HTML")
+}
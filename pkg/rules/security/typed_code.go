@@ -0,0 +1,170 @@
+package security
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// goSource is a Go file of the project, outside tests, with the type
+// information of its package.
+type goSource struct {
+	file *core.FileContext
+	info *types.Info
+}
+
+// walkGoSources calls visit for each node of the given types in the
+// project's Go files outside tests, with the nodes enclosing it, outermost
+// first and the node itself last.
+func walkGoSources(ctx *core.GoProjectContext, types []ast.Node, visit func(src goSource, n ast.Node, stack []ast.Node)) {
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.Package == nil {
+			continue
+		}
+		for _, file := range pkg.Files {
+			if file == nil || file.GoAST == nil || file.IsTestFile() {
+				continue
+			}
+			src := goSource{file: file, info: pkg.Package.TypesInfo}
+			inspector.New([]*ast.File{file.GoAST}).WithStack(types, func(n ast.Node, push bool, stack []ast.Node) bool {
+				if push {
+					visit(src, n, stack)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// at creates a violation at a node of the source.
+func (src goSource) at(create func(file string, line int, message string) *core.Violation, node ast.Node, message string) *core.Violation {
+	position := src.file.PositionFor(node)
+	v := create(src.file.RelPath, position.Line, message)
+	v.WithColumn(position.Column)
+	v.WithCode(strings.TrimSpace(src.file.GetLine(position.Line)))
+	return v
+}
+
+// calleeName returns the full name of what a call calls — "crypto/md5.Sum",
+// "(crypto/cipher.AEAD).Seal" — or "" for function values, conversions and
+// builtins.
+func calleeName(info *types.Info, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok {
+		return ""
+	}
+	return fn.FullName()
+}
+
+// funcRef returns the full name of the function an expression names without
+// calling it, as md5.New in hmac.New(md5.New, key).
+func funcRef(info *types.Info, expr ast.Expr) string {
+	fn, ok := funcRefObject(info, expr)
+	if !ok {
+		return ""
+	}
+	return fn.FullName()
+}
+
+func funcRefObject(info *types.Info, expr ast.Expr) (*types.Func, bool) {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil, false
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	return fn, ok
+}
+
+// purposeNames returns the names that tell what a value computed at the top
+// of stack is for: the function it is computed in, and the variable, field
+// or struct key it is assigned to.
+func purposeNames(stack []ast.Node) []string {
+	var names []string
+	assigned := false
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl:
+			names = append(names, n.Name.Name)
+		case *ast.AssignStmt:
+			if !assigned {
+				for _, lhs := range n.Lhs {
+					names = append(names, exprName(lhs))
+				}
+				assigned = true
+			}
+		case *ast.ValueSpec:
+			if !assigned {
+				for _, name := range n.Names {
+					names = append(names, name.Name)
+				}
+				assigned = true
+			}
+		case *ast.KeyValueExpr:
+			if !assigned {
+				names = append(names, exprName(n.Key))
+				assigned = true
+			}
+		}
+	}
+	return names
+}
+
+// exprName returns the identifier an expression ends in: x for x, s.x,
+// x[i] and *x.
+func exprName(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return exprName(e.X)
+	case *ast.StarExpr:
+		return exprName(e.X)
+	}
+	return ""
+}
+
+// namedWord returns the first word of the names found in words, splitting
+// names at case changes, digits and underscores: "newCSRFToken" holds "new",
+// "csrf" and "token".
+func namedWord(names []string, words map[string]bool) (string, bool) {
+	for _, name := range names {
+		for _, word := range identWords(name) {
+			if words[word] {
+				return word, true
+			}
+		}
+	}
+	return "", false
+}
+
+func identWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes) || runes[i] == '_' || unicode.IsDigit(runes[i]) != unicode.IsDigit(runes[i-1]) ||
+			(unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1])) ||
+			(unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))
+		if !boundary {
+			continue
+		}
+		if word := strings.Trim(string(runes[start:i]), "_"); word != "" {
+			words = append(words, strings.ToLower(word))
+		}
+		start = i
+	}
+	return words
+}
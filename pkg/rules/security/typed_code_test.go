@@ -0,0 +1,26 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func analyzeTypedRule(t *testing.T, rule rules.GoProjectRule, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := rule.AnalyzeGoProject(rulestest.Project(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func TestIdentWords(t *testing.T) {
+	assert.Equal(t, []string{"new", "csrf", "token"}, identWords("newCSRFToken"))
+	assert.Equal(t, []string{"session", "id"}, identWords("session_id"))
+	assert.Equal(t, []string{"sha", "256", "sum"}, identWords("SHA256Sum"))
+	assert.Equal(t, []string{"id"}, identWords("ID"))
+}
@@ -0,0 +1,86 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewWeakBcryptCostRule())
+}
+
+// defaultMinBcryptCost is bcrypt.DefaultCost, the least OWASP accepts.
+const defaultMinBcryptCost = 10
+
+// WeakBcryptCostRule detects bcrypt hashes generated with a constant cost
+// below the configured minimum (min_cost, default 10): every step down
+// halves the work of cracking a leaked hash.
+type WeakBcryptCostRule struct {
+	*rules.BaseRule
+	minCost int
+}
+
+// NewWeakBcryptCostRule creates the rule
+func NewWeakBcryptCostRule() *WeakBcryptCostRule {
+	return &WeakBcryptCostRule{
+		BaseRule: rules.NewBaseRule(
+			"weak-bcrypt-cost",
+			"security",
+			"Detects bcrypt hashes generated with a cost below the configured minimum",
+			core.SeverityHigh,
+		),
+		minCost: defaultMinBcryptCost,
+	}
+}
+
+// Configure reads min_cost.
+func (r *WeakBcryptCostRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	r.minCost = r.GetIntSetting("min_cost", defaultMinBcryptCost)
+	return nil
+}
+
+// AnalyzeFile is a no-op: the rule needs type information.
+func (r *WeakBcryptCostRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *WeakBcryptCostRule) RequiresSSA() bool { return false }
+
+// AnalyzeGoProject reports bcrypt.GenerateFromPassword calls with a low
+// constant cost.
+func (r *WeakBcryptCostRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("weak bcrypt cost: nil Go project context")
+	}
+	var violations []*core.Violation
+	walkGoSources(ctx, []ast.Node{(*ast.CallExpr)(nil)}, func(src goSource, n ast.Node, _ []ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 || calleeName(src.info, call) != "golang.org/x/crypto/bcrypt.GenerateFromPassword" {
+			return
+		}
+		tv, ok := src.info.Types[call.Args[1]]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+			return
+		}
+		cost, ok := constant.Int64Val(tv.Value)
+		if !ok || cost >= int64(r.minCost) {
+			return
+		}
+		v := src.at(r.CreateViolation, call.Args[1],
+			fmt.Sprintf("bcrypt cost %d is below the minimum of %d", cost, r.minCost))
+		v.WithSuggestion(fmt.Sprintf("Use a cost of at least %d; bcrypt.DefaultCost is 10", r.minCost))
+		v.WithContext("cwe", "CWE-916")
+		v.WithContext("cost", cost)
+		violations = append(violations, v)
+	})
+	return violations, nil
+}
@@ -0,0 +1,54 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bcryptModule stubs golang.org/x/crypto/bcrypt, which the test module cannot
+// download.
+func bcryptModule(app string) map[string]string {
+	return map[string]string{
+		"go.mod":         "module example.com/rulestest\n\ngo 1.24\n\nrequire golang.org/x/crypto v0.0.0\n\nreplace golang.org/x/crypto => ./xcrypto\n",
+		"xcrypto/go.mod": "module golang.org/x/crypto\n\ngo 1.24\n",
+		"xcrypto/bcrypt/bcrypt.go": `package bcrypt
+
+const (
+	MinCost     = 4
+	DefaultCost = 10
+)
+
+func GenerateFromPassword(password []byte, cost int) ([]byte, error) { return nil, nil }
+`,
+		"app/app.go": app,
+	}
+}
+
+const bcryptApp = `package app
+
+import "golang.org/x/crypto/bcrypt"
+
+const cost = 8
+
+func Hash(pw string, configured int) {
+	bcrypt.GenerateFromPassword([]byte(pw), bcrypt.MinCost)
+	bcrypt.GenerateFromPassword([]byte(pw), cost)
+	bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+	bcrypt.GenerateFromPassword([]byte(pw), 12)
+	bcrypt.GenerateFromPassword([]byte(pw), configured)
+}
+`
+
+func TestWeakBcryptCostRule(t *testing.T) {
+	violations := analyzeTypedRule(t, NewWeakBcryptCostRule(), bcryptModule(bcryptApp))
+	assert.Equal(t, []int{8, 9}, violationLines(violations))
+	assert.Equal(t, "bcrypt cost 4 is below the minimum of 10", violations[0].Message)
+	assert.Equal(t, "CWE-916", violations[0].Context["cwe"])
+
+	rule := NewWeakBcryptCostRule()
+	require.NoError(t, rule.Configure(map[string]any{"min_cost": 12}))
+	violations = analyzeTypedRule(t, rule, bcryptModule(bcryptApp))
+	assert.Equal(t, []int{8, 9, 10}, violationLines(violations))
+}
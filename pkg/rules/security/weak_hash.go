@@ -0,0 +1,147 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewWeakHashRule())
+}
+
+// WeakHashRule detects MD5 and SHA-1 protecting passwords or signatures.
+// Both are broken for collisions and fast enough to brute-force a password
+// database; a checksum or a cache key is fine, so a call counts only when
+// the names around it — the function, the variable it fills, the data it
+// hashes — speak of passwords, secrets or signatures, or when the hash keys
+// an HMAC or an RSA signature.
+type WeakHashRule struct {
+	*rules.BaseRule
+}
+
+// weakHashes are the constructors and one-shot functions of MD5 and SHA-1.
+var weakHashes = map[string]string{
+	"crypto/md5.New": "MD5", "crypto/md5.Sum": "MD5",
+	"crypto/sha1.New": "SHA-1", "crypto/sha1.Sum": "SHA-1",
+}
+
+// signers take a crypto.Hash naming the digest they sign.
+var signers = map[string]bool{
+	"crypto/rsa.SignPKCS1v15": true, "crypto/rsa.VerifyPKCS1v15": true,
+	"crypto/rsa.SignPSS": true, "crypto/rsa.VerifyPSS": true,
+}
+
+var secretPurposes = map[string]bool{
+	"password": true, "passwd": true, "pwd": true, "passphrase": true, "pass": true,
+	"secret": true, "credential": true, "credentials": true,
+	"signature": true, "sign": true, "signed": true, "signing": true, "sig": true, "hmac": true,
+}
+
+// NewWeakHashRule creates the rule
+func NewWeakHashRule() *WeakHashRule {
+	return &WeakHashRule{
+		BaseRule: rules.NewBaseRule(
+			"weak-hash",
+			"security",
+			"Detects MD5 and SHA-1 used for passwords or signatures",
+			core.SeverityHigh,
+		),
+	}
+}
+
+// AnalyzeFile is a no-op: the rule needs type information.
+func (r *WeakHashRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that typed packages are enough.
+func (r *WeakHashRule) RequiresSSA() bool { return false }
+
+// AnalyzeGoProject reports weak hashes protecting secrets or signatures.
+func (r *WeakHashRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("weak hash: nil Go project context")
+	}
+	var violations []*core.Violation
+	walkGoSources(ctx, []ast.Node{(*ast.CallExpr)(nil)}, func(src goSource, n ast.Node, stack []ast.Node) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		if message, ok := r.weakUse(src, call, stack); ok {
+			v := src.at(r.CreateViolation, call, message)
+			v.WithSuggestion("Hash passwords with bcrypt, scrypt or argon2id; sign with HMAC-SHA-256 or SHA-256 and up")
+			v.WithContext("cwe", "CWE-328")
+			violations = append(violations, v)
+		}
+	})
+	return violations, nil
+}
+
+func (r *WeakHashRule) weakUse(src goSource, call *ast.CallExpr, stack []ast.Node) (string, bool) {
+	name := calleeName(src.info, call)
+	if algorithm, ok := weakHashes[name]; ok {
+		names := purposeNames(stack)
+		for _, arg := range call.Args {
+			ast.Inspect(arg, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok {
+					names = append(names, ident.Name)
+				}
+				return true
+			})
+		}
+		if word, ok := namedWord(names, secretPurposes); ok {
+			return fmt.Sprintf("%s used for a %s: it is broken for collisions and fast to brute-force", algorithm, purposeNoun(word)), true
+		}
+		return "", false
+	}
+	if name == "crypto/hmac.New" && len(call.Args) > 0 {
+		if keyed, ok := weakHashes[funcRef(src.info, call.Args[0])]; ok {
+			return fmt.Sprintf("HMAC keyed with %s signs messages with a broken hash", keyed), true
+		}
+	}
+	if signers[name] {
+		for _, arg := range call.Args {
+			if digest, ok := weakHashID(src, arg); ok {
+				return fmt.Sprintf("%s called with %s signs a digest open to collisions", name[strings.LastIndexByte(name, '/')+1:], digest), true
+			}
+		}
+	}
+	return "", false
+}
+
+// weakHashID names the hash when an argument is the crypto.Hash constant of
+// MD5 or SHA-1.
+func weakHashID(src goSource, arg ast.Expr) (string, bool) {
+	tv, ok := src.info.Types[arg]
+	if !ok || tv.Value == nil || tv.Type == nil || tv.Type.String() != "crypto.Hash" {
+		return "", false
+	}
+	value, _ := constant.Int64Val(tv.Value)
+	// The values of crypto.MD5, crypto.SHA1 and crypto.MD5SHA1.
+	switch value {
+	case 2:
+		return "MD5", true
+	case 3:
+		return "SHA-1", true
+	case 8:
+		return "MD5+SHA-1", true
+	}
+	return "", false
+}
+
+func purposeNoun(word string) string {
+	switch word {
+	case "password", "passwd", "pwd", "passphrase", "pass":
+		return "password"
+	case "secret", "credential", "credentials":
+		return "secret"
+	}
+	return "signature"
+}
@@ -0,0 +1,58 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeakHashRule(t *testing.T) {
+	violations := analyzeTypedRule(t, NewWeakHashRule(), map[string]string{"app/app.go": `package app
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/hex"
+)
+
+func HashPassword(pw string) string {
+	sum := md5.Sum([]byte(pw))
+	return hex.EncodeToString(sum[:])
+}
+
+func Store(user, password string) [20]byte {
+	return sha1.Sum([]byte(user + password))
+}
+
+func Sign(key, body []byte) []byte {
+	mac := hmac.New(sha1.New, key)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func SignDigest(key *rsa.PrivateKey, digest []byte) ([]byte, error) {
+	return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest)
+}
+
+func ETag(body []byte) string {
+	sum := md5.Sum(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func Fingerprint(data []byte) [20]byte {
+	return sha1.Sum(data)
+}
+`})
+
+	require.Len(t, violations, 4)
+	assert.Equal(t, []int{14, 19, 23, 29}, violationLines(violations))
+	assert.Equal(t, "MD5 used for a password: it is broken for collisions and fast to brute-force", violations[0].Message)
+	assert.Contains(t, violations[2].Message, "HMAC keyed with SHA-1")
+	assert.Contains(t, violations[3].Message, "rsa.SignPKCS1v15 called with SHA-1")
+	assert.Equal(t, "CWE-328", violations[0].Context["cwe"])
+}
@@ -0,0 +1,454 @@
+package typesafety
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules"
+)
+
+func init() {
+	rules.Register(NewAPIContractDriftRule())
+}
+
+// APIContractDriftRule compares the JSON contract a Go backend sends with the
+// TypeScript types its frontend reads it into:
+//
+//	type Transfer struct {                  interface Transfer {
+//		AmountCents int64  `json:"amount"`     amountCents: number   // renamed: never sent
+//		Fee   decimal.Decimal `json:"fee"`     fee: number           // a string on the wire
+//		Memo  string `json:"memo,omitempty"`   memo: string          // may be absent
+//	}                                       }
+//
+// Both sides compile and pass their own tests; the frontend breaks at run
+// time. An exported Go struct with json tags is paired with the TypeScript
+// interface or object type of the same name, or with the one the pairs
+// setting maps it to, and the rule reports fields missing on one side,
+// optionality that differs (omitempty against ?) and kinds that differ
+// (string, number, boolean, array, object) once Go types are read the way
+// encoding/json writes them: decimal.Decimal and time.Time as strings.
+//
+// A same-name pair is only compared when the name is unique on both sides and
+// the two share at least one field: otherwise the names coincide.
+type APIContractDriftRule struct {
+	*rules.BaseRule
+	// pairs maps a Go type name to the TypeScript type that mirrors it.
+	pairs map[string]string
+}
+
+// NewAPIContractDriftRule creates the rule
+func NewAPIContractDriftRule() *APIContractDriftRule {
+	return &APIContractDriftRule{
+		BaseRule: rules.NewBaseRule(
+			"api-contract-drift",
+			"typesafety",
+			"Detects Go JSON structs and their TypeScript interfaces disagreeing on fields, optionality or types",
+			core.SeverityHigh,
+		),
+		pairs: map[string]string{},
+	}
+}
+
+// Configure reads the optional pairs setting: Go type name to TypeScript type
+// name, for the types whose names differ.
+func (r *APIContractDriftRule) Configure(settings map[string]any) error {
+	if err := r.BaseRule.Configure(settings); err != nil {
+		return err
+	}
+	raw, ok := settings["pairs"]
+	if !ok {
+		return nil
+	}
+	configured, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("configure api-contract-drift: pairs must be a map of Go to TypeScript type names, got %T", raw)
+	}
+	pairs := make(map[string]string, len(configured))
+	for goName, value := range configured {
+		tsName, ok := value.(string)
+		if !ok || strings.TrimSpace(tsName) == "" || strings.TrimSpace(goName) == "" {
+			return fmt.Errorf("configure api-contract-drift: pairs entry %q must map to a TypeScript type name, got %v", goName, value)
+		}
+		pairs[goName] = tsName
+	}
+	r.pairs = pairs
+	return nil
+}
+
+// AnalyzeFile is a no-op: a contract has a side in each language.
+func (r *APIContractDriftRule) AnalyzeFile(_ *core.FileContext) []*core.Violation {
+	return nil
+}
+
+// RequiresSSA reports that types are enough for this rule.
+func (r *APIContractDriftRule) RequiresSSA() bool { return false }
+
+// contractField is one JSON field of an API type.
+type contractField struct {
+	name     string
+	file     string
+	line     int
+	optional bool
+	// kind is the JSON kind the field carries, "" when unknown. TypeScript
+	// kinds are resolved from tsType once every declaration is known.
+	kind     string
+	typeText string
+	tsType   []string
+}
+
+// contractType is one side of a contract.
+type contractType struct {
+	name   string
+	file   string
+	line   int
+	fields []contractField
+	// open reports fields inherited from a type that could not be read, so
+	// that a field missing here may still exist.
+	open bool
+}
+
+func (c *contractType) field(name string) (contractField, bool) {
+	for _, field := range c.fields {
+		if field.name == name {
+			return field, true
+		}
+	}
+	return contractField{}, false
+}
+
+// tsShapes indexes the TypeScript declarations of the project by name.
+type tsShapes struct {
+	objects map[string][]*tsTypeDecl
+	aliases map[string][]string
+}
+
+// AnalyzeGoProject pairs the project's Go contracts with its TypeScript types
+// and reports where they disagree.
+func (r *APIContractDriftRule) AnalyzeGoProject(ctx *core.GoProjectContext) ([]*core.Violation, error) {
+	if ctx == nil {
+		return nil, errors.New("api contract drift: nil Go project context")
+	}
+
+	shapes := &tsShapes{objects: make(map[string][]*tsTypeDecl), aliases: make(map[string][]string)}
+	relPaths := make(map[string]string, len(ctx.AllFiles))
+	for _, fileCtx := range ctx.AllFiles {
+		relPaths[filepath.Clean(fileCtx.Path)] = fileCtx.RelPath
+		if !fileCtx.IsTypeScriptFile() || fileCtx.IsTestFile() {
+			continue
+		}
+		decls := tsTypeDecls(fileCtx)
+		for i := range decls {
+			decl := &decls[i]
+			if decl.object {
+				shapes.objects[decl.contract.name] = append(shapes.objects[decl.contract.name], decl)
+			} else {
+				shapes.aliases[decl.contract.name] = decl.alias
+			}
+		}
+	}
+	goTypes := make(map[string][]*contractType)
+	for _, pkg := range ctx.Packages {
+		if pkg == nil || pkg.Package == nil || pkg.Package.TypesInfo == nil {
+			return nil, errors.New("api contract drift: package has no typed syntax")
+		}
+		for _, fileCtx := range pkg.Files {
+			if fileCtx.GoAST == nil || fileCtx.IsTestFile() {
+				continue
+			}
+			for _, contract := range goContracts(ctx.FileSet, fileCtx, pkg.Package.TypesInfo, relPaths) {
+				goTypes[contract.name] = append(goTypes[contract.name], contract)
+			}
+		}
+	}
+
+	var violations []*core.Violation
+	goNames := make([]string, 0, len(goTypes))
+	for name := range goTypes {
+		goNames = append(goNames, name)
+	}
+	sort.Strings(goNames)
+	for _, goName := range goNames {
+		tsName, mapped := r.pairs[goName]
+		if !mapped {
+			tsName = goName
+		}
+		if len(goTypes[goName]) != 1 || len(shapes.objects[tsName]) != 1 {
+			continue
+		}
+		goSide := goTypes[goName][0]
+		tsSide := shapes.resolve(shapes.objects[tsName][0], map[string]bool{})
+		if !mapped && !sharesField(goSide, tsSide) {
+			continue
+		}
+		violations = append(violations, r.compare(goSide, tsSide)...)
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations, nil
+}
+
+// goContracts returns the exported structs of a file that carry json tags,
+// with their fields as encoding/json writes them.
+func goContracts(fset *token.FileSet, fileCtx *core.FileContext, info *types.Info, relPaths map[string]string) []*contractType {
+	var contracts []*contractType
+	for _, decl := range fileCtx.GoAST.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || !typeSpec.Name.IsExported() {
+				continue
+			}
+			obj, ok := info.Defs[typeSpec.Name].(*types.TypeName)
+			if !ok || obj.IsAlias() {
+				continue
+			}
+			structType, ok := obj.Type().Underlying().(*types.Struct)
+			if !ok || !hasJSONTag(structType) {
+				continue
+			}
+			contract := &contractType{name: obj.Name(), file: fileCtx.RelPath, line: fileCtx.LineFor(typeSpec)}
+			// A promoted field may be declared in another file, or outside the
+			// project, where the struct itself is the place to point at.
+			locate := func(pos token.Pos) (string, int) {
+				position := fset.Position(pos)
+				if relPath, ok := relPaths[filepath.Clean(position.Filename)]; ok {
+					return relPath, position.Line
+				}
+				return contract.file, contract.line
+			}
+			contract.fields = goJSONFields(structType, locate, map[*types.Struct]bool{structType: true})
+			contracts = append(contracts, contract)
+		}
+	}
+	return contracts
+}
+
+func hasJSONTag(structType *types.Struct) bool {
+	for i := range structType.NumFields() {
+		if _, ok := reflect.StructTag(structType.Tag(i)).Lookup("json"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// goJSONFields lists the fields encoding/json writes for a struct. Fields of
+// an embedded struct without a json name are promoted unless a shallower
+// field has their name.
+func goJSONFields(structType *types.Struct, locate func(token.Pos) (string, int), seen map[*types.Struct]bool) []contractField {
+	var fields, promoted []contractField
+	for i := range structType.NumFields() {
+		variable := structType.Field(i)
+		tag, tagged := reflect.StructTag(structType.Tag(i)).Lookup("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if variable.Embedded() && name == "" {
+			if embedded, ok := derefType(variable.Type()).Underlying().(*types.Struct); ok {
+				if !seen[embedded] {
+					seen[embedded] = true
+					promoted = append(promoted, goJSONFields(embedded, locate, seen)...)
+				}
+				continue
+			}
+		}
+		if !variable.Exported() {
+			continue
+		}
+		if !tagged || name == "" {
+			name = variable.Name()
+		}
+		file, line := locate(variable.Pos())
+		field := contractField{
+			name:     name,
+			file:     file,
+			line:     line,
+			optional: hasTagOption(options, "omitempty") || hasTagOption(options, "omitzero"),
+			kind:     goJSONKind(variable.Type()),
+			typeText: types.TypeString(variable.Type(), func(pkg *types.Package) string { return pkg.Name() }),
+		}
+		if hasTagOption(options, "string") && field.kind != "" {
+			field.kind = "string"
+		}
+		fields = append(fields, field)
+	}
+	for _, field := range promoted {
+		if !hasContractField(fields, field.name) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func hasTagOption(options, option string) bool {
+	return slices.Contains(strings.Split(options, ","), option)
+}
+
+func hasContractField(fields []contractField, name string) bool {
+	for _, field := range fields {
+		if field.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func derefType(t types.Type) types.Type {
+	if pointer, ok := types.Unalias(t).(*types.Pointer); ok {
+		return pointer.Elem()
+	}
+	return t
+}
+
+// goJSONKind returns the JSON kind encoding/json writes a Go type as, "" for
+// a custom marshaler it cannot tell and for interfaces.
+func goJSONKind(t types.Type) string {
+	t = types.Unalias(derefType(t))
+	if named, ok := t.(*types.Named); ok {
+		methods := types.NewMethodSet(types.NewPointer(named))
+		if methods.Lookup(nil, "MarshalJSON") != nil {
+			return marshalerKind(named)
+		}
+		if methods.Lookup(nil, "MarshalText") != nil {
+			return "string"
+		}
+	}
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		switch info := underlying.Info(); {
+		case info&types.IsString != 0:
+			return "string"
+		case info&types.IsBoolean != 0:
+			return "boolean"
+		case info&types.IsNumeric != 0:
+			return "number"
+		}
+	case *types.Slice:
+		if basic, ok := underlying.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return "string" // base64
+		}
+		return "array"
+	case *types.Array:
+		return "array"
+	case *types.Map, *types.Struct:
+		return "object"
+	}
+	return ""
+}
+
+// marshalerKind knows what the common JSON marshalers write.
+func marshalerKind(named *types.Named) string {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return ""
+	}
+	switch path := obj.Pkg().Path(); {
+	case path == "time" && obj.Name() == "Time":
+		return "string"
+	case path == "math/big" && obj.Name() == "Int":
+		return "number"
+	case (path == "decimal" || strings.HasSuffix(path, "/decimal")) && strings.Contains(obj.Name(), "Decimal"):
+		return "string"
+	}
+	return ""
+}
+
+// resolve returns the fields of a TypeScript shape with those of the
+// interfaces it extends, and the kinds of all of them.
+func (s *tsShapes) resolve(decl *tsTypeDecl, seen map[string]bool) *contractType {
+	resolved := decl.contract
+	resolved.fields = nil
+	seen[decl.contract.name] = true
+	for _, field := range decl.contract.fields {
+		field.file = decl.contract.file
+		kind, undefinedAllowed := s.tsKind(field.tsType, map[string]bool{})
+		field.kind = kind
+		field.optional = field.optional || undefinedAllowed
+		resolved.fields = append(resolved.fields, field)
+	}
+	for _, parent := range decl.extends {
+		if len(s.objects[parent]) != 1 || seen[parent] {
+			resolved.open = true
+			continue
+		}
+		inherited := s.resolve(s.objects[parent][0], seen)
+		resolved.open = resolved.open || inherited.open
+		for _, field := range inherited.fields {
+			if !hasContractField(resolved.fields, field.name) {
+				resolved.fields = append(resolved.fields, field)
+			}
+		}
+	}
+	return &resolved
+}
+
+func sharesField(goSide, tsSide *contractType) bool {
+	for _, field := range goSide.fields {
+		if hasContractField(tsSide.fields, field.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare reports each disagreement where it is fixed: a field only Go sends
+// at the Go field, everything the frontend reads wrongly at the TypeScript
+// field.
+func (r *APIContractDriftRule) compare(goSide, tsSide *contractType) []*core.Violation {
+	var violations []*core.Violation
+	report := func(field contractField, message string) {
+		v := r.CreateViolation(field.file, field.line, message)
+		v.WithSuggestion("Generate the TypeScript types from the Go structs, or change both sides in the same commit")
+		v.WithContext("pattern", "api_contract_drift")
+		v.WithContext("field", field.name)
+		v.WithContext("go_type", goSide.file+":"+fmt.Sprint(goSide.line))
+		v.WithContext("ts_type", tsSide.file+":"+fmt.Sprint(tsSide.line))
+		violations = append(violations, v)
+	}
+	goRef := fmt.Sprintf("Go %s (%s:%d)", goSide.name, goSide.file, goSide.line)
+	tsRef := fmt.Sprintf("TypeScript %s (%s:%d)", tsSide.name, tsSide.file, tsSide.line)
+
+	for _, goField := range goSide.fields {
+		tsField, ok := tsSide.field(goField.name)
+		if !ok {
+			if !tsSide.open {
+				report(goField, fmt.Sprintf("JSON field %q of Go %s is missing from %s", goField.name, goSide.name, tsRef))
+			}
+			continue
+		}
+		switch {
+		case goField.optional && !tsField.optional:
+			report(tsField, fmt.Sprintf("Field %q is required in TypeScript %s but Go omits it when empty (omitempty): %s", tsField.name, tsSide.name, goRef))
+		case !goField.optional && tsField.optional:
+			report(tsField, fmt.Sprintf("Field %q is optional in TypeScript %s but Go always sends it: %s", tsField.name, tsSide.name, goRef))
+		}
+		if goField.kind != "" && tsField.kind != "" && goField.kind != tsField.kind {
+			report(tsField, fmt.Sprintf("Field %q is %s (%s) in TypeScript %s but %s (%s) on the wire from %s",
+				tsField.name, tsField.kind, tsField.typeText, tsSide.name, goField.kind, goField.typeText, goRef))
+		}
+	}
+	for _, tsField := range tsSide.fields {
+		if _, ok := goSide.field(tsField.name); !ok {
+			report(tsField, fmt.Sprintf("Field %q of TypeScript %s is not in %s: the backend never sends it", tsField.name, tsSide.name, goRef))
+		}
+	}
+	return violations
+}
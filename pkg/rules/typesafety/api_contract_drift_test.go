@@ -0,0 +1,149 @@
+package typesafety
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aiseeq/glint/pkg/core"
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+const contractDecimal = `package decimal
+
+type Decimal struct{ value string }
+
+func (d Decimal) MarshalJSON() ([]byte, error) { return []byte("\"" + d.value + "\""), nil }
+`
+
+const contractTransfer = `package api
+
+import (
+	"time"
+
+	"example.com/rulestest/decimal"
+)
+
+type Audit struct {
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+type Transfer struct {
+	Audit
+	ID          string          ` + "`json:\"id\"`" + `
+	AmountCents int64           ` + "`json:\"amount_cents\"`" + `
+	Fee         decimal.Decimal ` + "`json:\"fee\"`" + `
+	Memo        string          ` + "`json:\"memo,omitempty\"`" + `
+	Tags        []string        ` + "`json:\"tags\"`" + `
+	internal    string
+}
+`
+
+func analyzeContracts(t *testing.T, rule *APIContractDriftRule, files map[string]string) []*core.Violation {
+	t.Helper()
+	violations, err := rule.AnalyzeGoProject(rulestest.Project(t, files))
+	require.NoError(t, err)
+	return violations
+}
+
+func contractMessages(violations []*core.Violation) []string {
+	messages := make([]string, len(violations))
+	for i, v := range violations {
+		messages[i] = fmt.Sprintf("%s:%d %s", v.File, v.Line, v.Message)
+	}
+	return messages
+}
+
+// Repro of the recurring incident: the backend renamed amount to
+// amount_cents, the frontend kept reading amount and showed nothing.
+func TestAPIContractDriftReportsEachDisagreement(t *testing.T) {
+	violations := analyzeContracts(t, NewAPIContractDriftRule(), map[string]string{
+		"decimal/decimal.go": contractDecimal,
+		"api/transfer.go":    contractTransfer,
+		"web/src/api/transfer.ts": `import { Money } from './money';
+
+export interface Transfer {
+  id: string;
+  amount: number;
+  fee: number;
+  memo: string;
+  tags?: string[];
+  created_at: string;
+}
+`,
+	})
+
+	assert.Equal(t, []string{
+		"api/transfer.go:16 JSON field \"amount_cents\" of Go Transfer is missing from TypeScript Transfer (web/src/api/transfer.ts:3)",
+		"web/src/api/transfer.ts:5 Field \"amount\" of TypeScript Transfer is not in Go Transfer (api/transfer.go:13): the backend never sends it",
+		"web/src/api/transfer.ts:6 Field \"fee\" is number (number) in TypeScript Transfer but string (decimal.Decimal) on the wire from Go Transfer (api/transfer.go:13)",
+		"web/src/api/transfer.ts:7 Field \"memo\" is required in TypeScript Transfer but Go omits it when empty (omitempty): Go Transfer (api/transfer.go:13)",
+		"web/src/api/transfer.ts:8 Field \"tags\" is optional in TypeScript Transfer but Go always sends it: Go Transfer (api/transfer.go:13)",
+	}, contractMessages(violations))
+	assert.Equal(t, "amount", violations[1].Context["field"])
+}
+
+func TestAPIContractDriftAcceptsAMatchingContract(t *testing.T) {
+	violations := analyzeContracts(t, NewAPIContractDriftRule(), map[string]string{
+		"decimal/decimal.go": contractDecimal,
+		"api/transfer.go":    contractTransfer,
+		"web/src/types.ts": `type Timestamps = {
+  created_at: string
+}
+
+export type Tag = 'urgent' | 'internal'
+
+export interface Transfer extends Timestamps {
+  readonly id: string
+  amount_cents: number
+  fee: string // decimal string
+  memo?: string
+  tags:
+    | Tag[]
+    | null
+}
+`,
+	})
+	assert.Empty(t, violations)
+}
+
+func TestAPIContractDriftPairsByConfig(t *testing.T) {
+	files := map[string]string{
+		"api/user.go": "package api\n\ntype UserResponse struct {\n\tName string `json:\"name\"`\n\tAge int `json:\"age\"`\n}\n",
+		"web/user.ts": "export type User = {\n  name: string\n  age: string\n}\n",
+	}
+	assert.Empty(t, analyzeContracts(t, NewAPIContractDriftRule(), files), "different names are not paired without config")
+
+	rule := NewAPIContractDriftRule()
+	require.NoError(t, rule.Configure(map[string]any{"pairs": map[string]any{"UserResponse": "User"}}))
+	violations := analyzeContracts(t, rule, files)
+	require.Len(t, violations, 1)
+	assert.Contains(t, violations[0].Message, `Field "age" is string (string) in TypeScript User but number (int)`)
+
+	assert.Error(t, NewAPIContractDriftRule().Configure(map[string]any{"pairs": []string{"User"}}))
+	assert.Error(t, NewAPIContractDriftRule().Configure(map[string]any{"pairs": map[string]any{"User": 1}}))
+}
+
+func TestAPIContractDriftSkipsCoincidentalNames(t *testing.T) {
+	violations := analyzeContracts(t, NewAPIContractDriftRule(), map[string]string{
+		"api/config.go": "package api\n\ntype Config struct {\n\tPort int `json:\"port\"`\n}\n",
+		// Same name, nothing in common: a component's config, not the API's.
+		"web/config.ts": "export interface Config {\n  theme: string\n}\n",
+		// A type extending what the rule cannot read may have any field.
+		"api/page.go": "package api\n\ntype Page struct {\n\tTotal int `json:\"total\"`\n\tCursor string `json:\"cursor\"`\n}\n",
+		"web/page.ts": "export interface Page extends Omit<Base, 'x'> {\n  total: number\n}\n",
+	})
+	assert.Empty(t, violations)
+}
+
+func TestAPIContractDriftMetadata(t *testing.T) {
+	rule := NewAPIContractDriftRule()
+	assert.Equal(t, "api-contract-drift", rule.Name())
+	assert.Equal(t, "typesafety", rule.Category())
+	assert.False(t, rule.RequiresSSA())
+	assert.Nil(t, rule.AnalyzeFile(nil))
+	_, err := rule.AnalyzeGoProject(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,397 @@
+package typesafety
+
+import (
+	"strings"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// tsTypeDecl is a top-level TypeScript interface or type alias. An object
+// shape — an interface, an object literal type, an intersection of them —
+// has fields; any other alias keeps its type for the kinds of the fields
+// that refer to it.
+type tsTypeDecl struct {
+	contract contractType
+	object   bool
+	// extends names the interfaces whose fields the shape inherits.
+	extends []string
+	// alias is the type of a non-object alias.
+	alias []string
+}
+
+// tsTypeDecls reads the top-level interfaces and type aliases of a file.
+func tsTypeDecls(ctx *core.FileContext) []tsTypeDecl {
+	var tokens []core.TSToken
+	for _, tok := range core.LexTypeScript(ctx.Content) {
+		if tok.Kind != core.TSComment {
+			tokens = append(tokens, tok)
+		}
+	}
+
+	var decls []tsTypeDecl
+	depth := 0
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind == core.TSPunct {
+			switch tok.Text {
+			case "{", "(", "[":
+				depth++
+			case "}", ")", "]":
+				depth = max(depth-1, 0)
+			}
+			continue
+		}
+		if depth > 0 || tok.Kind != core.TSIdent || i+1 >= len(tokens) || tokens[i+1].Kind != core.TSIdent {
+			continue
+		}
+		if i > 0 && !startsTSStatement(tokens, i) {
+			continue
+		}
+		var decl tsTypeDecl
+		var next int
+		switch tok.Text {
+		case "interface":
+			decl, next = readTSInterface(tokens, i+1)
+		case "type":
+			decl, next = readTSTypeAlias(tokens, i+1)
+		default:
+			continue
+		}
+		decl.contract.name = tokens[i+1].Text
+		decl.contract.file = ctx.RelPath
+		decl.contract.line = tok.Line
+		decls = append(decls, decl)
+		i = next - 1
+	}
+	return decls
+}
+
+// startsTSStatement reports whether the token at i begins a statement: it
+// follows a statement end, a closing brace or a declaration modifier.
+func startsTSStatement(tokens []core.TSToken, i int) bool {
+	previous := tokens[i-1]
+	switch previous.Text {
+	case ";", "}", "export", "declare", "default":
+		return true
+	}
+	return tokens[i].NewlineBefore
+}
+
+func readTSInterface(tokens []core.TSToken, i int) (tsTypeDecl, int) {
+	decl := tsTypeDecl{object: true}
+	i = skipTSAngles(tokens, i+1)
+	if i < len(tokens) && tokens[i].Text == "extends" {
+		i++
+		for i < len(tokens) && tokens[i].Text != "{" {
+			if tokens[i].Kind == core.TSIdent {
+				decl.extends = append(decl.extends, tokens[i].Text)
+			}
+			i = skipTSAngles(tokens, i+1)
+			if i < len(tokens) && tokens[i].Text == "," {
+				i++
+			}
+		}
+	}
+	if i >= len(tokens) || tokens[i].Text != "{" {
+		return decl, i
+	}
+	end := closingTSBracket(tokens, i)
+	decl.contract.fields = readTSMembers(tokens[i+1 : end])
+	return decl, end + 1
+}
+
+// readTSTypeAlias reads the alias after "type": an object literal type or an
+// intersection of them and named shapes is an object, anything else keeps its
+// type.
+func readTSTypeAlias(tokens []core.TSToken, i int) (tsTypeDecl, int) {
+	var decl tsTypeDecl
+	i = skipTSAngles(tokens, i+1)
+	if i >= len(tokens) || tokens[i].Text != "=" {
+		return decl, i
+	}
+	end := tsTypeEnd(tokens, i+1, len(tokens))
+	rhs := tokens[i+1 : end]
+	parts := splitTSTopLevel(rhs, "&")
+	decl.object = len(splitTSTopLevel(rhs, "|")) == 1
+	for _, part := range parts {
+		switch {
+		case len(part) > 0 && part[0].Text == "{":
+			closeAt := closingTSBracket(part, 0)
+			decl.contract.fields = append(decl.contract.fields, readTSMembers(part[1:closeAt])...)
+		case len(part) == 1 && part[0].Kind == core.TSIdent:
+			decl.extends = append(decl.extends, part[0].Text)
+		case len(parts) > 1:
+			decl.contract.open = true
+		default:
+			decl.object = false
+		}
+	}
+	if !decl.object {
+		decl.alias = tokenTexts(rhs)
+		decl.contract.fields, decl.extends = nil, nil
+	}
+	return decl, end
+}
+
+// readTSMembers reads the property signatures of an object type. Methods,
+// index and call signatures are not JSON fields and are skipped.
+func readTSMembers(tokens []core.TSToken) []contractField {
+	var fields []contractField
+	for i := 0; i < len(tokens); {
+		end := tsTypeEnd(tokens, i, len(tokens))
+		if end == i {
+			i++ // a separator
+			continue
+		}
+		if field, ok := readTSProperty(tokens[i:end]); ok {
+			fields = append(fields, field)
+		}
+		i = end
+	}
+	return fields
+}
+
+func readTSProperty(member []core.TSToken) (contractField, bool) {
+	i := 0
+	if member[i].Text == "readonly" && len(member) > 1 && member[1].Text != ":" && member[1].Text != "?" {
+		i++
+	}
+	nameTok := member[i]
+	var name string
+	switch nameTok.Kind {
+	case core.TSIdent, core.TSNumber:
+		name = nameTok.Text
+	case core.TSString:
+		name = strings.Trim(nameTok.Text, `"'`)
+	default:
+		return contractField{}, false
+	}
+	i++
+	field := contractField{name: name, line: nameTok.Line}
+	if i < len(member) && member[i].Text == "?" {
+		field.optional = true
+		i++
+	}
+	if i >= len(member) || member[i].Text != ":" {
+		return contractField{}, false
+	}
+	field.tsType = tokenTexts(member[i+1:])
+	field.typeText = strings.Join(field.tsType, " ")
+	return field, true
+}
+
+// tsTypeEnd returns the end of the member or type starting at i: a ";" or
+// "," at the top level, or a line break there that does not continue a union
+// or an intersection.
+func tsTypeEnd(tokens []core.TSToken, i, limit int) int {
+	depth := 0
+	for j := i; j < limit; j++ {
+		tok := tokens[j]
+		if depth == 0 && j > i && tok.NewlineBefore && !continuesTSType(tokens[j-1], tok) {
+			return j
+		}
+		if tok.Kind != core.TSPunct {
+			continue
+		}
+		switch tok.Text {
+		case "{", "(", "[", "<":
+			depth++
+		case "}", ")", "]", ">":
+			if depth == 0 {
+				return j
+			}
+			depth--
+		case ";", ",":
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return limit
+}
+
+func continuesTSType(previous, next core.TSToken) bool {
+	for _, text := range []string{"|", "&", ":", "=", "=>", "?"} {
+		if previous.Text == text || next.Text == text {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTSTopLevel splits a type at a top-level operator; a leading operator,
+// as in a union written one member per line, is dropped.
+func splitTSTopLevel(tokens []core.TSToken, op string) [][]core.TSToken {
+	var parts [][]core.TSToken
+	depth, start := 0, 0
+	for j, tok := range tokens {
+		if tok.Kind != core.TSPunct {
+			continue
+		}
+		switch tok.Text {
+		case "{", "(", "[", "<":
+			depth++
+		case "}", ")", "]", ">":
+			depth--
+		case op:
+			if depth == 0 {
+				if j > start {
+					parts = append(parts, tokens[start:j])
+				}
+				start = j + 1
+			}
+		}
+	}
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// skipTSAngles skips type parameters when they start at i.
+func skipTSAngles(tokens []core.TSToken, i int) int {
+	if i >= len(tokens) || tokens[i].Text != "<" {
+		return i
+	}
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].Text {
+		case "<":
+			depth++
+		case ">":
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+func closingTSBracket(tokens []core.TSToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].Kind != core.TSPunct {
+			continue
+		}
+		switch tokens[i].Text {
+		case "{", "(", "[":
+			depth++
+		case "}", ")", "]":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+func tokenTexts(tokens []core.TSToken) []string {
+	texts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		texts[i] = tok.Text
+	}
+	return texts
+}
+
+// tsKind returns the JSON kind a TypeScript type describes — "" when it is
+// unknown or mixes kinds — and whether it admits undefined. null is left
+// aside: either side may send it.
+func (s *tsShapes) tsKind(texts []string, seen map[string]bool) (string, bool) {
+	kind, optional, known := "", false, true
+	for _, member := range splitTexts(texts, "|") {
+		memberKind := s.tsMemberKind(member, seen)
+		switch memberKind {
+		case "null":
+			continue
+		case "undefined":
+			optional = true
+			continue
+		}
+		if memberKind == "" || (kind != "" && kind != memberKind) {
+			known = false
+		}
+		kind = memberKind
+	}
+	if !known {
+		return "", optional
+	}
+	return kind, optional
+}
+
+func (s *tsShapes) tsMemberKind(member []string, seen map[string]bool) string {
+	if len(member) == 0 {
+		return ""
+	}
+	first, last := member[0], member[len(member)-1]
+	switch {
+	case len(member) > 1 && last == "]" && member[len(member)-2] == "[":
+		return "array"
+	case first == "[" || ((first == "Array" || first == "ReadonlyArray") && len(member) > 1):
+		return "array"
+	case first == "{" || first == "Record" || first == "Partial" || first == "Required" ||
+		first == "Readonly" || first == "Pick" || first == "Omit":
+		return "object"
+	case first == "(" && last == ")":
+		kind, _ := s.tsKind(member[1:len(member)-1], seen)
+		return kind
+	case len(member) > 1:
+		if _, ok := s.objects[first]; ok {
+			return "object" // a generic shape: Page<Item>
+		}
+		return ""
+	}
+	switch first {
+	case "string":
+		return "string"
+	case "number", "bigint":
+		return "number"
+	case "boolean", "true", "false":
+		return "boolean"
+	case "object":
+		return "object"
+	case "null", "undefined":
+		return first
+	}
+	switch first[0] {
+	case '"', '\'', '`':
+		return "string"
+	case '-', '.', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return "number"
+	}
+	if _, ok := s.objects[first]; ok {
+		return "object"
+	}
+	if alias, ok := s.aliases[first]; ok && !seen[first] {
+		seen[first] = true
+		kind, _ := s.tsKind(alias, seen)
+		return kind
+	}
+	return ""
+}
+
+// splitTexts is splitTSTopLevel over token texts.
+func splitTexts(texts []string, op string) [][]string {
+	var parts [][]string
+	depth, start := 0, 0
+	for j, text := range texts {
+		switch text {
+		case "{", "(", "[", "<":
+			depth++
+		case "}", ")", "]", ">":
+			depth--
+		case op:
+			if depth == 0 {
+				if j > start {
+					parts = append(parts, texts[start:j])
+				}
+				start = j + 1
+			}
+		}
+	}
+	if start < len(texts) {
+		parts = append(parts, texts[start:])
+	}
+	return parts
+}
@@ -0,0 +1,668 @@
+package taint
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// label is one kind of data a value may hold: untrusted data from a source,
+// or a placeholder for whatever the caller passes in a parameter. Path leads
+// from where the data entered the function, or from the parameter, to the
+// value.
+type label struct {
+	param  int // -1 for data from a source
+	source string
+	path   []Step
+}
+
+// labelKey identifies a label within a function. Data from one source that
+// arrives through different calls keeps one label per call, so that the
+// labels of a function stay finite under recursion.
+type labelKey struct {
+	param  int
+	origin token.Pos // where the data entered the program
+	via    token.Pos // the call it came through, NoPos in the function it entered
+}
+
+type labels map[labelKey]*label
+
+// keys returns the keys in a fixed order: when two paths lead to one label,
+// the first one kept must not depend on map iteration.
+func (ls labels) keys() []labelKey {
+	keys := make([]labelKey, 0, len(ls))
+	for key := range ls {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.param != b.param {
+			return a.param < b.param
+		}
+		if a.origin != b.origin {
+			return a.origin < b.origin
+		}
+		return a.via < b.via
+	})
+	return keys
+}
+
+// merge adds the labels of other that ls lacks and reports whether it added
+// any.
+func (ls labels) merge(other labels) bool {
+	added := false
+	for _, key := range other.keys() {
+		if _, ok := ls[key]; !ok {
+			ls[key] = other[key]
+			added = true
+		}
+	}
+	return added
+}
+
+// sinkHit is a sink a parameter reaches; path leads from the parameter to the
+// sink call or conversion.
+type sinkHit struct {
+	sink   ssa.Instruction
+	callee string
+	path   []Step
+}
+
+// summary is what a call needs to know about its callee.
+type summary struct {
+	// returns holds the labels of the results.
+	returns labels
+	// writes holds, per parameter, the labels stored into the memory it
+	// points to, its own placeholder excluded.
+	writes map[int]labels
+	// sinks holds, per parameter, the sinks it reaches.
+	sinks map[int][]sinkHit
+}
+
+func newSummary() *summary {
+	return &summary{returns: labels{}, writes: make(map[int]labels), sinks: make(map[int][]sinkHit)}
+}
+
+type findingKey struct {
+	sink   token.Pos
+	origin token.Pos
+}
+
+type engine struct {
+	cfg       Config
+	idx       *index
+	summaries map[*ssa.Function]*summary
+	roles     map[string]*role
+	findings  map[findingKey]Finding
+}
+
+// role is what the Config makes of a name: whether a sanitizer spec matches
+// it, and the sink and source specs that do.
+type role struct {
+	sanitizer bool
+	sinks     []Spec
+	sources   []Spec
+}
+
+func newEngine(idx *index, cfg Config) *engine {
+	e := &engine{
+		cfg:       cfg,
+		idx:       idx,
+		summaries: make(map[*ssa.Function]*summary, len(idx.functions)),
+		roles:     make(map[string]*role),
+		findings:  make(map[findingKey]Finding),
+	}
+	for _, fn := range idx.functions {
+		e.summaries[fn] = newSummary()
+	}
+	return e
+}
+
+// roleOf matches a name against the specs once per analysis.
+func (e *engine) roleOf(name string) *role {
+	if r, ok := e.roles[name]; ok {
+		return r
+	}
+	r := &role{sanitizer: e.idx.matchesAny(e.cfg.Sanitizers, name)}
+	for _, spec := range e.cfg.Sinks {
+		if e.idx.matches(spec, name) {
+			r.sinks = append(r.sinks, spec)
+		}
+	}
+	for _, spec := range e.cfg.Sources {
+		if e.idx.matches(spec, name) {
+			r.sources = append(r.sources, spec)
+		}
+	}
+	e.roles[name] = r
+	return r
+}
+
+// run analyzes the functions until no summary grows any more. Summaries only
+// grow and their labels are finite, so the loop ends.
+func (e *engine) run() {
+	queue := append([]*ssa.Function(nil), e.idx.functions...)
+	queued := make(map[*ssa.Function]bool, len(e.idx.functions))
+	for _, fn := range e.idx.functions {
+		queued[fn] = true
+	}
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		queued[fn] = false
+		if !e.analyze(fn) {
+			continue
+		}
+		for _, caller := range e.idx.callers[fn] {
+			if !queued[caller] {
+				queued[caller] = true
+				queue = append(queue, caller)
+			}
+		}
+	}
+}
+
+func (e *engine) report(hit sinkHit, data *label) {
+	path := concat(data.path, hit.path)
+	key := findingKey{sink: hit.sink.Pos(), origin: path[0].Pos}
+	if _, ok := e.findings[key]; ok {
+		return
+	}
+	e.findings[key] = Finding{Sink: hit.sink, Callee: hit.callee, Source: data.source, Path: path}
+}
+
+// frame is the analysis of one function.
+type frame struct {
+	e     *engine
+	sum   *summary
+	state map[ssa.Value]labels
+	// guarded holds, for each value passed to a guard, the blocks whose code
+	// is clean of it: those a branch on the guard's result leads to.
+	guarded map[ssa.Value][]*ssa.BasicBlock
+	block   *ssa.BasicBlock // the block being transferred
+	changed bool            // the state grew in this pass
+	grown   bool            // the summary grew
+}
+
+// analyze analyzes a function with the current summaries of its callees and
+// reports whether its own summary grew.
+func (e *engine) analyze(fn *ssa.Function) bool {
+	f := &frame{e: e, sum: e.summaries[fn], state: make(map[ssa.Value]labels)}
+	f.guarded = e.guardedValues(fn)
+	for i, param := range fn.Params {
+		f.add(param, labels{{param: i}: {param: i}})
+	}
+	for {
+		f.changed = false
+		for _, block := range fn.Blocks {
+			f.block = block
+			for _, instr := range block.Instrs {
+				f.transfer(instr)
+			}
+		}
+		f.block = nil
+		if !f.changed {
+			break
+		}
+	}
+	for i, param := range fn.Params {
+		stored := labels{}
+		for key, l := range f.get(param) {
+			if key != (labelKey{param: i}) {
+				stored[key] = l
+			}
+		}
+		if len(stored) == 0 {
+			continue
+		}
+		if f.sum.writes[i] == nil {
+			f.sum.writes[i] = labels{}
+		}
+		f.grown = f.sum.writes[i].merge(stored) || f.grown
+	}
+	return f.grown
+}
+
+// root returns the value whose memory an address points into. Memory is
+// tracked per root: a field or an element shares the labels of its struct,
+// array or slice, and a pointer converted to an interface — the &v passed to
+// json.Unmarshal — shares the labels of what it points to.
+func root(v ssa.Value) ssa.Value {
+	for {
+		switch addr := v.(type) {
+		case *ssa.FieldAddr:
+			v = addr.X
+		case *ssa.IndexAddr:
+			v = addr.X
+		case *ssa.MakeInterface:
+			v = addr.X
+		case *ssa.ChangeInterface:
+			v = addr.X
+		case *ssa.ChangeType:
+			v = addr.X
+		default:
+			return v
+		}
+	}
+}
+
+// get returns the labels of a value; one a guard has vetted on the way to
+// this block holds none.
+func (f *frame) get(v ssa.Value) labels {
+	if v == nil || f.guardedHere(v) {
+		return nil
+	}
+	return f.state[root(v)]
+}
+
+func (f *frame) guardedHere(v ssa.Value) bool {
+	if f.block == nil {
+		return false
+	}
+	for _, branch := range f.guarded[v] {
+		if branch.Dominates(f.block) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *frame) add(v ssa.Value, ls labels) {
+	if v == nil || len(ls) == 0 {
+		return
+	}
+	if f.e.cfg.ScalarsAreClean && isScalar(v.Type()) {
+		return
+	}
+	r := root(v)
+	if f.state[r] == nil {
+		f.state[r] = labels{}
+	}
+	if f.state[r].merge(ls) {
+		f.changed = true
+	}
+}
+
+func (f *frame) transfer(instr ssa.Instruction) {
+	switch in := instr.(type) {
+	case *ssa.Store:
+		f.add(in.Addr, f.get(in.Val))
+	case *ssa.MapUpdate:
+		f.add(in.Map, f.get(in.Key))
+		f.add(in.Map, f.get(in.Value))
+	case *ssa.Send:
+		f.add(in.Chan, f.get(in.X))
+	case *ssa.Return:
+		for _, result := range in.Results {
+			f.grown = f.sum.returns.merge(f.get(result)) || f.grown
+		}
+	case ssa.CallInstruction:
+		f.call(in)
+	case *ssa.UnOp:
+		f.add(in, f.get(in.X))
+		if in.Op == token.MUL {
+			// An implicit load, as of r.URL, has no position of its own.
+			pos := in.Pos()
+			if pos == token.NoPos {
+				pos = in.X.Pos()
+			}
+			f.add(in, f.sourceAt(pos, loadedName(in.X)))
+		}
+	case *ssa.Field:
+		f.add(in, f.get(in.X))
+		f.add(in, f.sourceAt(in.Pos(), fieldName(in.X.Type(), in.Field)))
+	case *ssa.ChangeType:
+		f.convert(in, in.X)
+	case *ssa.Convert:
+		f.convert(in, in.X)
+	case ssa.Value:
+		for _, operand := range instr.Operands(nil) {
+			if *operand != nil {
+				f.add(in, f.get(*operand))
+			}
+		}
+	}
+}
+
+// conversion is a *ssa.ChangeType or a *ssa.Convert.
+type conversion interface {
+	ssa.Value
+	ssa.Instruction
+}
+
+// convert handles a conversion, which is a sink when a spec names the type
+// converted to, as of html/template.HTML.
+func (f *frame) convert(conv conversion, x ssa.Value) {
+	name := types.TypeString(conv.Type(), nil)
+	if len(f.e.roleOf(name).sinks) > 0 {
+		f.reach(f.get(x), sinkHit{sink: conv, callee: name, path: []Step{{Pos: conv.Pos(), Note: "reaches conversion to " + name}}})
+		return
+	}
+	f.add(conv, f.get(x))
+}
+
+// sourceAt returns the label of data entering at pos when name is a source
+// read by value: a field or a global.
+func (f *frame) sourceAt(pos token.Pos, name string) labels {
+	for _, spec := range f.e.roleOf(name).sources {
+		if spec.Arg < 0 {
+			return sourceLabel(pos, name)
+		}
+	}
+	return nil
+}
+
+func sourceLabel(pos token.Pos, name string) labels {
+	key := labelKey{param: -1, origin: pos}
+	return labels{key: {param: -1, source: name, path: []Step{{Pos: pos, Note: "untrusted data from " + name}}}}
+}
+
+// loadedName names what a load reads when it is a field or a global.
+func loadedName(addr ssa.Value) string {
+	switch addr := addr.(type) {
+	case *ssa.FieldAddr:
+		if ptr, ok := addr.X.Type().Underlying().(*types.Pointer); ok {
+			return fieldName(ptr.Elem(), addr.Field)
+		}
+	case *ssa.Global:
+		if addr.Pkg != nil {
+			return addr.Pkg.Pkg.Path() + "." + addr.Name()
+		}
+	}
+	return ""
+}
+
+// fieldName spells the i-th field of a named struct type as
+// "net/http.Request.URL".
+func fieldName(t types.Type, i int) string {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return ""
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok || i >= st.NumFields() {
+		return ""
+	}
+	return types.TypeString(named.Origin(), nil) + "." + st.Field(i).Name()
+}
+
+func (f *frame) call(call ssa.CallInstruction) {
+	common := call.Common()
+	name := f.e.idx.calleeName(common)
+	args := common.Args
+	if common.IsInvoke() {
+		args = append([]ssa.Value{common.Value}, args...)
+	}
+	// Specs count arguments without the receiver.
+	specArgs := args
+	if common.IsInvoke() || common.Signature().Recv() != nil {
+		specArgs = args[1:]
+	}
+
+	role := f.e.roleOf(name)
+	if role.sanitizer {
+		return
+	}
+	for _, spec := range role.sinks {
+		hit := sinkHit{sink: call, callee: name, path: []Step{{Pos: call.Pos(), Note: "reaches " + name}}}
+		for i, arg := range specArgs {
+			if spec.Arg < 0 || spec.Arg == i {
+				f.reach(f.get(arg), hit)
+			}
+		}
+	}
+	if len(role.sinks) > 0 {
+		return
+	}
+	for _, spec := range role.sources {
+		switch {
+		case spec.Arg < 0:
+			f.add(result(call), sourceLabel(call.Pos(), name))
+		case spec.Arg < len(specArgs):
+			f.add(specArgs[spec.Arg], sourceLabel(call.Pos(), name))
+		}
+	}
+	if len(role.sources) > 0 {
+		return
+	}
+
+	if callee := common.StaticCallee(); callee != nil {
+		if sum, ok := f.e.summaries[callee]; ok {
+			f.apply(call, name, sum)
+			return
+		}
+	}
+	// Outside the analyzed code: the result and the memory behind pointer
+	// operands may hold anything the operands hold.
+	all := labels{}
+	for _, arg := range args {
+		all.merge(f.get(arg))
+	}
+	if !common.IsInvoke() {
+		all.merge(f.get(common.Value))
+	}
+	f.add(result(call), all)
+	for _, arg := range args {
+		if isPointerLike(arg.Type()) {
+			f.add(arg, all)
+		}
+	}
+}
+
+// apply applies the summary of a callee in the analyzed code.
+func (f *frame) apply(call ssa.CallInstruction, name string, sum *summary) {
+	args := call.Common().Args
+	step := Step{Pos: call.Pos(), Note: "flows through " + name}
+	f.add(result(call), f.translate(sum.returns, call, args, step))
+	for i, stored := range sum.writes {
+		if i < len(args) {
+			f.add(args[i], f.translate(stored, call, args, step))
+		}
+	}
+	for i, hits := range sum.sinks {
+		if i >= len(args) {
+			continue
+		}
+		for _, hit := range hits {
+			f.reach(f.get(args[i]), sinkHit{sink: hit.sink, callee: hit.callee, path: concat([]Step{step}, hit.path)})
+		}
+	}
+}
+
+// translate turns labels of a callee into labels of the caller: a parameter
+// placeholder into the labels of the argument, data from a source into a
+// label of the call.
+func (f *frame) translate(ls labels, call ssa.CallInstruction, args []ssa.Value, step Step) labels {
+	out := labels{}
+	for _, key := range ls.keys() {
+		l := ls[key]
+		if l.param < 0 {
+			callKey := labelKey{param: -1, origin: key.origin, via: call.Pos()}
+			if _, ok := out[callKey]; !ok {
+				out[callKey] = &label{param: -1, source: l.source, path: concat(l.path, []Step{step})}
+			}
+			continue
+		}
+		if l.param >= len(args) {
+			continue
+		}
+		passed := f.get(args[l.param])
+		for _, argKey := range passed.keys() {
+			if _, ok := out[argKey]; !ok {
+				arg := passed[argKey]
+				out[argKey] = &label{param: arg.param, source: arg.source, path: concat(arg.path, []Step{step}, l.path)}
+			}
+		}
+	}
+	return out
+}
+
+// reach records data reaching a sink: a finding for data from a source, a
+// summary entry for a parameter's placeholder.
+func (f *frame) reach(ls labels, hit sinkHit) {
+	for _, key := range ls.keys() {
+		l := ls[key]
+		if l.param < 0 {
+			f.e.report(hit, l)
+			continue
+		}
+		if hasSink(f.sum.sinks[l.param], hit.sink) {
+			continue
+		}
+		f.sum.sinks[l.param] = append(f.sum.sinks[l.param], sinkHit{sink: hit.sink, callee: hit.callee, path: concat(l.path, hit.path)})
+		f.grown = true
+	}
+}
+
+// result returns the value a call produces, nil for go and defer.
+func result(call ssa.CallInstruction) ssa.Value {
+	if v := call.Value(); v != nil {
+		return v
+	}
+	return nil
+}
+
+// guardedValues returns, for each value a function passes to a guard, the
+// blocks in which it is clean.
+func (e *engine) guardedValues(fn *ssa.Function) map[ssa.Value][]*ssa.BasicBlock {
+	if len(e.cfg.Guards) == 0 {
+		return nil
+	}
+	guarded := make(map[ssa.Value][]*ssa.BasicBlock)
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			common := call.Common()
+			args := common.Args
+			if !common.IsInvoke() && common.Signature().Recv() != nil {
+				args = args[1:]
+			}
+			name := e.idx.calleeName(common)
+			vetted := e.idx.specArgs(e.cfg.Guards, name, args)
+			if len(vetted) == 0 || (e.cfg.TrustGuard != nil && !e.cfg.TrustGuard(name, args)) {
+				continue
+			}
+			passed := passedBranches(result(call))
+			for _, arg := range vetted {
+				guarded[arg] = append(guarded[arg], passed...)
+			}
+		}
+	}
+	return guarded
+}
+
+// passedBranches returns the branches taken once a guard passed: its boolean
+// result true, or the error that is its last result nil.
+func passedBranches(v ssa.Value) []*ssa.BasicBlock {
+	if v == nil || v.Referrers() == nil {
+		return nil
+	}
+	tuple, ok := v.Type().(*types.Tuple)
+	if !ok {
+		return branchesWhere(v, true)
+	}
+	var branches []*ssa.BasicBlock
+	for _, ref := range *v.Referrers() {
+		extract, ok := ref.(*ssa.Extract)
+		if !ok || extract.Index != tuple.Len()-1 || extract.Referrers() == nil {
+			continue
+		}
+		for _, use := range *extract.Referrers() {
+			cmp, ok := use.(*ssa.BinOp)
+			if !ok || !isNilConst(cmp.X) && !isNilConst(cmp.Y) {
+				continue
+			}
+			switch cmp.Op {
+			case token.EQL:
+				branches = append(branches, branchesWhere(cmp, true)...)
+			case token.NEQ:
+				branches = append(branches, branchesWhere(cmp, false)...)
+			}
+		}
+	}
+	return branches
+}
+
+// branchesWhere returns the successors of the if statements on v, negated or
+// not, that only the arm where v equals holds enters: code past them runs
+// only after that arm was taken. The block where both arms join is not one of them.
+func branchesWhere(v ssa.Value, holds bool) []*ssa.BasicBlock {
+	if v.Referrers() == nil {
+		return nil
+	}
+	var branches []*ssa.BasicBlock
+	for _, ref := range *v.Referrers() {
+		switch use := ref.(type) {
+		case *ssa.If:
+			succ := use.Block().Succs[1]
+			if holds {
+				succ = use.Block().Succs[0]
+			}
+			if len(succ.Preds) == 1 {
+				branches = append(branches, succ)
+			}
+		case *ssa.UnOp:
+			if use.Op == token.NOT {
+				branches = append(branches, branchesWhere(use, !holds)...)
+			}
+		}
+	}
+	return branches
+}
+
+func isNilConst(v ssa.Value) bool {
+	c, ok := v.(*ssa.Const)
+	return ok && c.IsNil()
+}
+
+func hasSink(hits []sinkHit, sink ssa.Instruction) bool {
+	for _, hit := range hits {
+		if hit.sink == sink {
+			return true
+		}
+	}
+	return false
+}
+
+// calleeName spells what a call calls the way Spec patterns match it.
+func calleeName(common *ssa.CallCommon) string {
+	if common.IsInvoke() {
+		return "(" + types.TypeString(common.Value.Type(), nil) + ")." + common.Method.Name()
+	}
+	switch callee := common.Value.(type) {
+	case *ssa.Function:
+		if origin := callee.Origin(); origin != nil {
+			callee = origin
+		}
+		return callee.String()
+	case *ssa.Builtin:
+		return callee.Name()
+	}
+	return ""
+}
+
+func isScalar(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsNumeric|types.IsBoolean) != 0
+}
+
+func isPointerLike(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan:
+		return true
+	}
+	return false
+}
+
+func concat(paths ...[]Step) []Step {
+	var out []Step
+	for _, path := range paths {
+		out = append(out, path...)
+	}
+	return out
+}
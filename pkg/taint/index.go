@@ -0,0 +1,113 @@
+package taint
+
+import (
+	"regexp"
+	"sync"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// index is what every analysis of the same functions shares, whatever its
+// Config: the functions in order, the analyzed callers of each, the names of
+// the calls and which names the specs match. Matching dominates the cost of
+// an analysis — the same few names meet the same specs at every call site,
+// in every pass — so it is remembered per spec and name.
+type index struct {
+	functions []*ssa.Function
+	callers   map[*ssa.Function][]*ssa.Function
+
+	mu      sync.Mutex
+	names   map[*ssa.CallCommon]string
+	matched map[matchKey]bool
+}
+
+type matchKey struct {
+	re   *regexp.Regexp
+	name string
+}
+
+// indexKey stores a project's index in its GoProjectContext.
+type indexKey struct{}
+
+func newIndex(functions []*ssa.Function) *index {
+	idx := &index{
+		functions: functions,
+		callers:   make(map[*ssa.Function][]*ssa.Function),
+		names:     make(map[*ssa.CallCommon]string),
+		matched:   make(map[matchKey]bool),
+	}
+	analyzed := make(map[*ssa.Function]bool, len(functions))
+	for _, fn := range functions {
+		analyzed[fn] = true
+	}
+	for _, fn := range functions {
+		seen := make(map[*ssa.Function]bool)
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				call, ok := instr.(ssa.CallInstruction)
+				if !ok {
+					continue
+				}
+				callee := call.Common().StaticCallee()
+				if analyzed[callee] && !seen[callee] {
+					seen[callee] = true
+					idx.callers[callee] = append(idx.callers[callee], fn)
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// calleeName is the package's calleeName, remembered per call.
+func (idx *index) calleeName(common *ssa.CallCommon) string {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	name, ok := idx.names[common]
+	if !ok {
+		name = calleeName(common)
+		idx.names[common] = name
+	}
+	return name
+}
+
+func (idx *index) matches(spec Spec, name string) bool {
+	if name == "" || spec.re == nil {
+		return false
+	}
+	key := matchKey{re: spec.re, name: name}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	matched, ok := idx.matched[key]
+	if !ok {
+		matched = spec.matches(name)
+		idx.matched[key] = matched
+	}
+	return matched
+}
+
+func (idx *index) matchesAny(specs []Spec, name string) bool {
+	for _, spec := range specs {
+		if idx.matches(spec, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// specArgs returns the arguments of a call to name that the matching specs
+// select.
+func (idx *index) specArgs(specs []Spec, name string, args []ssa.Value) []ssa.Value {
+	var selected []ssa.Value
+	for _, spec := range specs {
+		if !idx.matches(spec, name) {
+			continue
+		}
+		for i, arg := range args {
+			if spec.Arg < 0 || spec.Arg == i {
+				selected = append(selected, arg)
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,223 @@
+// Package taint tracks untrusted data through a Go program in SSA form: from
+// sources — request fields, command-line arguments, the environment, decoded
+// JSON — through assignments, calls and memory to sinks such as query
+// strings, unless a sanitizer cleans it on the way. Security rules describe
+// what they track in a Config and turn the Findings into violations.
+//
+// The analysis is interprocedural and summary-based. A function's summary
+// records which of its parameters reach its results, the memory behind its
+// pointer parameters and the sinks it calls; a call applies the summary of its
+// callee, and a function is analyzed again whenever the summary of one of its
+// callees grows. Within a function the analysis relies on SSA form for flow
+// sensitivity and is field-insensitive: untrusted data stored in one field of
+// a struct taints the whole struct. Calls to functions outside the analyzed
+// packages, through interfaces or through function values carry the data of
+// all their operands to their result and to the memory their pointer operands
+// refer to.
+package taint
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/aiseeq/glint/pkg/core"
+)
+
+// Spec names the functions, methods, fields or globals a Config entry applies
+// to. Pattern is matched against the full name — "os.Getenv",
+// "(*net/http.Request).FormValue", "net/http.Request.URL" for a field,
+// "os.Args" for a global, "(io.Reader).Read" for a method called through an
+// interface — and "*" in it matches any run of characters, so
+// "(*database/sql.*).Query" covers the methods of DB, Tx and Conn.
+//
+// Arg selects an argument, the receiver not counted: the one a sink checks,
+// or the one whose memory a source fills, like the target of json.Unmarshal.
+// It is -1 for every argument of a sink and for the result of a source.
+// Variadic arguments count as one, the slice they are passed in.
+type Spec struct {
+	Pattern string
+	Arg     int
+	re      *regexp.Regexp
+}
+
+// ParseSpec parses "pattern" or "pattern#arg".
+func ParseSpec(text string) (Spec, error) {
+	pattern, arg := strings.TrimSpace(text), -1
+	if i := strings.LastIndexByte(pattern, '#'); i >= 0 {
+		n, err := strconv.Atoi(pattern[i+1:])
+		if err != nil || n < 0 {
+			return Spec{}, fmt.Errorf("parse taint spec %q: argument must be a non-negative number", text)
+		}
+		pattern, arg = pattern[:i], n
+	}
+	if pattern == "" {
+		return Spec{}, fmt.Errorf("parse taint spec %q: empty pattern", text)
+	}
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, `.*`)
+	return Spec{Pattern: pattern, Arg: arg, re: regexp.MustCompile("^" + quoted + "$")}, nil
+}
+
+// MustParseSpecs parses specs known to be valid, such as a rule's defaults.
+func MustParseSpecs(texts ...string) []Spec {
+	specs := make([]Spec, 0, len(texts))
+	for _, text := range texts {
+		spec, err := ParseSpec(text)
+		if err != nil {
+			panic(err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+func (s Spec) matches(name string) bool {
+	return name != "" && s.re != nil && s.re.MatchString(name)
+}
+
+// Config describes what an analysis tracks. A sink is a call or, when its
+// pattern names a type such as "html/template.HTML", a conversion to it.
+type Config struct {
+	Sources    []Spec
+	Sanitizers []Spec
+	Sinks      []Spec
+	// Guards validate their argument without changing it and are trusted
+	// only where their verdict is acted on: a value passed to one is clean in the code that runs only once the guard
+	// passed — the branch on its boolean result being true, or on its error
+	// result being nil. A prefix test whose result decides nothing, or whose
+	// failure is the branch that goes on, rejects nothing.
+	Guards []Spec
+	// TrustGuard, when set, vets each call to a guard by its arguments
+	// without the receiver, like a prefix test of a directory without its
+	// trailing separator; a call it rejects guards nothing.
+	TrustGuard func(name string, args []ssa.Value) bool
+	// ScalarsAreClean drops the taint of numbers and booleans: once parsed
+	// into one, a value can no longer spell an injection.
+	ScalarsAreClean bool
+}
+
+// HTTPRequestSources are the parts of an incoming request a client controls.
+var HTTPRequestSources = MustParseSpecs(
+	"(*net/http.Request).FormValue", "(*net/http.Request).PostFormValue",
+	"(*net/http.Request).FormFile", "(*net/http.Request).Cookie", "(*net/http.Request).Cookies",
+	"(*net/http.Request).UserAgent", "(*net/http.Request).Referer", "(*net/http.Request).PathValue",
+	"(*net/http.Request).BasicAuth", "(*net/http.Request).MultipartReader",
+	"net/http.Request.URL", "net/http.Request.Header", "net/http.Request.Body",
+	"net/http.Request.Form", "net/http.Request.PostForm", "net/http.Request.MultipartForm",
+	"net/http.Request.Host", "net/http.Request.RequestURI", "net/http.Request.Trailer",
+)
+
+// ProcessSources are the command line and the environment.
+var ProcessSources = MustParseSpecs("os.Args", "os.Getenv", "os.LookupEnv", "os.Environ")
+
+// JSONSources fill the value JSON is decoded into.
+var JSONSources = MustParseSpecs("encoding/json.Unmarshal#1", "(*encoding/json.Decoder).Decode#0")
+
+// Step is one hop of the path untrusted data takes.
+type Step struct {
+	Pos  token.Pos
+	Note string
+}
+
+// Finding is untrusted data reaching a sink.
+type Finding struct {
+	// Sink is the call or conversion receiving the data, Callee the full
+	// name of what it calls or converts to.
+	Sink   ssa.Instruction
+	Callee string
+	// Source is the full name of the source the data comes from.
+	Source string
+	// Path leads from where the data enters through the calls it passes to
+	// the sink.
+	Path []Step
+}
+
+// Trace renders the path as "file:line: note" lines, file relative to root.
+func (f Finding) Trace(fset *token.FileSet, root string) []string {
+	trace := make([]string, 0, len(f.Path))
+	for _, step := range f.Path {
+		position := fset.Position(step.Pos)
+		file := position.Filename
+		if rel, err := filepath.Rel(root, file); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+		trace = append(trace, fmt.Sprintf("%s:%d: %s", file, position.Line, step.Note))
+	}
+	return trace
+}
+
+// AnalyzeProject runs the analysis over the functions of the project's own
+// packages; the SSA program must be built. What does not depend on the Config
+// — the functions, their callers, the spec matches — is computed once per
+// project and shared by the rules analyzing it.
+func AnalyzeProject(ctx *core.GoProjectContext, cfg Config) ([]Finding, error) {
+	if ctx == nil {
+		return nil, errors.New("taint analysis: nil Go project context")
+	}
+	if ctx.Program == nil {
+		return nil, errors.New("taint analysis: Go project has no SSA program")
+	}
+	idx := core.Shared(ctx, indexKey{}, func() *index { return newIndex(projectFunctions(ctx)) })
+	return analyze(idx, cfg), nil
+}
+
+// projectFunctions returns the functions of the project's packages with a
+// body, in source order.
+func projectFunctions(ctx *core.GoProjectContext) []*ssa.Function {
+	inProject := make(map[*ssa.Package]bool)
+	for _, pkg := range ctx.Packages {
+		if pkg != nil && pkg.SSA != nil {
+			inProject[pkg.SSA] = true
+		}
+	}
+	var functions []*ssa.Function
+	for fn := range ssautil.AllFunctions(ctx.Program) {
+		pkg := fn.Pkg
+		if pkg == nil && fn.Origin() != nil {
+			pkg = fn.Origin().Pkg
+		}
+		if inProject[pkg] && fn.Blocks != nil {
+			functions = append(functions, fn)
+		}
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Pos() != functions[j].Pos() {
+			return functions[i].Pos() < functions[j].Pos()
+		}
+		return functions[i].String() < functions[j].String()
+	})
+	return functions
+}
+
+// Analyze runs the analysis over the given functions; calls to any other
+// function are treated as calls outside the program. Findings are in the
+// order of their sinks, then of their sources.
+func Analyze(functions []*ssa.Function, cfg Config) []Finding {
+	return analyze(newIndex(functions), cfg)
+}
+
+func analyze(idx *index, cfg Config) []Finding {
+	e := newEngine(idx, cfg)
+	e.run()
+
+	findings := make([]Finding, 0, len(e.findings))
+	for _, finding := range e.findings {
+		findings = append(findings, finding)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Sink.Pos() != b.Sink.Pos() {
+			return a.Sink.Pos() < b.Sink.Pos()
+		}
+		return a.Path[0].Pos < b.Path[0].Pos
+	})
+	return findings
+}
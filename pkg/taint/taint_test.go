@@ -0,0 +1,255 @@
+package taint
+
+import (
+	"go/constant"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/aiseeq/glint/pkg/rules/rulestest"
+)
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec("(*database/sql.*).QueryContext#1")
+	require.NoError(t, err)
+	assert.Equal(t, "(*database/sql.*).QueryContext", spec.Pattern)
+	assert.Equal(t, 1, spec.Arg)
+	assert.True(t, spec.matches("(*database/sql.Tx).QueryContext"))
+	assert.False(t, spec.matches("(*database/sql.Tx).Query"))
+	assert.False(t, spec.matches("(*database/sql.Tx).QueryContextual"))
+
+	spec, err = ParseSpec(" os.Getenv ")
+	require.NoError(t, err)
+	assert.Equal(t, -1, spec.Arg)
+	assert.True(t, spec.matches("os.Getenv"))
+	assert.False(t, spec.matches("xos.Getenv"))
+
+	for _, text := range []string{"", "#0", "os.Getenv#", "os.Getenv#-1", "os.Getenv#a"} {
+		_, err := ParseSpec(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func analyzeSource(t *testing.T, source string, cfg Config) []Finding {
+	t.Helper()
+	ctx := rulestest.ProjectWithSSA(t, map[string]string{"app/app.go": source})
+	findings, err := AnalyzeProject(ctx, cfg)
+	require.NoError(t, err)
+	return findings
+}
+
+func notes(finding Finding) []string {
+	out := make([]string, 0, len(finding.Path))
+	for _, step := range finding.Path {
+		out = append(out, step.Note)
+	}
+	return out
+}
+
+const execApp = `package app
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type job struct {
+	Name string
+	Args []string
+}
+
+func command(j *job) string { return strings.Join(j.Args, " ") }
+
+func fill(j *job) { j.Args = append(j.Args, os.Args[1:]...) }
+
+func run(line string) { exec.Command("sh", "-c", line).Run() }
+
+func quote(s string) string { return strconv.Quote(s) }
+
+func Main() {
+	j := &job{Name: "build"}
+	fill(j)
+	run(command(j))
+	run(quote(os.Getenv("TARGET")))
+	n, _ := strconv.Atoi(os.Getenv("JOBS"))
+	run(strconv.Itoa(n))
+}
+`
+
+func TestAnalyzeFollowsSummaries(t *testing.T) {
+	findings := analyzeSource(t, execApp, Config{
+		Sources:         ProcessSources,
+		Sanitizers:      MustParseSpecs("example.com/rulestest/app.quote"),
+		Sinks:           MustParseSpecs("os/exec.Command#1"),
+		ScalarsAreClean: true,
+	})
+
+	require.Len(t, findings, 1)
+	finding := findings[0]
+	assert.Equal(t, "os.Args", finding.Source)
+	assert.Equal(t, "os/exec.Command", finding.Callee)
+	// The data enters j through fill's write to its parameter, leaves it
+	// through command's result and reaches the sink inside run.
+	assert.Equal(t, []string{
+		"untrusted data from os.Args",
+		"flows through example.com/rulestest/app.fill",
+		"flows through example.com/rulestest/app.command",
+		"flows through example.com/rulestest/app.run",
+		"reaches os/exec.Command",
+	}, notes(finding))
+}
+
+func TestAnalyzeWithoutScalarPruning(t *testing.T) {
+	findings := analyzeSource(t, execApp, Config{
+		Sources:    MustParseSpecs("os.Getenv"),
+		Sanitizers: MustParseSpecs("example.com/rulestest/app.quote"),
+		Sinks:      MustParseSpecs("os/exec.Command"),
+	})
+
+	// The parsed number keeps its taint; the quoted value does not.
+	require.Len(t, findings, 1)
+	assert.Equal(t, "os.Getenv", findings[0].Source)
+	assert.Contains(t, notes(findings[0]), "flows through example.com/rulestest/app.run")
+}
+
+func TestAnalyzeProjectSharesTheIndexAcrossConfigs(t *testing.T) {
+	ctx := rulestest.ProjectWithSSA(t, map[string]string{"app/app.go": execApp})
+	sanitized := Config{
+		Sources:    MustParseSpecs("os.Getenv"),
+		Sanitizers: MustParseSpecs("example.com/rulestest/app.quote"),
+		Sinks:      MustParseSpecs("os/exec.Command"),
+	}
+	removals := Config{Sources: MustParseSpecs("os.Getenv"), Sinks: MustParseSpecs("os.Remove")}
+
+	first, err := AnalyzeProject(ctx, sanitized)
+	require.NoError(t, err)
+	other, err := AnalyzeProject(ctx, removals)
+	require.NoError(t, err)
+	again, err := AnalyzeProject(ctx, sanitized)
+	require.NoError(t, err)
+
+	// The second config's specs leave nothing behind in the shared index.
+	assert.Len(t, first, 1)
+	assert.Empty(t, other)
+	assert.Equal(t, first, again)
+}
+
+func TestAnalyzeProjectRequiresSSA(t *testing.T) {
+	_, err := AnalyzeProject(nil, Config{})
+	assert.Error(t, err)
+
+	_, err = AnalyzeProject(rulestest.Project(t, map[string]string{"app/app.go": "package app\n"}), Config{})
+	assert.EqualError(t, err, "taint analysis: Go project has no SSA program")
+}
+
+func TestAnalyzeConversionSinks(t *testing.T) {
+	findings := analyzeSource(t, `package app
+
+import (
+	"html"
+	"html/template"
+	"os"
+)
+
+func Banner() template.HTML {
+	return template.HTML(os.Getenv("BANNER"))
+}
+
+func Title() template.HTML {
+	return template.HTML(html.EscapeString(os.Getenv("TITLE")))
+}
+`, Config{
+		Sources:    ProcessSources,
+		Sanitizers: MustParseSpecs("html.EscapeString"),
+		Sinks:      MustParseSpecs("html/template.HTML"),
+	})
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "html/template.HTML", findings[0].Callee)
+	assert.Equal(t, []string{"untrusted data from os.Getenv", "reaches conversion to html/template.HTML"}, notes(findings[0]))
+}
+
+func TestAnalyzeGuards(t *testing.T) {
+	findings := analyzeSource(t, `package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func Guarded() {
+	name := os.Getenv("NAME")
+	if !strings.HasPrefix(name, "data/") {
+		return
+	}
+	os.Remove(name)
+}
+
+func RelChecked() {
+	name := os.Getenv("NAME")
+	if _, err := filepath.Rel("/srv", name); err != nil {
+		return
+	}
+	os.Remove(name)
+}
+
+func Ignored() {
+	name := os.Getenv("NAME")
+	if strings.HasPrefix(name, "data/") {
+		println(name)
+	}
+	os.Remove(name)
+}
+
+func Unused() {
+	path := filepath.Clean(os.Getenv("NAME"))
+	valid := strings.HasPrefix(path, "data/")
+	_ = valid
+	os.Remove(path)
+}
+
+func FailedBranch() {
+	name := os.Getenv("NAME")
+	if !strings.HasPrefix(name, "data/") {
+		os.Remove(name)
+	}
+}
+
+func RelIgnored() {
+	name := os.Getenv("NAME")
+	rel, _ := filepath.Rel("/srv", name)
+	println(rel)
+	os.Remove(name)
+}
+
+func Untrusted() {
+	name := os.Getenv("NAME")
+	if !strings.HasPrefix(name, "data") {
+		return
+	}
+	os.Remove(name)
+}
+`, Config{
+		Sources: ProcessSources,
+		Sinks:   MustParseSpecs("os.Remove"),
+		Guards:  MustParseSpecs("strings.HasPrefix#0", "path/filepath.Rel#1"),
+		TrustGuard: func(name string, args []ssa.Value) bool {
+			prefix, ok := args[len(args)-1].(*ssa.Const)
+			return name != "strings.HasPrefix" || ok && strings.HasSuffix(constant.StringVal(prefix.Value), "/")
+		},
+	})
+
+	// Only the guards that passed on the way to the sink clean the name.
+	var lines []int
+	for _, finding := range findings {
+		assert.Equal(t, "os.Remove", finding.Callee)
+		lines = append(lines, finding.Sink.Parent().Prog.Fset.Position(finding.Sink.Pos()).Line)
+	}
+	assert.ElementsMatch(t, []int{30, 37, 43, 51, 59}, lines)
+}